@@ -24,32 +24,24 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/admin/users": {
-            "get": {
+        "/admin/billing/checkout": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all users, with optional filtering by role. Accessible by admins.",
+                "description": "Starts a payment for the next subscription period of the admin's school. A running paid subscription is extended from its end date.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - User Management"
-                ],
-                "summary": "Get All Users",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Filter users by role (teacher, student, admin)",
-                        "name": "role",
-                        "in": "query"
-                    }
+                    "Admin - Billing"
                 ],
+                "summary": "Create Renewal Checkout",
                 "responses": {
-                    "200": {
-                        "description": "Users retrieved successfully",
+                    "201": {
+                        "description": "Checkout created successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -59,13 +51,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.UserListResponse"
+                                            "$ref": "#/definitions/handlers.CheckoutResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -85,14 +83,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/guardian-links": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Allows an admin to create a new teacher or student account within their school.",
+                "description": "Allows a school admin to link an existing parent account to a student of their school.",
                 "consumes": [
                     "application/json"
                 ],
@@ -100,23 +100,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - User Management"
+                    "Admin - Guardians"
                 ],
-                "summary": "Create Teacher or Student",
+                "summary": "Link Parent to Student",
                 "parameters": [
                     {
-                        "description": "User details to create",
-                        "name": "createUserRequest",
+                        "description": "Parent and student to link",
+                        "name": "createGuardianLinkRequest",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CreateUserRequest"
+                            "$ref": "#/definitions/handlers.CreateGuardianLinkRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "User created successfully",
+                        "description": "Guardian link created successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -126,7 +126,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.UserDataResponse"
+                                            "$ref": "#/definitions/handlers.GuardianLinkResponse"
                                         }
                                     }
                                 }
@@ -150,35 +150,29 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CommonResponse"
-                        }
                     }
                 }
             }
         },
-        "/admin/users/{id}": {
-            "get": {
+        "/admin/guardian-links/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a specific user's details by their ID. Accessible by admins.",
+                "description": "Allows a school admin to remove a parent-student link.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - User Management"
+                    "Admin - Guardians"
                 ],
-                "summary": "Get User By ID",
+                "summary": "Delete Guardian Link",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Guardian link ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -186,21 +180,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "User retrieved successfully",
+                        "description": "Guardian link deleted successfully",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handlers.CommonResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/handlers.UserDataResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/handlers.CommonResponse"
                         }
                     },
                     "400": {
@@ -220,59 +202,28 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
-                    },
-                    "404": {
-                        "description": "User not found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CommonResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CommonResponse"
-                        }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/invoices": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates the details of an existing user. Accessible by admins.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Lists the invoices of the admin's school, newest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - User Management"
-                ],
-                "summary": "Update User",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "User details to update",
-                        "name": "updateUserRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.UpdateUserRequest"
-                        }
-                    }
+                    "Admin - Billing"
                 ],
+                "summary": "List School Invoices",
                 "responses": {
                     "200": {
-                        "description": "User updated successfully",
+                        "description": "Invoices retrieved successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -282,7 +233,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.UserDataResponse"
+                                            "$ref": "#/definitions/handlers.InvoiceListResponse"
                                         }
                                     }
                                 }
@@ -307,12 +258,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CommonResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -320,25 +265,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/invoices/{id}/download": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a user by their ID. Accessible by admins.",
+                "description": "Downloads one of the admin's school invoices as an HTML document.",
                 "produces": [
-                    "application/json"
+                    "text/html"
                 ],
                 "tags": [
-                    "Admin - User Management"
+                    "Admin - Billing"
                 ],
-                "summary": "Delete User",
+                "summary": "Download School Invoice",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Invoice ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -346,9 +293,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "User deleted successfully",
+                        "description": "Invoice document",
                         "schema": {
-                            "$ref": "#/definitions/handlers.CommonResponse"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -370,7 +317,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Invoice not found",
                         "schema": {
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
@@ -384,9 +331,67 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/login": {
-            "post": {
-                "description": "Authenticates a user and returns a JWT token.",
+        "/admin/ldap-config": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the LDAP / Active Directory login configuration of the admin's school. The bind password is never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - LDAP"
+                ],
+                "summary": "Get LDAP Configuration",
+                "responses": {
+                    "200": {
+                        "description": "LDAP config retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.LDAPConfigResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "LDAP config not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates or updates the LDAP / Active Directory login configuration of the admin's school. Omit bind_password to keep the stored one.",
                 "consumes": [
                     "application/json"
                 ],
@@ -394,23 +399,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin - LDAP"
                 ],
-                "summary": "User Login",
+                "summary": "Save LDAP Configuration",
                 "parameters": [
                     {
-                        "description": "Login Credentials",
-                        "name": "loginRequest",
+                        "description": "LDAP configuration",
+                        "name": "ldapConfigRequest",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.LoginRequest"
+                            "$ref": "#/definitions/handlers.LDAPConfigRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login successful",
+                        "description": "LDAP config saved successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -420,7 +425,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.LoginResponseData"
+                                            "$ref": "#/definitions/handlers.LDAPConfigResponseData"
                                         }
                                     }
                                 }
@@ -439,8 +444,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
@@ -448,24 +453,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/logout": {
-            "post": {
+        "/admin/permissions": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Invalidates the client-side JWT token (no server-side session invalidation for stateless JWT).",
+                "description": "Lists every permission that can be granted to a role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin - Roles"
                 ],
-                "summary": "User Logout",
+                "summary": "Get Permissions",
                 "responses": {
                     "200": {
-                        "description": "Logout successful",
+                        "description": "Permissions retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.PermissionListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
@@ -473,24 +508,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/profile": {
+        "/admin/plan": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves the basic profile information of the authenticated user.",
+                "description": "Returns the school's current package and subscription, its billing credit, any scheduled plan change and the plan change history.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin - Billing"
                 ],
-                "summary": "Get User Profile",
+                "summary": "Get Plan",
                 "responses": {
                     "200": {
-                        "description": "User profile retrieved successfully",
+                        "description": "Plan retrieved successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -500,19 +535,31 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.UserProfileResponseData"
+                                            "$ref": "#/definitions/services.PlanOverview"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -522,9 +569,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/register/admin-info": {
+        "/admin/plan/change": {
             "post": {
-                "description": "Step 2 of school registration: Register the primary admin user for the school.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves the school to another package. With ` + "`" + `at_period_end` + "`" + ` the change is scheduled for the end of the current period and nothing is charged now. Otherwise a checkout for the prorated difference is returned and the package changes when it is paid; a change covered by billing credit is applied immediately.",
                 "consumes": [
                     "application/json"
                 ],
@@ -532,23 +584,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "School Registration"
+                    "Admin - Billing"
                 ],
-                "summary": "Register Admin Information",
+                "summary": "Change Plan",
                 "parameters": [
                     {
-                        "description": "Admin Information",
-                        "name": "registerAdminInfoRequest",
+                        "description": "Target package",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.RegisterAdminInfoRequest"
+                            "$ref": "#/definitions/handlers.PlanChangeRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Admin user created and linked to school",
+                        "description": "Plan change requested successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -558,7 +610,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.RegisterAdminInfoResponseData"
+                                            "$ref": "#/definitions/services.PlanChangeResult"
                                         }
                                     }
                                 }
@@ -571,8 +623,44 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Package not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "School has more students than the target package allows",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.StudentQuotaErrorData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
@@ -580,9 +668,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/register/complete": {
+        "/admin/plan/preview": {
             "post": {
-                "description": "Step 6 of school registration: Finalizes the registration process after all previous steps are complete (including payment if applicable).",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Quotes a change to another package without changing anything. Immediate changes during a paid period credit the unused days of the current package against the target package for the same days; a negative difference becomes billing credit.",
                 "consumes": [
                     "application/json"
                 ],
@@ -590,23 +683,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "School Registration"
+                    "Admin - Billing"
                 ],
-                "summary": "Complete School Registration",
+                "summary": "Preview Plan Change",
                 "parameters": [
                     {
-                        "description": "School ID to complete registration for",
-                        "name": "completeRegistrationRequest",
+                        "description": "Target package",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CompleteRegistrationRequest"
+                            "$ref": "#/definitions/handlers.PlanChangeRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "School registration completed successfully",
+                        "description": "Plan change quoted successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -616,7 +709,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.CompleteRegistrationResponseData"
+                                            "$ref": "#/definitions/services.PlanChangeQuote"
                                         }
                                     }
                                 }
@@ -629,50 +722,40 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/register/email-verification/request-otp": {
-            "post": {
-                "description": "Step 4 of school registration: Sends an OTP to the user's email for verification.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "School Registration"
-                ],
-                "summary": "Request Email Verification OTP",
-                "parameters": [
-                    {
-                        "description": "User ID for OTP request",
-                        "name": "requestOTPRequest",
-                        "in": "body",
-                        "required": true,
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/handlers.RequestOTPRequest"
+                            "$ref": "#/definitions/handlers.CommonResponse"
                         }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OTP sent to email successfully",
+                    },
+                    "404": {
+                        "description": "Package not found",
                         "schema": {
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "409": {
+                        "description": "School has more students than the target package allows",
                         "schema": {
-                            "$ref": "#/definitions/handlers.CommonResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.StudentQuotaErrorData"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "500": {
@@ -684,39 +767,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/register/email-verification/verify-otp": {
-            "post": {
-                "description": "Step 4 of school registration: Verifies the OTP sent to the user's email.",
-                "consumes": [
-                    "application/json"
+        "/admin/plan/scheduled-change": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Cancels the plan change scheduled for the end of the current period.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "School Registration"
-                ],
-                "summary": "Verify Email OTP",
-                "parameters": [
-                    {
-                        "description": "User ID and OTP for verification",
-                        "name": "verifyOTPRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.VerifyOTPRequest"
-                        }
-                    }
+                    "Admin - Billing"
                 ],
+                "summary": "Cancel Scheduled Plan Change",
                 "responses": {
                     "200": {
-                        "description": "Email verified successfully",
+                        "description": "Scheduled plan change cancelled successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PlanChange"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad request (invalid/expired OTP)",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No scheduled plan change",
                         "schema": {
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
@@ -730,19 +828,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/register/packages": {
+        "/admin/roles": {
             "get": {
-                "description": "Retrieves a list of all available subscription packages (Free Trial, Premium, Enterprise).",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the system roles and the custom roles of the admin's school.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "School Registration"
+                    "Admin - Roles"
                 ],
-                "summary": "Get All Available Packages",
+                "summary": "Get Roles",
                 "responses": {
                     "200": {
-                        "description": "Packages retrieved successfully",
+                        "description": "Roles retrieved successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -752,13 +855,25 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.GetAllPackagesResponseData"
+                                            "$ref": "#/definitions/handlers.RoleListResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -766,11 +881,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/register/school-info": {
+            },
             "post": {
-                "description": "Step 1 of school registration: Register basic school details.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a custom role for the admin's school. The role inherits the behaviour of its base role and may only carry permissions granted to both the base role and the admin.",
                 "consumes": [
                     "application/json"
                 ],
@@ -778,23 +896,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "School Registration"
+                    "Admin - Roles"
                 ],
-                "summary": "Register School Information",
+                "summary": "Create Custom Role",
                 "parameters": [
                     {
-                        "description": "School Information",
-                        "name": "registerSchoolInfoRequest",
+                        "description": "Custom role details",
+                        "name": "createRoleRequest",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.RegisterSchoolInfoRequest"
+                            "$ref": "#/definitions/handlers.CreateRoleRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "School info registered successfully",
+                        "description": "Role created successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -804,7 +922,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.RegisterSchoolInfoResponseData"
+                                            "$ref": "#/definitions/handlers.RoleResponse"
                                         }
                                     }
                                 }
@@ -817,8 +935,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
@@ -826,9 +950,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/register/select-package": {
-            "post": {
-                "description": "Step 3 of school registration: Selects a subscription package for the school.",
+        "/admin/roles/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the name, description or permissions of a custom role of the admin's school.",
                 "consumes": [
                     "application/json"
                 ],
@@ -836,23 +965,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "School Registration"
+                    "Admin - Roles"
                 ],
-                "summary": "Select Package",
+                "summary": "Update Custom Role",
                 "parameters": [
                     {
-                        "description": "Package Selection",
-                        "name": "selectPackageRequest",
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role details to update",
+                        "name": "updateRoleRequest",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.SelectPackageRequest"
+                            "$ref": "#/definitions/handlers.UpdateRoleRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Package selected successfully",
+                        "description": "Role updated successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -862,7 +998,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.SelectPackageResponseData"
+                                            "$ref": "#/definitions/handlers.RoleResponse"
                                         }
                                     }
                                 }
@@ -875,364 +1011,8074 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
                         "schema": {
                             "$ref": "#/definitions/handlers.CommonResponse"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "handlers.CommonResponse": {
-            "type": "object",
-            "properties": {
-                "data": {},
-                "message": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "integer"
-                }
-            }
-        },
-        "handlers.CompleteRegistrationRequest": {
-            "type": "object",
-            "required": [
-                "school_id"
-            ],
-            "properties": {
-                "school_id": {
-                    "type": "string",
-                    "example": "a1b2c3d4-e5f6-7890-1234-567890abcdef"
-                }
-            }
-        },
-        "handlers.CompleteRegistrationResponseData": {
-            "type": "object",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a custom role of the admin's school. Roles still assigned to users cannot be deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Roles"
+                ],
+                "summary": "Delete Custom Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/saml-config": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the SAML single sign-on configuration of the admin's school.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - SAML"
+                ],
+                "summary": "Get SAML Configuration",
+                "responses": {
+                    "200": {
+                        "description": "SAML config retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.SAMLConfigResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "SAML config not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates or updates the SAML single sign-on configuration of the admin's school.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - SAML"
+                ],
+                "summary": "Save SAML Configuration",
+                "parameters": [
+                    {
+                        "description": "SAML configuration",
+                        "name": "samlConfigRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SAMLConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "SAML config saved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.SAMLConfigResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/usage": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Shows how many student seats of the admin's school are used out of the package limit.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Get Student Usage",
+                "responses": {
+                    "200": {
+                        "description": "Student usage retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.StudentUsageResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/user-imports": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Validates a roster like the preview and, when every row is valid, queues it for import in the background. Poll the returned import for progress and download the results file, which contains the generated passwords, once it is completed. If any row is invalid nothing is imported and the preview is returned with status 422.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Start User Import",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Roster (.csv or .xlsx, max 5 MB, max 5000 rows)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Import queued",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserImportResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Import file has invalid rows",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserImportPreviewResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/user-imports/preview": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Validates a CSV or XLSX roster of students and teachers without creating anything. The first row names the columns ` + "`" + `name` + "`" + `, ` + "`" + `email` + "`" + `, ` + "`" + `role` + "`" + ` (student or teacher) and optionally ` + "`" + `password` + "`" + `; rows without a password get a generated one. Every row is checked for missing fields, duplicate emails within the file and against existing users, and the school's student quota.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Preview User Import",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Roster (.csv or .xlsx, max 5 MB, max 5000 rows)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import file validated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserImportPreviewResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/user-imports/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns an import of the admin's school with its progress.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Get User Import",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Import ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserImportResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid import ID format",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Import not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/user-imports/{id}/results": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Downloads a CSV with the outcome of every row of a completed import: the created users with their generated passwords, and the rows that failed with the reason. The passwords are only included in the first download within 7 days of the import finishing; later downloads leave the column empty.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Download User Import Results",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Import ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import results",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid import ID format",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Import not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Import has not finished yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a page of users, with optional filtering, search and sorting. School admins see their own school; under /platform a super admin sees every school or the one given by school_id. Pages are fetched with the next_cursor of the previous page, keeping the same filters and sort.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Get All Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter users by role (teacher, student, parent, admin)",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "suspended",
+                            "locked",
+                            "graduated"
+                        ],
+                        "type": "string",
+                        "description": "Filter by account status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by name or email",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users of this school (super admin only)",
+                        "name": "school_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or after this date (YYYY-MM-DD)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or before this date (YYYY-MM-DD)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "email",
+                            "created_at"
+                        ],
+                        "type": "string",
+                        "default": "name",
+                        "description": "Sort column",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Sort order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Users per page (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserPageResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Allows an admin to create a new teacher, student or parent account within their school.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Create Teacher or Student",
+                "parameters": [
+                    {
+                        "description": "User details to create",
+                        "name": "createUserRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserDataResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Student quota exceeded",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.StudentQuotaErrorData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/deleted": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the users in the recycle bin, most recently deleted first, with the time until which each can be restored. School admins see their own school; under /platform a super admin sees every school, or the one given by school_id.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "List Deleted Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only users of this school (super admin only)",
+                        "name": "school_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deleted users retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.DeletedUserListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Streams the user list as a CSV or XLSX file, with the same role filter and school scoping as Get All Users. Under /platform a super admin can limit the export to one school.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Export Users",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter users by role (teacher, student, parent, admin)",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated columns out of id, name, email, role, status, position, whatsapp_number, school_id, created_at (default all)",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users of this school (super admin only)",
+                        "name": "school_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User export",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/reactivate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Makes up to 500 suspended, locked or graduated users of the admin's school active again. Users that cannot be reactivated are listed under failed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Reactivate Users",
+                "parameters": [
+                    {
+                        "description": "Users and optional reason",
+                        "name": "reactivateUsersRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReactivateUsersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users reactivated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserStatusChangeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/status": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gives up to 500 users of the admin's school an account status: active, suspended, locked or graduated. Only students can graduate. Only active users can log in. Users that cannot be changed are listed under failed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Set User Status",
+                "parameters": [
+                    {
+                        "description": "Users, status and optional reason",
+                        "name": "setUserStatusRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetUserStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User status updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserStatusChangeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/suspend": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Suspends up to 500 users of the admin's school. Suspended users cannot log in, and tokens they already hold stop working on the next request. Users that cannot be suspended, such as users of another school or the admin's own account, are listed under failed; the others are still suspended.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Suspend Users",
+                "parameters": [
+                    {
+                        "description": "Users and reason",
+                        "name": "suspendUsersRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SuspendUsersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users suspended",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserStatusChangeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a specific user's details by their ID. Accessible by admins.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Get User By ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserDataResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the details of an existing user. Accessible by admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Update User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User details to update",
+                        "name": "updateUserRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserDataResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves a user to the recycle bin. Accessible by admins. The user can no longer log in and their email stays taken; they can be restored until DELETED_USER_RETENTION_DAYS have passed, after which they are purged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Delete User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/guardians": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the parents linked to a student of the admin's school.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Guardians"
+                ],
+                "summary": "Get Student Guardians",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Student user ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Guardians retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.GuardianLinkListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/invite-code": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generates a single-use code (valid for 7 days) that a parent can redeem to link to the student.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Guardians"
+                ],
+                "summary": "Create Student Invite Code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Student user ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Invite code created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.StudentInviteCodeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Brings a user back from the recycle bin within the retention period (DELETED_USER_RETENTION_DAYS, 30 days by default). A student is only restored if the school has a free student seat.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Restore Deleted User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User restored successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserDataResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Deleted user not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Student quota exceeded",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.StudentQuotaErrorData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "410": {
+                        "description": "Retention period has passed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assigns a system role or one of the school's custom roles to a user of the admin's school.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Roles"
+                ],
+                "summary": "Assign Role to User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role to assign",
+                        "name": "assignRoleRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AssignRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role assigned successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserDataResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User or role not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Student quota exceeded",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.StudentQuotaErrorData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticates a user and returns a JWT token. Suspended, locked and graduated accounts are refused with 403.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "User Login",
+                "parameters": [
+                    {
+                        "description": "Login Credentials",
+                        "name": "loginRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.LoginResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Account is not active",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invalidates the client-side JWT token (no server-side session invalidation for stateless JWT).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "User Logout",
+                "responses": {
+                    "200": {
+                        "description": "Logout successful",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/saml/{school_id}/acs": {
+            "post": {
+                "description": "Validates the signed SAML response posted by the identity provider and issues a JWT token.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth - SAML"
+                ],
+                "summary": "SAML Assertion Consumer Service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "School ID",
+                        "name": "school_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Base64 encoded SAML response",
+                        "name": "SAMLResponse",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.LoginResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/saml/{school_id}/login": {
+            "get": {
+                "description": "Redirects the browser to the school's identity provider to start SP-initiated SAML login.",
+                "tags": [
+                    "Auth - SAML"
+                ],
+                "summary": "Start SAML Login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "School ID",
+                        "name": "school_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque state returned by the IdP",
+                        "name": "relay_state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the identity provider"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/saml/{school_id}/metadata": {
+            "get": {
+                "description": "Returns the SAML SP metadata XML a school registers with its identity provider.",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "Auth - SAML"
+                ],
+                "summary": "Get SAML Service Provider Metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "School ID",
+                        "name": "school_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "SP metadata XML",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/set-password": {
+            "post": {
+                "description": "Sets the password of the account a set-password link was emailed for, such as the school admin after registration completes. Each link can be used once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Set Password",
+                "parameters": [
+                    {
+                        "description": "Link token and new password",
+                        "name": "setPasswordRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password set successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid, expired or already used link",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/org": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the organization admin's organization with its campuses.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Get My Organization",
+                "responses": {
+                    "200": {
+                        "description": "Organization retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.OrganizationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/org/invoices": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the consolidated invoices of the organization admin's organization, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "List Organization Invoices",
+                "responses": {
+                    "200": {
+                        "description": "Invoices retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.InvoiceListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/org/invoices/{id}/download": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Downloads one of the organization's consolidated invoices as an HTML document.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Download Organization Invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invoice document",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Invoice not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/org/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the users of every campus of the organization admin's organization, with optional filtering by role and campus.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Get Organization Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter users by role (teacher, student, parent, admin)",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by campus",
+                        "name": "school_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Allows an organization admin to create a campus admin, teacher, student or parent account at one of the organization's campuses.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Create Campus User",
+                "parameters": [
+                    {
+                        "description": "User details to create",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateCampusUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserDataResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Student quota exceeded",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.StudentQuotaErrorData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/org/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a specific user's details by their ID. Accessible by admins.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Get User By ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserDataResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the details of an existing user. Accessible by admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Update User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User details to update",
+                        "name": "updateUserRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserDataResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves a user to the recycle bin. Accessible by admins. The user can no longer log in and their email stays taken; they can be restored until DELETED_USER_RETENTION_DAYS have passed, after which they are purged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Delete User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/org/users/{id}/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves a teacher to another campus of the same organization. A custom role of the old campus is replaced by the system teacher role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Transfer Teacher",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target campus",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TransferTeacherRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Teacher transferred successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserDataResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User or school not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/parent/students": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the students linked to the authenticated parent.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parent"
+                ],
+                "summary": "Get Linked Students",
+                "responses": {
+                    "200": {
+                        "description": "Linked students retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.GuardianLinkListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/parent/students/redeem": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Links the authenticated parent to the student the invite code was issued for, and returns a refreshed token listing the linked students.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parent"
+                ],
+                "summary": "Redeem Student Invite Code",
+                "parameters": [
+                    {
+                        "description": "Invite code",
+                        "name": "redeemInviteCodeRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RedeemInviteCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Student linked successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.RedeemInviteCodeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/webhook": {
+            "post": {
+                "description": "Receives payment notifications from the configured provider. The signature is verified before anything is applied; a successful payment activates or extends the school's subscription and issues a paid invoice.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Payment Webhook",
+                "responses": {
+                    "200": {
+                        "description": "Notification processed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid signature",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/invoices": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists invoices of all schools for platform admins, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Billing"
+                ],
+                "summary": "List Invoices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by school ID",
+                        "name": "school_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by organization ID (consolidated invoices)",
+                        "name": "organization_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status (issued, overdue, paid, void)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invoices retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.InvoiceListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/invoices/{id}/download": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Downloads any school's invoice as an HTML document.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Platform - Billing"
+                ],
+                "summary": "Download Invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invoice document",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Invoice not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/invoices/{id}/mark-paid": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records the payment of an issued or overdue invoice. Paying a school invoice adds its period to the school's subscription, continuing from the end date while a paid period is running, and moves the school to the invoiced package.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Billing"
+                ],
+                "summary": "Mark Invoice Paid",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MarkInvoicePaidRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invoice marked as paid successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.InvoiceResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Invoice not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/invoices/{id}/void": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Voids an unpaid invoice, for example one issued with wrong data. The number is not reused.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Billing"
+                ],
+                "summary": "Void Invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invoice voided successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.InvoiceResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Invoice not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/job-runs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists recent runs of the background jobs, newest first, with their outcome and summary.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Jobs"
+                ],
+                "summary": "List Background Job Runs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by job name",
+                        "name": "job",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of runs (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Job runs retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.JobRunListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/organizations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists every organization with its campuses.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Organizations"
+                ],
+                "summary": "List Organizations",
+                "responses": {
+                    "200": {
+                        "description": "Organizations retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.OrganizationListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates an organization (yayasan) that can own several schools as campuses.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Organizations"
+                ],
+                "summary": "Create Organization",
+                "parameters": [
+                    {
+                        "description": "Organization details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Organization created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.OrganizationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/organizations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves an organization with its campuses and their packages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Organizations"
+                ],
+                "summary": "Get Organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Organization retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.OrganizationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Organization not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates an organization's details and billing mode.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Organizations"
+                ],
+                "summary": "Update Organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Organization details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Organization updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.OrganizationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Organization not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/organizations/{id}/admins": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates an organization admin who manages users across all campuses of the organization.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Organizations"
+                ],
+                "summary": "Create Organization Admin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Admin details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateOrganizationAdminRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Organization admin created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserDataResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Organization not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/organizations/{id}/invoices": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issues one consolidated invoice for an organization with consolidated billing. Every campus is charged for its current subscription period and student count; campuses already invoiced for that period are skipped.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Organizations"
+                ],
+                "summary": "Generate Organization Invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Invoice generated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.InvoiceResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Organization not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/organizations/{id}/schools": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Makes an existing school a campus of the organization. Only schools on a package with ` + "`" + `multi_campus` + "`" + ` (Enterprise) can join, and a school belongs to at most one organization.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Organizations"
+                ],
+                "summary": "Add Organization Campus",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "School to add",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AddOrganizationSchoolRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "School added successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.OrganizationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Organization or school not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/organizations/{id}/schools/{school_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Detaches a school from the organization. Its users stay with the school.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Organizations"
+                ],
+                "summary": "Remove Organization Campus",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "School ID",
+                        "name": "school_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "School removed successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.OrganizationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Organization or school not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/packages": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the whole package catalog, including retired packages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Packages"
+                ],
+                "summary": "List All Packages",
+                "responses": {
+                    "200": {
+                        "description": "Packages retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.GetAllPackagesResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a package to the catalog. Trial packages require duration_days; omit max_students for unlimited students.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Packages"
+                ],
+                "summary": "Create Package",
+                "parameters": [
+                    {
+                        "description": "Package details",
+                        "name": "packageRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Package created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.PackageResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/packages/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the details of a package. Schools that already selected it keep their current limits and subscription dates.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Packages"
+                ],
+                "summary": "Update Package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Package details",
+                        "name": "packageRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Package updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.PackageResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Package not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/packages/{id}/retire": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retires a package. It stays attached to existing schools but is no longer listed in or selectable during registration.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Packages"
+                ],
+                "summary": "Retire Package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Package retired successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.PackageResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Package not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/registrations/purged": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists school registrations that were abandoned before completion and deleted by the cleanup job, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Schools"
+                ],
+                "summary": "List Purged Registrations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Purged registrations retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.PurgedRegistrationListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/schools": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists every registered school for platform admins, with optional search and filters.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Schools"
+                ],
+                "summary": "List Schools",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search by school name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by package ID",
+                        "name": "package_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by school status (Negeri, Swasta)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by education level",
+                        "name": "education_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by suspension",
+                        "name": "suspended",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subscription ends before this date (YYYY-MM-DD)",
+                        "name": "expires_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subscription ends after this date (YYYY-MM-DD)",
+                        "name": "expires_after",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schools retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.SchoolListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/schools/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a school with its primary admin and user counts per role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Schools"
+                ],
+                "summary": "Get School Detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "School ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "School retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.SchoolDetailResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permanently deletes a school together with its users, SSO settings, custom roles, imports and unpaid payments. Schools with paid payments or invoices cannot be deleted and should be suspended instead; campuses must be removed from their organization first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Schools"
+                ],
+                "summary": "Delete School",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "School ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "School deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "School has billing history or belongs to an organization",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/schools/{id}/invoices": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issues an invoice for the school's next subscription period, the period and student count a checkout would charge, priced from its package plus PPN. A school can have one unpaid invoice at a time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Billing"
+                ],
+                "summary": "Generate Invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "School ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Invoice generated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.InvoiceResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "School already has an unpaid invoice",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/schools/{id}/reactivate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lifts the suspension of a school.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Schools"
+                ],
+                "summary": "Reactivate School",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "School ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "School reactivated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.SchoolResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/schools/{id}/suspend": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Suspends a school. Its users can no longer obtain tokens until the school is reactivated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Platform - Schools"
+                ],
+                "summary": "Suspend School",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "School ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Suspension reason",
+                        "name": "suspendSchoolRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SuspendSchoolRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "School suspended successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.SchoolResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a page of users, with optional filtering, search and sorting. School admins see their own school; under /platform a super admin sees every school or the one given by school_id. Pages are fetched with the next_cursor of the previous page, keeping the same filters and sort.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Get All Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter users by role (teacher, student, parent, admin)",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "suspended",
+                            "locked",
+                            "graduated"
+                        ],
+                        "type": "string",
+                        "description": "Filter by account status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by name or email",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users of this school (super admin only)",
+                        "name": "school_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or after this date (YYYY-MM-DD)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or before this date (YYYY-MM-DD)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "email",
+                            "created_at"
+                        ],
+                        "type": "string",
+                        "default": "name",
+                        "description": "Sort column",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Sort order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Users per page (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserPageResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/users/deleted": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the users in the recycle bin, most recently deleted first, with the time until which each can be restored. School admins see their own school; under /platform a super admin sees every school, or the one given by school_id.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "List Deleted Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only users of this school (super admin only)",
+                        "name": "school_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deleted users retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.DeletedUserListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/users/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Streams the user list as a CSV or XLSX file, with the same role filter and school scoping as Get All Users. Under /platform a super admin can limit the export to one school.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Export Users",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter users by role (teacher, student, parent, admin)",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated columns out of id, name, email, role, status, position, whatsapp_number, school_id, created_at (default all)",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users of this school (super admin only)",
+                        "name": "school_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User export",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/users/reactivate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Makes up to 500 suspended, locked or graduated users of the admin's school active again. Users that cannot be reactivated are listed under failed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Reactivate Users",
+                "parameters": [
+                    {
+                        "description": "Users and optional reason",
+                        "name": "reactivateUsersRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReactivateUsersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users reactivated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserStatusChangeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/users/status": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gives up to 500 users of the admin's school an account status: active, suspended, locked or graduated. Only students can graduate. Only active users can log in. Users that cannot be changed are listed under failed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Set User Status",
+                "parameters": [
+                    {
+                        "description": "Users, status and optional reason",
+                        "name": "setUserStatusRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetUserStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User status updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserStatusChangeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/users/suspend": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Suspends up to 500 users of the admin's school. Suspended users cannot log in, and tokens they already hold stop working on the next request. Users that cannot be suspended, such as users of another school or the admin's own account, are listed under failed; the others are still suspended.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Suspend Users",
+                "parameters": [
+                    {
+                        "description": "Users and reason",
+                        "name": "suspendUsersRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SuspendUsersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users suspended",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserStatusChangeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a specific user's details by their ID. Accessible by admins.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Get User By ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserDataResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the details of an existing user. Accessible by admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Update User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User details to update",
+                        "name": "updateUserRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserDataResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves a user to the recycle bin. Accessible by admins. The user can no longer log in and their email stays taken; they can be restored until DELETED_USER_RETENTION_DAYS have passed, after which they are purged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Delete User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platform/users/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Brings a user back from the recycle bin within the retention period (DELETED_USER_RETENTION_DAYS, 30 days by default). A student is only restored if the school has a free student seat.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - User Management"
+                ],
+                "summary": "Restore Deleted User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User restored successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserDataResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Deleted user not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Student quota exceeded",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.StudentQuotaErrorData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "410": {
+                        "description": "Retention period has passed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the basic profile information of the authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get User Profile",
+                "responses": {
+                    "200": {
+                        "description": "User profile retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.UserProfileResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/admin-info": {
+            "post": {
+                "security": [
+                    {
+                        "RegistrationToken": []
+                    }
+                ],
+                "description": "Step 2 of school registration: Register the primary admin user for the school. No password is returned; the admin sets one through the link emailed when registration completes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "School Registration"
+                ],
+                "summary": "Register Admin Information",
+                "parameters": [
+                    {
+                        "description": "Admin Information",
+                        "name": "registerAdminInfoRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RegisterAdminInfoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Admin user created and linked to school",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.RegisterAdminInfoResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing, invalid or expired registration session",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already exists, step out of order or registration already completed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/checkout": {
+            "post": {
+                "security": [
+                    {
+                        "RegistrationToken": []
+                    }
+                ],
+                "description": "Starts a payment for the paid package selected during registration. Redirect the user to ` + "`" + `redirect_url` + "`" + `; the subscription is activated by the payment webhook.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Registration"
+                ],
+                "summary": "Create Registration Checkout",
+                "responses": {
+                    "201": {
+                        "description": "Checkout created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.CheckoutResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing, invalid or expired registration session",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/complete": {
+            "post": {
+                "security": [
+                    {
+                        "RegistrationToken": []
+                    }
+                ],
+                "description": "Step 6 of school registration: Finalizes the registration process after all previous steps are complete. A paid package must have been paid through ` + "`" + `POST /register/checkout` + "`" + ` first; until the payment webhook arrives this returns 402. The admin is emailed a single-use link to set their password; if the email cannot be sent the registration stays open and can be completed again. The registration token cannot be used afterwards.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "School Registration"
+                ],
+                "summary": "Complete School Registration",
+                "responses": {
+                    "200": {
+                        "description": "School registration completed successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.CompleteRegistrationResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing, invalid or expired registration session",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "The selected paid package has not been paid yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Step out of order or registration already completed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/email-verification/request-otp": {
+            "post": {
+                "security": [
+                    {
+                        "RegistrationToken": []
+                    }
+                ],
+                "description": "Step 4 of school registration: Sends an OTP to the email of the admin registered in step 2.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "School Registration"
+                ],
+                "summary": "Request Email Verification OTP",
+                "responses": {
+                    "200": {
+                        "description": "OTP sent to email successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing, invalid or expired registration session",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Step out of order or registration already completed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many OTP requests from this client",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/email-verification/verify-otp": {
+            "post": {
+                "security": [
+                    {
+                        "RegistrationToken": []
+                    }
+                ],
+                "description": "Step 4 of school registration: Verifies the OTP sent to the admin's email.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "School Registration"
+                ],
+                "summary": "Verify Email OTP",
+                "parameters": [
+                    {
+                        "description": "OTP for verification",
+                        "name": "verifyOTPRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.VerifyOTPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email verified successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request (invalid/expired OTP)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing, invalid or expired registration session",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Step out of order or registration already completed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many OTP requests from this client, or too many invalid attempts on the code",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/packages": {
+            "get": {
+                "description": "Retrieves the subscription packages that can currently be selected (e.g. Free Trial, Premium, Enterprise). Retired packages are not listed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "School Registration"
+                ],
+                "summary": "Get All Available Packages",
+                "responses": {
+                    "200": {
+                        "description": "Packages retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.GetAllPackagesResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/resume/request-otp": {
+            "post": {
+                "description": "Sends a code to the admin email of an unfinished registration so the applicant can continue it, for example after the registration session expired.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "School Registration"
+                ],
+                "summary": "Request Registration Resume OTP",
+                "parameters": [
+                    {
+                        "description": "Admin email entered in step 2",
+                        "name": "resumeRegistrationRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResumeRegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OTP sent to email successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No unfinished registration for this email",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Registration is already completed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many OTP requests from this client",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/resume/verify-otp": {
+            "post": {
+                "description": "Checks the resume code and returns a new registration token together with the registration status, so the applicant can continue with the next step. Earlier registration tokens stop working.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "School Registration"
+                ],
+                "summary": "Resume Registration",
+                "parameters": [
+                    {
+                        "description": "Admin email and OTP",
+                        "name": "verifyResumeOTPRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.VerifyResumeOTPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Registration resumed successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.ResumeRegistrationResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request (invalid/expired OTP)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No unfinished registration for this email",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Registration is already completed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many OTP requests from this client, or too many invalid attempts on the code",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/school-info": {
+            "post": {
+                "description": "Step 1 of school registration: Register basic school details. The returned ` + "`" + `registration_token` + "`" + ` must be sent in the ` + "`" + `X-Registration-Token` + "`" + ` header of every later step; it expires after a period without registration activity.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "School Registration"
+                ],
+                "summary": "Register School Information",
+                "parameters": [
+                    {
+                        "description": "School Information",
+                        "name": "registerSchoolInfoRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RegisterSchoolInfoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "School info registered successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.RegisterSchoolInfoResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/select-package": {
+            "post": {
+                "security": [
+                    {
+                        "RegistrationToken": []
+                    }
+                ],
+                "description": "Step 3 of school registration: Selects a subscription package for the school.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "School Registration"
+                ],
+                "summary": "Select Package",
+                "parameters": [
+                    {
+                        "description": "Package Selection",
+                        "name": "selectPackageRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SelectPackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Package selected successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.CommonResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.SelectPackageResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing, invalid or expired registration session",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Package not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Step out of order, registration already completed, or the selected package was already paid for",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommonResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.AddOrganizationSchoolRequest": {
+            "type": "object",
+            "required": [
+                "school_id"
+            ],
+            "properties": {
+                "school_id": {
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+                }
+            }
+        },
+        "handlers.AssignRoleRequest": {
+            "type": "object",
+            "required": [
+                "role_id"
+            ],
+            "properties": {
+                "role_id": {
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+                }
+            }
+        },
+        "handlers.CheckoutResponse": {
+            "type": "object",
+            "properties": {
+                "payment": {
+                    "$ref": "#/definitions/models.Payment"
+                }
+            }
+        },
+        "handlers.CommonResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.CompleteRegistrationResponseData": {
+            "type": "object",
+            "properties": {
+                "school": {
+                    "$ref": "#/definitions/models.School"
+                }
+            }
+        },
+        "handlers.CreateCampusUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password",
+                "role_name",
+                "school_id"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Teacher John"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "securepassword"
+                },
+                "role_name": {
+                    "type": "string",
+                    "enum": [
+                        "teacher",
+                        "student",
+                        "parent",
+                        "admin"
+                    ],
+                    "example": "teacher"
+                },
+                "school_id": {
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+                }
+            }
+        },
+        "handlers.CreateGuardianLinkRequest": {
+            "type": "object",
+            "required": [
+                "parent_user_id",
+                "student_user_id"
+            ],
+            "properties": {
+                "parent_user_id": {
+                    "type": "string",
+                    "example": "c3d4e5f6-a7b8-9012-3456-7890abcdef12"
+                },
+                "relationship": {
+                    "type": "string",
+                    "example": "Ibu"
+                },
+                "student_user_id": {
+                    "type": "string",
+                    "example": "f1e2d3c4-b5a6-9876-5432-10fedcba9876"
+                }
+            }
+        },
+        "handlers.CreateOrganizationAdminRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "ketua@yayasan.sch.id"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Ketua Yayasan"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "securepassword"
+                }
+            }
+        },
+        "handlers.CreateRoleRequest": {
+            "type": "object",
+            "required": [
+                "base_role_name",
+                "name"
+            ],
+            "properties": {
+                "base_role_name": {
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "teacher",
+                        "student",
+                        "parent"
+                    ],
+                    "example": "teacher"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Homeroom teacher"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Wali Kelas"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "users:read"
+                    ]
+                }
+            }
+        },
+        "handlers.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password",
+                "role_name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Teacher John"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "securepassword"
+                },
+                "role_name": {
+                    "type": "string",
+                    "enum": [
+                        "teacher",
+                        "student",
+                        "parent"
+                    ],
+                    "example": "teacher"
+                }
+            }
+        },
+        "handlers.DeletedUserListResponse": {
+            "type": "object",
+            "properties": {
+                "deleted_users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.DeletedUser"
+                    }
+                }
+            }
+        },
+        "handlers.GetAllPackagesResponseData": {
+            "type": "object",
+            "properties": {
+                "packages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Package"
+                    }
+                }
+            }
+        },
+        "handlers.GuardianLinkListResponse": {
+            "type": "object",
+            "properties": {
+                "guardian_links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GuardianLink"
+                    }
+                }
+            }
+        },
+        "handlers.GuardianLinkResponse": {
+            "type": "object",
+            "properties": {
+                "guardian_link": {
+                    "$ref": "#/definitions/models.GuardianLink"
+                }
+            }
+        },
+        "handlers.InvoiceListResponse": {
+            "type": "object",
+            "properties": {
+                "invoices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Invoice"
+                    }
+                }
+            }
+        },
+        "handlers.InvoiceResponse": {
+            "type": "object",
+            "properties": {
+                "invoice": {
+                    "$ref": "#/definitions/models.Invoice"
+                }
+            }
+        },
+        "handlers.JobRunListResponse": {
+            "type": "object",
+            "properties": {
+                "job_runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JobRun"
+                    }
+                }
+            }
+        },
+        "handlers.LDAPConfigRequest": {
+            "type": "object",
+            "required": [
+                "base_dn",
+                "url",
+                "user_filter"
+            ],
+            "properties": {
+                "admin_groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "CN=Operator Sekolah",
+                        "OU=Groups",
+                        "DC=sekolah",
+                        "DC=local"
+                    ]
+                },
+                "base_dn": {
+                    "type": "string",
+                    "example": "OU=Staff,DC=sekolah,DC=local"
+                },
+                "bind_dn": {
+                    "type": "string",
+                    "example": "CN=svc-barniee,OU=Service,DC=sekolah,DC=local"
+                },
+                "bind_password": {
+                    "type": "string",
+                    "example": "service-account-password"
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "group_attribute": {
+                    "type": "string",
+                    "example": "memberOf"
+                },
+                "name_attribute": {
+                    "type": "string",
+                    "example": "displayName"
+                },
+                "start_tls": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "teacher_groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "CN=Guru",
+                        "OU=Groups",
+                        "DC=sekolah",
+                        "DC=local"
+                    ]
+                },
+                "url": {
+                    "type": "string",
+                    "example": "ldaps://dc01.sekolah.local:636"
+                },
+                "user_filter": {
+                    "type": "string",
+                    "example": "(\u0026(objectClass=user)(mail=%s))"
+                }
+            }
+        },
+        "handlers.LDAPConfigResponseData": {
+            "type": "object",
+            "properties": {
+                "ldap_config": {
+                    "$ref": "#/definitions/models.SchoolLDAPConfig"
+                }
+            }
+        },
+        "handlers.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                }
+            }
+        },
+        "handlers.LoginResponseData": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                }
+            }
+        },
+        "handlers.MarkInvoicePaidRequest": {
+            "type": "object",
+            "required": [
+                "payment_reference"
+            ],
+            "properties": {
+                "paid_at": {
+                    "description": "Defaults to now",
+                    "type": "string",
+                    "example": "2026-01-15T10:00:00Z"
+                },
+                "payment_reference": {
+                    "type": "string",
+                    "example": "TRF-BCA-20260115-001"
+                }
+            }
+        },
+        "handlers.OrganizationListResponse": {
+            "type": "object",
+            "properties": {
+                "organizations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Organization"
+                    }
+                }
+            }
+        },
+        "handlers.OrganizationRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "Jl. Merdeka No. 1, Jakarta"
+                },
+                "billing_email": {
+                    "type": "string",
+                    "example": "keuangan@yayasan.sch.id"
+                },
+                "consolidated_billing": {
+                    "description": "Invoice all campuses on one organization invoice",
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Yayasan Pendidikan Barniee"
+                }
+            }
+        },
+        "handlers.OrganizationResponse": {
+            "type": "object",
+            "properties": {
+                "organization": {
+                    "$ref": "#/definitions/models.Organization"
+                }
+            }
+        },
+        "handlers.PackageRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "duration_days": {
+                    "type": "integer",
+                    "example": 365
+                },
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "AI Analytics lengkap",
+                        "Priority support 24/7"
+                    ]
+                },
+                "is_trial": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "max_students": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "multi_campus": {
+                    "description": "Lets schools on the package join an organization",
+                    "type": "boolean",
+                    "example": false
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Premium"
+                },
+                "price_per_student": {
+                    "type": "number",
+                    "example": 50000
+                },
+                "price_per_year": {
+                    "type": "number",
+                    "example": 10000000
+                }
+            }
+        },
+        "handlers.PackageResponse": {
+            "type": "object",
+            "properties": {
+                "package": {
+                    "$ref": "#/definitions/models.Package"
+                }
+            }
+        },
+        "handlers.PermissionListResponse": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Permission"
+                    }
+                }
+            }
+        },
+        "handlers.PlanChangeRequest": {
+            "type": "object",
+            "required": [
+                "package_id"
+            ],
+            "properties": {
+                "at_period_end": {
+                    "description": "Switch when the current period ends instead of now",
+                    "type": "boolean",
+                    "example": false
+                },
+                "package_id": {
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+                }
+            }
+        },
+        "handlers.PurgedRegistrationListResponse": {
+            "type": "object",
+            "properties": {
+                "purged_registrations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PurgedRegistration"
+                    }
+                }
+            }
+        },
+        "handlers.ReactivateUsersRequest": {
+            "type": "object",
+            "required": [
+                "user_ids"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Masa skorsing selesai"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.RedeemInviteCodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "K7P2QX9M"
+                },
+                "relationship": {
+                    "type": "string",
+                    "example": "Ayah"
+                }
+            }
+        },
+        "handlers.RedeemInviteCodeResponse": {
+            "type": "object",
+            "properties": {
+                "guardian_link": {
+                    "$ref": "#/definitions/models.GuardianLink"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                }
+            }
+        },
+        "handlers.RegisterAdminInfoRequest": {
+            "type": "object",
+            "required": [
+                "admin_email",
+                "admin_name",
+                "position",
+                "whatsapp_number"
+            ],
+            "properties": {
+                "admin_email": {
+                    "type": "string",
+                    "example": "siti.aminah@example.com"
+                },
+                "admin_name": {
+                    "type": "string",
+                    "example": "Siti Aminah"
+                },
+                "position": {
+                    "type": "string",
+                    "example": "Direktur"
+                },
+                "whatsapp_number": {
+                    "type": "string",
+                    "example": "081234567890"
+                }
+            }
+        },
+        "handlers.RegisterAdminInfoResponseData": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "siti.aminah@example.com"
+                },
+                "school_id": {
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "f1e2d3c4-b5a6-9876-5432-10fedcba9876"
+                }
+            }
+        },
+        "handlers.RegisterSchoolInfoRequest": {
+            "type": "object",
+            "required": [
+                "address",
+                "education_level",
+                "initial_student_count",
+                "name",
+                "status"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "Jl. Inovasi No. 10, Kota Teknologi"
+                },
+                "education_level": {
+                    "type": "string",
+                    "enum": [
+                        "SD",
+                        "SMP",
+                        "SMA",
+                        "SMK",
+                        "PerguruanTinggi",
+                        "Lainnya"
+                    ],
+                    "example": "SMA"
+                },
+                "initial_student_count": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 150
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Barniee Academy"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "Negeri",
+                        "Swasta"
+                    ],
+                    "example": "Swasta"
+                }
+            }
+        },
+        "handlers.RegisterSchoolInfoResponseData": {
+            "type": "object",
+            "properties": {
+                "registration_token": {
+                    "description": "Send as X-Registration-Token in the next steps",
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "school_id": {
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+                },
+                "school_name": {
+                    "type": "string",
+                    "example": "Barniee Academy"
+                }
+            }
+        },
+        "handlers.ResumeRegistrationRequest": {
+            "type": "object",
+            "required": [
+                "admin_email"
+            ],
+            "properties": {
+                "admin_email": {
+                    "type": "string",
+                    "example": "siti.aminah@example.com"
+                }
+            }
+        },
+        "handlers.ResumeRegistrationResponseData": {
+            "type": "object",
+            "properties": {
+                "registration_status": {
+                    "description": "Last completed step; continue with the next one",
+                    "type": "string",
+                    "example": "package_selected"
+                },
+                "registration_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "school_id": {
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+                },
+                "school_name": {
+                    "type": "string",
+                    "example": "Barniee Academy"
+                }
+            }
+        },
+        "handlers.RoleListResponse": {
+            "type": "object",
+            "properties": {
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                }
+            }
+        },
+        "handlers.RoleResponse": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "$ref": "#/definitions/models.Role"
+                }
+            }
+        },
+        "handlers.SAMLConfigRequest": {
+            "type": "object",
+            "properties": {
+                "auto_provision": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "default_role_name": {
+                    "type": "string",
+                    "enum": [
+                        "teacher",
+                        "student",
+                        "admin"
+                    ],
+                    "example": "student"
+                },
+                "email_attribute": {
+                    "type": "string",
+                    "example": "urn:oid:0.9.2342.19200300.100.1.3"
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "idp_metadata_url": {
+                    "type": "string",
+                    "example": "https://sso.disdik.example.go.id/metadata"
+                },
+                "idp_metadata_xml": {
+                    "type": "string"
+                },
+                "name_attribute": {
+                    "type": "string",
+                    "example": "displayName"
+                },
+                "role_attribute": {
+                    "type": "string",
+                    "example": "eduPersonAffiliation"
+                },
+                "role_mapping": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.SAMLConfigResponseData": {
+            "type": "object",
+            "properties": {
+                "saml_config": {
+                    "$ref": "#/definitions/models.SchoolSAMLConfig"
+                }
+            }
+        },
+        "handlers.SchoolDetailResponse": {
+            "type": "object",
+            "properties": {
+                "school_detail": {
+                    "$ref": "#/definitions/services.SchoolDetail"
+                }
+            }
+        },
+        "handlers.SchoolListResponse": {
+            "type": "object",
+            "properties": {
+                "schools": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.School"
+                    }
+                }
+            }
+        },
+        "handlers.SchoolResponse": {
+            "type": "object",
+            "properties": {
+                "school": {
+                    "$ref": "#/definitions/models.School"
+                }
+            }
+        },
+        "handlers.SelectPackageRequest": {
+            "type": "object",
+            "required": [
+                "package_id"
+            ],
+            "properties": {
+                "package_id": {
+                    "type": "string",
+                    "example": "package-uuid-for-premium"
+                }
+            }
+        },
+        "handlers.SelectPackageResponseData": {
+            "type": "object",
+            "properties": {
+                "school": {
+                    "$ref": "#/definitions/models.School"
+                }
+            }
+        },
+        "handlers.SetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "securepassword"
+                },
+                "token": {
+                    "description": "The token query parameter of the emailed link",
+                    "type": "string",
+                    "example": "3f9a1c..."
+                }
+            }
+        },
+        "handlers.SetUserStatusRequest": {
+            "type": "object",
+            "required": [
+                "status",
+                "user_ids"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Lulus tahun ajaran 2025/2026"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "suspended",
+                        "locked",
+                        "graduated"
+                    ],
+                    "example": "graduated"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.StudentInviteCodeResponse": {
+            "type": "object",
+            "properties": {
+                "invite_code": {
+                    "$ref": "#/definitions/models.StudentInviteCode"
+                }
+            }
+        },
+        "handlers.StudentQuotaErrorData": {
+            "type": "object",
+            "properties": {
+                "max_students_allowed": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "students_used": {
+                    "type": "integer",
+                    "example": 50
+                }
+            }
+        },
+        "handlers.StudentUsageResponse": {
+            "type": "object",
+            "properties": {
+                "usage": {
+                    "$ref": "#/definitions/services.StudentUsage"
+                }
+            }
+        },
+        "handlers.SuspendSchoolRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Tagihan belum dibayar"
+                }
+            }
+        },
+        "handlers.SuspendUsersRequest": {
+            "type": "object",
+            "required": [
+                "reason",
+                "user_ids"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Pelanggaran tata tertib sekolah"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.TransferTeacherRequest": {
+            "type": "object",
+            "required": [
+                "school_id"
+            ],
+            "properties": {
+                "school_id": {
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+                }
+            }
+        },
+        "handlers.UpdateRoleRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Homeroom teacher"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Wali Kelas"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "users:read"
+                    ]
+                }
+            }
+        },
+        "handlers.UpdateUserRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "role_name": {
+                    "type": "string",
+                    "enum": [
+                        "teacher",
+                        "student",
+                        "parent",
+                        "admin"
+                    ],
+                    "example": "student"
+                }
+            }
+        },
+        "handlers.UserDataResponse": {
+            "type": "object",
+            "properties": {
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "handlers.UserImportPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "preview": {
+                    "$ref": "#/definitions/services.ImportPreview"
+                }
+            }
+        },
+        "handlers.UserImportResponse": {
+            "type": "object",
+            "properties": {
+                "import": {
+                    "$ref": "#/definitions/models.UserImport"
+                }
+            }
+        },
+        "handlers.UserListResponse": {
+            "type": "object",
+            "properties": {
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.User"
+                    }
+                }
+            }
+        },
+        "handlers.UserPageResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "Pass as cursor to get the next page; absent on the last page",
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 1250
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.User"
+                    }
+                }
+            }
+        },
+        "handlers.UserProfileResponseData": {
+            "type": "object",
+            "properties": {
+                "school": {
+                    "$ref": "#/definitions/models.School"
+                },
+                "subscription_status": {
+                    "type": "string",
+                    "example": "trialing"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "handlers.UserStatusChangeResponse": {
+            "type": "object",
+            "properties": {
+                "result": {
+                    "$ref": "#/definitions/services.UserStatusChangeResult"
+                }
+            }
+        },
+        "handlers.VerifyOTPRequest": {
+            "type": "object",
+            "required": [
+                "otp"
+            ],
+            "properties": {
+                "otp": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "handlers.VerifyResumeOTPRequest": {
+            "type": "object",
+            "required": [
+                "admin_email",
+                "otp"
+            ],
+            "properties": {
+                "admin_email": {
+                    "type": "string",
+                    "example": "siti.aminah@example.com"
+                },
+                "otp": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "models.GuardianLink": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "parent": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "parent_user_id": {
+                    "type": "string"
+                },
+                "relationship": {
+                    "type": "string"
+                },
+                "student": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "student_user_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Invoice": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InvoiceLine"
+                    }
+                },
+                "number": {
+                    "description": "INV/2026/000123",
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "Set on consolidated organization invoices",
+                    "type": "string"
+                },
+                "package_id": {
+                    "type": "string"
+                },
+                "package_name": {
+                    "type": "string"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "paid_by": {
+                    "type": "string"
+                },
+                "payment_reference": {
+                    "type": "string"
+                },
+                "period_end": {
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "school_address": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "string"
+                },
+                "school_name": {
+                    "description": "Organization name on organization invoices",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "student_count": {
+                    "type": "integer"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "tax_amount": {
+                    "type": "number"
+                },
+                "tax_rate_percent": {
+                    "description": "PPN",
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InvoiceLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invoice_id": {
+                    "type": "string"
+                },
+                "package_id": {
+                    "description": "Package billed for that campus",
+                    "type": "string"
+                },
+                "period_start": {
+                    "description": "Start of that campus's period, fixed when paid",
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "school_id": {
+                    "description": "Campus billed by this line of an organization invoice",
+                    "type": "string"
+                },
+                "unit_price": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.JobRun": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "job_name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Organization": {
+            "type": "object",
             "properties": {
-                "school": {
-                    "$ref": "#/definitions/models.School"
+                "address": {
+                    "type": "string"
+                },
+                "billing_email": {
+                    "type": "string"
+                },
+                "consolidated_billing": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "schools": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.School"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.CreateUserRequest": {
+        "models.Package": {
             "type": "object",
-            "required": [
-                "email",
-                "name",
-                "password",
-                "role_name"
-            ],
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "john@example.com"
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "duration_days": {
+                    "type": "integer"
+                },
+                "features": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_trial": {
+                    "type": "boolean"
+                },
+                "max_students": {
+                    "type": "integer"
+                },
+                "multi_campus": {
+                    "description": "Schools on the package may join an organization",
+                    "type": "boolean"
                 },
                 "name": {
-                    "type": "string",
-                    "example": "Teacher John"
+                    "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6,
-                    "example": "securepassword"
+                "price_per_student": {
+                    "type": "number"
                 },
-                "role_name": {
-                    "type": "string",
-                    "enum": [
-                        "teacher",
-                        "student"
-                    ],
-                    "example": "teacher"
+                "price_per_year": {
+                    "type": "number"
+                },
+                "retired_at": {
+                    "description": "Retired packages stay on existing schools but cannot be selected",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.GetAllPackagesResponseData": {
+        "models.Payment": {
             "type": "object",
             "properties": {
-                "packages": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Package"
-                    }
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "credit_applied": {
+                    "description": "Billing credit deducted from the subtotal",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invoice_id": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "description": "Sent to the provider and echoed back in webhooks",
+                    "type": "string"
+                },
+                "package_id": {
+                    "type": "string"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "period_end": {
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "plan_change_id": {
+                    "description": "Set when the payment settles a mid-period plan change",
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "provider_reference": {
+                    "type": "string"
+                },
+                "redirect_url": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "student_count": {
+                    "type": "integer"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "tax_amount": {
+                    "type": "number"
+                },
+                "tax_rate_percent": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.LoginRequest": {
+        "models.Permission": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "user@example.com"
+                "created_at": {
+                    "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "example": "password123"
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.LoginResponseData": {
+        "models.PlanChange": {
             "type": "object",
             "properties": {
-                "token": {
-                    "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                "amount_due": {
+                    "description": "Including PPN",
+                    "type": "number"
+                },
+                "applied_at": {
+                    "type": "string"
+                },
+                "at_period_end": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "credit_to_balance": {
+                    "description": "Credit left over when the target costs less",
+                    "type": "number"
+                },
+                "effective_at": {
+                    "type": "string"
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "from_package": {
+                    "$ref": "#/definitions/models.Package"
+                },
+                "from_package_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "prorated_charge": {
+                    "description": "Target package for the rest of the period",
+                    "type": "number"
+                },
+                "proration_credit": {
+                    "description": "Unused share of the current paid period",
+                    "type": "number"
+                },
+                "school_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "to_package": {
+                    "$ref": "#/definitions/models.Package"
+                },
+                "to_package_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.RegisterAdminInfoRequest": {
+        "models.PurgedRegistration": {
             "type": "object",
-            "required": [
-                "admin_email",
-                "admin_name",
-                "position",
-                "school_id",
-                "whatsapp_number"
-            ],
             "properties": {
                 "admin_email": {
-                    "type": "string",
-                    "example": "siti.aminah@example.com"
+                    "description": "Empty when the admin step was never reached",
+                    "type": "string"
                 },
-                "admin_name": {
-                    "type": "string",
-                    "example": "Siti Aminah"
+                "id": {
+                    "type": "string"
+                },
+                "last_activity_at": {
+                    "type": "string"
+                },
+                "purged_at": {
+                    "type": "string"
                 },
-                "position": {
-                    "type": "string",
-                    "example": "Direktur"
+                "registration_status": {
+                    "type": "string"
+                },
+                "reminder_sent_at": {
+                    "type": "string"
                 },
                 "school_id": {
-                    "type": "string",
-                    "example": "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+                    "type": "string"
                 },
-                "whatsapp_number": {
-                    "type": "string",
-                    "example": "081234567890"
+                "school_name": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.RegisterAdminInfoResponseData": {
+        "models.Role": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "siti.aminah@example.com"
+                "base_role": {
+                    "$ref": "#/definitions/models.Role"
                 },
-                "password": {
-                    "type": "string",
-                    "example": "GeneratedPass123"
+                "base_role_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Permission"
+                    }
                 },
                 "school_id": {
-                    "type": "string",
-                    "example": "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+                    "type": "string"
                 },
-                "user_id": {
-                    "type": "string",
-                    "example": "f1e2d3c4-b5a6-9876-5432-10fedcba9876"
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.User"
+                    }
                 }
             }
         },
-        "handlers.RegisterSchoolInfoRequest": {
+        "models.School": {
             "type": "object",
-            "required": [
-                "address",
-                "education_level",
-                "initial_student_count",
-                "name",
-                "status"
-            ],
             "properties": {
                 "address": {
-                    "type": "string",
-                    "example": "Jl. Inovasi No. 10, Kota Teknologi"
+                    "type": "string"
+                },
+                "admin_user_id": {
+                    "type": "string"
+                },
+                "billing_credit": {
+                    "description": "Left over from downgrades, used by the next checkout",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
                 },
                 "education_level": {
-                    "type": "string",
-                    "enum": [
-                        "SD",
-                        "SMP",
-                        "SMA",
-                        "SMK",
-                        "PerguruanTinggi",
-                        "Lainnya"
-                    ],
-                    "example": "SMA"
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
                 },
                 "initial_student_count": {
-                    "type": "integer",
-                    "minimum": 1,
-                    "example": 150
+                    "type": "integer"
+                },
+                "max_students_allowed": {
+                    "type": "integer"
                 },
                 "name": {
-                    "type": "string",
-                    "example": "Barniee Academy"
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "Set for campuses of a foundation",
+                    "type": "string"
+                },
+                "package": {
+                    "$ref": "#/definitions/models.Package"
+                },
+                "package_id": {
+                    "type": "string"
+                },
+                "registration_reminder_at": {
+                    "description": "When the \"finish your registration\" email was sent",
+                    "type": "string"
+                },
+                "registration_status": {
+                    "type": "string"
                 },
                 "status": {
-                    "type": "string",
-                    "enum": [
-                        "Negeri",
-                        "Swasta"
-                    ],
-                    "example": "Swasta"
-                }
-            }
-        },
-        "handlers.RegisterSchoolInfoResponseData": {
-            "type": "object",
-            "properties": {
-                "school_id": {
-                    "type": "string",
-                    "example": "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+                    "type": "string"
                 },
-                "school_name": {
-                    "type": "string",
-                    "example": "Barniee Academy"
-                }
-            }
-        },
-        "handlers.RequestOTPRequest": {
-            "type": "object",
-            "required": [
-                "user_id"
-            ],
-            "properties": {
-                "user_id": {
-                    "type": "string",
-                    "example": "f1e2d3c4-b5a6-9876-5432-10fedcba9876"
+                "subscription_end_date": {
+                    "type": "string"
+                },
+                "subscription_start_date": {
+                    "type": "string"
+                },
+                "subscription_status": {
+                    "type": "string"
+                },
+                "suspended_at": {
+                    "type": "string"
+                },
+                "suspended_by": {
+                    "type": "string"
+                },
+                "suspended_reason": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.SelectPackageRequest": {
+        "models.SchoolLDAPConfig": {
             "type": "object",
-            "required": [
-                "package_id",
-                "school_id"
-            ],
             "properties": {
-                "package_id": {
-                    "type": "string",
-                    "example": "package-uuid-for-premium"
+                "admin_groups": {
+                    "type": "string"
+                },
+                "base_dn": {
+                    "type": "string"
+                },
+                "bind_dn": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "group_attribute": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name_attribute": {
+                    "type": "string"
                 },
                 "school_id": {
-                    "type": "string",
-                    "example": "a1b2c3d4-e5f6-7890-1234-567890abcdef"
-                }
-            }
-        },
-        "handlers.SelectPackageResponseData": {
-            "type": "object",
-            "properties": {
-                "school": {
-                    "$ref": "#/definitions/models.School"
-                }
-            }
-        },
-        "handlers.UpdateUserRequest": {
-            "type": "object",
-            "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "john.doe@example.com"
+                    "type": "string"
                 },
-                "name": {
-                    "type": "string",
-                    "example": "John Doe"
+                "start_tls": {
+                    "type": "boolean"
                 },
-                "role_name": {
-                    "type": "string",
-                    "enum": [
-                        "teacher",
-                        "student",
-                        "admin"
-                    ],
-                    "example": "student"
-                }
-            }
-        },
-        "handlers.UserDataResponse": {
-            "type": "object",
-            "properties": {
-                "user": {
-                    "$ref": "#/definitions/models.User"
-                }
-            }
-        },
-        "handlers.UserListResponse": {
-            "type": "object",
-            "properties": {
-                "users": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.User"
-                    }
-                }
-            }
-        },
-        "handlers.UserProfileResponseData": {
-            "type": "object",
-            "properties": {
-                "school": {
-                    "$ref": "#/definitions/models.School"
+                "teacher_groups": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/models.User"
-                }
-            }
-        },
-        "handlers.VerifyOTPRequest": {
-            "type": "object",
-            "required": [
-                "otp",
-                "user_id"
-            ],
-            "properties": {
-                "otp": {
-                    "type": "string",
-                    "example": "123456"
+                "updated_by": {
+                    "type": "string"
                 },
-                "user_id": {
-                    "type": "string",
-                    "example": "f1e2d3c4-b5a6-9876-5432-10fedcba9876"
+                "url": {
+                    "type": "string"
+                },
+                "user_filter": {
+                    "description": "e.g. (mail=%s)",
+                    "type": "string"
                 }
             }
         },
-        "models.Package": {
+        "models.SchoolSAMLConfig": {
             "type": "object",
             "properties": {
+                "auto_provision": {
+                    "type": "boolean"
+                },
                 "created_at": {
                     "type": "string"
                 },
                 "created_by": {
                     "type": "string"
                 },
-                "duration_days": {
-                    "type": "integer"
+                "default_role_name": {
+                    "type": "string"
                 },
-                "features": {
+                "email_attribute": {
                     "type": "string"
                 },
+                "enabled": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "string"
                 },
-                "max_students": {
-                    "type": "integer"
+                "idp_metadata_url": {
+                    "type": "string"
                 },
-                "name": {
+                "idp_metadata_xml": {
                     "type": "string"
                 },
-                "price_per_student": {
-                    "type": "number"
+                "name_attribute": {
+                    "type": "string"
                 },
-                "price_per_year": {
-                    "type": "number"
+                "role_attribute": {
+                    "type": "string"
+                },
+                "role_mapping": {
+                    "description": "IdP attribute value -\u003e role name",
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
@@ -1242,22 +9088,31 @@ const docTemplate = `{
                 }
             }
         },
-        "models.Role": {
+        "models.StudentInviteCode": {
             "type": "object",
             "properties": {
+                "code": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
                 "created_by": {
                     "type": "string"
                 },
-                "description": {
+                "expires_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "redeemed_at": {
+                    "type": "string"
+                },
+                "redeemed_by": {
+                    "type": "string"
+                },
+                "student_user_id": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -1265,58 +9120,61 @@ const docTemplate = `{
                 },
                 "updated_by": {
                     "type": "string"
-                },
-                "users": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.User"
-                    }
                 }
             }
         },
-        "models.School": {
+        "models.User": {
             "type": "object",
             "properties": {
-                "address": {
+                "created_at": {
                     "type": "string"
                 },
-                "admin_user_id": {
+                "created_by": {
                     "type": "string"
                 },
-                "created_at": {
-                    "type": "string"
+                "deleted_at": {
+                    "description": "Deleted users stay in the recycle bin, hidden from every query and unable to log in,\nuntil they are restored or purged. Their email stays taken meanwhile.",
+                    "type": "string",
+                    "format": "date-time"
                 },
-                "created_by": {
+                "deleted_by": {
                     "type": "string"
                 },
-                "education_level": {
+                "email": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "initial_student_count": {
-                    "type": "integer"
+                "name": {
+                    "type": "string"
                 },
-                "max_students_allowed": {
-                    "type": "integer"
+                "organization_id": {
+                    "description": "Set for organization admins, who have no school",
+                    "type": "string"
                 },
-                "name": {
+                "position": {
                     "type": "string"
                 },
-                "package": {
-                    "$ref": "#/definitions/models.Package"
+                "role": {
+                    "$ref": "#/definitions/models.Role"
                 },
-                "package_id": {
+                "role_id": {
+                    "type": "string"
+                },
+                "school_id": {
                     "type": "string"
                 },
                 "status": {
                     "type": "string"
                 },
-                "subscription_end_date": {
+                "status_changed_at": {
                     "type": "string"
                 },
-                "subscription_start_date": {
+                "status_changed_by": {
+                    "type": "string"
+                },
+                "status_reason": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -1324,10 +9182,13 @@ const docTemplate = `{
                 },
                 "updated_by": {
                     "type": "string"
+                },
+                "whatsapp_number": {
+                    "type": "string"
                 }
             }
         },
-        "models.User": {
+        "models.UserImport": {
             "type": "object",
             "properties": {
                 "created_at": {
@@ -1336,37 +9197,289 @@ const docTemplate = `{
                 "created_by": {
                     "type": "string"
                 },
-                "email": {
+                "created_count": {
+                    "type": "integer"
+                },
+                "failed_count": {
+                    "type": "integer"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "finished_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "passwords_cleared_at": {
+                    "description": "PasswordsClearedAt is set once the generated passwords were downloaded or expired; later\nresults files no longer contain them.",
                     "type": "string"
                 },
-                "position": {
+                "processed_rows": {
+                    "type": "integer"
+                },
+                "school_id": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_rows": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "description": "Bumped on every processed row; a running import that stops updating is resumed",
+                    "type": "string"
+                }
+            }
+        },
+        "services.DeletedUser": {
+            "type": "object",
+            "properties": {
+                "restorable_until": {
+                    "description": "The user is purged afterwards",
                     "type": "string"
                 },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "services.ImportPreview": {
+            "type": "object",
+            "properties": {
+                "invalid_rows": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.ImportRowPreview"
+                    }
+                },
+                "students": {
+                    "type": "integer",
+                    "example": 110
+                },
+                "teachers": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total_rows": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "valid_rows": {
+                    "type": "integer",
+                    "example": 118
+                }
+            }
+        },
+        "services.ImportRowPreview": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "budi@example.com"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "email already exists in the file on row 5"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Budi Santoso"
+                },
                 "role": {
-                    "$ref": "#/definitions/models.Role"
+                    "type": "string",
+                    "example": "student"
                 },
-                "role_id": {
+                "row": {
+                    "description": "Line in the file, counting the header",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "services.PlanChangeQuote": {
+            "type": "object",
+            "properties": {
+                "amount_due": {
+                    "type": "number",
+                    "example": 10339727
+                },
+                "at_period_end": {
+                    "type": "boolean"
+                },
+                "credit_applied": {
+                    "description": "Taken from the school's billing credit",
+                    "type": "number",
+                    "example": 0
+                },
+                "credit_to_balance": {
+                    "description": "Added to the billing credit when the target costs less",
+                    "type": "number",
+                    "example": 0
+                },
+                "current_package": {
+                    "$ref": "#/definitions/models.Package"
+                },
+                "effective_at": {
                     "type": "string"
                 },
-                "school_id": {
+                "period_end": {
                     "type": "string"
                 },
-                "updated_at": {
+                "period_start": {
+                    "description": "Period the school will be in after the change; a change away from a trial starts a new period",
                     "type": "string"
                 },
-                "updated_by": {
+                "prorated_charge": {
+                    "type": "number",
+                    "example": 18630137
+                },
+                "proration_credit": {
+                    "type": "number",
+                    "example": 9315068
+                },
+                "remaining_days": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "student_count": {
+                    "type": "integer",
+                    "example": 340
+                },
+                "subtotal": {
+                    "type": "number",
+                    "example": 9315069
+                },
+                "target_package": {
+                    "$ref": "#/definitions/models.Package"
+                },
+                "tax_amount": {
+                    "type": "number",
+                    "example": 1024658
+                },
+                "tax_rate_percent": {
+                    "type": "integer",
+                    "example": 11
+                }
+            }
+        },
+        "services.PlanChangeResult": {
+            "type": "object",
+            "properties": {
+                "payment": {
+                    "$ref": "#/definitions/models.Payment"
+                },
+                "plan_change": {
+                    "$ref": "#/definitions/models.PlanChange"
+                }
+            }
+        },
+        "services.PlanOverview": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlanChange"
+                    }
+                },
+                "scheduled_change": {
+                    "$ref": "#/definitions/models.PlanChange"
+                },
+                "school": {
+                    "$ref": "#/definitions/models.School"
+                }
+            }
+        },
+        "services.SchoolDetail": {
+            "type": "object",
+            "properties": {
+                "admin_user": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "school": {
+                    "$ref": "#/definitions/models.School"
+                },
+                "total_users": {
+                    "type": "integer",
+                    "example": 353
+                },
+                "user_counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    },
+                    "example": {
+                        "admin": 1,
+                        "student": 340,
+                        "teacher": 12
+                    }
+                }
+            }
+        },
+        "services.StudentUsage": {
+            "type": "object",
+            "properties": {
+                "max_students_allowed": {
+                    "description": "0 means unlimited",
+                    "type": "integer",
+                    "example": 50
+                },
+                "remaining": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "students_used": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "unlimited": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "services.UserStatusChangeFailure": {
+            "type": "object",
+            "properties": {
+                "error": {
                     "type": "string"
                 },
-                "whatsapp_number": {
+                "user_id": {
                     "type": "string"
                 }
             }
+        },
+        "services.UserStatusChangeResult": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.UserStatusChangeFailure"
+                    }
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -1375,6 +9488,12 @@ const docTemplate = `{
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
+        },
+        "RegistrationToken": {
+            "description": "The registration_token returned by POST /register/school-info.",
+            "type": "apiKey",
+            "name": "X-Registration-Token",
+            "in": "header"
         }
     }
 }`
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"auth-barniee/internal/config"
 	"auth-barniee/internal/database"
+	"auth-barniee/internal/repositories"
 	"auth-barniee/internal/routes"
+	"auth-barniee/internal/scheduler"
+	"auth-barniee/internal/services"
 
 	"github.com/gin-gonic/gin"
 
@@ -36,11 +43,28 @@ import (
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
 
+// @securityDefinitions.apikey RegistrationToken
+// @in header
+// @name X-Registration-Token
+// @description The registration_token returned by POST /register/school-info.
+
 func main() {
 	cfg := config.LoadConfig()
 
 	db := database.InitDB(cfg)
 
+	repos := repositories.NewRepositories(db)
+	svc, err := services.NewServices(repos, repositories.NewUnitOfWork(db), cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up services: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if cfg.SchedulerEnabled {
+		scheduler.NewDefault(repos, svc, cfg).Start(ctx)
+	}
+
 	gin.SetMode(gin.ReleaseMode)
 
 	r := gin.Default()
@@ -48,7 +72,7 @@ func main() {
 	// Swagger documentation route
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	routes.SetupAuthRoutes(r, db, cfg)
+	routes.SetupAuthRoutes(r, db, svc, cfg)
 
 	log.Printf("Auth service listening on port %s...", "8080")
 	if err := r.Run(":8080"); err != nil {
@@ -21,6 +21,28 @@ type Config struct {
 	SMTPPassword     string
 	SenderEmail      string
 	OTPExpiryMinutes int
+	OTPMaxAttempts   int // Wrong guesses after which a code is invalidated
+	PublicBaseURL    string
+	SAMLCertFile     string
+	SAMLKeyFile      string
+	// Days after SubscriptionEndDate a paid school stays fully usable while payment is pending
+	SubscriptionPastDueDays int
+	// Days after that (or after a trial ends) before the subscription expires
+	SubscriptionGraceDays int
+	SchedulerEnabled      bool // Background jobs; disable on replicas that should only serve requests
+	PPNRatePercent        int  // Indonesian VAT applied to invoices
+	InvoiceDueDays        int
+	PaymentProvider       string // midtrans or fake
+	MidtransServerKey     string
+	MidtransProduction    bool
+	FakePaymentSecret     string // Signs webhooks of the fake provider
+	// Minutes without any registration step before the registration session expires
+	RegistrationSessionIdleMinutes int
+	RegistrationReminderHours      int    // Hours without a step before the applicant is reminded to finish
+	RegistrationPurgeDays          int    // Days without a step before an unfinished registration is deleted
+	PasswordSetupURL               string // Frontend page that receives ?token= from set-password emails
+	PasswordSetupExpiryHours       int
+	DeletedUserRetentionDays       int // Days a deleted user can be restored before it is purged
 }
 
 func LoadConfig() *Config {
@@ -31,6 +53,46 @@ func LoadConfig() *Config {
 
 	smtpPort, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
 	otpExpiryMinutes, _ := strconv.Atoi(os.Getenv("OTP_EXPIRY_MINUTES"))
+	otpMaxAttempts, err := strconv.Atoi(os.Getenv("OTP_MAX_ATTEMPTS"))
+	if err != nil || otpMaxAttempts <= 0 {
+		otpMaxAttempts = 5
+	}
+	subscriptionPastDueDays, err := strconv.Atoi(os.Getenv("SUBSCRIPTION_PAST_DUE_DAYS"))
+	if err != nil {
+		subscriptionPastDueDays = 7
+	}
+	subscriptionGraceDays, err := strconv.Atoi(os.Getenv("SUBSCRIPTION_GRACE_DAYS"))
+	if err != nil {
+		subscriptionGraceDays = 7
+	}
+	ppnRatePercent, err := strconv.Atoi(os.Getenv("PPN_RATE_PERCENT"))
+	if err != nil {
+		ppnRatePercent = 11
+	}
+	invoiceDueDays, err := strconv.Atoi(os.Getenv("INVOICE_DUE_DAYS"))
+	if err != nil {
+		invoiceDueDays = 14
+	}
+	registrationSessionIdleMinutes, err := strconv.Atoi(os.Getenv("REGISTRATION_SESSION_IDLE_MINUTES"))
+	if err != nil {
+		registrationSessionIdleMinutes = 30
+	}
+	registrationReminderHours, err := strconv.Atoi(os.Getenv("REGISTRATION_REMINDER_HOURS"))
+	if err != nil {
+		registrationReminderHours = 24
+	}
+	registrationPurgeDays, err := strconv.Atoi(os.Getenv("REGISTRATION_PURGE_DAYS"))
+	if err != nil {
+		registrationPurgeDays = 14
+	}
+	passwordSetupExpiryHours, err := strconv.Atoi(os.Getenv("PASSWORD_SETUP_EXPIRY_HOURS"))
+	if err != nil {
+		passwordSetupExpiryHours = 72
+	}
+	deletedUserRetentionDays, err := strconv.Atoi(os.Getenv("DELETED_USER_RETENTION_DAYS"))
+	if err != nil {
+		deletedUserRetentionDays = 30
+	}
 
 	return &Config{
 		DBHost:           os.Getenv("DB_HOST"),
@@ -45,5 +107,26 @@ func LoadConfig() *Config {
 		SMTPPassword:     os.Getenv("SMTP_PASSWORD"),
 		SenderEmail:      os.Getenv("SENDER_EMAIL"),
 		OTPExpiryMinutes: otpExpiryMinutes,
+		OTPMaxAttempts:   otpMaxAttempts,
+		PublicBaseURL:    os.Getenv("PUBLIC_BASE_URL"),
+		SAMLCertFile:     os.Getenv("SAML_CERT_FILE"),
+		SAMLKeyFile:      os.Getenv("SAML_KEY_FILE"),
+
+		SubscriptionPastDueDays: subscriptionPastDueDays,
+		SubscriptionGraceDays:   subscriptionGraceDays,
+		SchedulerEnabled:        os.Getenv("SCHEDULER_ENABLED") != "false",
+		PPNRatePercent:          ppnRatePercent,
+		InvoiceDueDays:          invoiceDueDays,
+		PaymentProvider:         os.Getenv("PAYMENT_PROVIDER"),
+		MidtransServerKey:       os.Getenv("MIDTRANS_SERVER_KEY"),
+		MidtransProduction:      os.Getenv("MIDTRANS_PRODUCTION") == "true",
+		FakePaymentSecret:       os.Getenv("FAKE_PAYMENT_SECRET"),
+
+		RegistrationSessionIdleMinutes: registrationSessionIdleMinutes,
+		RegistrationReminderHours:      registrationReminderHours,
+		RegistrationPurgeDays:          registrationPurgeDays,
+		PasswordSetupURL:               os.Getenv("PASSWORD_SETUP_URL"),
+		PasswordSetupExpiryHours:       passwordSetupExpiryHours,
+		DeletedUserRetentionDays:       deletedUserRetentionDays,
 	}
 }
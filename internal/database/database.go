@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"gorm.io/driver/postgres"
 	"log"
+	"slices"
 	"time"
 
 	"auth-barniee/internal/config"
@@ -24,17 +25,38 @@ func InitDB(cfg *config.Config) *gorm.DB {
 	}
 
 	err = db.AutoMigrate(
+		&models.Permission{},
 		&models.Role{},
 		&models.User{},
 		&models.School{},
 		&models.Package{},
 		&models.EmailVerification{},
+		&models.SchoolSAMLConfig{},
+		&models.SchoolLDAPConfig{},
+		&models.GuardianLink{},
+		&models.StudentInviteCode{},
+		&models.JobLock{},
+		&models.JobRun{},
+		&models.SubscriptionReminder{},
+		&models.Invoice{},
+		&models.InvoiceLine{},
+		&models.InvoiceSequence{},
+		&models.Payment{},
+		&models.PlanChange{},
+		&models.Organization{},
+		&models.RegistrationSession{},
+		&models.PurgedRegistration{},
+		&models.PasswordSetupToken{},
+		&models.UserImport{},
+		&models.UserImportRow{},
 	)
 	if err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
 	seedRoles(db)
+	newPermissions := seedPermissions(db)
+	seedRolePermissions(db, newPermissions)
 	seedPackages(db)
 	seedAdminUser(db)
 
@@ -43,14 +65,17 @@ func InitDB(cfg *config.Config) *gorm.DB {
 
 func seedRoles(db *gorm.DB) {
 	roles := []models.Role{
+		{Name: "super_admin", Description: "Platform Administrator"},
+		{Name: "org_admin", Description: "Organization Administrator"},
 		{Name: "admin", Description: "Administrator"},
 		{Name: "teacher", Description: "Teacher"},
 		{Name: "student", Description: "Student"},
+		{Name: "parent", Description: "Parent"},
 	}
 
 	for _, role := range roles {
 		var existingRole models.Role
-		db.Where("name = ?", role.Name).First(&existingRole)
+		db.Where("name = ? AND school_id IS NULL", role.Name).First(&existingRole)
 		if existingRole.ID == uuid.Nil {
 			role.ID = uuid.New()
 			db.Create(&role)
@@ -58,6 +83,111 @@ func seedRoles(db *gorm.DB) {
 	}
 }
 
+// seedPermissions creates missing permissions and returns the names of the ones it created.
+func seedPermissions(db *gorm.DB) []string {
+	permissions := []models.Permission{
+		{Name: models.PermissionUsersRead, Description: "View users of the school"},
+		{Name: models.PermissionUsersCreate, Description: "Create users in the school"},
+		{Name: models.PermissionUsersUpdate, Description: "Update users of the school"},
+		{Name: models.PermissionUsersDelete, Description: "Delete users of the school"},
+		{Name: models.PermissionSchoolRead, Description: "View school settings"},
+		{Name: models.PermissionSchoolUpdate, Description: "Change school settings such as SSO"},
+		{Name: models.PermissionGuardiansManage, Description: "Link parents to students and issue invite codes"},
+		{Name: models.PermissionRolesManage, Description: "Manage the school's custom roles and assign roles to users"},
+		{Name: models.PermissionBillingRead, Description: "View and download the school's invoices"},
+		{Name: models.PermissionBillingManage, Description: "Pay for the school's subscription"},
+		{Name: models.PermissionOrganizationManage, Description: "View the organization's campuses and transfer teachers between them"},
+		{Name: models.PermissionPlatformManage, Description: "Manage all schools on the platform"},
+		{Name: models.PermissionStudentsReadLinked, Description: "View and link own children as a parent"},
+	}
+
+	var created []string
+	for _, permission := range permissions {
+		var existingPermission models.Permission
+		db.Where("name = ?", permission.Name).First(&existingPermission)
+		if existingPermission.ID == uuid.Nil {
+			permission.ID = uuid.New()
+			if err := db.Create(&permission).Error; err == nil {
+				created = append(created, permission.Name)
+			}
+		}
+	}
+	return created
+}
+
+// seedRolePermissions gives the system roles their default permissions.
+// Roles that already have permissions only receive defaults for newly created permissions,
+// so changes made in the database survive restarts.
+func seedRolePermissions(db *gorm.DB, newPermissions []string) {
+	rolePermissions := map[string][]string{
+		"super_admin": {
+			models.PermissionUsersRead,
+			models.PermissionUsersCreate,
+			models.PermissionUsersUpdate,
+			models.PermissionUsersDelete,
+			models.PermissionSchoolRead,
+			models.PermissionSchoolUpdate,
+			models.PermissionGuardiansManage,
+			models.PermissionRolesManage,
+			models.PermissionBillingRead,
+			models.PermissionBillingManage,
+			models.PermissionOrganizationManage,
+			models.PermissionPlatformManage,
+		},
+		// Everything a campus admin has, so organization admins can appoint campus admins
+		"org_admin": {
+			models.PermissionUsersRead,
+			models.PermissionUsersCreate,
+			models.PermissionUsersUpdate,
+			models.PermissionUsersDelete,
+			models.PermissionSchoolRead,
+			models.PermissionSchoolUpdate,
+			models.PermissionGuardiansManage,
+			models.PermissionRolesManage,
+			models.PermissionBillingRead,
+			models.PermissionBillingManage,
+			models.PermissionOrganizationManage,
+		},
+		"admin": {
+			models.PermissionUsersRead,
+			models.PermissionUsersCreate,
+			models.PermissionUsersUpdate,
+			models.PermissionUsersDelete,
+			models.PermissionSchoolRead,
+			models.PermissionSchoolUpdate,
+			models.PermissionGuardiansManage,
+			models.PermissionRolesManage,
+			models.PermissionBillingRead,
+			models.PermissionBillingManage,
+		},
+		"parent": {
+			models.PermissionStudentsReadLinked,
+		},
+	}
+
+	for roleName, permissionNames := range rolePermissions {
+		var role models.Role
+		db.Where("name = ? AND school_id IS NULL", roleName).First(&role)
+		if role.ID == uuid.Nil {
+			continue
+		}
+		if db.Model(&role).Association("Permissions").Count() > 0 {
+			permissionNames = slices.DeleteFunc(slices.Clone(permissionNames), func(name string) bool {
+				return !slices.Contains(newPermissions, name)
+			})
+			if len(permissionNames) == 0 {
+				continue
+			}
+		}
+
+		var permissions []models.Permission
+		db.Where("name IN ?", permissionNames).Find(&permissions)
+		if err := db.Model(&role).Association("Permissions").Append(&permissions); err != nil {
+			log.Printf("Failed to seed permissions for role '%s': %v", roleName, err)
+		}
+	}
+}
+
 func seedPackages(db *gorm.DB) {
 	freeTrialMaxStudents := 50
 	freeTrialDurationDays := 30
@@ -67,6 +197,7 @@ func seedPackages(db *gorm.DB) {
 	packages := []models.Package{
 		{
 			Name:         "Free Trial",
+			IsTrial:      true,
 			DurationDays: &freeTrialDurationDays,
 			MaxStudents:  &freeTrialMaxStudents,
 			Features:     `["Dashboard dasar", "Laporan bulanan", "Email support", "Data backup"]`,
@@ -79,6 +210,7 @@ func seedPackages(db *gorm.DB) {
 		{
 			Name:         "Enterprise",
 			PricePerYear: &enterprisePricePerYear,
+			MultiCampus:  true,
 			Features:     `["Unlimited siswa", "Multi-campus support", "Custom AI features", "Dedicated account manager", "On-site training", "API integration"]`,
 		},
 	}
@@ -91,20 +223,39 @@ func seedPackages(db *gorm.DB) {
 			db.Create(&pkg)
 		}
 	}
+
+	// Older databases recognised the trial package by name only
+	var trialCount int64
+	db.Model(&models.Package{}).Where("is_trial = ?", true).Count(&trialCount)
+	if trialCount == 0 {
+		db.Model(&models.Package{}).Where("name = ?", "Free Trial").Update("is_trial", true)
+	}
+	// ... and offered multi-campus support on Enterprise only
+	var multiCampusCount int64
+	db.Model(&models.Package{}).Where("multi_campus = ?", true).Count(&multiCampusCount)
+	if multiCampusCount == 0 {
+		db.Model(&models.Package{}).Where("name = ?", "Enterprise").Update("multi_campus", true)
+	}
 }
 
 func seedAdminUser(db *gorm.DB) {
 	var adminRole models.Role
-	db.Where("name = ?", "admin").First(&adminRole)
+	db.Where("name = ? AND school_id IS NULL", "super_admin").First(&adminRole)
 
 	if adminRole.ID == uuid.Nil {
-		log.Println("Admin role not found, cannot seed master admin user.")
+		log.Println("Super admin role not found, cannot seed master admin user.")
 		return
 	}
 
 	var existingAdmin models.User
 	db.Where("email = ?", "masteradmin@barniee.com").First(&existingAdmin)
 
+	// Older databases seeded the master admin as an "admin" without a school
+	if existingAdmin.ID != uuid.Nil && existingAdmin.RoleID != adminRole.ID {
+		db.Model(&existingAdmin).Update("role_id", adminRole.ID)
+		log.Println("Master admin user 'masteradmin@barniee.com' moved to the super_admin role.")
+	}
+
 	if existingAdmin.ID == uuid.Nil {
 		hashedPassword, err := utils.HashPassword("masteradminpassword")
 		if err != nil {
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"auth-barniee/internal/models"
@@ -36,14 +37,21 @@ type LoginResponseData struct {
 	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
 }
 
+// SetPasswordRequest represents the request body for setting a password from a set-password link.
+type SetPasswordRequest struct {
+	Token    string `json:"token" binding:"required" example:"3f9a1c..."` // The token query parameter of the emailed link
+	Password string `json:"password" binding:"required,min=6" example:"securepassword"`
+}
+
 // UserProfileResponseData represents the data returned for user profile.
 type UserProfileResponseData struct {
-	User   models.User    `json:"user"`
-	School *models.School `json:"school,omitempty"`
+	User               models.User    `json:"user"`
+	School             *models.School `json:"school,omitempty"`
+	SubscriptionStatus string         `json:"subscription_status,omitempty" example:"trialing"`
 }
 
 // @Summary User Login
-// @Description Authenticates a user and returns a JWT token.
+// @Description Authenticates a user and returns a JWT token. Suspended, locked and graduated accounts are refused with 403.
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -51,6 +59,7 @@ type UserProfileResponseData struct {
 // @Success 200 {object} CommonResponse{data=LoginResponseData} "Login successful"
 // @Failure 400 {object} CommonResponse "Bad request"
 // @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Account is not active"
 // @Failure 500 {object} CommonResponse "Internal server error"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -66,6 +75,15 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	token, err := h.authService.Login(req.Email, req.Password)
 	if err != nil {
+		var inactiveErr *services.AccountInactiveError
+		if errors.As(err, &inactiveErr) {
+			c.JSON(http.StatusForbidden, CommonResponse{
+				Status:  http.StatusForbidden,
+				Message: err.Error(),
+				Data:    nil,
+			})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, CommonResponse{
 			Status:  http.StatusUnauthorized,
 			Message: err.Error(),
@@ -81,6 +99,52 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	})
 }
 
+func setPasswordErrorStatus(err error) int {
+	switch err.Error() {
+	case "invalid password setup link", "password setup link has expired", "password setup link has already been used":
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// @Summary Set Password
+// @Description Sets the password of the account a set-password link was emailed for, such as the school admin after registration completes. Each link can be used once.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param setPasswordRequest body SetPasswordRequest true "Link token and new password"
+// @Success 200 {object} CommonResponse "Password set successfully"
+// @Failure 400 {object} CommonResponse "Invalid, expired or already used link"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /auth/set-password [post]
+func (h *AuthHandler) SetPassword(c *gin.Context) {
+	var req SetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := h.authService.SetPassword(req.Token, req.Password); err != nil {
+		statusCode := setPasswordErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Password set successfully",
+		Data:    nil,
+	})
+}
+
 // @Summary User Logout
 // @Description Invalidates the client-side JWT token (no server-side session invalidation for stateless JWT).
 // @Tags Auth
@@ -140,6 +204,9 @@ func (h *AuthHandler) GetUserProfile(c *gin.Context) {
 		User:   *user,
 		School: school,
 	}
+	if school != nil {
+		responseData.SubscriptionStatus = school.SubscriptionStatus
+	}
 
 	c.JSON(http.StatusOK, CommonResponse{
 		Status:  http.StatusOK,
@@ -0,0 +1,390 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type GuardianHandler struct {
+	guardianService services.GuardianService
+}
+
+func NewGuardianHandler(guardianService services.GuardianService) *GuardianHandler {
+	return &GuardianHandler{guardianService: guardianService}
+}
+
+// CreateGuardianLinkRequest represents the request body for linking a parent to a student.
+type CreateGuardianLinkRequest struct {
+	ParentUserID  uuid.UUID `json:"parent_user_id" binding:"required" example:"c3d4e5f6-a7b8-9012-3456-7890abcdef12"`
+	StudentUserID uuid.UUID `json:"student_user_id" binding:"required" example:"f1e2d3c4-b5a6-9876-5432-10fedcba9876"`
+	Relationship  string    `json:"relationship" example:"Ibu"`
+}
+
+// RedeemInviteCodeRequest represents the request body for a parent redeeming a student invite code.
+type RedeemInviteCodeRequest struct {
+	Code         string `json:"code" binding:"required" example:"K7P2QX9M"`
+	Relationship string `json:"relationship" example:"Ayah"`
+}
+
+// GuardianLinkResponse represents a single guardian link for API response.
+type GuardianLinkResponse struct {
+	GuardianLink models.GuardianLink `json:"guardian_link"`
+}
+
+// GuardianLinkListResponse represents a list of guardian links for API response.
+type GuardianLinkListResponse struct {
+	GuardianLinks []models.GuardianLink `json:"guardian_links"`
+}
+
+// StudentInviteCodeResponse represents a generated student invite code.
+type StudentInviteCodeResponse struct {
+	InviteCode models.StudentInviteCode `json:"invite_code"`
+}
+
+// RedeemInviteCodeResponse represents the data returned after a parent redeems an invite code.
+type RedeemInviteCodeResponse struct {
+	GuardianLink models.GuardianLink `json:"guardian_link"`
+	Token        string              `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+// @Summary Link Parent to Student
+// @Description Allows a school admin to link an existing parent account to a student of their school.
+// @Tags Admin - Guardians
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param createGuardianLinkRequest body CreateGuardianLinkRequest true "Parent and student to link"
+// @Success 201 {object} CommonResponse{data=GuardianLinkResponse} "Guardian link created successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Router /admin/guardian-links [post]
+func (h *GuardianHandler) CreateGuardianLink(c *gin.Context) {
+	var req CreateGuardianLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	link, err := h.guardianService.LinkParentToStudent(adminUUID, req.ParentUserID, req.StudentUserID, req.Relationship)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CommonResponse{
+		Status:  http.StatusCreated,
+		Message: "Guardian link created successfully",
+		Data:    GuardianLinkResponse{GuardianLink: *link},
+	})
+}
+
+// @Summary Delete Guardian Link
+// @Description Allows a school admin to remove a parent-student link.
+// @Tags Admin - Guardians
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Guardian link ID" format:"uuid"
+// @Success 200 {object} CommonResponse "Guardian link deleted successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Router /admin/guardian-links/{id} [delete]
+func (h *GuardianHandler) DeleteGuardianLink(c *gin.Context) {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid guardian link ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := h.guardianService.UnlinkGuardian(adminUUID, linkID); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Guardian link deleted successfully",
+		Data:    nil,
+	})
+}
+
+// @Summary Get Student Guardians
+// @Description Lists the parents linked to a student of the admin's school.
+// @Tags Admin - Guardians
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Student user ID" format:"uuid"
+// @Success 200 {object} CommonResponse{data=GuardianLinkListResponse} "Guardians retrieved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Router /admin/users/{id}/guardians [get]
+func (h *GuardianHandler) GetStudentGuardians(c *gin.Context) {
+	studentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	links, err := h.guardianService.GetStudentGuardians(adminUUID, studentID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Guardians retrieved successfully",
+		Data:    GuardianLinkListResponse{GuardianLinks: links},
+	})
+}
+
+// @Summary Create Student Invite Code
+// @Description Generates a single-use code (valid for 7 days) that a parent can redeem to link to the student.
+// @Tags Admin - Guardians
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Student user ID" format:"uuid"
+// @Success 201 {object} CommonResponse{data=StudentInviteCodeResponse} "Invite code created successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Router /admin/users/{id}/invite-code [post]
+func (h *GuardianHandler) CreateStudentInviteCode(c *gin.Context) {
+	studentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	inviteCode, err := h.guardianService.CreateStudentInviteCode(adminUUID, studentID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CommonResponse{
+		Status:  http.StatusCreated,
+		Message: "Invite code created successfully",
+		Data:    StudentInviteCodeResponse{InviteCode: *inviteCode},
+	})
+}
+
+// @Summary Redeem Student Invite Code
+// @Description Links the authenticated parent to the student the invite code was issued for, and returns a refreshed token listing the linked students.
+// @Tags Parent
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param redeemInviteCodeRequest body RedeemInviteCodeRequest true "Invite code"
+// @Success 201 {object} CommonResponse{data=RedeemInviteCodeResponse} "Student linked successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Router /parent/students/redeem [post]
+func (h *GuardianHandler) RedeemStudentInviteCode(c *gin.Context) {
+	var req RedeemInviteCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	parentID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "User ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	parentUUID, ok := parentID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid user ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	link, token, err := h.guardianService.RedeemStudentInviteCode(parentUUID, req.Code, req.Relationship)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CommonResponse{
+		Status:  http.StatusCreated,
+		Message: "Student linked successfully",
+		Data:    RedeemInviteCodeResponse{GuardianLink: *link, Token: token},
+	})
+}
+
+// @Summary Get Linked Students
+// @Description Lists the students linked to the authenticated parent.
+// @Tags Parent
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} CommonResponse{data=GuardianLinkListResponse} "Linked students retrieved successfully"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /parent/students [get]
+func (h *GuardianHandler) GetLinkedStudents(c *gin.Context) {
+	parentID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "User ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	parentUUID, ok := parentID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid user ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	links, err := h.guardianService.GetLinkedStudents(parentUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Linked students retrieved successfully",
+		Data:    GuardianLinkListResponse{GuardianLinks: links},
+	})
+}
@@ -0,0 +1,660 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+	"auth-barniee/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InvoiceHandler struct {
+	invoiceService services.InvoiceService
+}
+
+func NewInvoiceHandler(invoiceService services.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{invoiceService: invoiceService}
+}
+
+// MarkInvoicePaidRequest represents the request body for recording an invoice payment.
+type MarkInvoicePaidRequest struct {
+	PaymentReference string     `json:"payment_reference" binding:"required" example:"TRF-BCA-20260115-001"`
+	PaidAt           *time.Time `json:"paid_at,omitempty" example:"2026-01-15T10:00:00Z"` // Defaults to now
+}
+
+// InvoiceListResponse represents a list of invoices for API response.
+type InvoiceListResponse struct {
+	Invoices []models.Invoice `json:"invoices"`
+}
+
+// InvoiceResponse represents a single invoice for API response.
+type InvoiceResponse struct {
+	Invoice models.Invoice `json:"invoice"`
+}
+
+// writeInvoiceHTML sends the invoice as a downloadable HTML document.
+func (h *InvoiceHandler) writeInvoiceHTML(c *gin.Context, invoice *models.Invoice) {
+	body, err := h.invoiceService.RenderInvoiceHTML(invoice)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	filename := strings.ReplaceAll(invoice.Number, "/", "-") + ".html"
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/html; charset=utf-8", body)
+}
+
+// @Summary List School Invoices
+// @Description Lists the invoices of the admin's school, newest first.
+// @Tags Admin - Billing
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} CommonResponse{data=InvoiceListResponse} "Invoices retrieved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/invoices [get]
+func (h *InvoiceHandler) GetSchoolInvoices(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	invoices, err := h.invoiceService.GetSchoolInvoices(adminUUID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "admin is not associated with a school" {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Invoices retrieved successfully",
+		Data:    InvoiceListResponse{Invoices: invoices},
+	})
+}
+
+// @Summary Download School Invoice
+// @Description Downloads one of the admin's school invoices as an HTML document.
+// @Tags Admin - Billing
+// @Security BearerAuth
+// @Produce html
+// @Param id path string true "Invoice ID" format:"uuid"
+// @Success 200 {string} string "Invoice document"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Invoice not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/invoices/{id}/download [get]
+func (h *InvoiceHandler) DownloadSchoolInvoice(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid invoice ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	invoice, err := h.invoiceService.GetSchoolInvoice(adminUUID, invoiceID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "invoice not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "admin is not associated with a school" {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	h.writeInvoiceHTML(c, invoice)
+}
+
+// @Summary List Organization Invoices
+// @Description Lists the consolidated invoices of the organization admin's organization, newest first.
+// @Tags Organization
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} CommonResponse{data=InvoiceListResponse} "Invoices retrieved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /org/invoices [get]
+func (h *InvoiceHandler) GetOrganizationInvoices(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	invoices, err := h.invoiceService.GetOrganizationInvoices(adminUUID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "admin is not associated with an organization" {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Invoices retrieved successfully",
+		Data:    InvoiceListResponse{Invoices: invoices},
+	})
+}
+
+// @Summary Download Organization Invoice
+// @Description Downloads one of the organization's consolidated invoices as an HTML document.
+// @Tags Organization
+// @Security BearerAuth
+// @Produce html
+// @Param id path string true "Invoice ID" format:"uuid"
+// @Success 200 {string} string "Invoice document"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Invoice not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /org/invoices/{id}/download [get]
+func (h *InvoiceHandler) DownloadOrganizationInvoice(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid invoice ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	invoice, err := h.invoiceService.GetOrganizationInvoice(adminUUID, invoiceID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "invoice not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "admin is not associated with an organization" {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	h.writeInvoiceHTML(c, invoice)
+}
+
+// @Summary List Invoices
+// @Description Lists invoices of all schools for platform admins, newest first.
+// @Tags Platform - Billing
+// @Security BearerAuth
+// @Produce json
+// @Param school_id query string false "Filter by school ID" format:"uuid"
+// @Param organization_id query string false "Filter by organization ID (consolidated invoices)" format:"uuid"
+// @Param status query string false "Filter by status (issued, overdue, paid, void)" example:"overdue"
+// @Success 200 {object} CommonResponse{data=InvoiceListResponse} "Invoices retrieved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/invoices [get]
+func (h *InvoiceHandler) ListInvoices(c *gin.Context) {
+	filter := repositories.InvoiceFilter{Status: c.Query("status")}
+	if schoolIDParam := c.Query("school_id"); schoolIDParam != "" {
+		schoolID, err := uuid.Parse(schoolIDParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, CommonResponse{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid school ID format",
+				Data:    nil,
+			})
+			return
+		}
+		filter.SchoolID = &schoolID
+	}
+	if organizationIDParam := c.Query("organization_id"); organizationIDParam != "" {
+		organizationID, err := uuid.Parse(organizationIDParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, CommonResponse{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid organization ID format",
+				Data:    nil,
+			})
+			return
+		}
+		filter.OrganizationID = &organizationID
+	}
+
+	invoices, err := h.invoiceService.ListInvoices(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Invoices retrieved successfully",
+		Data:    InvoiceListResponse{Invoices: invoices},
+	})
+}
+
+// @Summary Generate Invoice
+// @Description Issues an invoice for the school's next subscription period, the period and student count a checkout would charge, priced from its package plus PPN. A school can have one unpaid invoice at a time.
+// @Tags Platform - Billing
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "School ID" format:"uuid"
+// @Success 201 {object} CommonResponse{data=InvoiceResponse} "Invoice generated successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "School not found"
+// @Failure 409 {object} CommonResponse "School already has an unpaid invoice"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/schools/{id}/invoices [post]
+func (h *InvoiceHandler) GenerateInvoice(c *gin.Context) {
+	schoolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid school ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	invoice, err := h.invoiceService.GenerateInvoice(adminUUID, schoolID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "school not found":
+			statusCode = http.StatusNotFound
+		case "school already has an unpaid invoice":
+			statusCode = http.StatusConflict
+		case "trial packages are not invoiced", "package has no price to invoice",
+			"school is billed through its organization":
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CommonResponse{
+		Status:  http.StatusCreated,
+		Message: "Invoice generated successfully",
+		Data:    InvoiceResponse{Invoice: *invoice},
+	})
+}
+
+// @Summary Generate Organization Invoice
+// @Description Issues one consolidated invoice for an organization with consolidated billing. Every campus is charged for its current subscription period and student count; campuses already invoiced for that period are skipped.
+// @Tags Platform - Organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization ID" format:"uuid"
+// @Success 201 {object} CommonResponse{data=InvoiceResponse} "Invoice generated successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Organization not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/organizations/{id}/invoices [post]
+func (h *InvoiceHandler) GenerateOrganizationInvoice(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid organization ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	invoice, err := h.invoiceService.GenerateOrganizationInvoice(adminUUID, organizationID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "organization not found":
+			statusCode = http.StatusNotFound
+		case "organization does not use consolidated billing", "no campus has a paid package to invoice":
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CommonResponse{
+		Status:  http.StatusCreated,
+		Message: "Invoice generated successfully",
+		Data:    InvoiceResponse{Invoice: *invoice},
+	})
+}
+
+// @Summary Download Invoice
+// @Description Downloads any school's invoice as an HTML document.
+// @Tags Platform - Billing
+// @Security BearerAuth
+// @Produce html
+// @Param id path string true "Invoice ID" format:"uuid"
+// @Success 200 {string} string "Invoice document"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Invoice not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/invoices/{id}/download [get]
+func (h *InvoiceHandler) DownloadInvoice(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid invoice ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	invoice, err := h.invoiceService.GetInvoice(invoiceID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "invoice not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	h.writeInvoiceHTML(c, invoice)
+}
+
+// @Summary Mark Invoice Paid
+// @Description Records the payment of an issued or overdue invoice. Paying a school invoice adds its period to the school's subscription, continuing from the end date while a paid period is running, and moves the school to the invoiced package.
+// @Tags Platform - Billing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Invoice ID" format:"uuid"
+// @Param request body MarkInvoicePaidRequest true "Payment details"
+// @Success 200 {object} CommonResponse{data=InvoiceResponse} "Invoice marked as paid successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Invoice not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/invoices/{id}/mark-paid [post]
+func (h *InvoiceHandler) MarkInvoicePaid(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid invoice ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	var req MarkInvoicePaidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	invoice, err := h.invoiceService.MarkInvoicePaid(adminUUID, invoiceID, req.PaymentReference, req.PaidAt)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "invoice not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Invoice marked as paid successfully",
+		Data:    InvoiceResponse{Invoice: *invoice},
+	})
+}
+
+// @Summary Void Invoice
+// @Description Voids an unpaid invoice, for example one issued with wrong data. The number is not reused.
+// @Tags Platform - Billing
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Invoice ID" format:"uuid"
+// @Success 200 {object} CommonResponse{data=InvoiceResponse} "Invoice voided successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Invoice not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/invoices/{id}/void [post]
+func (h *InvoiceHandler) VoidInvoice(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid invoice ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	invoice, err := h.invoiceService.VoidInvoice(adminUUID, invoiceID)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "invoice not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Invoice voided successfully",
+		Data:    InvoiceResponse{Invoice: *invoice},
+	})
+}
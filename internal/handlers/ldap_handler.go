@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type LDAPHandler struct {
+	ldapConfigService services.LDAPConfigService
+}
+
+func NewLDAPHandler(ldapConfigService services.LDAPConfigService) *LDAPHandler {
+	return &LDAPHandler{ldapConfigService: ldapConfigService}
+}
+
+// LDAPConfigRequest represents the request body for configuring LDAP / Active Directory login.
+type LDAPConfigRequest struct {
+	Enabled        bool     `json:"enabled" example:"true"`
+	URL            string   `json:"url" binding:"required" example:"ldaps://dc01.sekolah.local:636"`
+	StartTLS       bool     `json:"start_tls" example:"false"`
+	BindDN         string   `json:"bind_dn" example:"CN=svc-barniee,OU=Service,DC=sekolah,DC=local"`
+	BindPassword   *string  `json:"bind_password,omitempty" example:"service-account-password"`
+	BaseDN         string   `json:"base_dn" binding:"required" example:"OU=Staff,DC=sekolah,DC=local"`
+	UserFilter     string   `json:"user_filter" binding:"required" example:"(&(objectClass=user)(mail=%s))"`
+	NameAttribute  string   `json:"name_attribute" example:"displayName"`
+	GroupAttribute string   `json:"group_attribute" example:"memberOf"`
+	AdminGroups    []string `json:"admin_groups" example:"CN=Operator Sekolah,OU=Groups,DC=sekolah,DC=local"`
+	TeacherGroups  []string `json:"teacher_groups" example:"CN=Guru,OU=Groups,DC=sekolah,DC=local"`
+}
+
+// LDAPConfigResponseData represents a school's LDAP configuration.
+type LDAPConfigResponseData struct {
+	LDAPConfig models.SchoolLDAPConfig `json:"ldap_config"`
+}
+
+// @Summary Get LDAP Configuration
+// @Description Retrieves the LDAP / Active Directory login configuration of the admin's school. The bind password is never returned.
+// @Tags Admin - LDAP
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} CommonResponse{data=LDAPConfigResponseData} "LDAP config retrieved successfully"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 404 {object} CommonResponse "LDAP config not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/ldap-config [get]
+func (h *LDAPHandler) GetConfig(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	ldapConfig, err := h.ldapConfigService.GetConfig(adminUUID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "LDAP config not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "LDAP config retrieved successfully",
+		Data:    LDAPConfigResponseData{LDAPConfig: *ldapConfig},
+	})
+}
+
+// @Summary Save LDAP Configuration
+// @Description Creates or updates the LDAP / Active Directory login configuration of the admin's school. Omit bind_password to keep the stored one.
+// @Tags Admin - LDAP
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param ldapConfigRequest body LDAPConfigRequest true "LDAP configuration"
+// @Success 200 {object} CommonResponse{data=LDAPConfigResponseData} "LDAP config saved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Router /admin/ldap-config [put]
+func (h *LDAPHandler) SaveConfig(c *gin.Context) {
+	var req LDAPConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	ldapConfig, err := h.ldapConfigService.SaveConfig(adminUUID, services.LDAPConfigInput{
+		Enabled:        req.Enabled,
+		URL:            req.URL,
+		StartTLS:       req.StartTLS,
+		BindDN:         req.BindDN,
+		BindPassword:   req.BindPassword,
+		BaseDN:         req.BaseDN,
+		UserFilter:     req.UserFilter,
+		NameAttribute:  req.NameAttribute,
+		GroupAttribute: req.GroupAttribute,
+		AdminGroups:    req.AdminGroups,
+		TeacherGroups:  req.TeacherGroups,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "LDAP config saved successfully",
+		Data:    LDAPConfigResponseData{LDAPConfig: *ldapConfig},
+	})
+}
@@ -0,0 +1,549 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type OrganizationHandler struct {
+	organizationService services.OrganizationService
+}
+
+func NewOrganizationHandler(organizationService services.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{organizationService: organizationService}
+}
+
+// OrganizationRequest represents the request body for creating or updating an organization.
+type OrganizationRequest struct {
+	Name                string `json:"name" binding:"required" example:"Yayasan Pendidikan Barniee"`
+	Address             string `json:"address" example:"Jl. Merdeka No. 1, Jakarta"`
+	BillingEmail        string `json:"billing_email" binding:"omitempty,email" example:"keuangan@yayasan.sch.id"`
+	ConsolidatedBilling bool   `json:"consolidated_billing" example:"true"` // Invoice all campuses on one organization invoice
+}
+
+// AddOrganizationSchoolRequest represents the request body for adding a campus to an organization.
+type AddOrganizationSchoolRequest struct {
+	SchoolID string `json:"school_id" binding:"required" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+}
+
+// CreateOrganizationAdminRequest represents the request body for creating an organization admin.
+type CreateOrganizationAdminRequest struct {
+	Name     string `json:"name" binding:"required" example:"Ketua Yayasan"`
+	Email    string `json:"email" binding:"required,email" example:"ketua@yayasan.sch.id"`
+	Password string `json:"password" binding:"required,min=6" example:"securepassword"`
+}
+
+// OrganizationResponse represents a single organization with its campuses for API response.
+type OrganizationResponse struct {
+	Organization models.Organization `json:"organization"`
+}
+
+// OrganizationListResponse represents a list of organizations for API response.
+type OrganizationListResponse struct {
+	Organizations []models.Organization `json:"organizations"`
+}
+
+func organizationErrorStatus(err error) int {
+	switch err.Error() {
+	case "organization not found", "school not found":
+		return http.StatusNotFound
+	case "school already belongs to this organization", "school already belongs to another organization",
+		"school does not belong to this organization", "user with this email already exists",
+		"admin is not associated with an organization", "school's package does not include multi-campus support":
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// @Summary Create Organization
+// @Description Creates an organization (yayasan) that can own several schools as campuses.
+// @Tags Platform - Organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body OrganizationRequest true "Organization details"
+// @Success 201 {object} CommonResponse{data=OrganizationResponse} "Organization created successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/organizations [post]
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var req OrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	organization, err := h.organizationService.CreateOrganization(adminUUID, services.OrganizationInput{
+		Name:                req.Name,
+		Address:             req.Address,
+		BillingEmail:        req.BillingEmail,
+		ConsolidatedBilling: req.ConsolidatedBilling,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CommonResponse{
+		Status:  http.StatusCreated,
+		Message: "Organization created successfully",
+		Data:    OrganizationResponse{Organization: *organization},
+	})
+}
+
+// @Summary List Organizations
+// @Description Lists every organization with its campuses.
+// @Tags Platform - Organizations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} CommonResponse{data=OrganizationListResponse} "Organizations retrieved successfully"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/organizations [get]
+func (h *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	organizations, err := h.organizationService.ListOrganizations()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Organizations retrieved successfully",
+		Data:    OrganizationListResponse{Organizations: organizations},
+	})
+}
+
+// @Summary Get Organization
+// @Description Retrieves an organization with its campuses and their packages.
+// @Tags Platform - Organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization ID" format:"uuid"
+// @Success 200 {object} CommonResponse{data=OrganizationResponse} "Organization retrieved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Organization not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/organizations/{id} [get]
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid organization ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	organization, err := h.organizationService.GetOrganization(organizationID)
+	if err != nil {
+		statusCode := organizationErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Organization retrieved successfully",
+		Data:    OrganizationResponse{Organization: *organization},
+	})
+}
+
+// @Summary Update Organization
+// @Description Updates an organization's details and billing mode.
+// @Tags Platform - Organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID" format:"uuid"
+// @Param request body OrganizationRequest true "Organization details"
+// @Success 200 {object} CommonResponse{data=OrganizationResponse} "Organization updated successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Organization not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/organizations/{id} [put]
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid organization ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	var req OrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	organization, err := h.organizationService.UpdateOrganization(adminUUID, organizationID, services.OrganizationInput{
+		Name:                req.Name,
+		Address:             req.Address,
+		BillingEmail:        req.BillingEmail,
+		ConsolidatedBilling: req.ConsolidatedBilling,
+	})
+	if err != nil {
+		statusCode := organizationErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Organization updated successfully",
+		Data:    OrganizationResponse{Organization: *organization},
+	})
+}
+
+// @Summary Add Organization Campus
+// @Description Makes an existing school a campus of the organization. Only schools on a package with `multi_campus` (Enterprise) can join, and a school belongs to at most one organization.
+// @Tags Platform - Organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID" format:"uuid"
+// @Param request body AddOrganizationSchoolRequest true "School to add"
+// @Success 200 {object} CommonResponse{data=OrganizationResponse} "School added successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Organization or school not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/organizations/{id}/schools [post]
+func (h *OrganizationHandler) AddSchool(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid organization ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	var req AddOrganizationSchoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	schoolID, err := uuid.Parse(req.SchoolID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid school ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	organization, err := h.organizationService.AddSchool(adminUUID, organizationID, schoolID)
+	if err != nil {
+		statusCode := organizationErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "School added successfully",
+		Data:    OrganizationResponse{Organization: *organization},
+	})
+}
+
+// @Summary Remove Organization Campus
+// @Description Detaches a school from the organization. Its users stay with the school.
+// @Tags Platform - Organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization ID" format:"uuid"
+// @Param school_id path string true "School ID" format:"uuid"
+// @Success 200 {object} CommonResponse{data=OrganizationResponse} "School removed successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Organization or school not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/organizations/{id}/schools/{school_id} [delete]
+func (h *OrganizationHandler) RemoveSchool(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid organization ID format",
+			Data:    nil,
+		})
+		return
+	}
+	schoolID, err := uuid.Parse(c.Param("school_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid school ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	organization, err := h.organizationService.RemoveSchool(adminUUID, organizationID, schoolID)
+	if err != nil {
+		statusCode := organizationErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "School removed successfully",
+		Data:    OrganizationResponse{Organization: *organization},
+	})
+}
+
+// @Summary Create Organization Admin
+// @Description Creates an organization admin who manages users across all campuses of the organization.
+// @Tags Platform - Organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID" format:"uuid"
+// @Param request body CreateOrganizationAdminRequest true "Admin details"
+// @Success 201 {object} CommonResponse{data=UserDataResponse} "Organization admin created successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Organization not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/organizations/{id}/admins [post]
+func (h *OrganizationHandler) CreateOrganizationAdmin(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid organization ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	var req CreateOrganizationAdminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	user, err := h.organizationService.CreateOrganizationAdmin(adminUUID, organizationID, req.Name, req.Email, req.Password)
+	if err != nil {
+		statusCode := organizationErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CommonResponse{
+		Status:  http.StatusCreated,
+		Message: "Organization admin created successfully",
+		Data:    UserDataResponse{User: *user},
+	})
+}
+
+// @Summary Get My Organization
+// @Description Retrieves the organization admin's organization with its campuses.
+// @Tags Organization
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} CommonResponse{data=OrganizationResponse} "Organization retrieved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /org [get]
+func (h *OrganizationHandler) GetAdminOrganization(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	organization, err := h.organizationService.GetAdminOrganization(adminUUID)
+	if err != nil {
+		statusCode := organizationErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Organization retrieved successfully",
+		Data:    OrganizationResponse{Organization: *organization},
+	})
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PaymentHandler struct {
+	paymentService services.PaymentService
+}
+
+func NewPaymentHandler(paymentService services.PaymentService) *PaymentHandler {
+	return &PaymentHandler{paymentService: paymentService}
+}
+
+// CheckoutResponse represents a created checkout for API response.
+type CheckoutResponse struct {
+	Payment models.Payment `json:"payment"`
+}
+
+func checkoutErrorStatus(err error) int {
+	switch err.Error() {
+	case "school not found":
+		return http.StatusNotFound
+	case "trial packages do not require payment", "package has no price to pay", "admin is not associated with a school",
+		"school is billed through its organization":
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// @Summary Create Registration Checkout
+// @Description Starts a payment for the paid package selected during registration. Redirect the user to `redirect_url`; the subscription is activated by the payment webhook.
+// @Tags Registration
+// @Security RegistrationToken
+// @Produce json
+// @Success 201 {object} CommonResponse{data=CheckoutResponse} "Checkout created successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Missing, invalid or expired registration session"
+// @Failure 404 {object} CommonResponse "School not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /register/checkout [post]
+func (h *PaymentHandler) CreateRegistrationCheckout(c *gin.Context) {
+	schoolID, exists := c.Get("registrationSchoolID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Registration session not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	schoolUUID, ok := schoolID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid school ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	payment, err := h.paymentService.CreateCheckout(schoolUUID, uuid.Nil)
+	if err != nil {
+		statusCode := checkoutErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CommonResponse{
+		Status:  http.StatusCreated,
+		Message: "Checkout created successfully",
+		Data:    CheckoutResponse{Payment: *payment},
+	})
+}
+
+// @Summary Create Renewal Checkout
+// @Description Starts a payment for the next subscription period of the admin's school. A running paid subscription is extended from its end date.
+// @Tags Admin - Billing
+// @Security BearerAuth
+// @Produce json
+// @Success 201 {object} CommonResponse{data=CheckoutResponse} "Checkout created successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/billing/checkout [post]
+func (h *PaymentHandler) CreateSchoolCheckout(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	payment, err := h.paymentService.CreateSchoolCheckout(adminUUID)
+	if err != nil {
+		statusCode := checkoutErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CommonResponse{
+		Status:  http.StatusCreated,
+		Message: "Checkout created successfully",
+		Data:    CheckoutResponse{Payment: *payment},
+	})
+}
+
+// @Summary Payment Webhook
+// @Description Receives payment notifications from the configured provider. The signature is verified before anything is applied; a successful payment activates or extends the school's subscription and issues a paid invoice.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Success 200 {object} CommonResponse "Notification processed"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Invalid signature"
+// @Failure 404 {object} CommonResponse "Payment not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /payments/webhook [post]
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Failed to read request body",
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := h.paymentService.HandleNotification(c.Request.Header, body); err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidPaymentSignature) {
+			statusCode = http.StatusUnauthorized
+		} else if err.Error() == "payment not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "payment amount does not match" || strings.HasPrefix(err.Error(), "invalid notification") {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Notification processed",
+		Data:    nil,
+	})
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-barniee/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PlanHandler struct {
+	planChangeService services.PlanChangeService
+}
+
+func NewPlanHandler(planChangeService services.PlanChangeService) *PlanHandler {
+	return &PlanHandler{planChangeService: planChangeService}
+}
+
+// PlanChangeRequest represents the request body for previewing or requesting a plan change.
+type PlanChangeRequest struct {
+	PackageID   string `json:"package_id" binding:"required" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+	AtPeriodEnd bool   `json:"at_period_end" example:"false"` // Switch when the current period ends instead of now
+}
+
+func planChangeErrorStatus(err error) int {
+	switch err.Error() {
+	case "school not found", "package not found", "no scheduled plan change":
+		return http.StatusNotFound
+	case "package is no longer available", "school is already on this package", "cannot change to a trial package",
+		"school has no running subscription period; change the plan immediately instead", "admin is not associated with a school",
+		"campuses of an organization need a multi-campus package", "school is billed through its organization":
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// bindPlanChangeRequest parses the request body, writing the error response if it is invalid.
+func bindPlanChangeRequest(c *gin.Context) (uuid.UUID, bool, bool) {
+	var req PlanChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return uuid.Nil, false, false
+	}
+	packageID, err := uuid.Parse(req.PackageID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid package ID format",
+			Data:    nil,
+		})
+		return uuid.Nil, false, false
+	}
+	return packageID, req.AtPeriodEnd, true
+}
+
+// @Summary Get Plan
+// @Description Returns the school's current package and subscription, its billing credit, any scheduled plan change and the plan change history.
+// @Tags Admin - Billing
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} CommonResponse{data=services.PlanOverview} "Plan retrieved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/plan [get]
+func (h *PlanHandler) GetPlan(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	overview, err := h.planChangeService.GetPlan(adminUUID)
+	if err != nil {
+		statusCode := planChangeErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Plan retrieved successfully",
+		Data:    overview,
+	})
+}
+
+// @Summary Preview Plan Change
+// @Description Quotes a change to another package without changing anything. Immediate changes during a paid period credit the unused days of the current package against the target package for the same days; a negative difference becomes billing credit.
+// @Tags Admin - Billing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body PlanChangeRequest true "Target package"
+// @Success 200 {object} CommonResponse{data=services.PlanChangeQuote} "Plan change quoted successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Package not found"
+// @Failure 409 {object} CommonResponse{data=StudentQuotaErrorData} "School has more students than the target package allows"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/plan/preview [post]
+func (h *PlanHandler) PreviewChange(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+	packageID, atPeriodEnd, ok := bindPlanChangeRequest(c)
+	if !ok {
+		return
+	}
+
+	quote, err := h.planChangeService.PreviewChange(adminUUID, packageID, atPeriodEnd)
+	if err != nil {
+		if respondStudentQuotaExceeded(c, err) {
+			return
+		}
+		statusCode := planChangeErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Plan change quoted successfully",
+		Data:    quote,
+	})
+}
+
+// @Summary Change Plan
+// @Description Moves the school to another package. With `at_period_end` the change is scheduled for the end of the current period and nothing is charged now. Otherwise a checkout for the prorated difference is returned and the package changes when it is paid; a change covered by billing credit is applied immediately.
+// @Tags Admin - Billing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body PlanChangeRequest true "Target package"
+// @Success 201 {object} CommonResponse{data=services.PlanChangeResult} "Plan change requested successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Package not found"
+// @Failure 409 {object} CommonResponse{data=StudentQuotaErrorData} "School has more students than the target package allows"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/plan/change [post]
+func (h *PlanHandler) ChangePlan(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+	packageID, atPeriodEnd, ok := bindPlanChangeRequest(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.planChangeService.ChangePlan(adminUUID, packageID, atPeriodEnd)
+	if err != nil {
+		if respondStudentQuotaExceeded(c, err) {
+			return
+		}
+		statusCode := planChangeErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CommonResponse{
+		Status:  http.StatusCreated,
+		Message: "Plan change requested successfully",
+		Data:    result,
+	})
+}
+
+// @Summary Cancel Scheduled Plan Change
+// @Description Cancels the plan change scheduled for the end of the current period.
+// @Tags Admin - Billing
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} CommonResponse{data=models.PlanChange} "Scheduled plan change cancelled successfully"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "No scheduled plan change"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/plan/scheduled-change [delete]
+func (h *PlanHandler) CancelScheduledChange(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	change, err := h.planChangeService.CancelScheduledChange(adminUUID)
+	if err != nil {
+		statusCode := planChangeErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Scheduled plan change cancelled successfully",
+		Data:    change,
+	})
+}
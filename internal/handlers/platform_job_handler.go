@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlatformJobHandler struct {
+	jobService services.JobService
+}
+
+func NewPlatformJobHandler(jobService services.JobService) *PlatformJobHandler {
+	return &PlatformJobHandler{jobService: jobService}
+}
+
+// JobRunListResponse represents the background job run history for API response.
+type JobRunListResponse struct {
+	JobRuns []models.JobRun `json:"job_runs"`
+}
+
+// @Summary List Background Job Runs
+// @Description Lists recent runs of the background jobs, newest first, with their outcome and summary.
+// @Tags Platform - Jobs
+// @Security BearerAuth
+// @Produce json
+// @Param job query string false "Filter by job name" example:"purge-expired-codes"
+// @Param limit query int false "Maximum number of runs (default 50, max 500)" example:"50"
+// @Success 200 {object} CommonResponse{data=JobRunListResponse} "Job runs retrieved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/job-runs [get]
+func (h *PlatformJobHandler) GetJobRuns(c *gin.Context) {
+	limit := 0
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, CommonResponse{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid limit, expected a number",
+				Data:    nil,
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	runs, err := h.jobService.GetJobRuns(c.Query("job"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Job runs retrieved successfully",
+		Data:    JobRunListResponse{JobRuns: runs},
+	})
+}
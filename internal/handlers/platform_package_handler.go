@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PlatformPackageHandler struct {
+	packageService services.PackageService
+}
+
+func NewPlatformPackageHandler(packageService services.PackageService) *PlatformPackageHandler {
+	return &PlatformPackageHandler{packageService: packageService}
+}
+
+// PackageRequest represents the request body for creating or updating a package.
+type PackageRequest struct {
+	Name            string   `json:"name" binding:"required" example:"Premium"`
+	PricePerStudent *float64 `json:"price_per_student,omitempty" example:"50000"`
+	PricePerYear    *float64 `json:"price_per_year,omitempty" example:"10000000"`
+	DurationDays    *int     `json:"duration_days,omitempty" example:"365"`
+	MaxStudents     *int     `json:"max_students,omitempty" example:"500"`
+	Features        []string `json:"features" example:"AI Analytics lengkap,Priority support 24/7"`
+	IsTrial         bool     `json:"is_trial" example:"false"`
+	MultiCampus     bool     `json:"multi_campus" example:"false"` // Lets schools on the package join an organization
+}
+
+func (req PackageRequest) toInput() services.PackageInput {
+	return services.PackageInput{
+		Name:            req.Name,
+		PricePerStudent: req.PricePerStudent,
+		PricePerYear:    req.PricePerYear,
+		DurationDays:    req.DurationDays,
+		MaxStudents:     req.MaxStudents,
+		Features:        req.Features,
+		IsTrial:         req.IsTrial,
+		MultiCampus:     req.MultiCampus,
+	}
+}
+
+// PackageResponse represents a single package for API response.
+type PackageResponse struct {
+	Package models.Package `json:"package"`
+}
+
+// @Summary List All Packages
+// @Description Lists the whole package catalog, including retired packages.
+// @Tags Platform - Packages
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} CommonResponse{data=GetAllPackagesResponseData} "Packages retrieved successfully"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/packages [get]
+func (h *PlatformPackageHandler) GetAllPackages(c *gin.Context) {
+	packages, err := h.packageService.GetAllPackages()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Packages retrieved successfully",
+		Data:    GetAllPackagesResponseData{Packages: packages},
+	})
+}
+
+// @Summary Create Package
+// @Description Adds a package to the catalog. Trial packages require duration_days; omit max_students for unlimited students.
+// @Tags Platform - Packages
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param packageRequest body PackageRequest true "Package details"
+// @Success 201 {object} CommonResponse{data=PackageResponse} "Package created successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Router /platform/packages [post]
+func (h *PlatformPackageHandler) CreatePackage(c *gin.Context) {
+	var req PackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	pkg, err := h.packageService.CreatePackage(adminUUID, req.toInput())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CommonResponse{
+		Status:  http.StatusCreated,
+		Message: "Package created successfully",
+		Data:    PackageResponse{Package: *pkg},
+	})
+}
+
+// @Summary Update Package
+// @Description Replaces the details of a package. Schools that already selected it keep their current limits and subscription dates.
+// @Tags Platform - Packages
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Package ID" format:"uuid"
+// @Param packageRequest body PackageRequest true "Package details"
+// @Success 200 {object} CommonResponse{data=PackageResponse} "Package updated successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Package not found"
+// @Router /platform/packages/{id} [put]
+func (h *PlatformPackageHandler) UpdatePackage(c *gin.Context) {
+	packageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid package ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	var req PackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	pkg, err := h.packageService.UpdatePackage(adminUUID, packageID, req.toInput())
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "package not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Package updated successfully",
+		Data:    PackageResponse{Package: *pkg},
+	})
+}
+
+// @Summary Retire Package
+// @Description Retires a package. It stays attached to existing schools but is no longer listed in or selectable during registration.
+// @Tags Platform - Packages
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Package ID" format:"uuid"
+// @Success 200 {object} CommonResponse{data=PackageResponse} "Package retired successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Package not found"
+// @Router /platform/packages/{id}/retire [post]
+func (h *PlatformPackageHandler) RetirePackage(c *gin.Context) {
+	packageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid package ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	pkg, err := h.packageService.RetirePackage(adminUUID, packageID)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "package not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Package retired successfully",
+		Data:    PackageResponse{Package: *pkg},
+	})
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlatformRegistrationHandler struct {
+	cleanupService services.RegistrationCleanupService
+}
+
+func NewPlatformRegistrationHandler(cleanupService services.RegistrationCleanupService) *PlatformRegistrationHandler {
+	return &PlatformRegistrationHandler{cleanupService: cleanupService}
+}
+
+// PurgedRegistrationListResponse represents the purged registration report for API response.
+type PurgedRegistrationListResponse struct {
+	PurgedRegistrations []models.PurgedRegistration `json:"purged_registrations"`
+}
+
+// @Summary List Purged Registrations
+// @Description Lists school registrations that were abandoned before completion and deleted by the cleanup job, newest first.
+// @Tags Platform - Schools
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Maximum number of entries (default 50, max 500)" example:"50"
+// @Success 200 {object} CommonResponse{data=PurgedRegistrationListResponse} "Purged registrations retrieved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/registrations/purged [get]
+func (h *PlatformRegistrationHandler) GetPurgedRegistrations(c *gin.Context) {
+	limit := 0
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, CommonResponse{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid limit, expected a number",
+				Data:    nil,
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	purged, err := h.cleanupService.GetPurgedRegistrations(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Purged registrations retrieved successfully",
+		Data:    PurgedRegistrationListResponse{PurgedRegistrations: purged},
+	})
+}
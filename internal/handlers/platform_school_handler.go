@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+	"auth-barniee/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PlatformSchoolHandler struct {
+	platformSchoolService services.PlatformSchoolService
+}
+
+func NewPlatformSchoolHandler(platformSchoolService services.PlatformSchoolService) *PlatformSchoolHandler {
+	return &PlatformSchoolHandler{platformSchoolService: platformSchoolService}
+}
+
+// SuspendSchoolRequest represents the request body for suspending a school.
+type SuspendSchoolRequest struct {
+	Reason string `json:"reason" binding:"required" example:"Tagihan belum dibayar"`
+}
+
+// SchoolListResponse represents a list of schools for API response.
+type SchoolListResponse struct {
+	Schools []models.School `json:"schools"`
+}
+
+// SchoolResponse represents a single school for API response.
+type SchoolResponse struct {
+	School models.School `json:"school"`
+}
+
+// SchoolDetailResponse represents a school with its admin and user counts for API response.
+type SchoolDetailResponse struct {
+	SchoolDetail services.SchoolDetail `json:"school_detail"`
+}
+
+// parseSchoolFilter reads the list filters from the query string.
+func parseSchoolFilter(c *gin.Context) (repositories.SchoolFilter, string) {
+	filter := repositories.SchoolFilter{
+		Search:         c.Query("search"),
+		Status:         c.Query("status"),
+		EducationLevel: c.Query("education_level"),
+	}
+	if packageIDParam := c.Query("package_id"); packageIDParam != "" {
+		packageID, err := uuid.Parse(packageIDParam)
+		if err != nil {
+			return filter, "Invalid package ID format"
+		}
+		filter.PackageID = &packageID
+	}
+	if suspendedParam := c.Query("suspended"); suspendedParam != "" {
+		suspended, err := strconv.ParseBool(suspendedParam)
+		if err != nil {
+			return filter, "Invalid suspended value, expected true or false"
+		}
+		filter.Suspended = &suspended
+	}
+	if expiresBeforeParam := c.Query("expires_before"); expiresBeforeParam != "" {
+		expiresBefore, err := time.Parse("2006-01-02", expiresBeforeParam)
+		if err != nil {
+			return filter, "Invalid expires_before date, expected YYYY-MM-DD"
+		}
+		filter.ExpiresBefore = &expiresBefore
+	}
+	if expiresAfterParam := c.Query("expires_after"); expiresAfterParam != "" {
+		expiresAfter, err := time.Parse("2006-01-02", expiresAfterParam)
+		if err != nil {
+			return filter, "Invalid expires_after date, expected YYYY-MM-DD"
+		}
+		filter.ExpiresAfter = &expiresAfter
+	}
+	return filter, ""
+}
+
+// @Summary List Schools
+// @Description Lists every registered school for platform admins, with optional search and filters.
+// @Tags Platform - Schools
+// @Security BearerAuth
+// @Produce json
+// @Param search query string false "Search by school name" example:"Barniee"
+// @Param package_id query string false "Filter by package ID" format:"uuid"
+// @Param status query string false "Filter by school status (Negeri, Swasta)" example:"Swasta"
+// @Param education_level query string false "Filter by education level" example:"SMA"
+// @Param suspended query bool false "Filter by suspension"
+// @Param expires_before query string false "Subscription ends before this date (YYYY-MM-DD)" example:"2026-12-31"
+// @Param expires_after query string false "Subscription ends after this date (YYYY-MM-DD)" example:"2026-01-01"
+// @Success 200 {object} CommonResponse{data=SchoolListResponse} "Schools retrieved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/schools [get]
+func (h *PlatformSchoolHandler) ListSchools(c *gin.Context) {
+	filter, errMessage := parseSchoolFilter(c)
+	if errMessage != "" {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: errMessage,
+			Data:    nil,
+		})
+		return
+	}
+
+	schools, err := h.platformSchoolService.ListSchools(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Schools retrieved successfully",
+		Data:    SchoolListResponse{Schools: schools},
+	})
+}
+
+// @Summary Get School Detail
+// @Description Retrieves a school with its primary admin and user counts per role.
+// @Tags Platform - Schools
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "School ID" format:"uuid"
+// @Success 200 {object} CommonResponse{data=SchoolDetailResponse} "School retrieved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "School not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/schools/{id} [get]
+func (h *PlatformSchoolHandler) GetSchoolDetail(c *gin.Context) {
+	schoolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid school ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	detail, err := h.platformSchoolService.GetSchoolDetail(schoolID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "school not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "School retrieved successfully",
+		Data:    SchoolDetailResponse{SchoolDetail: *detail},
+	})
+}
+
+// @Summary Suspend School
+// @Description Suspends a school. Its users can no longer obtain tokens until the school is reactivated.
+// @Tags Platform - Schools
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "School ID" format:"uuid"
+// @Param suspendSchoolRequest body SuspendSchoolRequest true "Suspension reason"
+// @Success 200 {object} CommonResponse{data=SchoolResponse} "School suspended successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "School not found"
+// @Router /platform/schools/{id}/suspend [post]
+func (h *PlatformSchoolHandler) SuspendSchool(c *gin.Context) {
+	schoolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid school ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	var req SuspendSchoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	school, err := h.platformSchoolService.SuspendSchool(adminUUID, schoolID, req.Reason)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "school not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "School suspended successfully",
+		Data:    SchoolResponse{School: *school},
+	})
+}
+
+// @Summary Reactivate School
+// @Description Lifts the suspension of a school.
+// @Tags Platform - Schools
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "School ID" format:"uuid"
+// @Success 200 {object} CommonResponse{data=SchoolResponse} "School reactivated successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "School not found"
+// @Router /platform/schools/{id}/reactivate [post]
+func (h *PlatformSchoolHandler) ReactivateSchool(c *gin.Context) {
+	schoolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid school ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	school, err := h.platformSchoolService.ReactivateSchool(adminUUID, schoolID)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "school not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "School reactivated successfully",
+		Data:    SchoolResponse{School: *school},
+	})
+}
+
+// @Summary Delete School
+// @Description Permanently deletes a school together with its users, SSO settings, custom roles, imports and unpaid payments. Schools with paid payments or invoices cannot be deleted and should be suspended instead; campuses must be removed from their organization first.
+// @Tags Platform - Schools
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "School ID" format:"uuid"
+// @Success 200 {object} CommonResponse "School deleted successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "School not found"
+// @Failure 409 {object} CommonResponse "School has billing history or belongs to an organization"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /platform/schools/{id} [delete]
+func (h *PlatformSchoolHandler) DeleteSchool(c *gin.Context) {
+	schoolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid school ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := h.platformSchoolService.DeleteSchool(schoolID); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "school not found":
+			statusCode = http.StatusNotFound
+		case "school has billing history and cannot be deleted", "school belongs to an organization, remove it from the organization first":
+			statusCode = http.StatusConflict
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "School deleted successfully",
+		Data:    nil,
+	})
+}
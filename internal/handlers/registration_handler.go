@@ -29,46 +29,54 @@ type RegisterSchoolInfoRequest struct {
 	InitialStudentCount int    `json:"initial_student_count" binding:"required,min=1" example:"150"`
 }
 
+// The steps after RegisterSchoolInfo identify the registration by the X-Registration-Token header,
+// so their bodies carry no school or user IDs.
 type RegisterAdminInfoRequest struct {
-	SchoolID       uuid.UUID `json:"school_id" binding:"required" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
-	AdminName      string    `json:"admin_name" binding:"required" example:"Siti Aminah"`
-	AdminEmail     string    `json:"admin_email" binding:"required,email" example:"siti.aminah@example.com"`
-	WhatsappNumber string    `json:"whatsapp_number" binding:"required" example:"081234567890"`
-	Position       string    `json:"position" binding:"required" example:"Direktur"`
+	AdminName      string `json:"admin_name" binding:"required" example:"Siti Aminah"`
+	AdminEmail     string `json:"admin_email" binding:"required,email" example:"siti.aminah@example.com"`
+	WhatsappNumber string `json:"whatsapp_number" binding:"required" example:"081234567890"`
+	Position       string `json:"position" binding:"required" example:"Direktur"`
 }
 
 type SelectPackageRequest struct {
-	SchoolID  uuid.UUID `json:"school_id" binding:"required" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
 	PackageID uuid.UUID `json:"package_id" binding:"required" example:"package-uuid-for-premium"`
 }
 
-type RequestOTPRequest struct {
-	UserID uuid.UUID `json:"user_id" binding:"required" example:"f1e2d3c4-b5a6-9876-5432-10fedcba9876"`
+type VerifyOTPRequest struct {
+	OTP string `json:"otp" binding:"required,len=6" example:"123456"`
 }
 
-type VerifyOTPRequest struct {
-	UserID uuid.UUID `json:"user_id" binding:"required" example:"f1e2d3c4-b5a6-9876-5432-10fedcba9876"`
-	OTP    string    `json:"otp" binding:"required,len=6" example:"123456"`
+type ResumeRegistrationRequest struct {
+	AdminEmail string `json:"admin_email" binding:"required,email" example:"siti.aminah@example.com"`
 }
 
-type CompleteRegistrationRequest struct {
-	SchoolID uuid.UUID `json:"school_id" binding:"required" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+type VerifyResumeOTPRequest struct {
+	AdminEmail string `json:"admin_email" binding:"required,email" example:"siti.aminah@example.com"`
+	OTP        string `json:"otp" binding:"required,len=6" example:"123456"`
 }
 
 // RegisterSchoolInfoResponseData represents the data returned after registering school info.
 type RegisterSchoolInfoResponseData struct {
-	SchoolID   uuid.UUID `json:"school_id" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
-	SchoolName string    `json:"school_name" example:"Barniee Academy"`
+	SchoolID          uuid.UUID `json:"school_id" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+	SchoolName        string    `json:"school_name" example:"Barniee Academy"`
+	RegistrationToken string    `json:"registration_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // Send as X-Registration-Token in the next steps
 }
 
 // RegisterAdminInfoResponseData represents the data returned after registering admin info.
 type RegisterAdminInfoResponseData struct {
 	UserID   uuid.UUID `json:"user_id" example:"f1e2d3c4-b5a6-9876-5432-10fedcba9876"`
 	Email    string    `json:"email" example:"siti.aminah@example.com"`
-	Password string    `json:"password" example:"GeneratedPass123"`
 	SchoolID uuid.UUID `json:"school_id" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
 }
 
+// ResumeRegistrationResponseData represents the registration returned after a successful resume.
+type ResumeRegistrationResponseData struct {
+	SchoolID           uuid.UUID `json:"school_id" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+	SchoolName         string    `json:"school_name" example:"Barniee Academy"`
+	RegistrationStatus string    `json:"registration_status" example:"package_selected"` // Last completed step; continue with the next one
+	RegistrationToken  string    `json:"registration_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
 // SelectPackageResponseData represents the data returned after selecting a package.
 type SelectPackageResponseData struct {
 	School models.School `json:"school"`
@@ -84,8 +92,25 @@ type CompleteRegistrationResponseData struct {
 	School models.School `json:"school"`
 }
 
+func registrationErrorStatus(err error) int {
+	switch err.Error() {
+	case "school not found", "school not found for completion", "package not found", "no unfinished registration for this email":
+		return http.StatusNotFound
+	case "admin user with this email already exists", "registration is already completed", "registration step is out of order",
+		"package cannot be changed after payment":
+		return http.StatusConflict
+	case "payment is required to complete registration":
+		return http.StatusPaymentRequired
+	case "package is no longer available", "admin info has not been registered yet", "invalid OTP", "OTP has already been used", "OTP has expired":
+		return http.StatusBadRequest
+	case "too many invalid OTP attempts, request a new OTP":
+		return http.StatusTooManyRequests
+	}
+	return http.StatusInternalServerError
+}
+
 // @Summary Register School Information
-// @Description Step 1 of school registration: Register basic school details.
+// @Description Step 1 of school registration: Register basic school details. The returned `registration_token` must be sent in the `X-Registration-Token` header of every later step; it expires after a period without registration activity.
 // @Tags School Registration
 // @Accept json
 // @Produce json
@@ -105,7 +130,7 @@ func (h *RegistrationHandler) RegisterSchoolInfo(c *gin.Context) {
 		return
 	}
 
-	school, err := h.regService.RegisterSchoolInfo(req.Name, req.EducationLevel, req.Status, req.Address, req.InitialStudentCount)
+	school, registrationToken, err := h.regService.RegisterSchoolInfo(req.Name, req.EducationLevel, req.Status, req.Address, req.InitialStudentCount)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, CommonResponse{
 			Status:  http.StatusInternalServerError,
@@ -118,23 +143,45 @@ func (h *RegistrationHandler) RegisterSchoolInfo(c *gin.Context) {
 		Status:  http.StatusCreated,
 		Message: "School info registered successfully",
 		Data: RegisterSchoolInfoResponseData{
-			SchoolID:   school.ID,
-			SchoolName: school.Name,
+			SchoolID:          school.ID,
+			SchoolName:        school.Name,
+			RegistrationToken: registrationToken,
 		},
 	})
 }
 
 // @Summary Register Admin Information
-// @Description Step 2 of school registration: Register the primary admin user for the school.
+// @Description Step 2 of school registration: Register the primary admin user for the school. No password is returned; the admin sets one through the link emailed when registration completes.
 // @Tags School Registration
+// @Security RegistrationToken
 // @Accept json
 // @Produce json
 // @Param registerAdminInfoRequest body RegisterAdminInfoRequest true "Admin Information"
 // @Success 201 {object} CommonResponse{data=RegisterAdminInfoResponseData} "Admin user created and linked to school"
 // @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Missing, invalid or expired registration session"
+// @Failure 409 {object} CommonResponse "Email already exists, step out of order or registration already completed"
 // @Failure 500 {object} CommonResponse "Internal server error"
 // @Router /register/admin-info [post]
 func (h *RegistrationHandler) RegisterAdminInfo(c *gin.Context) {
+	schoolID, exists := c.Get("registrationSchoolID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Registration session not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	schoolUUID, ok := schoolID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid school ID type in context",
+			Data:    nil,
+		})
+		return
+	}
 	var req RegisterAdminInfoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, CommonResponse{
@@ -145,10 +192,11 @@ func (h *RegistrationHandler) RegisterAdminInfo(c *gin.Context) {
 		return
 	}
 
-	adminUser, generatedPassword, err := h.regService.RegisterAdminInfo(req.SchoolID, req.AdminName, req.AdminEmail, req.WhatsappNumber, req.Position)
+	adminUser, err := h.regService.RegisterAdminInfo(schoolUUID, req.AdminName, req.AdminEmail, req.WhatsappNumber, req.Position)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, CommonResponse{
-			Status:  http.StatusInternalServerError,
+		statusCode := registrationErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
 			Message: err.Error(),
 			Data:    nil,
 		})
@@ -160,14 +208,13 @@ func (h *RegistrationHandler) RegisterAdminInfo(c *gin.Context) {
 		Data: RegisterAdminInfoResponseData{
 			UserID:   adminUser.ID,
 			Email:    adminUser.Email,
-			Password: generatedPassword,
 			SchoolID: adminUser.SchoolID,
 		},
 	})
 }
 
 // @Summary Get All Available Packages
-// @Description Retrieves a list of all available subscription packages (Free Trial, Premium, Enterprise).
+// @Description Retrieves the subscription packages that can currently be selected (e.g. Free Trial, Premium, Enterprise). Retired packages are not listed.
 // @Tags School Registration
 // @Produce json
 // @Success 200 {object} CommonResponse{data=GetAllPackagesResponseData} "Packages retrieved successfully"
@@ -184,7 +231,7 @@ func (h *RegistrationHandler) GetAllPackages(c *gin.Context) {
 		return
 	}
 	pkgRepo := repositories.NewPackageRepository(db)
-	packages, err := pkgRepo.FindAll()
+	packages, err := pkgRepo.FindActive()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, CommonResponse{
 			Status:  http.StatusInternalServerError,
@@ -203,14 +250,36 @@ func (h *RegistrationHandler) GetAllPackages(c *gin.Context) {
 // @Summary Select Package
 // @Description Step 3 of school registration: Selects a subscription package for the school.
 // @Tags School Registration
+// @Security RegistrationToken
 // @Accept json
 // @Produce json
 // @Param selectPackageRequest body SelectPackageRequest true "Package Selection"
 // @Success 200 {object} CommonResponse{data=SelectPackageResponseData} "Package selected successfully"
 // @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Missing, invalid or expired registration session"
+// @Failure 404 {object} CommonResponse "Package not found"
+// @Failure 409 {object} CommonResponse "Step out of order, registration already completed, or the selected package was already paid for"
 // @Failure 500 {object} CommonResponse "Internal server error"
 // @Router /register/select-package [post]
 func (h *RegistrationHandler) SelectPackage(c *gin.Context) {
+	schoolID, exists := c.Get("registrationSchoolID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Registration session not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	schoolUUID, ok := schoolID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid school ID type in context",
+			Data:    nil,
+		})
+		return
+	}
 	var req SelectPackageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, CommonResponse{
@@ -221,10 +290,11 @@ func (h *RegistrationHandler) SelectPackage(c *gin.Context) {
 		return
 	}
 
-	school, err := h.regService.SelectPackage(req.SchoolID, req.PackageID)
+	school, err := h.regService.SelectPackage(schoolUUID, req.PackageID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, CommonResponse{
-			Status:  http.StatusInternalServerError,
+		statusCode := registrationErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
 			Message: err.Error(),
 			Data:    nil,
 		})
@@ -238,30 +308,42 @@ func (h *RegistrationHandler) SelectPackage(c *gin.Context) {
 }
 
 // @Summary Request Email Verification OTP
-// @Description Step 4 of school registration: Sends an OTP to the user's email for verification.
+// @Description Step 4 of school registration: Sends an OTP to the email of the admin registered in step 2.
 // @Tags School Registration
-// @Accept json
+// @Security RegistrationToken
 // @Produce json
-// @Param requestOTPRequest body RequestOTPRequest true "User ID for OTP request"
 // @Success 200 {object} CommonResponse "OTP sent to email successfully"
 // @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Missing, invalid or expired registration session"
+// @Failure 409 {object} CommonResponse "Step out of order or registration already completed"
+// @Failure 429 {object} CommonResponse "Too many OTP requests from this client"
 // @Failure 500 {object} CommonResponse "Internal server error"
 // @Router /register/email-verification/request-otp [post]
 func (h *RegistrationHandler) RequestEmailVerificationOTP(c *gin.Context) {
-	var req RequestOTPRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, CommonResponse{
-			Status:  http.StatusBadRequest,
-			Message: err.Error(),
+	schoolID, exists := c.Get("registrationSchoolID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Registration session not found in context",
 			Data:    nil,
 		})
 		return
 	}
-
-	err := h.regService.RequestEmailVerificationOTP(req.UserID)
-	if err != nil {
+	schoolUUID, ok := schoolID.(uuid.UUID)
+	if !ok {
 		c.JSON(http.StatusInternalServerError, CommonResponse{
 			Status:  http.StatusInternalServerError,
+			Message: "Invalid school ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	err := h.regService.RequestEmailVerificationOTP(schoolUUID)
+	if err != nil {
+		statusCode := registrationErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
 			Message: err.Error(),
 			Data:    nil,
 		})
@@ -275,16 +357,38 @@ func (h *RegistrationHandler) RequestEmailVerificationOTP(c *gin.Context) {
 }
 
 // @Summary Verify Email OTP
-// @Description Step 4 of school registration: Verifies the OTP sent to the user's email.
+// @Description Step 4 of school registration: Verifies the OTP sent to the admin's email.
 // @Tags School Registration
+// @Security RegistrationToken
 // @Accept json
 // @Produce json
-// @Param verifyOTPRequest body VerifyOTPRequest true "User ID and OTP for verification"
+// @Param verifyOTPRequest body VerifyOTPRequest true "OTP for verification"
 // @Success 200 {object} CommonResponse "Email verified successfully"
 // @Failure 400 {object} CommonResponse "Bad request (invalid/expired OTP)"
+// @Failure 401 {object} CommonResponse "Missing, invalid or expired registration session"
+// @Failure 409 {object} CommonResponse "Step out of order or registration already completed"
+// @Failure 429 {object} CommonResponse "Too many OTP requests from this client, or too many invalid attempts on the code"
 // @Failure 500 {object} CommonResponse "Internal server error"
 // @Router /register/email-verification/verify-otp [post]
 func (h *RegistrationHandler) VerifyEmailOTP(c *gin.Context) {
+	schoolID, exists := c.Get("registrationSchoolID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Registration session not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	schoolUUID, ok := schoolID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid school ID type in context",
+			Data:    nil,
+		})
+		return
+	}
 	var req VerifyOTPRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, CommonResponse{
@@ -295,13 +399,9 @@ func (h *RegistrationHandler) VerifyEmailOTP(c *gin.Context) {
 		return
 	}
 
-	err := h.regService.VerifyEmailOTP(req.UserID, req.OTP)
+	err := h.regService.VerifyEmailOTP(schoolUUID, req.OTP)
 	if err != nil {
-		// Differentiate between user error (invalid OTP) and internal error
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "invalid OTP or user ID" || err.Error() == "email already verified" || err.Error() == "OTP has expired" {
-			statusCode = http.StatusBadRequest
-		}
+		statusCode := registrationErrorStatus(err)
 		c.JSON(statusCode, CommonResponse{
 			Status:  statusCode,
 			Message: err.Error(),
@@ -317,17 +417,69 @@ func (h *RegistrationHandler) VerifyEmailOTP(c *gin.Context) {
 }
 
 // @Summary Complete School Registration
-// @Description Step 6 of school registration: Finalizes the registration process after all previous steps are complete (including payment if applicable).
+// @Description Step 6 of school registration: Finalizes the registration process after all previous steps are complete. A paid package must have been paid through `POST /register/checkout` first; until the payment webhook arrives this returns 402. The admin is emailed a single-use link to set their password; if the email cannot be sent the registration stays open and can be completed again. The registration token cannot be used afterwards.
 // @Tags School Registration
-// @Accept json
+// @Security RegistrationToken
 // @Produce json
-// @Param completeRegistrationRequest body CompleteRegistrationRequest true "School ID to complete registration for"
 // @Success 200 {object} CommonResponse{data=CompleteRegistrationResponseData} "School registration completed successfully"
 // @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Missing, invalid or expired registration session"
+// @Failure 402 {object} CommonResponse "The selected paid package has not been paid yet"
+// @Failure 409 {object} CommonResponse "Step out of order or registration already completed"
 // @Failure 500 {object} CommonResponse "Internal server error"
 // @Router /register/complete [post]
 func (h *RegistrationHandler) CompleteRegistration(c *gin.Context) {
-	var req CompleteRegistrationRequest
+	schoolID, exists := c.Get("registrationSchoolID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Registration session not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	schoolUUID, ok := schoolID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid school ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	school, err := h.regService.CompleteRegistration(schoolUUID)
+	if err != nil {
+		statusCode := registrationErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "School registration completed successfully",
+		Data:    CompleteRegistrationResponseData{School: *school},
+	})
+}
+
+// @Summary Request Registration Resume OTP
+// @Description Sends a code to the admin email of an unfinished registration so the applicant can continue it, for example after the registration session expired.
+// @Tags School Registration
+// @Accept json
+// @Produce json
+// @Param resumeRegistrationRequest body ResumeRegistrationRequest true "Admin email entered in step 2"
+// @Success 200 {object} CommonResponse "OTP sent to email successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 404 {object} CommonResponse "No unfinished registration for this email"
+// @Failure 409 {object} CommonResponse "Registration is already completed"
+// @Failure 429 {object} CommonResponse "Too many OTP requests from this client"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /register/resume/request-otp [post]
+func (h *RegistrationHandler) RequestResumeOTP(c *gin.Context) {
+	var req ResumeRegistrationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, CommonResponse{
 			Status:  http.StatusBadRequest,
@@ -337,10 +489,11 @@ func (h *RegistrationHandler) CompleteRegistration(c *gin.Context) {
 		return
 	}
 
-	school, err := h.regService.CompleteRegistration(req.SchoolID)
+	err := h.regService.RequestResumeOTP(req.AdminEmail)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, CommonResponse{
-			Status:  http.StatusInternalServerError,
+		statusCode := registrationErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
 			Message: err.Error(),
 			Data:    nil,
 		})
@@ -348,7 +501,53 @@ func (h *RegistrationHandler) CompleteRegistration(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, CommonResponse{
 		Status:  http.StatusOK,
-		Message: "School registration completed successfully",
-		Data:    CompleteRegistrationResponseData{School: *school},
+		Message: "OTP sent to email successfully",
+		Data:    nil,
+	})
+}
+
+// @Summary Resume Registration
+// @Description Checks the resume code and returns a new registration token together with the registration status, so the applicant can continue with the next step. Earlier registration tokens stop working.
+// @Tags School Registration
+// @Accept json
+// @Produce json
+// @Param verifyResumeOTPRequest body VerifyResumeOTPRequest true "Admin email and OTP"
+// @Success 200 {object} CommonResponse{data=ResumeRegistrationResponseData} "Registration resumed successfully"
+// @Failure 400 {object} CommonResponse "Bad request (invalid/expired OTP)"
+// @Failure 404 {object} CommonResponse "No unfinished registration for this email"
+// @Failure 409 {object} CommonResponse "Registration is already completed"
+// @Failure 429 {object} CommonResponse "Too many OTP requests from this client, or too many invalid attempts on the code"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /register/resume/verify-otp [post]
+func (h *RegistrationHandler) ResumeRegistration(c *gin.Context) {
+	var req VerifyResumeOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	school, registrationToken, err := h.regService.ResumeRegistration(req.AdminEmail, req.OTP)
+	if err != nil {
+		statusCode := registrationErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Registration resumed successfully",
+		Data: ResumeRegistrationResponseData{
+			SchoolID:           school.ID,
+			SchoolName:         school.Name,
+			RegistrationStatus: school.RegistrationStatus,
+			RegistrationToken:  registrationToken,
+		},
 	})
 }
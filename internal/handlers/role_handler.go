@@ -0,0 +1,405 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RoleHandler struct {
+	roleService services.RoleService
+}
+
+func NewRoleHandler(roleService services.RoleService) *RoleHandler {
+	return &RoleHandler{roleService: roleService}
+}
+
+// CreateRoleRequest represents the request body for creating a custom school role.
+type CreateRoleRequest struct {
+	Name         string   `json:"name" binding:"required" example:"Wali Kelas"`
+	Description  string   `json:"description" example:"Homeroom teacher"`
+	BaseRoleName string   `json:"base_role_name" binding:"required,oneof=admin teacher student parent" example:"teacher"`
+	Permissions  []string `json:"permissions" example:"users:read"`
+}
+
+// UpdateRoleRequest represents the request body for updating a custom school role.
+// Omitting permissions keeps the current ones; an empty list removes them all.
+type UpdateRoleRequest struct {
+	Name        *string  `json:"name,omitempty" example:"Wali Kelas"`
+	Description *string  `json:"description,omitempty" example:"Homeroom teacher"`
+	Permissions []string `json:"permissions,omitempty" example:"users:read"`
+}
+
+// AssignRoleRequest represents the request body for assigning a role to a user.
+type AssignRoleRequest struct {
+	RoleID uuid.UUID `json:"role_id" binding:"required" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+}
+
+// RoleResponse represents a single role for API response.
+type RoleResponse struct {
+	Role models.Role `json:"role"`
+}
+
+// RoleListResponse represents a list of roles for API response.
+type RoleListResponse struct {
+	Roles []models.Role `json:"roles"`
+}
+
+// PermissionListResponse represents a list of permissions for API response.
+type PermissionListResponse struct {
+	Permissions []models.Permission `json:"permissions"`
+}
+
+// @Summary Get Roles
+// @Description Lists the system roles and the custom roles of the admin's school.
+// @Tags Admin - Roles
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} CommonResponse{data=RoleListResponse} "Roles retrieved successfully"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/roles [get]
+func (h *RoleHandler) GetRoles(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	roles, err := h.roleService.GetRoles(adminUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Roles retrieved successfully",
+		Data:    RoleListResponse{Roles: roles},
+	})
+}
+
+// @Summary Create Custom Role
+// @Description Creates a custom role for the admin's school. The role inherits the behaviour of its base role and may only carry permissions granted to both the base role and the admin.
+// @Tags Admin - Roles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param createRoleRequest body CreateRoleRequest true "Custom role details"
+// @Success 201 {object} CommonResponse{data=RoleResponse} "Role created successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Router /admin/roles [post]
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	role, err := h.roleService.CreateCustomRole(adminUUID, req.Name, req.Description, req.BaseRoleName, req.Permissions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CommonResponse{
+		Status:  http.StatusCreated,
+		Message: "Role created successfully",
+		Data:    RoleResponse{Role: *role},
+	})
+}
+
+// @Summary Update Custom Role
+// @Description Updates the name, description or permissions of a custom role of the admin's school.
+// @Tags Admin - Roles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID" format:"uuid"
+// @Param updateRoleRequest body UpdateRoleRequest true "Role details to update"
+// @Success 200 {object} CommonResponse{data=RoleResponse} "Role updated successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Role not found"
+// @Router /admin/roles/{id} [put]
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid role ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	var req UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	role, err := h.roleService.UpdateCustomRole(adminUUID, roleID, req.Name, req.Description, req.Permissions)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "role not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Role updated successfully",
+		Data:    RoleResponse{Role: *role},
+	})
+}
+
+// @Summary Delete Custom Role
+// @Description Deletes a custom role of the admin's school. Roles still assigned to users cannot be deleted.
+// @Tags Admin - Roles
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Role ID" format:"uuid"
+// @Success 200 {object} CommonResponse "Role deleted successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Role not found"
+// @Router /admin/roles/{id} [delete]
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid role ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := h.roleService.DeleteCustomRole(adminUUID, roleID); err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "role not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Role deleted successfully",
+		Data:    nil,
+	})
+}
+
+// @Summary Assign Role to User
+// @Description Assigns a system role or one of the school's custom roles to a user of the admin's school.
+// @Tags Admin - Roles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format:"uuid"
+// @Param assignRoleRequest body AssignRoleRequest true "Role to assign"
+// @Success 200 {object} CommonResponse{data=UserDataResponse} "Role assigned successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "User or role not found"
+// @Failure 409 {object} CommonResponse{data=StudentQuotaErrorData} "Student quota exceeded"
+// @Router /admin/users/{id}/role [put]
+func (h *RoleHandler) AssignRole(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	var req AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	user, err := h.roleService.AssignRole(adminUUID, userID, req.RoleID)
+	if err != nil {
+		if respondStudentQuotaExceeded(c, err) {
+			return
+		}
+		statusCode := http.StatusBadRequest
+		if err.Error() == "user not found" || err.Error() == "role not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Role assigned successfully",
+		Data:    UserDataResponse{User: *user},
+	})
+}
+
+// @Summary Get Permissions
+// @Description Lists every permission that can be granted to a role.
+// @Tags Admin - Roles
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} CommonResponse{data=PermissionListResponse} "Permissions retrieved successfully"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/permissions [get]
+func (h *RoleHandler) GetPermissions(c *gin.Context) {
+	permissions, err := h.roleService.GetPermissions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Permissions retrieved successfully",
+		Data:    PermissionListResponse{Permissions: permissions},
+	})
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const samlRequestIDCookie = "saml_request_id"
+
+type SAMLHandler struct {
+	samlService services.SAMLService
+}
+
+func NewSAMLHandler(samlService services.SAMLService) *SAMLHandler {
+	return &SAMLHandler{samlService: samlService}
+}
+
+// SAMLConfigRequest represents the request body for configuring SAML single sign-on.
+type SAMLConfigRequest struct {
+	Enabled         bool              `json:"enabled" example:"true"`
+	IDPMetadataURL  string            `json:"idp_metadata_url" binding:"omitempty,url" example:"https://sso.disdik.example.go.id/metadata"`
+	IDPMetadataXML  string            `json:"idp_metadata_xml"`
+	EmailAttribute  string            `json:"email_attribute" example:"urn:oid:0.9.2342.19200300.100.1.3"`
+	NameAttribute   string            `json:"name_attribute" example:"displayName"`
+	RoleAttribute   string            `json:"role_attribute" example:"eduPersonAffiliation"`
+	RoleMapping     map[string]string `json:"role_mapping"`
+	DefaultRoleName string            `json:"default_role_name" binding:"omitempty,oneof=teacher student admin" example:"student"`
+	AutoProvision   bool              `json:"auto_provision" example:"true"`
+}
+
+// SAMLConfigResponseData represents a school's SAML configuration.
+type SAMLConfigResponseData struct {
+	SAMLConfig models.SchoolSAMLConfig `json:"saml_config"`
+}
+
+// @Summary Get SAML Service Provider Metadata
+// @Description Returns the SAML SP metadata XML a school registers with its identity provider.
+// @Tags Auth - SAML
+// @Produce xml
+// @Param school_id path string true "School ID" format:"uuid"
+// @Success 200 {string} string "SP metadata XML"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /auth/saml/{school_id}/metadata [get]
+func (h *SAMLHandler) Metadata(c *gin.Context) {
+	schoolID, err := uuid.Parse(c.Param("school_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid school ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	metadata, err := h.samlService.GetMetadata(schoolID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "school not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/samlmetadata+xml", metadata)
+}
+
+// @Summary Start SAML Login
+// @Description Redirects the browser to the school's identity provider to start SP-initiated SAML login.
+// @Tags Auth - SAML
+// @Param school_id path string true "School ID" format:"uuid"
+// @Param relay_state query string false "Opaque state returned by the IdP"
+// @Success 302 "Redirect to the identity provider"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Router /auth/saml/{school_id}/login [get]
+func (h *SAMLHandler) Login(c *gin.Context) {
+	schoolID, err := uuid.Parse(c.Param("school_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid school ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	redirectURL, requestID, err := h.samlService.BuildLoginRedirect(schoolID, c.Query("relay_state"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	// The IdP posts back cross-site, so the cookie must be SameSite=None.
+	c.SetSameSite(http.SameSiteNoneMode)
+	c.SetCookie(samlRequestIDCookie, requestID, 300, fmt.Sprintf("/api/v1/auth/saml/%s", schoolID), "", true, true)
+	c.Redirect(http.StatusFound, redirectURL)
+}
+
+// @Summary SAML Assertion Consumer Service
+// @Description Validates the signed SAML response posted by the identity provider and issues a JWT token.
+// @Tags Auth - SAML
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param school_id path string true "School ID" format:"uuid"
+// @Param SAMLResponse formData string true "Base64 encoded SAML response"
+// @Success 200 {object} CommonResponse{data=LoginResponseData} "Login successful"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Router /auth/saml/{school_id}/acs [post]
+func (h *SAMLHandler) AssertionConsumerService(c *gin.Context) {
+	schoolID, err := uuid.Parse(c.Param("school_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid school ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	var possibleRequestIDs []string
+	if requestID, err := c.Cookie(samlRequestIDCookie); err == nil && requestID != "" {
+		possibleRequestIDs = append(possibleRequestIDs, requestID)
+	}
+
+	token, err := h.samlService.ConsumeAssertion(schoolID, c.Request, possibleRequestIDs)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.SetSameSite(http.SameSiteNoneMode)
+	c.SetCookie(samlRequestIDCookie, "", -1, fmt.Sprintf("/api/v1/auth/saml/%s", schoolID), "", true, true)
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Login successful",
+		Data:    LoginResponseData{Token: token},
+	})
+}
+
+// @Summary Get SAML Configuration
+// @Description Retrieves the SAML single sign-on configuration of the admin's school.
+// @Tags Admin - SAML
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} CommonResponse{data=SAMLConfigResponseData} "SAML config retrieved successfully"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 404 {object} CommonResponse "SAML config not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/saml-config [get]
+func (h *SAMLHandler) GetConfig(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	samlConfig, err := h.samlService.GetConfig(adminUUID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "SAML config not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "SAML config retrieved successfully",
+		Data:    SAMLConfigResponseData{SAMLConfig: *samlConfig},
+	})
+}
+
+// @Summary Save SAML Configuration
+// @Description Creates or updates the SAML single sign-on configuration of the admin's school.
+// @Tags Admin - SAML
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param samlConfigRequest body SAMLConfigRequest true "SAML configuration"
+// @Success 200 {object} CommonResponse{data=SAMLConfigResponseData} "SAML config saved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Router /admin/saml-config [put]
+func (h *SAMLHandler) SaveConfig(c *gin.Context) {
+	var req SAMLConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	samlConfig, err := h.samlService.SaveConfig(adminUUID, services.SAMLConfigInput{
+		Enabled:         req.Enabled,
+		IDPMetadataURL:  req.IDPMetadataURL,
+		IDPMetadataXML:  req.IDPMetadataXML,
+		EmailAttribute:  req.EmailAttribute,
+		NameAttribute:   req.NameAttribute,
+		RoleAttribute:   req.RoleAttribute,
+		RoleMapping:     req.RoleMapping,
+		DefaultRoleName: req.DefaultRoleName,
+		AutoProvision:   req.AutoProvision,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "SAML config saved successfully",
+		Data:    SAMLConfigResponseData{SAMLConfig: *samlConfig},
+	})
+}
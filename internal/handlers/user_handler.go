@@ -1,10 +1,18 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
 	"auth-barniee/internal/services"
+	"auth-barniee/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -23,14 +31,28 @@ type CreateUserRequest struct {
 	Name     string `json:"name" binding:"required" example:"Teacher John"`
 	Email    string `json:"email" binding:"required,email" example:"john@example.com"`
 	Password string `json:"password" binding:"required,min=6" example:"securepassword"`
-	RoleName string `json:"role_name" binding:"required,oneof=teacher student" example:"teacher"`
+	RoleName string `json:"role_name" binding:"required,oneof=teacher student parent" example:"teacher"`
 }
 
 // UpdateUserRequest represents the request body for updating a user.
 type UpdateUserRequest struct {
 	Name     *string `json:"name" example:"John Doe"`
 	Email    *string `json:"email" example:"john.doe@example.com"`
-	RoleName *string `json:"role_name,omitempty" binding:"omitempty,oneof=teacher student admin" example:"student"`
+	RoleName *string `json:"role_name,omitempty" binding:"omitempty,oneof=teacher student parent admin" example:"student"`
+}
+
+// CreateCampusUserRequest represents the request body for an organization admin creating a user at one of its campuses.
+type CreateCampusUserRequest struct {
+	Name     string `json:"name" binding:"required" example:"Teacher John"`
+	Email    string `json:"email" binding:"required,email" example:"john@example.com"`
+	Password string `json:"password" binding:"required,min=6" example:"securepassword"`
+	RoleName string `json:"role_name" binding:"required,oneof=teacher student parent admin" example:"teacher"`
+	SchoolID string `json:"school_id" binding:"required" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+}
+
+// TransferTeacherRequest represents the request body for moving a teacher to another campus.
+type TransferTeacherRequest struct {
+	SchoolID string `json:"school_id" binding:"required" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
 }
 
 // UserDataResponse represents a single user's data for API response.
@@ -43,8 +65,43 @@ type UserListResponse struct {
 	Users []models.User `json:"users"`
 }
 
+// UserPageResponse represents one page of a user list for API response.
+type UserPageResponse struct {
+	Users      []models.User `json:"users"`
+	Total      int64         `json:"total" example:"1250"`
+	NextCursor string        `json:"next_cursor,omitempty"` // Pass as cursor to get the next page; absent on the last page
+}
+
+// StudentUsageResponse represents the student seat usage of a school.
+type StudentUsageResponse struct {
+	Usage services.StudentUsage `json:"usage"`
+}
+
+// StudentQuotaErrorData represents the current usage returned when the student quota is full.
+type StudentQuotaErrorData struct {
+	StudentsUsed       int64 `json:"students_used" example:"50"`
+	MaxStudentsAllowed int   `json:"max_students_allowed" example:"50"`
+}
+
+// respondStudentQuotaExceeded writes a 409 response if err is a student quota error.
+func respondStudentQuotaExceeded(c *gin.Context, err error) bool {
+	var quotaErr *repositories.StudentQuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		return false
+	}
+	c.JSON(http.StatusConflict, CommonResponse{
+		Status:  http.StatusConflict,
+		Message: err.Error(),
+		Data: StudentQuotaErrorData{
+			StudentsUsed:       quotaErr.Used,
+			MaxStudentsAllowed: quotaErr.Limit,
+		},
+	})
+	return true
+}
+
 // @Summary Create Teacher or Student
-// @Description Allows an admin to create a new teacher or student account within their school.
+// @Description Allows an admin to create a new teacher, student or parent account within their school.
 // @Tags Admin - User Management
 // @Security BearerAuth
 // @Accept json
@@ -54,6 +111,7 @@ type UserListResponse struct {
 // @Failure 400 {object} CommonResponse "Bad request"
 // @Failure 401 {object} CommonResponse "Unauthorized"
 // @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 409 {object} CommonResponse{data=StudentQuotaErrorData} "Student quota exceeded"
 // @Failure 500 {object} CommonResponse "Internal server error"
 // @Router /admin/users [post]
 func (h *UserHandler) CreateTeacherOrStudent(c *gin.Context) {
@@ -88,6 +146,9 @@ func (h *UserHandler) CreateTeacherOrStudent(c *gin.Context) {
 
 	user, err := h.userService.CreateTeacherOrStudent(req.Name, req.Email, req.Password, req.RoleName, adminUUID)
 	if err != nil {
+		if respondStudentQuotaExceeded(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, CommonResponse{
 			Status:  http.StatusInternalServerError,
 			Message: err.Error(),
@@ -103,20 +164,92 @@ func (h *UserHandler) CreateTeacherOrStudent(c *gin.Context) {
 	})
 }
 
+// parseUserListQuery reads the list filters, sort and page from the query string.
+func parseUserListQuery(c *gin.Context) (services.UserListQuery, string) {
+	query := services.UserListQuery{
+		RoleName: c.Query("role"),
+		Status:   c.Query("status"),
+		Search:   c.Query("search"),
+		SortBy:   c.Query("sort"),
+		Cursor:   c.Query("cursor"),
+	}
+	if schoolIDParam := c.Query("school_id"); schoolIDParam != "" {
+		schoolID, err := uuid.Parse(schoolIDParam)
+		if err != nil {
+			return query, "Invalid school ID format"
+		}
+		query.SchoolID = &schoolID
+	}
+	if createdFromParam := c.Query("created_from"); createdFromParam != "" {
+		createdFrom, err := time.Parse("2006-01-02", createdFromParam)
+		if err != nil {
+			return query, "Invalid created_from date, expected YYYY-MM-DD"
+		}
+		query.CreatedFrom = &createdFrom
+	}
+	if createdToParam := c.Query("created_to"); createdToParam != "" {
+		createdTo, err := time.Parse("2006-01-02", createdToParam)
+		if err != nil {
+			return query, "Invalid created_to date, expected YYYY-MM-DD"
+		}
+		createdBefore := createdTo.AddDate(0, 0, 1) // created_to includes the whole day
+		query.CreatedBefore = &createdBefore
+	}
+	switch c.DefaultQuery("order", "asc") {
+	case "asc":
+	case "desc":
+		query.Desc = true
+	default:
+		return query, "Invalid order, expected asc or desc"
+	}
+	if limitParam := c.Query("limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil {
+			return query, "Invalid limit, expected a number"
+		}
+		query.Limit = limit
+	}
+	return query, ""
+}
+
+func userListErrorStatus(err error) int {
+	switch err.Error() {
+	case "school not found":
+		return http.StatusNotFound
+	case "admin is not associated with a school", "invalid sort, expected name, email or created_at",
+		"invalid cursor", "cursor does not match the requested sort":
+		return http.StatusBadRequest
+	}
+	if strings.HasPrefix(err.Error(), "role '") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
 // @Summary Get All Users
-// @Description Retrieves a list of all users, with optional filtering by role. Accessible by admins.
+// @Description Retrieves a page of users, with optional filtering, search and sorting. School admins see their own school; under /platform a super admin sees every school or the one given by school_id. Pages are fetched with the next_cursor of the previous page, keeping the same filters and sort.
 // @Tags Admin - User Management
 // @Security BearerAuth
 // @Produce json
-// @Param role query string false "Filter users by role (teacher, student, admin)" example:"teacher"
-// @Success 200 {object} CommonResponse{data=UserListResponse} "Users retrieved successfully"
+// @Param role query string false "Filter users by role (teacher, student, parent, admin)" example:"teacher"
+// @Param status query string false "Filter by account status" Enums(active, suspended, locked, graduated)
+// @Param search query string false "Search by name or email" example:"budi"
+// @Param school_id query string false "Only users of this school (super admin only)" format:"uuid"
+// @Param created_from query string false "Created on or after this date (YYYY-MM-DD)" example:"2026-01-01"
+// @Param created_to query string false "Created on or before this date (YYYY-MM-DD)" example:"2026-12-31"
+// @Param sort query string false "Sort column" Enums(name, email, created_at) default(name)
+// @Param order query string false "Sort order" Enums(asc, desc) default(asc)
+// @Param limit query int false "Users per page (default 50, max 200)" example:"50"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} CommonResponse{data=UserPageResponse} "Users retrieved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
 // @Failure 401 {object} CommonResponse "Unauthorized"
 // @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "School not found"
 // @Failure 500 {object} CommonResponse "Internal server error"
 // @Router /admin/users [get]
+// @Router /platform/users [get]
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	roleName := c.Query("role")
-
 	adminID, exists := c.Get("userID")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, CommonResponse{
@@ -135,11 +268,21 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 		})
 		return
 	}
+	query, errMessage := parseUserListQuery(c)
+	if errMessage != "" {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: errMessage,
+			Data:    nil,
+		})
+		return
+	}
 
-	users, err := h.userService.GetAllUsers(roleName, adminUUID)
+	page, err := h.userService.GetAllUsers(query, adminUUID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, CommonResponse{
-			Status:  http.StatusInternalServerError,
+		statusCode := userListErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
 			Message: err.Error(),
 			Data:    nil,
 		})
@@ -149,8 +292,115 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	c.JSON(http.StatusOK, CommonResponse{
 		Status:  http.StatusOK,
 		Message: "Users retrieved successfully",
-		Data:    UserListResponse{Users: users},
+		Data:    UserPageResponse{Users: page.Users, Total: page.Total, NextCursor: page.NextCursor},
+	})
+}
+
+func userExportErrorStatus(err error) int {
+	switch err.Error() {
+	case "school not found":
+		return http.StatusNotFound
+	case "admin is not associated with a school":
+		return http.StatusBadRequest
+	}
+	if strings.HasPrefix(err.Error(), "unknown export column") || strings.HasPrefix(err.Error(), "role '") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// @Summary Export Users
+// @Description Streams the user list as a CSV or XLSX file, with the same role filter and school scoping as Get All Users. Under /platform a super admin can limit the export to one school.
+// @Tags Admin - User Management
+// @Security BearerAuth
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "File format" Enums(csv, xlsx) default(csv)
+// @Param role query string false "Filter users by role (teacher, student, parent, admin)" example:"student"
+// @Param columns query string false "Comma-separated columns out of id, name, email, role, status, position, whatsapp_number, school_id, created_at (default all)" example:"name,email,role"
+// @Param school_id query string false "Only users of this school (super admin only)" format:"uuid"
+// @Success 200 {file} file "User export"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "School not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/users/export [get]
+// @Router /platform/users/export [get]
+func (h *UserHandler) ExportUsers(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case "csv":
+	case "xlsx":
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid format, expected csv or xlsx",
+			Data:    nil,
+		})
+		return
+	}
+	var columns []string
+	if columnsParam := c.Query("columns"); columnsParam != "" {
+		for _, column := range strings.Split(columnsParam, ",") {
+			columns = append(columns, strings.TrimSpace(column))
+		}
+	}
+	var schoolID *uuid.UUID
+	if schoolParam := c.Query("school_id"); schoolParam != "" {
+		parsed, err := uuid.Parse(schoolParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, CommonResponse{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid school ID format",
+				Data:    nil,
+			})
+			return
+		}
+		schoolID = &parsed
+	}
+
+	err := h.userService.ExportUsers(c.Query("role"), schoolID, columns, adminUUID, func() (utils.SpreadsheetWriter, error) {
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "users-"+time.Now().Format("20060102")+"."+format))
+		c.Status(http.StatusOK)
+		return utils.NewSpreadsheetWriter(format, c.Writer)
 	})
+	if err != nil {
+		if c.Writer.Written() {
+			// Part of the file is already sent, so the error can no longer replace it; the download ends truncated
+			log.Printf("User export failed after streaming started: %v", err)
+			return
+		}
+		c.Writer.Header().Del("Content-Disposition")
+		statusCode := userExportErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
 }
 
 // @Summary Get User By ID
@@ -166,6 +416,8 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 // @Failure 404 {object} CommonResponse "User not found"
 // @Failure 500 {object} CommonResponse "Internal server error"
 // @Router /admin/users/{id} [get]
+// @Router /platform/users/{id} [get]
+// @Router /org/users/{id} [get]
 func (h *UserHandler) GetUserByID(c *gin.Context) {
 	userIDParam := c.Param("id")
 	userID, err := uuid.Parse(userIDParam)
@@ -178,7 +430,26 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.GetUserByID(userID)
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	user, err := h.userService.GetUserByID(userID, adminUUID)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "user not found" {
@@ -214,6 +485,8 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 // @Failure 404 {object} CommonResponse "User not found"
 // @Failure 500 {object} CommonResponse "Internal server error"
 // @Router /admin/users/{id} [put]
+// @Router /platform/users/{id} [put]
+// @Router /org/users/{id} [put]
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	userIDParam := c.Param("id")
 	userID, err := uuid.Parse(userIDParam)
@@ -257,8 +530,12 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 
 	updatedUser, err := h.userService.UpdateUser(userID, adminUUID, req.Name, req.Email, req.RoleName)
 	if err != nil {
+		if respondStudentQuotaExceeded(c, err) {
+			return
+		}
 		statusCode := http.StatusInternalServerError
-		if err.Error() == "user not found" || err.Error() == "email already taken by another user" || err.Error() == "role '...' not found" || err.Error() == "unauthorized: school admin cannot update users outside their school" {
+		if err.Error() == "user not found" || err.Error() == "email already taken by another user" || err.Error() == "role '...' not found" || err.Error() == "unauthorized: school admin cannot update users outside their school" ||
+			err.Error() == "unauthorized: cannot change the school's primary admin" || err.Error() == "unauthorized: cannot change a user with permissions you do not have" {
 			statusCode = http.StatusBadRequest // Or 403 for forbidden
 		}
 		c.JSON(statusCode, CommonResponse{
@@ -277,7 +554,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 }
 
 // @Summary Delete User
-// @Description Deletes a user by their ID. Accessible by admins.
+// @Description Moves a user to the recycle bin. Accessible by admins. The user can no longer log in and their email stays taken; they can be restored until DELETED_USER_RETENTION_DAYS have passed, after which they are purged.
 // @Tags Admin - User Management
 // @Security BearerAuth
 // @Produce json
@@ -289,6 +566,8 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 // @Failure 404 {object} CommonResponse "User not found"
 // @Failure 500 {object} CommonResponse "Internal server error"
 // @Router /admin/users/{id} [delete]
+// @Router /platform/users/{id} [delete]
+// @Router /org/users/{id} [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	userIDParam := c.Param("id")
 	userID, err := uuid.Parse(userIDParam)
@@ -323,7 +602,8 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	err = h.userService.DeleteUser(userID, adminUUID)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		if err.Error() == "user not found" || err.Error() == "cannot delete your own admin account" || err.Error() == "unauthorized: school admin cannot delete users outside their school" {
+		if err.Error() == "user not found" || err.Error() == "cannot delete your own admin account" || err.Error() == "unauthorized: school admin cannot delete users outside their school" ||
+			err.Error() == "unauthorized: cannot change the school's primary admin" || err.Error() == "unauthorized: cannot change a user with permissions you do not have" {
 			statusCode = http.StatusBadRequest // Or 403 for forbidden
 		}
 		c.JSON(statusCode, CommonResponse{
@@ -340,3 +620,295 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		Data:    nil,
 	})
 }
+
+// @Summary Get Student Usage
+// @Description Shows how many student seats of the admin's school are used out of the package limit.
+// @Tags Admin - User Management
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} CommonResponse{data=StudentUsageResponse} "Student usage retrieved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/usage [get]
+func (h *UserHandler) GetStudentUsage(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	usage, err := h.userService.GetStudentUsage(adminUUID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "admin is not associated with a school" {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Student usage retrieved successfully",
+		Data:    StudentUsageResponse{Usage: *usage},
+	})
+}
+
+// @Summary Get Organization Users
+// @Description Retrieves the users of every campus of the organization admin's organization, with optional filtering by role and campus.
+// @Tags Organization
+// @Security BearerAuth
+// @Produce json
+// @Param role query string false "Filter users by role (teacher, student, parent, admin)" example:"teacher"
+// @Param school_id query string false "Filter by campus" format:"uuid"
+// @Success 200 {object} CommonResponse{data=UserListResponse} "Users retrieved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /org/users [get]
+func (h *UserHandler) GetOrganizationUsers(c *gin.Context) {
+	roleName := c.Query("role")
+	var schoolID *uuid.UUID
+	if schoolIDParam := c.Query("school_id"); schoolIDParam != "" {
+		parsed, err := uuid.Parse(schoolIDParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, CommonResponse{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid school ID format",
+				Data:    nil,
+			})
+			return
+		}
+		schoolID = &parsed
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	users, err := h.userService.GetOrganizationUsers(roleName, schoolID, adminUUID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "admin is not associated with an organization" {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Users retrieved successfully",
+		Data:    UserListResponse{Users: users},
+	})
+}
+
+// @Summary Create Campus User
+// @Description Allows an organization admin to create a campus admin, teacher, student or parent account at one of the organization's campuses.
+// @Tags Organization
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body CreateCampusUserRequest true "User details to create"
+// @Success 201 {object} CommonResponse{data=UserDataResponse} "User created successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "School not found"
+// @Failure 409 {object} CommonResponse{data=StudentQuotaErrorData} "Student quota exceeded"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /org/users [post]
+func (h *UserHandler) CreateCampusUser(c *gin.Context) {
+	var req CreateCampusUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	schoolID, err := uuid.Parse(req.SchoolID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid school ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	user, err := h.userService.CreateCampusUser(req.Name, req.Email, req.Password, req.RoleName, schoolID, adminUUID)
+	if err != nil {
+		if respondStudentQuotaExceeded(c, err) {
+			return
+		}
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "school not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "user with this email already exists" {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CommonResponse{
+		Status:  http.StatusCreated,
+		Message: "User created successfully",
+		Data:    UserDataResponse{User: *user},
+	})
+}
+
+// @Summary Transfer Teacher
+// @Description Moves a teacher to another campus of the same organization. A custom role of the old campus is replaced by the system teacher role.
+// @Tags Organization
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format:"uuid" example:"f1e2d3c4-b5a6-9876-5432-10fedcba9876"
+// @Param request body TransferTeacherRequest true "Target campus"
+// @Success 200 {object} CommonResponse{data=UserDataResponse} "Teacher transferred successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "User or school not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /org/users/{id}/transfer [post]
+func (h *UserHandler) TransferTeacher(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	var req TransferTeacherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	schoolID, err := uuid.Parse(req.SchoolID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid school ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	user, err := h.userService.TransferTeacher(userID, schoolID, adminUUID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "user not found", "school not found":
+			statusCode = http.StatusNotFound
+		case "only teachers can be transferred between campuses", "teacher is already at this campus",
+			"teachers can only be transferred between campuses of the same organization":
+			statusCode = http.StatusBadRequest
+		case "unauthorized: you do not have permission to transfer teachers":
+			statusCode = http.StatusForbidden
+		}
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Teacher transferred successfully",
+		Data:    UserDataResponse{User: *user},
+	})
+}
@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxImportFileSize is the largest roster accepted for upload.
+const maxImportFileSize = 5 << 20
+
+type UserImportHandler struct {
+	importService services.UserImportService
+}
+
+func NewUserImportHandler(importService services.UserImportService) *UserImportHandler {
+	return &UserImportHandler{importService: importService}
+}
+
+// UserImportPreviewResponse represents the validation result of an import file for API response.
+type UserImportPreviewResponse struct {
+	Preview services.ImportPreview `json:"preview"`
+}
+
+// UserImportResponse represents a user import and its progress for API response.
+type UserImportResponse struct {
+	Import models.UserImport `json:"import"`
+}
+
+func userImportErrorStatus(err error) int {
+	switch err.Error() {
+	case "import not found", "school not found":
+		return http.StatusNotFound
+	case "import has not finished yet":
+		return http.StatusConflict
+	case "admin is not associated with a school", "unsupported file type, expected .csv or .xlsx":
+		return http.StatusBadRequest
+	}
+	if strings.HasPrefix(err.Error(), "invalid import file") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// readImportFile reads the uploaded roster, writing the error response if it is missing or too large.
+func readImportFile(c *gin.Context) (string, []byte, bool) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "A CSV or XLSX file is required in the 'file' field",
+			Data:    nil,
+		})
+		return "", nil, false
+	}
+	if fileHeader.Size > maxImportFileSize {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: fmt.Sprintf("File is too large, the maximum is %d MB", maxImportFileSize>>20),
+			Data:    nil,
+		})
+		return "", nil, false
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Failed to read uploaded file",
+			Data:    nil,
+		})
+		return "", nil, false
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxImportFileSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Failed to read uploaded file",
+			Data:    nil,
+		})
+		return "", nil, false
+	}
+	return fileHeader.Filename, data, true
+}
+
+// @Summary Preview User Import
+// @Description Validates a CSV or XLSX roster of students and teachers without creating anything. The first row names the columns `name`, `email`, `role` (student or teacher) and optionally `password`; rows without a password get a generated one. Every row is checked for missing fields, duplicate emails within the file and against existing users, and the school's student quota.
+// @Tags Admin - User Management
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Roster (.csv or .xlsx, max 5 MB, max 5000 rows)"
+// @Success 200 {object} CommonResponse{data=UserImportPreviewResponse} "Import file validated"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/user-imports/preview [post]
+func (h *UserImportHandler) PreviewImport(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+	fileName, data, ok := readImportFile(c)
+	if !ok {
+		return
+	}
+
+	preview, err := h.importService.PreviewImport(adminUUID, fileName, data)
+	if err != nil {
+		statusCode := userImportErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Import file validated",
+		Data:    UserImportPreviewResponse{Preview: *preview},
+	})
+}
+
+// @Summary Start User Import
+// @Description Validates a roster like the preview and, when every row is valid, queues it for import in the background. Poll the returned import for progress and download the results file, which contains the generated passwords, once it is completed. If any row is invalid nothing is imported and the preview is returned with status 422.
+// @Tags Admin - User Management
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Roster (.csv or .xlsx, max 5 MB, max 5000 rows)"
+// @Success 202 {object} CommonResponse{data=UserImportResponse} "Import queued"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 422 {object} CommonResponse{data=UserImportPreviewResponse} "Import file has invalid rows"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/user-imports [post]
+func (h *UserImportHandler) StartImport(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+	fileName, data, ok := readImportFile(c)
+	if !ok {
+		return
+	}
+
+	userImport, preview, err := h.importService.StartImport(adminUUID, fileName, data)
+	if err != nil {
+		if errors.Is(err, services.ErrImportHasInvalidRows) {
+			c.JSON(http.StatusUnprocessableEntity, CommonResponse{
+				Status:  http.StatusUnprocessableEntity,
+				Message: err.Error(),
+				Data:    UserImportPreviewResponse{Preview: *preview},
+			})
+			return
+		}
+		statusCode := userImportErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, CommonResponse{
+		Status:  http.StatusAccepted,
+		Message: "Import queued",
+		Data:    UserImportResponse{Import: *userImport},
+	})
+}
+
+// @Summary Get User Import
+// @Description Returns an import of the admin's school with its progress.
+// @Tags Admin - User Management
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Import ID"
+// @Success 200 {object} CommonResponse{data=UserImportResponse} "Import retrieved successfully"
+// @Failure 400 {object} CommonResponse "Invalid import ID format"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Import not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/user-imports/{id} [get]
+func (h *UserImportHandler) GetImport(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+	importID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid import ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	userImport, err := h.importService.GetImport(adminUUID, importID)
+	if err != nil {
+		statusCode := userImportErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Import retrieved successfully",
+		Data:    UserImportResponse{Import: *userImport},
+	})
+}
+
+// @Summary Download User Import Results
+// @Description Downloads a CSV with the outcome of every row of a completed import: the created users with their generated passwords, and the rows that failed with the reason. The passwords are only included in the first download within 7 days of the import finishing; later downloads leave the column empty.
+// @Tags Admin - User Management
+// @Security BearerAuth
+// @Produce text/csv
+// @Param id path string true "Import ID"
+// @Success 200 {file} file "Import results"
+// @Failure 400 {object} CommonResponse "Invalid import ID format"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Import not found"
+// @Failure 409 {object} CommonResponse "Import has not finished yet"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/user-imports/{id}/results [get]
+func (h *UserImportHandler) DownloadResults(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+	importID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid import ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	userImport, body, err := h.importService.GetImportResults(adminUUID, importID)
+	if err != nil {
+		statusCode := userImportErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("import-%s-results.csv", userImport.ID)))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", body)
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-barniee/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type UserRecycleBinHandler struct {
+	recycleBinService services.UserRecycleBinService
+}
+
+func NewUserRecycleBinHandler(recycleBinService services.UserRecycleBinService) *UserRecycleBinHandler {
+	return &UserRecycleBinHandler{recycleBinService: recycleBinService}
+}
+
+// DeletedUserListResponse represents the users in the recycle bin for API response.
+type DeletedUserListResponse struct {
+	DeletedUsers []services.DeletedUser `json:"deleted_users"`
+}
+
+func userRecycleBinErrorStatus(err error) int {
+	switch err.Error() {
+	case "deleted user not found", "school not found":
+		return http.StatusNotFound
+	case "unauthorized: you do not have permission to restore users", "unauthorized: school admin cannot restore users outside their school":
+		return http.StatusForbidden
+	case "user can no longer be restored":
+		return http.StatusGone
+	case "admin is not associated with a school":
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// @Summary List Deleted Users
+// @Description Lists the users in the recycle bin, most recently deleted first, with the time until which each can be restored. School admins see their own school; under /platform a super admin sees every school, or the one given by school_id.
+// @Tags Admin - User Management
+// @Security BearerAuth
+// @Produce json
+// @Param school_id query string false "Only users of this school (super admin only)" format:"uuid"
+// @Success 200 {object} CommonResponse{data=DeletedUserListResponse} "Deleted users retrieved successfully"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "School not found"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/users/deleted [get]
+// @Router /platform/users/deleted [get]
+func (h *UserRecycleBinHandler) GetDeletedUsers(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+	var schoolID *uuid.UUID
+	if schoolIDParam := c.Query("school_id"); schoolIDParam != "" {
+		parsed, err := uuid.Parse(schoolIDParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, CommonResponse{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid school ID format",
+				Data:    nil,
+			})
+			return
+		}
+		schoolID = &parsed
+	}
+
+	deletedUsers, err := h.recycleBinService.GetDeletedUsers(schoolID, adminUUID)
+	if err != nil {
+		statusCode := userRecycleBinErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "Deleted users retrieved successfully",
+		Data:    DeletedUserListResponse{DeletedUsers: deletedUsers},
+	})
+}
+
+// @Summary Restore Deleted User
+// @Description Brings a user back from the recycle bin within the retention period (DELETED_USER_RETENTION_DAYS, 30 days by default). A student is only restored if the school has a free student seat.
+// @Tags Admin - User Management
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID" format:"uuid"
+// @Success 200 {object} CommonResponse{data=UserDataResponse} "User restored successfully"
+// @Failure 400 {object} CommonResponse "Invalid user ID format"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 404 {object} CommonResponse "Deleted user not found"
+// @Failure 409 {object} CommonResponse{data=StudentQuotaErrorData} "Student quota exceeded"
+// @Failure 410 {object} CommonResponse "Retention period has passed"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/users/{id}/restore [post]
+// @Router /platform/users/{id}/restore [post]
+func (h *UserRecycleBinHandler) RestoreUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid user ID format",
+			Data:    nil,
+		})
+		return
+	}
+
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	user, err := h.recycleBinService.RestoreUser(userID, adminUUID)
+	if err != nil {
+		if respondStudentQuotaExceeded(c, err) {
+			return
+		}
+		statusCode := userRecycleBinErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: "User restored successfully",
+		Data:    UserDataResponse{User: *user},
+	})
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type UserStatusHandler struct {
+	userStatusService services.UserStatusService
+}
+
+func NewUserStatusHandler(userStatusService services.UserStatusService) *UserStatusHandler {
+	return &UserStatusHandler{userStatusService: userStatusService}
+}
+
+// SuspendUsersRequest represents the request body for suspending users.
+type SuspendUsersRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" binding:"required,min=1,max=500"`
+	Reason  string      `json:"reason" binding:"required" example:"Pelanggaran tata tertib sekolah"`
+}
+
+// ReactivateUsersRequest represents the request body for reactivating users.
+type ReactivateUsersRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" binding:"required,min=1,max=500"`
+	Reason  string      `json:"reason" example:"Masa skorsing selesai"`
+}
+
+// SetUserStatusRequest represents the request body for giving users any account status.
+type SetUserStatusRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" binding:"required,min=1,max=500"`
+	Status  string      `json:"status" binding:"required,oneof=active suspended locked graduated" example:"graduated"`
+	Reason  string      `json:"reason" example:"Lulus tahun ajaran 2025/2026"`
+}
+
+// UserStatusChangeResponse represents the outcome of a bulk status change for API response.
+type UserStatusChangeResponse struct {
+	Result services.UserStatusChangeResult `json:"result"`
+}
+
+func userStatusErrorStatus(err error) int {
+	switch err.Error() {
+	case "unauthorized: you do not have permission to update users", "unauthorized: cannot change the school's primary admin",
+		"unauthorized: cannot change a user with permissions you do not have":
+		return http.StatusForbidden
+	case "invalid status, expected active, suspended, locked or graduated", "no users given":
+		return http.StatusBadRequest
+	}
+	if strings.HasPrefix(err.Error(), "at most ") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// setStatus applies a status change for the admin in the context and writes the response.
+func (h *UserStatusHandler) setStatus(c *gin.Context, userIDs []uuid.UUID, status, reason, message string) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, CommonResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "Admin ID not found in context",
+			Data:    nil,
+		})
+		return
+	}
+	adminUUID, ok := adminID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, CommonResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Invalid admin ID type in context",
+			Data:    nil,
+		})
+		return
+	}
+
+	result, err := h.userStatusService.SetStatus(userIDs, status, reason, adminUUID)
+	if err != nil {
+		statusCode := userStatusErrorStatus(err)
+		c.JSON(statusCode, CommonResponse{
+			Status:  statusCode,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommonResponse{
+		Status:  http.StatusOK,
+		Message: message,
+		Data:    UserStatusChangeResponse{Result: *result},
+	})
+}
+
+// @Summary Suspend Users
+// @Description Suspends up to 500 users of the admin's school. Suspended users cannot log in, and tokens they already hold stop working on the next request. Users that cannot be suspended, such as users of another school or the admin's own account, are listed under failed; the others are still suspended.
+// @Tags Admin - User Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param suspendUsersRequest body SuspendUsersRequest true "Users and reason"
+// @Success 200 {object} CommonResponse{data=UserStatusChangeResponse} "Users suspended"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/users/suspend [post]
+// @Router /platform/users/suspend [post]
+func (h *UserStatusHandler) SuspendUsers(c *gin.Context) {
+	var req SuspendUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	h.setStatus(c, req.UserIDs, models.UserStatusSuspended, req.Reason, "Users suspended")
+}
+
+// @Summary Reactivate Users
+// @Description Makes up to 500 suspended, locked or graduated users of the admin's school active again. Users that cannot be reactivated are listed under failed.
+// @Tags Admin - User Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param reactivateUsersRequest body ReactivateUsersRequest true "Users and optional reason"
+// @Success 200 {object} CommonResponse{data=UserStatusChangeResponse} "Users reactivated"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/users/reactivate [post]
+// @Router /platform/users/reactivate [post]
+func (h *UserStatusHandler) ReactivateUsers(c *gin.Context) {
+	var req ReactivateUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	h.setStatus(c, req.UserIDs, models.UserStatusActive, req.Reason, "Users reactivated")
+}
+
+// @Summary Set User Status
+// @Description Gives up to 500 users of the admin's school an account status: active, suspended, locked or graduated. Only students can graduate. Only active users can log in. Users that cannot be changed are listed under failed.
+// @Tags Admin - User Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param setUserStatusRequest body SetUserStatusRequest true "Users, status and optional reason"
+// @Success 200 {object} CommonResponse{data=UserStatusChangeResponse} "User status updated"
+// @Failure 400 {object} CommonResponse "Bad request"
+// @Failure 401 {object} CommonResponse "Unauthorized"
+// @Failure 403 {object} CommonResponse "Forbidden"
+// @Failure 500 {object} CommonResponse "Internal server error"
+// @Router /admin/users/status [post]
+// @Router /platform/users/status [post]
+func (h *UserStatusHandler) SetUserStatus(c *gin.Context) {
+	var req SetUserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CommonResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	h.setStatus(c, req.UserIDs, req.Status, req.Reason, "User status updated")
+}
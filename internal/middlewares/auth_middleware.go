@@ -1,10 +1,14 @@
 package middlewares
 
 import (
+	"errors"
 	"net/http"
+	"slices"
 	"strings"
 
 	"auth-barniee/internal/config"
+	"auth-barniee/internal/services"
+	"auth-barniee/internal/utils"
 	"github.com/dgrijalva/jwt-go" // Ensure this is imported for Claims type
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid" // Ensure this is imported for uuid.UUID
@@ -12,13 +16,18 @@ import (
 
 // Define Claims structure if not already defined in utils/jwt.go
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
-	Role   string    `json:"role"`
+	UserID      uuid.UUID   `json:"user_id"`
+	Email       string      `json:"email"`
+	Role        string      `json:"role"`
+	SchoolID    *uuid.UUID  `json:"school_id,omitempty"`
+	StudentIDs  []uuid.UUID `json:"student_ids,omitempty"`
+	Permissions []string    `json:"permissions,omitempty"`
 	jwt.StandardClaims
 }
 
-func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+// AuthMiddleware validates the JWT, rejects users who are no longer active and applies the subscription
+// limits of the user's school on every request.
+func AuthMiddleware(cfg *config.Config, subscription services.SubscriptionService, userStatus services.UserStatusService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -47,14 +56,44 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		if !token.Valid {
+		// Registration-session tokens are signed with the same secret but do not identify a user
+		if !token.Valid || claims.Audience == utils.RegistrationTokenAudience {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
 			return
 		}
 
+		if err := userStatus.CheckActive(claims.UserID); err != nil {
+			var inactiveErr *services.AccountInactiveError
+			if errors.As(err, &inactiveErr) {
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			} else {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Could not verify account: " + err.Error()})
+			}
+			c.Abort()
+			return
+		}
+
+		if claims.SchoolID != nil {
+			school, err := subscription.RefreshSchoolStatus(*claims.SchoolID)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Could not verify school: " + err.Error()})
+				c.Abort()
+				return
+			}
+			readOnly := c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead || c.Request.Method == http.MethodOptions
+			if err := subscription.CheckAccess(school.SubscriptionStatus, claims.Role, readOnly); err != nil {
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+				c.Abort()
+				return
+			}
+			c.Set("subscriptionStatus", school.SubscriptionStatus)
+		}
+
 		c.Set("userID", claims.UserID)
 		c.Set("userRole", claims.Role)
+		c.Set("studentIDs", claims.StudentIDs)
+		c.Set("userPermissions", claims.Permissions)
 		c.Next()
 	}
 }
@@ -86,3 +125,32 @@ func AuthorizeRoles(allowedRoles ...string) gin.HandlerFunc {
 		c.Abort()
 	}
 }
+
+// RequirePermission allows the request only if the token grants every listed permission.
+func RequirePermission(requiredPermissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userPermissions, exists := c.Get("userPermissions")
+		if !exists {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Permission information not found"})
+			c.Abort()
+			return
+		}
+
+		granted, ok := userPermissions.([]string)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid permissions type"})
+			c.Abort()
+			return
+		}
+
+		for _, required := range requiredPermissions {
+			if !slices.Contains(granted, required) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to perform this action"})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
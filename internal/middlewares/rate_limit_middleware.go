@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateWindow counts the requests of one client in the current window.
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// RateLimit allows each client IP at most limit requests per window on the routes it guards. The counts
+// are kept in memory, so each replica enforces the limit on its own.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
+	windows := make(map[string]*rateWindow)
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		key := c.ClientIP()
+
+		mu.Lock()
+		current, ok := windows[key]
+		if !ok || now.Sub(current.start) >= window {
+			// Drop finished windows now and then so clients that went away are forgotten
+			if len(windows) >= 10000 {
+				for k, w := range windows {
+					if now.Sub(w.start) >= window {
+						delete(windows, k)
+					}
+				}
+			}
+			current = &rateWindow{start: now}
+			windows[key] = current
+		}
+		current.count++
+		count, retryAfter := current.count, current.start.Add(window).Sub(now)
+		mu.Unlock()
+
+		if count > limit {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "too many requests, try again later"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"auth-barniee/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// RegistrationTokenHeader carries the token issued by the first registration step.
+const RegistrationTokenHeader = "X-Registration-Token"
+
+// RegistrationSessionMiddleware requires a valid registration-session token and binds the request
+// to the school of that registration.
+func RegistrationSessionMiddleware(regService services.RegistrationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(RegistrationTokenHeader)
+		if token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": RegistrationTokenHeader + " header required"})
+			c.Abort()
+			return
+		}
+
+		schoolID, err := regService.ResolveSession(token)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			switch err.Error() {
+			case "invalid registration session", "registration session expired", "registration session has ended":
+				statusCode = http.StatusUnauthorized
+			}
+			c.JSON(statusCode, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		c.Set("registrationSchoolID", schoolID)
+		c.Next()
+	}
+}
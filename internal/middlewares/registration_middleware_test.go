@@ -0,0 +1,63 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/services"
+	"auth-barniee/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// serve runs a request with the header through middleware and reports the status and whether the handler ran.
+func serve(middleware gin.HandlerFunc, header, value string) (int, bool) {
+	gin.SetMode(gin.TestMode)
+	reached := false
+	router := gin.New()
+	router.GET("/", middleware, func(c *gin.Context) {
+		reached = true
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if value != "" {
+		req.Header.Set(header, value)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code, reached
+}
+
+func TestTokensOnlyOpenTheirOwnRoutes(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "rahasia", RegistrationSessionIdleMinutes: 30}
+	registrationToken, err := utils.GenerateRegistrationToken(uuid.New(), uuid.New(), cfg)
+	if err != nil {
+		t.Fatalf("GenerateRegistrationToken returned error: %v", err)
+	}
+	loginToken, err := utils.GenerateToken(&models.User{ID: uuid.New(), Email: "tu@sekolah.sch.id"}, utils.ExtraClaims{}, cfg)
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+	// Both tokens are refused before any repository is needed
+	auth := AuthMiddleware(cfg, nil, nil)
+	registration := RegistrationSessionMiddleware(services.NewRegistrationService(nil, nil, nil, nil, cfg))
+
+	for name, tc := range map[string]struct {
+		middleware gin.HandlerFunc
+		header     string
+		token      string
+	}{
+		"registration token on a normal route": {auth, "Authorization", "Bearer " + registrationToken},
+		"login token on a registration route":  {registration, RegistrationTokenHeader, loginToken},
+		"no token on a registration route":     {registration, RegistrationTokenHeader, ""},
+	} {
+		if code, reached := serve(tc.middleware, tc.header, tc.token); code != http.StatusUnauthorized || reached {
+			t.Errorf("%s: status %d, handler reached %v; want 401 before the handler", name, code, reached)
+		}
+	}
+}
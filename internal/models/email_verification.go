@@ -7,13 +7,21 @@ import (
 	"gorm.io/gorm"
 )
 
+// Purposes of an email OTP. A code issued for one purpose is never accepted for another.
+const (
+	EmailVerificationPurposeVerify = "email_verification"
+	EmailVerificationPurposeResume = "registration_resume" // Proves ownership of the admin email to resume a registration
+)
+
 type EmailVerification struct {
 	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
 	UserID     uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
 	Email      string    `gorm:"type:varchar(255);not null" json:"email"`
 	OTP        string    `gorm:"type:varchar(6);not null" json:"otp"`
+	Purpose    string    `gorm:"type:varchar(30);not null;default:email_verification" json:"purpose"`
 	ExpiresAt  time.Time `gorm:"not null" json:"expires_at"`
 	IsVerified bool      `gorm:"default:false" json:"is_verified"`
+	Attempts   int       `gorm:"not null;default:0" json:"attempts"` // Wrong guesses so far
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 	User       User      `gorm:"foreignKey:UserID"`
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GuardianLink connects a parent account to a student it may follow. Parent and student may belong to different schools.
+type GuardianLink struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	ParentUserID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_guardian_links_parent_student" json:"parent_user_id"`
+	StudentUserID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_guardian_links_parent_student;index" json:"student_user_id"`
+	Relationship  string    `gorm:"type:varchar(50)" json:"relationship"`
+	CreatedAt     time.Time `json:"created_at"`
+	CreatedBy     uuid.UUID `gorm:"type:uuid" json:"created_by"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	UpdatedBy     uuid.UUID `gorm:"type:uuid" json:"updated_by"`
+	Parent        User      `gorm:"foreignKey:ParentUserID" json:"parent,omitempty"`
+	Student       User      `gorm:"foreignKey:StudentUserID" json:"student,omitempty"`
+}
+
+func (g *GuardianLink) BeforeCreate(tx *gorm.DB) (err error) {
+	if g.ID == uuid.Nil {
+		g.ID = uuid.New()
+	}
+	g.CreatedAt = time.Now()
+	return
+}
+
+func (g *GuardianLink) BeforeUpdate(tx *gorm.DB) (err error) {
+	g.UpdatedAt = time.Now()
+	return
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Invoice states. Issued invoices become overdue once their due date passes.
+const (
+	InvoiceStatusIssued  = "issued"
+	InvoiceStatusOverdue = "overdue"
+	InvoiceStatusPaid    = "paid"
+	InvoiceStatusVoid    = "void"
+)
+
+// Invoice bills a school for one subscription period, or an organization for the current periods
+// of its campuses. The billed party and package details are copied in at issue time so the invoice
+// stays unchanged when the school or catalog changes.
+type Invoice struct {
+	ID               uuid.UUID     `gorm:"type:uuid;primaryKey" json:"id"`
+	Number           string        `gorm:"type:varchar(30);not null;unique" json:"number"` // INV/2026/000123
+	SchoolID         *uuid.UUID    `gorm:"type:uuid;index" json:"school_id,omitempty"`
+	OrganizationID   *uuid.UUID    `gorm:"type:uuid;index" json:"organization_id,omitempty"` // Set on consolidated organization invoices
+	SchoolName       string        `gorm:"type:varchar(255);not null" json:"school_name"`    // Organization name on organization invoices
+	SchoolAddress    string        `gorm:"type:text" json:"school_address"`
+	PackageID        *uuid.UUID    `gorm:"type:uuid" json:"package_id,omitempty"`
+	PackageName      string        `gorm:"type:varchar(100);not null" json:"package_name"`
+	PeriodStart      time.Time     `gorm:"not null" json:"period_start"`
+	PeriodEnd        time.Time     `gorm:"not null" json:"period_end"`
+	StudentCount     int64         `gorm:"not null" json:"student_count"`
+	Subtotal         float64       `gorm:"type:decimal(14,2);not null" json:"subtotal"`
+	TaxRatePercent   int           `gorm:"not null" json:"tax_rate_percent"` // PPN
+	TaxAmount        float64       `gorm:"type:decimal(14,2);not null" json:"tax_amount"`
+	Total            float64       `gorm:"type:decimal(14,2);not null" json:"total"`
+	Status           string        `gorm:"type:varchar(20);not null;index" json:"status"`
+	IssuedAt         time.Time     `gorm:"not null" json:"issued_at"`
+	DueDate          time.Time     `gorm:"not null" json:"due_date"`
+	PaidAt           *time.Time    `json:"paid_at,omitempty"`
+	PaidBy           *uuid.UUID    `gorm:"type:uuid" json:"paid_by,omitempty"`
+	PaymentReference string        `gorm:"type:varchar(255)" json:"payment_reference,omitempty"`
+	Lines            []InvoiceLine `gorm:"foreignKey:InvoiceID" json:"lines"`
+	CreatedAt        time.Time     `json:"created_at"`
+	CreatedBy        uuid.UUID     `gorm:"type:uuid" json:"created_by"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+	UpdatedBy        uuid.UUID     `gorm:"type:uuid" json:"updated_by"`
+}
+
+func (i *Invoice) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	i.CreatedAt = time.Now()
+	return
+}
+
+func (i *Invoice) BeforeUpdate(tx *gorm.DB) (err error) {
+	i.UpdatedAt = time.Now()
+	return
+}
+
+type InvoiceLine struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	InvoiceID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"invoice_id"`
+	Description string     `gorm:"type:varchar(255);not null" json:"description"`
+	Quantity    int64      `gorm:"not null" json:"quantity"`
+	UnitPrice   float64    `gorm:"type:decimal(14,2);not null" json:"unit_price"`
+	Amount      float64    `gorm:"type:decimal(14,2);not null" json:"amount"`
+	SchoolID    *uuid.UUID `gorm:"type:uuid;index" json:"school_id,omitempty"` // Campus billed by this line of an organization invoice
+	PackageID   *uuid.UUID `gorm:"type:uuid" json:"package_id,omitempty"`      // Package billed for that campus
+	PeriodStart *time.Time `json:"period_start,omitempty"`                     // Start of that campus's period, fixed when paid
+}
+
+func (l *InvoiceLine) BeforeCreate(tx *gorm.DB) (err error) {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return
+}
+
+// InvoiceSequence holds the last invoice number issued in a year, so numbers are gapless per year.
+type InvoiceSequence struct {
+	Year       int   `gorm:"primaryKey;autoIncrement:false" json:"year"`
+	LastNumber int64 `gorm:"not null" json:"last_number"`
+}
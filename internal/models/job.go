@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Job run outcomes recorded in JobRun.Status.
+const (
+	JobRunStatusRunning   = "running"
+	JobRunStatusSucceeded = "succeeded"
+	JobRunStatusFailed    = "failed"
+)
+
+// JobLock is a lease on a background job. Only the replica holding an unexpired lease runs the job.
+type JobLock struct {
+	Name        string    `gorm:"type:varchar(100);primaryKey" json:"name"`
+	Owner       string    `gorm:"type:varchar(255);not null" json:"owner"`
+	LockedUntil time.Time `gorm:"not null" json:"locked_until"`
+}
+
+// JobRun records one execution of a background job.
+type JobRun struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	JobName    string     `gorm:"type:varchar(100);not null;index" json:"job_name"`
+	Owner      string     `gorm:"type:varchar(255);not null" json:"owner"`
+	Status     string     `gorm:"type:varchar(20);not null" json:"status"`
+	Summary    string     `gorm:"type:text" json:"summary,omitempty"`
+	Error      string     `gorm:"type:text" json:"error,omitempty"`
+	StartedAt  time.Time  `gorm:"not null;index" json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+func (r *JobRun) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Organization is a foundation (yayasan) that runs several schools as its campuses.
+// Its admins manage users across all campuses; with ConsolidatedBilling the campuses
+// are invoiced together on one organization invoice.
+type Organization struct {
+	ID                  uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Name                string    `gorm:"type:varchar(255);not null" json:"name"`
+	Address             string    `gorm:"type:text" json:"address"`
+	BillingEmail        string    `gorm:"type:varchar(255)" json:"billing_email"`
+	ConsolidatedBilling bool      `gorm:"not null;default:false" json:"consolidated_billing"`
+	CreatedAt           time.Time `json:"created_at"`
+	CreatedBy           uuid.UUID `gorm:"type:uuid" json:"created_by"`
+	UpdatedAt           time.Time `json:"updated_at"`
+	UpdatedBy           uuid.UUID `gorm:"type:uuid" json:"updated_by"`
+	Schools             []School  `gorm:"foreignKey:OrganizationID" json:"schools,omitempty"`
+}
+
+func (o *Organization) BeforeCreate(tx *gorm.DB) (err error) {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	o.CreatedAt = time.Now()
+	return
+}
+
+func (o *Organization) BeforeUpdate(tx *gorm.DB) (err error) {
+	o.UpdatedAt = time.Now()
+	return
+}
@@ -8,17 +8,20 @@ import (
 )
 
 type Package struct {
-	ID              uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
-	Name            string    `gorm:"type:varchar(100);not null;unique" json:"name"`
-	PricePerStudent *float64  `gorm:"type:decimal(10,2)" json:"price_per_student,omitempty"`
-	PricePerYear    *float64  `gorm:"type:decimal(10,2)" json:"price_per_year,omitempty"`
-	DurationDays    *int      `json:"duration_days,omitempty"`
-	MaxStudents     *int      `json:"max_students,omitempty"`
-	Features        string    `gorm:"type:jsonb" json:"features"`
-	CreatedAt       time.Time `json:"created_at"`
-	CreatedBy       uuid.UUID `gorm:"type:uuid" json:"created_by"`
-	UpdatedAt       time.Time `json:"updated_at"`
-	UpdatedBy       uuid.UUID `gorm:"type:uuid" json:"updated_by"`
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	Name            string     `gorm:"type:varchar(100);not null;unique" json:"name"`
+	PricePerStudent *float64   `gorm:"type:decimal(10,2)" json:"price_per_student,omitempty"`
+	PricePerYear    *float64   `gorm:"type:decimal(10,2)" json:"price_per_year,omitempty"`
+	DurationDays    *int       `json:"duration_days,omitempty"`
+	MaxStudents     *int       `json:"max_students,omitempty"`
+	Features        string     `gorm:"type:jsonb" json:"features"`
+	IsTrial         bool       `gorm:"default:false" json:"is_trial"`
+	MultiCampus     bool       `gorm:"default:false" json:"multi_campus"` // Schools on the package may join an organization
+	RetiredAt       *time.Time `json:"retired_at,omitempty"`              // Retired packages stay on existing schools but cannot be selected
+	CreatedAt       time.Time  `json:"created_at"`
+	CreatedBy       uuid.UUID  `gorm:"type:uuid" json:"created_by"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	UpdatedBy       uuid.UUID  `gorm:"type:uuid" json:"updated_by"`
 }
 
 func (p *Package) BeforeCreate(tx *gorm.DB) (err error) {
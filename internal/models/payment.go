@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Payment states, driven by the payment provider's webhook.
+const (
+	PaymentStatusPending = "pending"
+	PaymentStatusPaid    = "paid"
+	PaymentStatusFailed  = "failed"
+	PaymentStatusExpired = "expired"
+)
+
+// Payment is one checkout for a subscription period of a school, or for the prorated difference of
+// a plan change. The price is fixed when the checkout is created; a successful payment applies
+// the period or plan to the school and issues a paid invoice.
+type Payment struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	OrderID           string     `gorm:"type:varchar(50);not null;unique" json:"order_id"` // Sent to the provider and echoed back in webhooks
+	SchoolID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"school_id"`
+	PackageID         uuid.UUID  `gorm:"type:uuid;not null" json:"package_id"`
+	InvoiceID         *uuid.UUID `gorm:"type:uuid" json:"invoice_id,omitempty"`
+	PlanChangeID      *uuid.UUID `gorm:"type:uuid" json:"plan_change_id,omitempty"` // Set when the payment settles a mid-period plan change
+	Provider          string     `gorm:"type:varchar(30);not null" json:"provider"`
+	ProviderReference string     `gorm:"type:varchar(255)" json:"provider_reference,omitempty"`
+	RedirectURL       string     `gorm:"type:text" json:"redirect_url"`
+	PeriodStart       time.Time  `gorm:"not null" json:"period_start"`
+	PeriodEnd         time.Time  `gorm:"not null" json:"period_end"`
+	StudentCount      int64      `gorm:"not null" json:"student_count"`
+	CreditApplied     float64    `gorm:"type:decimal(14,2);not null;default:0" json:"credit_applied"` // Billing credit deducted from the subtotal
+	Subtotal          float64    `gorm:"type:decimal(14,2);not null" json:"subtotal"`
+	TaxRatePercent    int        `gorm:"not null" json:"tax_rate_percent"`
+	TaxAmount         float64    `gorm:"type:decimal(14,2);not null" json:"tax_amount"`
+	Amount            float64    `gorm:"type:decimal(14,2);not null" json:"amount"`
+	Status            string     `gorm:"type:varchar(20);not null;index" json:"status"`
+	PaidAt            *time.Time `json:"paid_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	CreatedBy         uuid.UUID  `gorm:"type:uuid" json:"created_by"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+func (p *Payment) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	p.CreatedAt = time.Now()
+	return
+}
+
+func (p *Payment) BeforeUpdate(tx *gorm.DB) (err error) {
+	p.UpdatedAt = time.Now()
+	return
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Permission names checked by middlewares.RequirePermission and the services.
+const (
+	PermissionUsersRead          = "users:read"
+	PermissionUsersCreate        = "users:create"
+	PermissionUsersUpdate        = "users:update"
+	PermissionUsersDelete        = "users:delete"
+	PermissionSchoolRead         = "school:read"
+	PermissionSchoolUpdate       = "school:update"
+	PermissionGuardiansManage    = "guardians:manage"
+	PermissionRolesManage        = "roles:manage"
+	PermissionBillingRead        = "billing:read"
+	PermissionBillingManage      = "billing:manage"
+	PermissionOrganizationManage = "organization:manage"
+	PermissionPlatformManage     = "platform:manage"
+	PermissionStudentsReadLinked = "students:read_linked"
+)
+
+type Permission struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Name        string    `gorm:"type:varchar(100);unique;not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	CreatedBy   uuid.UUID `gorm:"type:uuid" json:"created_by"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	UpdatedBy   uuid.UUID `gorm:"type:uuid" json:"updated_by"`
+}
+
+func (p *Permission) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	p.CreatedAt = time.Now()
+	return
+}
+
+func (p *Permission) BeforeUpdate(tx *gorm.DB) (err error) {
+	p.UpdatedAt = time.Now()
+	return
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Plan change states. Immediate changes that cost money wait in pending_payment until the
+// payment webhook applies them; changes at the end of the period wait in scheduled.
+const (
+	PlanChangeStatusScheduled      = "scheduled"
+	PlanChangeStatusPendingPayment = "pending_payment"
+	PlanChangeStatusApplied        = "applied"
+	PlanChangeStatusCancelled      = "cancelled"
+	PlanChangeStatusFailed         = "failed"
+)
+
+// PlanChange records a school admin's request to move the school to another package.
+type PlanChange struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	SchoolID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"school_id"`
+	FromPackageID   uuid.UUID  `gorm:"type:uuid;not null" json:"from_package_id"`
+	ToPackageID     uuid.UUID  `gorm:"type:uuid;not null" json:"to_package_id"`
+	AtPeriodEnd     bool       `gorm:"not null;default:false" json:"at_period_end"`
+	EffectiveAt     time.Time  `gorm:"not null;index" json:"effective_at"`
+	Status          string     `gorm:"type:varchar(20);not null;index" json:"status"`
+	ProrationCredit float64    `gorm:"type:decimal(14,2);not null;default:0" json:"proration_credit"`  // Unused share of the current paid period
+	ProratedCharge  float64    `gorm:"type:decimal(14,2);not null;default:0" json:"prorated_charge"`   // Target package for the rest of the period
+	AmountDue       float64    `gorm:"type:decimal(14,2);not null;default:0" json:"amount_due"`        // Including PPN
+	CreditToBalance float64    `gorm:"type:decimal(14,2);not null;default:0" json:"credit_to_balance"` // Credit left over when the target costs less
+	PaymentID       *uuid.UUID `gorm:"type:uuid" json:"payment_id,omitempty"`
+	FailureReason   string     `gorm:"type:text" json:"failure_reason,omitempty"`
+	AppliedAt       *time.Time `json:"applied_at,omitempty"`
+	FromPackage     Package    `gorm:"foreignKey:FromPackageID" json:"from_package"`
+	ToPackage       Package    `gorm:"foreignKey:ToPackageID" json:"to_package"`
+	CreatedAt       time.Time  `json:"created_at"`
+	CreatedBy       uuid.UUID  `gorm:"type:uuid" json:"created_by"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	UpdatedBy       uuid.UUID  `gorm:"type:uuid" json:"updated_by"`
+}
+
+func (c *PlanChange) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	c.CreatedAt = time.Now()
+	return
+}
+
+func (c *PlanChange) BeforeUpdate(tx *gorm.DB) (err error) {
+	c.UpdatedAt = time.Now()
+	return
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PurgedRegistration records an abandoned registration that was deleted, so platform admins can
+// see what was removed after the school row is gone.
+type PurgedRegistration struct {
+	ID                 uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	SchoolID           uuid.UUID  `gorm:"type:uuid;not null" json:"school_id"`
+	SchoolName         string     `gorm:"type:varchar(255);not null" json:"school_name"`
+	AdminEmail         string     `gorm:"type:varchar(255)" json:"admin_email,omitempty"` // Empty when the admin step was never reached
+	RegistrationStatus string     `gorm:"type:varchar(20);not null" json:"registration_status"`
+	StartedAt          time.Time  `gorm:"not null" json:"started_at"`
+	LastActivityAt     time.Time  `gorm:"not null" json:"last_activity_at"`
+	ReminderSentAt     *time.Time `json:"reminder_sent_at,omitempty"`
+	PurgedAt           time.Time  `gorm:"not null;index" json:"purged_at"`
+}
+
+func (p *PurgedRegistration) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RegistrationSession binds the steps of a school registration to the school created in the first step.
+// The client holds it as a signed token; the session expires when no step is taken for a while.
+type RegistrationSession struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	SchoolID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"school_id"`
+	LastActivityAt time.Time  `gorm:"not null" json:"last_activity_at"`
+	EndedAt        *time.Time `json:"ended_at,omitempty"` // Set when the registration is completed
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func (rs *RegistrationSession) BeforeCreate(tx *gorm.DB) (err error) {
+	if rs.ID == uuid.Nil {
+		rs.ID = uuid.New()
+	}
+	rs.CreatedAt = time.Now()
+	return
+}
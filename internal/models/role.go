@@ -7,15 +7,29 @@ import (
 	"gorm.io/gorm"
 )
 
+// Role is either a global system role (SchoolID nil) or a custom role a school builds on top of one (BaseRoleID set).
 type Role struct {
-	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
-	Name        string    `gorm:"type:varchar(50);unique;not null" json:"name"`
-	Description string    `gorm:"type:text" json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	CreatedBy   uuid.UUID `gorm:"type:uuid" json:"created_by"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	UpdatedBy   uuid.UUID `gorm:"type:uuid" json:"updated_by"`
-	Users       []User    `gorm:"foreignKey:RoleID"`
+	ID          uuid.UUID    `gorm:"type:uuid;primaryKey" json:"id"`
+	Name        string       `gorm:"type:varchar(50);not null;uniqueIndex:idx_roles_global_name,where:school_id IS NULL;uniqueIndex:idx_roles_school_name,priority:2,where:school_id IS NOT NULL" json:"name"`
+	Description string       `gorm:"type:text" json:"description"`
+	SchoolID    *uuid.UUID   `gorm:"type:uuid;uniqueIndex:idx_roles_school_name,priority:1" json:"school_id,omitempty"`
+	BaseRoleID  *uuid.UUID   `gorm:"type:uuid" json:"base_role_id,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	CreatedBy   uuid.UUID    `gorm:"type:uuid" json:"created_by"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	UpdatedBy   uuid.UUID    `gorm:"type:uuid" json:"updated_by"`
+	Users       []User       `gorm:"foreignKey:RoleID"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions,omitempty"`
+	BaseRole    *Role        `gorm:"foreignKey:BaseRoleID" json:"base_role,omitempty"`
+}
+
+// SystemName returns the global role this role behaves as: its own name for system roles,
+// the base role's name for custom school roles. BaseRole must be preloaded for custom roles.
+func (r *Role) SystemName() string {
+	if r.BaseRoleID != nil && r.BaseRole != nil {
+		return r.BaseRole.Name
+	}
+	return r.Name
 }
 
 func (r *Role) BeforeCreate(tx *gorm.DB) (err error) {
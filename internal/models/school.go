@@ -7,23 +7,52 @@ import (
 	"gorm.io/gorm"
 )
 
+// Subscription states of a school, see services.SubscriptionService for the transitions.
+const (
+	SubscriptionStatusTrialing  = "trialing"
+	SubscriptionStatusUnpaid    = "unpaid" // A paid package whose first period has not been paid for
+	SubscriptionStatusActive    = "active"
+	SubscriptionStatusPastDue   = "past_due"
+	SubscriptionStatusGrace     = "grace"
+	SubscriptionStatusExpired   = "expired"
+	SubscriptionStatusSuspended = "suspended"
+)
+
+// Registration states of a school. Each registration step moves the school one state forward,
+// see services.RegistrationService.
+const (
+	RegistrationStatusDraft           = "draft"
+	RegistrationStatusAdminAdded      = "admin_added"
+	RegistrationStatusPackageSelected = "package_selected"
+	RegistrationStatusEmailVerified   = "email_verified"
+	RegistrationStatusCompleted       = "completed"
+)
+
 type School struct {
-	ID                    uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
-	Name                  string     `gorm:"type:varchar(255);not null" json:"name"`
-	EducationLevel        string     `gorm:"type:varchar(50);not null" json:"education_level"`
-	Status                string     `gorm:"type:varchar(50);not null" json:"status"`
-	Address               string     `gorm:"type:text;not null" json:"address"`
-	InitialStudentCount   int        `gorm:"not null" json:"initial_student_count"`
-	AdminUserID           uuid.UUID  `gorm:"type:uuid;null" json:"admin_user_id"`
-	PackageID             uuid.UUID  `gorm:"type:uuid;not null" json:"package_id"`
-	SubscriptionStartDate *time.Time `json:"subscription_start_date"`
-	SubscriptionEndDate   *time.Time `json:"subscription_end_date"`
-	MaxStudentsAllowed    int        `gorm:"not null" json:"max_students_allowed"`
-	CreatedAt             time.Time  `json:"created_at"`
-	CreatedBy             uuid.UUID  `gorm:"type:uuid" json:"created_by"`
-	UpdatedAt             time.Time  `json:"updated_at"`
-	UpdatedBy             uuid.UUID  `gorm:"type:uuid" json:"updated_by"`
-	Package               Package    `gorm:"foreignKey:PackageID"`
+	ID                     uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	Name                   string     `gorm:"type:varchar(255);not null" json:"name"`
+	EducationLevel         string     `gorm:"type:varchar(50);not null" json:"education_level"`
+	Status                 string     `gorm:"type:varchar(50);not null" json:"status"`
+	Address                string     `gorm:"type:text;not null" json:"address"`
+	InitialStudentCount    int        `gorm:"not null" json:"initial_student_count"`
+	AdminUserID            uuid.UUID  `gorm:"type:uuid;null" json:"admin_user_id"`
+	OrganizationID         *uuid.UUID `gorm:"type:uuid;index" json:"organization_id,omitempty"` // Set for campuses of a foundation
+	PackageID              uuid.UUID  `gorm:"type:uuid;not null" json:"package_id"`
+	SubscriptionStartDate  *time.Time `json:"subscription_start_date"`
+	SubscriptionEndDate    *time.Time `json:"subscription_end_date"`
+	SubscriptionStatus     string     `gorm:"type:varchar(20);not null;default:active" json:"subscription_status"`
+	MaxStudentsAllowed     int        `gorm:"not null" json:"max_students_allowed"`
+	RegistrationStatus     string     `gorm:"type:varchar(20);not null;default:completed;index" json:"registration_status"`
+	RegistrationReminderAt *time.Time `json:"registration_reminder_at,omitempty"`                          // When the "finish your registration" email was sent
+	BillingCredit          float64    `gorm:"type:decimal(14,2);not null;default:0" json:"billing_credit"` // Left over from downgrades, used by the next checkout
+	SuspendedAt            *time.Time `json:"suspended_at"`
+	SuspendedReason        string     `gorm:"type:text" json:"suspended_reason,omitempty"`
+	SuspendedBy            *uuid.UUID `gorm:"type:uuid" json:"suspended_by,omitempty"`
+	CreatedAt              time.Time  `json:"created_at"`
+	CreatedBy              uuid.UUID  `gorm:"type:uuid" json:"created_by"`
+	UpdatedAt              time.Time  `json:"updated_at"`
+	UpdatedBy              uuid.UUID  `gorm:"type:uuid" json:"updated_by"`
+	Package                Package    `gorm:"foreignKey:PackageID"`
 }
 
 func (s *School) BeforeCreate(tx *gorm.DB) (err error) {
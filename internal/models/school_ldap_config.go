@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SchoolLDAPConfig holds the directory settings used to authenticate a school's staff by LDAP bind.
+type SchoolLDAPConfig struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	SchoolID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"school_id"`
+	Enabled        bool      `gorm:"default:false" json:"enabled"`
+	URL            string    `gorm:"type:varchar(255);not null" json:"url"`
+	StartTLS       bool      `gorm:"default:false" json:"start_tls"`
+	BindDN         string    `gorm:"type:varchar(255)" json:"bind_dn"`
+	BindPassword   string    `gorm:"type:varchar(255)" json:"-"`
+	BaseDN         string    `gorm:"type:varchar(255);not null" json:"base_dn"`
+	UserFilter     string    `gorm:"type:varchar(255);not null" json:"user_filter"` // e.g. (mail=%s)
+	NameAttribute  string    `gorm:"type:varchar(100)" json:"name_attribute"`
+	GroupAttribute string    `gorm:"type:varchar(100)" json:"group_attribute"`
+	AdminGroups    string    `gorm:"type:jsonb" json:"admin_groups"`
+	TeacherGroups  string    `gorm:"type:jsonb" json:"teacher_groups"`
+	CreatedAt      time.Time `json:"created_at"`
+	CreatedBy      uuid.UUID `gorm:"type:uuid" json:"created_by"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	UpdatedBy      uuid.UUID `gorm:"type:uuid" json:"updated_by"`
+}
+
+func (c *SchoolLDAPConfig) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	c.CreatedAt = time.Now()
+	return
+}
+
+func (c *SchoolLDAPConfig) BeforeUpdate(tx *gorm.DB) (err error) {
+	c.UpdatedAt = time.Now()
+	return
+}
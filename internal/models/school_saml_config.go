@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SchoolSAMLConfig holds the identity provider settings a school uses for SAML single sign-on.
+type SchoolSAMLConfig struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	SchoolID        uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"school_id"`
+	Enabled         bool      `gorm:"default:false" json:"enabled"`
+	IDPMetadataURL  string    `gorm:"type:text" json:"idp_metadata_url"`
+	IDPMetadataXML  string    `gorm:"type:text" json:"idp_metadata_xml,omitempty"`
+	EmailAttribute  string    `gorm:"type:varchar(255)" json:"email_attribute"`
+	NameAttribute   string    `gorm:"type:varchar(255)" json:"name_attribute"`
+	RoleAttribute   string    `gorm:"type:varchar(255)" json:"role_attribute"`
+	RoleMapping     string    `gorm:"type:jsonb" json:"role_mapping"` // IdP attribute value -> role name
+	DefaultRoleName string    `gorm:"type:varchar(50)" json:"default_role_name"`
+	AutoProvision   bool      `gorm:"default:false" json:"auto_provision"`
+	CreatedAt       time.Time `json:"created_at"`
+	CreatedBy       uuid.UUID `gorm:"type:uuid" json:"created_by"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	UpdatedBy       uuid.UUID `gorm:"type:uuid" json:"updated_by"`
+}
+
+func (c *SchoolSAMLConfig) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	c.CreatedAt = time.Now()
+	return
+}
+
+func (c *SchoolSAMLConfig) BeforeUpdate(tx *gorm.DB) (err error) {
+	c.UpdatedAt = time.Now()
+	return
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StudentInviteCode is a single-use code a parent redeems to link themselves to a student.
+type StudentInviteCode struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	StudentUserID uuid.UUID  `gorm:"type:uuid;not null;index" json:"student_user_id"`
+	Code          string     `gorm:"type:varchar(16);not null;unique" json:"code"`
+	ExpiresAt     time.Time  `gorm:"not null" json:"expires_at"`
+	RedeemedAt    *time.Time `json:"redeemed_at,omitempty"`
+	RedeemedBy    uuid.UUID  `gorm:"type:uuid" json:"redeemed_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+	CreatedBy     uuid.UUID  `gorm:"type:uuid" json:"created_by"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	UpdatedBy     uuid.UUID  `gorm:"type:uuid" json:"updated_by"`
+}
+
+func (c *StudentInviteCode) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	c.CreatedAt = time.Now()
+	return
+}
+
+func (c *StudentInviteCode) BeforeUpdate(tx *gorm.DB) (err error) {
+	c.UpdatedAt = time.Now()
+	return
+}
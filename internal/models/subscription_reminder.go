@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SubscriptionReminder marks that a trial-expiry reminder was sent, so each one goes out only once per end date.
+type SubscriptionReminder struct {
+	ID                  uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	SchoolID            uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_subscription_reminders_once" json:"school_id"`
+	DaysBefore          int       `gorm:"not null;uniqueIndex:idx_subscription_reminders_once" json:"days_before"`
+	SubscriptionEndDate time.Time `gorm:"not null;uniqueIndex:idx_subscription_reminders_once" json:"subscription_end_date"`
+	SentTo              string    `gorm:"type:varchar(255);not null" json:"sent_to"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+func (r *SubscriptionReminder) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	r.CreatedAt = time.Now()
+	return
+}
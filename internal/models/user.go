@@ -7,26 +7,46 @@ import (
 	"gorm.io/gorm"
 )
 
+// Account states. Only active users can log in or use their tokens.
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusLocked    = "locked"
+	UserStatusGraduated = "graduated" // Students who have left the school
+)
+
 type User struct {
-	ID             uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
-	Name           string    `gorm:"type:varchar(255);not null" json:"name"`
-	Email          string    `gorm:"type:varchar(255);unique;not null" json:"email"`
-	Password       string    `gorm:"type:varchar(255);not null" json:"-"`
-	WhatsappNumber string    `gorm:"type:varchar(20)" json:"whatsapp_number"`
-	Position       string    `gorm:"type:varchar(100)" json:"position"`
-	RoleID         uuid.UUID `gorm:"type:uuid;not null" json:"role_id"`
-	SchoolID       uuid.UUID `gorm:"type:uuid;null" json:"school_id"`
-	Role           Role      `gorm:"foreignKey:RoleID" json:"role"`
-	CreatedAt      time.Time `json:"created_at"`
-	CreatedBy      uuid.UUID `gorm:"type:uuid" json:"created_by"`
-	UpdatedAt      time.Time `json:"updated_at"`
-	UpdatedBy      uuid.UUID `gorm:"type:uuid" json:"updated_by"`
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	Name            string     `gorm:"type:varchar(255);not null" json:"name"`
+	Email           string     `gorm:"type:varchar(255);unique;not null" json:"email"`
+	Password        string     `gorm:"type:varchar(255);not null" json:"-"`
+	WhatsappNumber  string     `gorm:"type:varchar(20)" json:"whatsapp_number"`
+	Position        string     `gorm:"type:varchar(100)" json:"position"`
+	RoleID          uuid.UUID  `gorm:"type:uuid;not null" json:"role_id"`
+	SchoolID        uuid.UUID  `gorm:"type:uuid;null" json:"school_id"`
+	OrganizationID  *uuid.UUID `gorm:"type:uuid;index" json:"organization_id,omitempty"` // Set for organization admins, who have no school
+	Role            Role       `gorm:"foreignKey:RoleID" json:"role"`
+	Status          string     `gorm:"type:varchar(20);not null;default:active;index" json:"status"`
+	StatusReason    string     `gorm:"type:text" json:"status_reason,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+	StatusChangedBy *uuid.UUID `gorm:"type:uuid" json:"status_changed_by,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	CreatedBy       uuid.UUID  `gorm:"type:uuid" json:"created_by"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	UpdatedBy       uuid.UUID  `gorm:"type:uuid" json:"updated_by"`
+	// Deleted users stay in the recycle bin, hidden from every query and unable to log in,
+	// until they are restored or purged. Their email stays taken meanwhile.
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty" swaggertype:"string" format:"date-time"`
+	DeletedBy *uuid.UUID     `gorm:"type:uuid" json:"deleted_by,omitempty"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
 	if u.ID == uuid.Nil {
 		u.ID = uuid.New()
 	}
+	if u.Status == "" {
+		u.Status = UserStatusActive
+	}
 	u.CreatedAt = time.Now()
 	return
 }
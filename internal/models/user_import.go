@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// User import states. Pending imports are picked up by the user-imports background job.
+const (
+	UserImportStatusPending   = "pending"
+	UserImportStatusRunning   = "running"
+	UserImportStatusCompleted = "completed"
+)
+
+// User import row states.
+const (
+	UserImportRowStatusPending = "pending"
+	UserImportRowStatusCreated = "created"
+	UserImportRowStatusFailed  = "failed"
+)
+
+// UserImport is a roster upload that creates the school's students and teachers in the background.
+type UserImport struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	SchoolID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"school_id"`
+	FileName      string     `gorm:"type:varchar(255);not null" json:"file_name"`
+	Status        string     `gorm:"type:varchar(20);not null;index" json:"status"`
+	TotalRows     int        `gorm:"not null" json:"total_rows"`
+	ProcessedRows int        `gorm:"not null;default:0" json:"processed_rows"`
+	CreatedCount  int        `gorm:"not null;default:0" json:"created_count"`
+	FailedCount   int        `gorm:"not null;default:0" json:"failed_count"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+	// PasswordsClearedAt is set once the generated passwords were downloaded or expired; later
+	// results files no longer contain them.
+	PasswordsClearedAt *time.Time `json:"passwords_cleared_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	CreatedBy          uuid.UUID  `gorm:"type:uuid" json:"created_by"`
+	UpdatedAt          time.Time  `json:"updated_at"` // Bumped on every processed row; a running import that stops updating is resumed
+}
+
+func (i *UserImport) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	i.CreatedAt = time.Now()
+	i.UpdatedAt = i.CreatedAt
+	return
+}
+
+// UserImportRow is one validated line of an import file.
+type UserImportRow struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	ImportID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"import_id"`
+	RowNumber         int        `gorm:"not null" json:"row_number"` // Line in the uploaded file, counting the header
+	Name              string     `gorm:"type:varchar(255);not null" json:"name"`
+	Email             string     `gorm:"type:varchar(255);not null" json:"email"`
+	RoleName          string     `gorm:"type:varchar(50);not null" json:"role_name"`
+	Password          string     `gorm:"type:varchar(255)" json:"-"` // Cleared after processing unless it was generated, and once the results file delivers it
+	PasswordGenerated bool       `gorm:"not null;default:false" json:"password_generated"`
+	Status            string     `gorm:"type:varchar(20);not null" json:"status"`
+	Error             string     `gorm:"type:text" json:"error,omitempty"`
+	UserID            *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+}
+
+func (r *UserImportRow) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return
+}
@@ -10,10 +10,10 @@ import (
 
 type EmailVerificationRepository interface {
 	Create(verification *models.EmailVerification) error
-	FindByUserIDAndOTP(userID uuid.UUID, otp string) (*models.EmailVerification, error)
 	Update(verification *models.EmailVerification) error
-	DeleteExpired() error
-	FindByUserID(userID uuid.UUID) (*models.EmailVerification, error)
+	DeleteExpired() (int64, error)
+	// FindByUserID returns the latest code issued to the user for the purpose.
+	FindByUserID(userID uuid.UUID, purpose string) (*models.EmailVerification, error)
 }
 
 type emailVerificationRepository struct {
@@ -28,26 +28,18 @@ func (r *emailVerificationRepository) Create(verification *models.EmailVerificat
 	return r.db.Create(verification).Error
 }
 
-func (r *emailVerificationRepository) FindByUserIDAndOTP(userID uuid.UUID, otp string) (*models.EmailVerification, error) {
-	var verification models.EmailVerification
-	result := r.db.Where("user_id = ? AND otp = ?", userID, otp).First(&verification)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	return &verification, nil
-}
-
 func (r *emailVerificationRepository) Update(verification *models.EmailVerification) error {
 	return r.db.Save(verification).Error
 }
 
-func (r *emailVerificationRepository) DeleteExpired() error {
-	return r.db.Where("expires_at < ?", time.Now()).Delete(&models.EmailVerification{}).Error
+func (r *emailVerificationRepository) DeleteExpired() (int64, error) {
+	result := r.db.Where("expires_at < ?", time.Now()).Delete(&models.EmailVerification{})
+	return result.RowsAffected, result.Error
 }
 
-func (r *emailVerificationRepository) FindByUserID(userID uuid.UUID) (*models.EmailVerification, error) {
+func (r *emailVerificationRepository) FindByUserID(userID uuid.UUID, purpose string) (*models.EmailVerification, error) {
 	var verification models.EmailVerification
-	result := r.db.Where("user_id = ?", userID).Order("created_at DESC").First(&verification)
+	result := r.db.Where("user_id = ? AND purpose = ?", userID, purpose).Order("created_at DESC").First(&verification)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"auth-barniee/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GuardianLinkRepository interface {
+	Create(link *models.GuardianLink) error
+	FindByID(id uuid.UUID) (*models.GuardianLink, error)
+	FindByParentAndStudent(parentUserID, studentUserID uuid.UUID) (*models.GuardianLink, error)
+	FindByParentUserID(parentUserID uuid.UUID) ([]models.GuardianLink, error)
+	FindByStudentUserID(studentUserID uuid.UUID) ([]models.GuardianLink, error)
+	Delete(id uuid.UUID) error
+}
+
+type guardianLinkRepository struct {
+	db *gorm.DB
+}
+
+func NewGuardianLinkRepository(db *gorm.DB) GuardianLinkRepository {
+	return &guardianLinkRepository{db: db}
+}
+
+func (r *guardianLinkRepository) Create(link *models.GuardianLink) error {
+	return r.db.Create(link).Error
+}
+
+func (r *guardianLinkRepository) FindByID(id uuid.UUID) (*models.GuardianLink, error) {
+	var link models.GuardianLink
+	result := r.db.Preload("Parent.Role").Preload("Student.Role").First(&link, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &link, nil
+}
+
+func (r *guardianLinkRepository) FindByParentAndStudent(parentUserID, studentUserID uuid.UUID) (*models.GuardianLink, error) {
+	var link models.GuardianLink
+	result := r.db.Where("parent_user_id = ? AND student_user_id = ?", parentUserID, studentUserID).First(&link)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &link, nil
+}
+
+func (r *guardianLinkRepository) FindByParentUserID(parentUserID uuid.UUID) ([]models.GuardianLink, error) {
+	var links []models.GuardianLink
+	// Links to a student in the recycle bin come back when the student is restored
+	activeUsers := r.db.Model(&models.User{}).Select("id")
+	result := r.db.Preload("Student.Role").Where("parent_user_id = ? AND student_user_id IN (?)", parentUserID, activeUsers).Find(&links)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return links, nil
+}
+
+func (r *guardianLinkRepository) FindByStudentUserID(studentUserID uuid.UUID) ([]models.GuardianLink, error) {
+	var links []models.GuardianLink
+	activeUsers := r.db.Model(&models.User{}).Select("id")
+	result := r.db.Preload("Parent.Role").Where("student_user_id = ? AND parent_user_id IN (?)", studentUserID, activeUsers).Find(&links)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return links, nil
+}
+
+func (r *guardianLinkRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.GuardianLink{}, id).Error
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"auth-barniee/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InvoiceFilter narrows down the invoices returned by FindAll. Zero values are ignored.
+type InvoiceFilter struct {
+	SchoolID       *uuid.UUID
+	OrganizationID *uuid.UUID
+	Status         string
+}
+
+type InvoiceRepository interface {
+	CreateWithNextNumber(invoice *models.Invoice) error
+	FindByID(id uuid.UUID) (*models.Invoice, error)
+	FindAll(filter InvoiceFilter) ([]models.Invoice, error)
+	// ExistsUnpaid reports whether the school has an issued or overdue invoice, either its own or
+	// a line on its organization's invoice.
+	ExistsUnpaid(schoolID uuid.UUID) (bool, error)
+	// MarkPaid records the payment of an issued or overdue invoice, with the periods of its campus
+	// lines. It reports false when the invoice was paid or voided in the meantime.
+	MarkPaid(invoice *models.Invoice) (bool, error)
+	FindPaidCovering(schoolID uuid.UUID, at time.Time) (*models.Invoice, error)
+	Update(invoice *models.Invoice) error
+	MarkOverdue(now time.Time) (int64, error)
+}
+
+type invoiceRepository struct {
+	db *gorm.DB
+}
+
+func NewInvoiceRepository(db *gorm.DB) InvoiceRepository {
+	return &invoiceRepository{db: db}
+}
+
+// CreateWithNextNumber numbers the invoice from its year's sequence and saves it with its lines.
+// The sequence row is incremented in the same transaction, so numbers have no gaps or duplicates.
+func (r *invoiceRepository) CreateWithNextNumber(invoice *models.Invoice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		year := invoice.IssuedAt.Year()
+		var number int64
+		err := tx.Raw(`INSERT INTO invoice_sequences (year, last_number) VALUES (?, 1)
+			ON CONFLICT (year) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+			RETURNING last_number`, year).Scan(&number).Error
+		if err != nil {
+			return err
+		}
+		invoice.Number = fmt.Sprintf("INV/%d/%06d", year, number)
+		return tx.Create(invoice).Error
+	})
+}
+
+func (r *invoiceRepository) FindByID(id uuid.UUID) (*models.Invoice, error) {
+	var invoice models.Invoice
+	result := r.db.Preload("Lines").First(&invoice, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &invoice, nil
+}
+
+func (r *invoiceRepository) FindAll(filter InvoiceFilter) ([]models.Invoice, error) {
+	var invoices []models.Invoice
+	query := r.db.Preload("Lines").Order("issued_at DESC")
+	if filter.SchoolID != nil && *filter.SchoolID != uuid.Nil {
+		query = query.Where("school_id = ?", *filter.SchoolID)
+	}
+	if filter.OrganizationID != nil && *filter.OrganizationID != uuid.Nil {
+		query = query.Where("organization_id = ?", *filter.OrganizationID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	result := query.Find(&invoices)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return invoices, nil
+}
+
+func (r *invoiceRepository) ExistsUnpaid(schoolID uuid.UUID) (bool, error) {
+	var count int64
+	organizationLines := r.db.Model(&models.InvoiceLine{}).Select("invoice_id").Where("school_id = ?", schoolID)
+	err := r.db.Model(&models.Invoice{}).
+		Where("school_id = ? OR id IN (?)", schoolID, organizationLines).
+		Where("status IN ?", []string{models.InvoiceStatusIssued, models.InvoiceStatusOverdue}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *invoiceRepository) MarkPaid(invoice *models.Invoice) (bool, error) {
+	result := r.db.Model(&models.Invoice{}).
+		Where("id = ? AND status IN ?", invoice.ID, []string{models.InvoiceStatusIssued, models.InvoiceStatusOverdue}).
+		Updates(map[string]interface{}{
+			"status":            models.InvoiceStatusPaid,
+			"paid_at":           invoice.PaidAt,
+			"paid_by":           invoice.PaidBy,
+			"payment_reference": invoice.PaymentReference,
+			"period_start":      invoice.PeriodStart,
+			"period_end":        invoice.PeriodEnd,
+			"updated_by":        invoice.UpdatedBy,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected != 1 {
+		return false, nil
+	}
+	for _, line := range invoice.Lines {
+		if line.SchoolID == nil {
+			continue
+		}
+		if err := r.db.Model(&models.InvoiceLine{}).Where("id = ?", line.ID).Update("period_start", line.PeriodStart).Error; err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// FindPaidCovering returns the latest paid invoice whose period contains at.
+func (r *invoiceRepository) FindPaidCovering(schoolID uuid.UUID, at time.Time) (*models.Invoice, error) {
+	var invoice models.Invoice
+	result := r.db.Where("school_id = ? AND status = ? AND period_start <= ? AND period_end > ?", schoolID, models.InvoiceStatusPaid, at, at).
+		Order("issued_at DESC").First(&invoice)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &invoice, nil
+}
+
+func (r *invoiceRepository) Update(invoice *models.Invoice) error {
+	return r.db.Omit("Lines").Save(invoice).Error
+}
+
+func (r *invoiceRepository) MarkOverdue(now time.Time) (int64, error) {
+	result := r.db.Model(&models.Invoice{}).
+		Where("status = ? AND due_date < ?", models.InvoiceStatusIssued, now).
+		Updates(map[string]interface{}{"status": models.InvoiceStatusOverdue, "updated_at": now})
+	return result.RowsAffected, result.Error
+}
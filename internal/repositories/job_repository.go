@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"time"
+
+	"auth-barniee/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRepository interface {
+	AcquireLease(name, owner string, ttl time.Duration) (bool, error)
+	// RenewLease extends a lease owner still holds. It reports false when the lease was lost.
+	RenewLease(name, owner string, ttl time.Duration) (bool, error)
+	// ReleaseLease lets the lease expire at until, or right away if until has passed.
+	ReleaseLease(name, owner string, until time.Time) error
+	CreateRun(run *models.JobRun) error
+	UpdateRun(run *models.JobRun) error
+	FindRuns(jobName string, limit int) ([]models.JobRun, error)
+}
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+// AcquireLease takes the job lease if it is free, expired or already held by owner.
+// The upsert is a single statement, so two replicas can never both get it.
+func (r *jobRepository) AcquireLease(name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	lock := models.JobLock{Name: name, Owner: owner, LockedUntil: now.Add(ttl)}
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"owner", "locked_until"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Or(
+				clause.Lt{Column: clause.Column{Table: "job_locks", Name: "locked_until"}, Value: now},
+				clause.Eq{Column: clause.Column{Table: "job_locks", Name: "owner"}, Value: owner},
+			),
+		}},
+	}).Create(&lock)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *jobRepository) RenewLease(name, owner string, ttl time.Duration) (bool, error) {
+	result := r.db.Model(&models.JobLock{}).
+		Where("name = ? AND owner = ?", name, owner).
+		Update("locked_until", time.Now().Add(ttl))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *jobRepository) ReleaseLease(name, owner string, until time.Time) error {
+	if now := time.Now(); until.Before(now) {
+		until = now
+	}
+	return r.db.Model(&models.JobLock{}).
+		Where("name = ? AND owner = ?", name, owner).
+		Update("locked_until", until).Error
+}
+
+func (r *jobRepository) CreateRun(run *models.JobRun) error {
+	return r.db.Create(run).Error
+}
+
+func (r *jobRepository) UpdateRun(run *models.JobRun) error {
+	return r.db.Save(run).Error
+}
+
+func (r *jobRepository) FindRuns(jobName string, limit int) ([]models.JobRun, error) {
+	var runs []models.JobRun
+	query := r.db.Order("started_at DESC").Limit(limit)
+	if jobName != "" {
+		query = query.Where("job_name = ?", jobName)
+	}
+	result := query.Find(&runs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return runs, nil
+}
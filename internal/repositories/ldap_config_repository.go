@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"auth-barniee/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LDAPConfigRepository interface {
+	FindBySchoolID(schoolID uuid.UUID) (*models.SchoolLDAPConfig, error)
+	Save(ldapConfig *models.SchoolLDAPConfig) error
+}
+
+type ldapConfigRepository struct {
+	db *gorm.DB
+}
+
+func NewLDAPConfigRepository(db *gorm.DB) LDAPConfigRepository {
+	return &ldapConfigRepository{db: db}
+}
+
+func (r *ldapConfigRepository) FindBySchoolID(schoolID uuid.UUID) (*models.SchoolLDAPConfig, error) {
+	var ldapConfig models.SchoolLDAPConfig
+	result := r.db.Where("school_id = ?", schoolID).First(&ldapConfig)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &ldapConfig, nil
+}
+
+func (r *ldapConfigRepository) Save(ldapConfig *models.SchoolLDAPConfig) error {
+	return r.db.Save(ldapConfig).Error
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"auth-barniee/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrganizationRepository interface {
+	Create(organization *models.Organization) error
+	FindByID(id uuid.UUID) (*models.Organization, error) // Preloads the campuses and their packages
+	FindAll() ([]models.Organization, error)
+	Update(organization *models.Organization) error
+}
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+func (r *organizationRepository) Create(organization *models.Organization) error {
+	return r.db.Omit(clause.Associations).Create(organization).Error
+}
+
+func (r *organizationRepository) FindByID(id uuid.UUID) (*models.Organization, error) {
+	var organization models.Organization
+	result := r.db.Preload("Schools", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name")
+	}).Preload("Schools.Package").First(&organization, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &organization, nil
+}
+
+func (r *organizationRepository) FindAll() ([]models.Organization, error) {
+	var organizations []models.Organization
+	result := r.db.Preload("Schools").Order("name").Find(&organizations)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return organizations, nil
+}
+
+// Update saves the organization itself; campuses are attached through the school's OrganizationID.
+func (r *organizationRepository) Update(organization *models.Organization) error {
+	return r.db.Omit(clause.Associations).Save(organization).Error
+}
@@ -7,9 +7,12 @@ import (
 )
 
 type PackageRepository interface {
+	Create(pkg *models.Package) error
 	FindByID(id uuid.UUID) (*models.Package, error)
 	FindByName(name string) (*models.Package, error)
-	FindAll() ([]models.Package, error)
+	FindAll() ([]models.Package, error)    // Includes retired packages
+	FindActive() ([]models.Package, error) // Packages that can still be selected
+	Update(pkg *models.Package) error
 }
 
 type packageRepository struct {
@@ -20,6 +23,10 @@ func NewPackageRepository(db *gorm.DB) PackageRepository {
 	return &packageRepository{db: db}
 }
 
+func (r *packageRepository) Create(pkg *models.Package) error {
+	return r.db.Create(pkg).Error
+}
+
 func (r *packageRepository) FindByID(id uuid.UUID) (*models.Package, error) {
 	var pkg models.Package
 	result := r.db.First(&pkg, id)
@@ -46,3 +53,16 @@ func (r *packageRepository) FindAll() ([]models.Package, error) {
 	}
 	return pkgs, nil
 }
+
+func (r *packageRepository) FindActive() ([]models.Package, error) {
+	var pkgs []models.Package
+	result := r.db.Where("retired_at IS NULL").Find(&pkgs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return pkgs, nil
+}
+
+func (r *packageRepository) Update(pkg *models.Package) error {
+	return r.db.Save(pkg).Error
+}
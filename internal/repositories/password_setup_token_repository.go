@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"time"
+
+	"auth-barniee/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PasswordSetupTokenRepository interface {
+	Create(token *models.PasswordSetupToken) error
+	FindByTokenHash(tokenHash string) (*models.PasswordSetupToken, error)
+	// MarkUsed reports false when the token was already used, so a link works only once under concurrent requests.
+	MarkUsed(id uuid.UUID, at time.Time) (bool, error)
+	// InvalidateForUser marks the user's unused tokens as used, before a new one is issued.
+	InvalidateForUser(userID uuid.UUID, at time.Time) error
+	// DeleteSpent deletes tokens that were used or have expired by now.
+	DeleteSpent(now time.Time) (int64, error)
+}
+
+type passwordSetupTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordSetupTokenRepository(db *gorm.DB) PasswordSetupTokenRepository {
+	return &passwordSetupTokenRepository{db: db}
+}
+
+func (r *passwordSetupTokenRepository) Create(token *models.PasswordSetupToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *passwordSetupTokenRepository) FindByTokenHash(tokenHash string) (*models.PasswordSetupToken, error) {
+	var token models.PasswordSetupToken
+	result := r.db.Where("token_hash = ?", tokenHash).First(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
+func (r *passwordSetupTokenRepository) MarkUsed(id uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.Model(&models.PasswordSetupToken{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *passwordSetupTokenRepository) InvalidateForUser(userID uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.PasswordSetupToken{}).Where("user_id = ? AND used_at IS NULL", userID).Update("used_at", at).Error
+}
+
+func (r *passwordSetupTokenRepository) DeleteSpent(now time.Time) (int64, error) {
+	result := r.db.Where("used_at IS NOT NULL OR expires_at < ?", now).Delete(&models.PasswordSetupToken{})
+	return result.RowsAffected, result.Error
+}
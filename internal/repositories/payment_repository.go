@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"time"
+
+	"auth-barniee/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PaymentRepository interface {
+	Create(payment *models.Payment) error
+	FindByOrderID(orderID string) (*models.Payment, error)
+	Update(payment *models.Payment) error
+	// MarkPaid moves a payment to paid unless it already is. It reports whether this call made
+	// the change, so a webhook delivered twice extends the subscription only once.
+	MarkPaid(id uuid.UUID, providerReference string, paidAt time.Time) (bool, error)
+	UpdateStatus(id uuid.UUID, status string) error
+	DeleteUnpaidBySchoolID(schoolID uuid.UUID) error
+}
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+func (r *paymentRepository) Create(payment *models.Payment) error {
+	return r.db.Create(payment).Error
+}
+
+func (r *paymentRepository) FindByOrderID(orderID string) (*models.Payment, error) {
+	var payment models.Payment
+	result := r.db.Where("order_id = ?", orderID).First(&payment)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) Update(payment *models.Payment) error {
+	return r.db.Save(payment).Error
+}
+
+func (r *paymentRepository) MarkPaid(id uuid.UUID, providerReference string, paidAt time.Time) (bool, error) {
+	result := r.db.Model(&models.Payment{}).
+		Where("id = ? AND status <> ?", id, models.PaymentStatusPaid).
+		Updates(map[string]interface{}{
+			"status":             models.PaymentStatusPaid,
+			"provider_reference": providerReference,
+			"paid_at":            paidAt,
+			"updated_at":         time.Now(),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *paymentRepository) UpdateStatus(id uuid.UUID, status string) error {
+	return r.db.Model(&models.Payment{}).
+		Where("id = ? AND status <> ?", id, models.PaymentStatusPaid).
+		Updates(map[string]interface{}{"status": status, "updated_at": time.Now()}).Error
+}
+
+func (r *paymentRepository) DeleteUnpaidBySchoolID(schoolID uuid.UUID) error {
+	return r.db.Where("school_id = ? AND status <> ?", schoolID, models.PaymentStatusPaid).Delete(&models.Payment{}).Error
+}
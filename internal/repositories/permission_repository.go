@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"auth-barniee/internal/models"
+	"gorm.io/gorm"
+)
+
+type PermissionRepository interface {
+	FindAll() ([]models.Permission, error)
+	FindByNames(names []string) ([]models.Permission, error)
+}
+
+type permissionRepository struct {
+	db *gorm.DB
+}
+
+func NewPermissionRepository(db *gorm.DB) PermissionRepository {
+	return &permissionRepository{db: db}
+}
+
+func (r *permissionRepository) FindAll() ([]models.Permission, error) {
+	var permissions []models.Permission
+	result := r.db.Order("name").Find(&permissions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return permissions, nil
+}
+
+func (r *permissionRepository) FindByNames(names []string) ([]models.Permission, error) {
+	var permissions []models.Permission
+	result := r.db.Where("name IN ?", names).Find(&permissions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return permissions, nil
+}
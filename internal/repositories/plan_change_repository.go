@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"time"
+
+	"auth-barniee/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PlanChangeRepository interface {
+	Create(change *models.PlanChange) error
+	FindByID(id uuid.UUID) (*models.PlanChange, error)
+	FindBySchoolID(schoolID uuid.UUID) ([]models.PlanChange, error)
+	FindScheduledBySchoolID(schoolID uuid.UUID) (*models.PlanChange, error)
+	// FindOpenBySchoolID returns the changes still scheduled or waiting for their payment.
+	FindOpenBySchoolID(schoolID uuid.UUID) ([]models.PlanChange, error)
+	FindDue(now time.Time) ([]models.PlanChange, error) // Scheduled changes whose effective time has passed
+	Update(change *models.PlanChange) error
+}
+
+type planChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewPlanChangeRepository(db *gorm.DB) PlanChangeRepository {
+	return &planChangeRepository{db: db}
+}
+
+func (r *planChangeRepository) Create(change *models.PlanChange) error {
+	return r.db.Omit(clause.Associations).Create(change).Error
+}
+
+func (r *planChangeRepository) FindByID(id uuid.UUID) (*models.PlanChange, error) {
+	var change models.PlanChange
+	result := r.db.Preload("FromPackage").Preload("ToPackage").First(&change, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &change, nil
+}
+
+func (r *planChangeRepository) FindBySchoolID(schoolID uuid.UUID) ([]models.PlanChange, error) {
+	var changes []models.PlanChange
+	result := r.db.Preload("FromPackage").Preload("ToPackage").
+		Where("school_id = ?", schoolID).Order("created_at DESC").Find(&changes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return changes, nil
+}
+
+func (r *planChangeRepository) FindScheduledBySchoolID(schoolID uuid.UUID) (*models.PlanChange, error) {
+	var change models.PlanChange
+	result := r.db.Preload("FromPackage").Preload("ToPackage").
+		Where("school_id = ? AND status = ?", schoolID, models.PlanChangeStatusScheduled).First(&change)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &change, nil
+}
+
+func (r *planChangeRepository) FindOpenBySchoolID(schoolID uuid.UUID) ([]models.PlanChange, error) {
+	var changes []models.PlanChange
+	result := r.db.Preload("FromPackage").Preload("ToPackage").
+		Where("school_id = ? AND status IN ?", schoolID, []string{models.PlanChangeStatusScheduled, models.PlanChangeStatusPendingPayment}).
+		Order("created_at").Find(&changes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return changes, nil
+}
+
+func (r *planChangeRepository) FindDue(now time.Time) ([]models.PlanChange, error) {
+	var changes []models.PlanChange
+	result := r.db.Preload("ToPackage").
+		Where("status = ? AND effective_at <= ?", models.PlanChangeStatusScheduled, now).Find(&changes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return changes, nil
+}
+
+// Update saves the change itself; the preloaded packages are never written.
+func (r *planChangeRepository) Update(change *models.PlanChange) error {
+	return r.db.Omit(clause.Associations).Save(change).Error
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"auth-barniee/internal/models"
+	"gorm.io/gorm"
+)
+
+type PurgedRegistrationRepository interface {
+	Create(purged *models.PurgedRegistration) error
+	FindRecent(limit int) ([]models.PurgedRegistration, error)
+}
+
+type purgedRegistrationRepository struct {
+	db *gorm.DB
+}
+
+func NewPurgedRegistrationRepository(db *gorm.DB) PurgedRegistrationRepository {
+	return &purgedRegistrationRepository{db: db}
+}
+
+func (r *purgedRegistrationRepository) Create(purged *models.PurgedRegistration) error {
+	return r.db.Create(purged).Error
+}
+
+func (r *purgedRegistrationRepository) FindRecent(limit int) ([]models.PurgedRegistration, error) {
+	var purged []models.PurgedRegistration
+	result := r.db.Order("purged_at DESC").Limit(limit).Find(&purged)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return purged, nil
+}
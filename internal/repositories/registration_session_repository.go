@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"time"
+
+	"auth-barniee/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RegistrationSessionRepository interface {
+	Create(session *models.RegistrationSession) error
+	FindByID(id uuid.UUID) (*models.RegistrationSession, error)
+	// Touch records activity on a session that is still open and was last active after idleSince.
+	// It reports false when the session has ended or has been idle for too long.
+	Touch(id uuid.UUID, idleSince, now time.Time) (bool, error)
+	EndForSchool(schoolID uuid.UUID, now time.Time) error
+	// DeleteInactive deletes sessions that have ended or were last active before idleBefore.
+	DeleteInactive(idleBefore time.Time) (int64, error)
+}
+
+type registrationSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewRegistrationSessionRepository(db *gorm.DB) RegistrationSessionRepository {
+	return &registrationSessionRepository{db: db}
+}
+
+func (r *registrationSessionRepository) Create(session *models.RegistrationSession) error {
+	return r.db.Create(session).Error
+}
+
+func (r *registrationSessionRepository) FindByID(id uuid.UUID) (*models.RegistrationSession, error) {
+	var session models.RegistrationSession
+	result := r.db.Where("id = ?", id).First(&session)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+func (r *registrationSessionRepository) Touch(id uuid.UUID, idleSince, now time.Time) (bool, error) {
+	result := r.db.Model(&models.RegistrationSession{}).
+		Where("id = ? AND ended_at IS NULL AND last_activity_at >= ?", id, idleSince).
+		Update("last_activity_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *registrationSessionRepository) EndForSchool(schoolID uuid.UUID, now time.Time) error {
+	return r.db.Model(&models.RegistrationSession{}).
+		Where("school_id = ? AND ended_at IS NULL", schoolID).
+		Update("ended_at", now).Error
+}
+
+func (r *registrationSessionRepository) DeleteInactive(idleBefore time.Time) (int64, error) {
+	result := r.db.Where("ended_at IS NOT NULL OR last_activity_at < ?", idleBefore).Delete(&models.RegistrationSession{})
+	return result.RowsAffected, result.Error
+}
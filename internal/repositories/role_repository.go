@@ -2,11 +2,21 @@ package repositories
 
 import (
 	"auth-barniee/internal/models"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type RoleRepository interface {
-	FindByName(name string) (*models.Role, error)
+	FindByName(name string) (*models.Role, error) // Global system roles only
+	FindByID(id uuid.UUID) (*models.Role, error)
+	FindGlobal() ([]models.Role, error)
+	FindBySchoolID(schoolID uuid.UUID) ([]models.Role, error)
+	FindPermissionNames(roleID uuid.UUID) ([]string, error)
+	Create(role *models.Role) error
+	Update(role *models.Role) error
+	ReplacePermissions(role *models.Role, permissions []models.Permission) error
+	CountUsers(roleID uuid.UUID) (int64, error)
+	Delete(id uuid.UUID) error
 }
 
 type roleRepository struct {
@@ -19,9 +29,77 @@ func NewRoleRepository(db *gorm.DB) RoleRepository {
 
 func (r *roleRepository) FindByName(name string) (*models.Role, error) {
 	var role models.Role
-	result := r.db.Where("name = ?", name).First(&role)
+	result := r.db.Where("name = ? AND school_id IS NULL", name).First(&role)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return &role, nil
 }
+
+func (r *roleRepository) FindByID(id uuid.UUID) (*models.Role, error) {
+	var role models.Role
+	result := r.db.Preload("Permissions").Preload("BaseRole").First(&role, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) FindGlobal() ([]models.Role, error) {
+	var roles []models.Role
+	result := r.db.Preload("Permissions").Where("school_id IS NULL").Order("name").Find(&roles)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) FindBySchoolID(schoolID uuid.UUID) ([]models.Role, error) {
+	var roles []models.Role
+	result := r.db.Preload("Permissions").Preload("BaseRole").Where("school_id = ?", schoolID).Order("name").Find(&roles)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) FindPermissionNames(roleID uuid.UUID) ([]string, error) {
+	var names []string
+	result := r.db.Model(&models.Permission{}).
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Where("role_permissions.role_id = ?", roleID).
+		Order("permissions.name").
+		Pluck("permissions.name", &names)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return names, nil
+}
+
+func (r *roleRepository) Create(role *models.Role) error {
+	return r.db.Create(role).Error
+}
+
+func (r *roleRepository) Update(role *models.Role) error {
+	return r.db.Omit("Permissions", "BaseRole", "Users").Save(role).Error
+}
+
+func (r *roleRepository) ReplacePermissions(role *models.Role, permissions []models.Permission) error {
+	return r.db.Model(role).Association("Permissions").Replace(permissions)
+}
+
+// CountUsers includes users in the recycle bin, which would get the role back if restored.
+func (r *roleRepository) CountUsers(roleID uuid.UUID) (int64, error) {
+	var count int64
+	result := r.db.Unscoped().Model(&models.User{}).Where("role_id = ?", roleID).Count(&count)
+	return count, result.Error
+}
+
+func (r *roleRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM role_permissions WHERE role_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Role{}, id).Error
+	})
+}
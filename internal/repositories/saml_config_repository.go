@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"auth-barniee/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SAMLConfigRepository interface {
+	FindBySchoolID(schoolID uuid.UUID) (*models.SchoolSAMLConfig, error)
+	Save(samlConfig *models.SchoolSAMLConfig) error
+}
+
+type samlConfigRepository struct {
+	db *gorm.DB
+}
+
+func NewSAMLConfigRepository(db *gorm.DB) SAMLConfigRepository {
+	return &samlConfigRepository{db: db}
+}
+
+func (r *samlConfigRepository) FindBySchoolID(schoolID uuid.UUID) (*models.SchoolSAMLConfig, error) {
+	var samlConfig models.SchoolSAMLConfig
+	result := r.db.Where("school_id = ?", schoolID).First(&samlConfig)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &samlConfig, nil
+}
+
+func (r *samlConfigRepository) Save(samlConfig *models.SchoolSAMLConfig) error {
+	return r.db.Save(samlConfig).Error
+}
@@ -1,16 +1,45 @@
 package repositories
 
 import (
+	"time"
+
 	"auth-barniee/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// SchoolFilter narrows down the schools returned by FindAll. Zero values are ignored.
+type SchoolFilter struct {
+	Search         string // Matched against the school name
+	PackageID      *uuid.UUID
+	Status         string // Negeri or Swasta
+	EducationLevel string
+	Suspended      *bool
+	ExpiresBefore  *time.Time
+	ExpiresAfter   *time.Time
+}
+
 type SchoolRepository interface {
 	Create(school *models.School) error
 	FindByID(id uuid.UUID) (*models.School, error)
+	// FindByIDForUpdate locks the school row until the surrounding transaction ends.
+	// Use it inside a UnitOfWork so concurrent writers of the same school run one at a time.
+	FindByIDForUpdate(id uuid.UUID) (*models.School, error)
+	FindAll(filter SchoolFilter) ([]models.School, error)
 	Update(school *models.School) error
 	FindByAdminUserID(adminUserID uuid.UUID) (*models.School, error)
+	// Delete removes the school and everything that belongs to it. Callers must make sure the school
+	// has no billing history first, see HasBillingHistory; unpaid payments are deleted with the school.
+	Delete(id uuid.UUID) error
+	// HasBillingHistory reports whether the school has paid payments or appears on an invoice,
+	// records that must be kept after the school is gone.
+	HasBillingHistory(id uuid.UUID) (bool, error)
+	UpdateSubscriptionStatus(id uuid.UUID, status string) error
+	// FindIncompleteRegistrations returns unfinished registrations without a step since idleBefore.
+	// Registrations with a paid payment are left out; they need a person to look at them.
+	FindIncompleteRegistrations(idleBefore time.Time) ([]models.School, error)
+	MarkRegistrationReminded(id uuid.UUID, at time.Time) error
 }
 
 type schoolRepository struct {
@@ -34,6 +63,50 @@ func (r *schoolRepository) FindByID(id uuid.UUID) (*models.School, error) {
 	return &school, nil
 }
 
+func (r *schoolRepository) FindByIDForUpdate(id uuid.UUID) (*models.School, error) {
+	var school models.School
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Package").First(&school, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &school, nil
+}
+
+func (r *schoolRepository) FindAll(filter SchoolFilter) ([]models.School, error) {
+	var schools []models.School
+	query := r.db.Preload("Package")
+	if filter.Search != "" {
+		query = query.Where("name ILIKE ?", "%"+filter.Search+"%")
+	}
+	if filter.PackageID != nil && *filter.PackageID != uuid.Nil {
+		query = query.Where("package_id = ?", *filter.PackageID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.EducationLevel != "" {
+		query = query.Where("education_level = ?", filter.EducationLevel)
+	}
+	if filter.Suspended != nil {
+		if *filter.Suspended {
+			query = query.Where("suspended_at IS NOT NULL")
+		} else {
+			query = query.Where("suspended_at IS NULL")
+		}
+	}
+	if filter.ExpiresBefore != nil {
+		query = query.Where("subscription_end_date < ?", *filter.ExpiresBefore)
+	}
+	if filter.ExpiresAfter != nil {
+		query = query.Where("subscription_end_date > ?", *filter.ExpiresAfter)
+	}
+	result := query.Order("created_at DESC").Find(&schools)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return schools, nil
+}
+
 func (r *schoolRepository) Update(school *models.School) error {
 	return r.db.Save(school).Error
 }
@@ -46,3 +119,100 @@ func (r *schoolRepository) FindByAdminUserID(adminUserID uuid.UUID) (*models.Sch
 	}
 	return &school, nil
 }
+
+func (r *schoolRepository) UpdateSubscriptionStatus(id uuid.UUID, status string) error {
+	return r.db.Model(&models.School{}).Where("id = ?", id).UpdateColumn("subscription_status", status).Error
+}
+
+func (r *schoolRepository) FindIncompleteRegistrations(idleBefore time.Time) ([]models.School, error) {
+	var schools []models.School
+	paid := r.db.Model(&models.Payment{}).Select("school_id").Where("status = ?", models.PaymentStatusPaid)
+	result := r.db.Where("registration_status <> ? AND updated_at < ? AND id NOT IN (?)", models.RegistrationStatusCompleted, idleBefore, paid).
+		Order("updated_at").
+		Find(&schools)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return schools, nil
+}
+
+// MarkRegistrationReminded does not touch updated_at, which measures the applicant's inactivity.
+func (r *schoolRepository) MarkRegistrationReminded(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.School{}).Where("id = ?", id).UpdateColumn("registration_reminder_at", at).Error
+}
+
+// Delete removes a school together with its users, SSO settings, custom roles and the rows that reference them.
+func (r *schoolRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		schoolUsers := tx.Unscoped().Model(&models.User{}).Select("id").Where("school_id = ?", id)
+		schoolRoles := tx.Model(&models.Role{}).Select("id").Where("school_id = ?", id)
+
+		if err := tx.Where("parent_user_id IN (?) OR student_user_id IN (?)", schoolUsers, schoolUsers).Delete(&models.GuardianLink{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("student_user_id IN (?)", schoolUsers).Delete(&models.StudentInviteCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN (?)", schoolUsers).Delete(&models.EmailVerification{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN (?)", schoolUsers).Delete(&models.PasswordSetupToken{}).Error; err != nil {
+			return err
+		}
+		schoolImports := tx.Model(&models.UserImport{}).Select("id").Where("school_id = ?", id)
+		if err := tx.Where("import_id IN (?)", schoolImports).Delete(&models.UserImportRow{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("school_id = ?", id).Delete(&models.UserImport{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("school_id = ?", id).Delete(&models.User{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM role_permissions WHERE role_id IN (?)", schoolRoles).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("school_id = ?", id).Delete(&models.Role{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("school_id = ?", id).Delete(&models.SchoolSAMLConfig{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("school_id = ?", id).Delete(&models.SchoolLDAPConfig{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("school_id = ?", id).Delete(&models.RegistrationSession{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("school_id = ?", id).Delete(&models.PlanChange{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("school_id = ? AND status <> ?", id, models.PaymentStatusPaid).Delete(&models.Payment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("school_id = ?", id).Delete(&models.SubscriptionReminder{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.School{}, id).Error
+	})
+}
+
+func (r *schoolRepository) HasBillingHistory(id uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.Payment{}).Where("school_id = ? AND status = ?", id, models.PaymentStatusPaid).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	if err := r.db.Model(&models.Invoice{}).Where("school_id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	if err := r.db.Model(&models.InvoiceLine{}).Where("school_id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
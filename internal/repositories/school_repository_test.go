@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIncompleteRegistrationsLeaveOutPaidSchools(t *testing.T) {
+	db, statements := newDryRunDB(t)
+	idleBefore := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	if _, err := NewSchoolRepository(db).FindIncompleteRegistrations(idleBefore); err != nil {
+		t.Fatalf("FindIncompleteRegistrations returned error: %v", err)
+	}
+	want := `registration_status <> 'completed' AND updated_at < '2026-10-18 09:00:00' AND ` +
+		`id NOT IN (SELECT "school_id" FROM "payments" WHERE status = 'paid')`
+	if sql := statements("schools"); !strings.Contains(sql, want) {
+		t.Errorf("incomplete registrations SQL = %s\nwant it to contain %s", sql, want)
+	}
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"time"
+
+	"auth-barniee/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StudentInviteCodeRepository interface {
+	Create(inviteCode *models.StudentInviteCode) error
+	FindByCode(code string) (*models.StudentInviteCode, error)
+	Update(inviteCode *models.StudentInviteCode) error
+	// MarkRedeemed redeems an unused, unexpired code. It reports false if the code was used or expired in the meantime.
+	MarkRedeemed(id uuid.UUID, redeemedBy uuid.UUID, at time.Time) (bool, error)
+	DeleteExpiredUnredeemed() (int64, error) // Redeemed codes are kept as a record of the link
+}
+
+type studentInviteCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewStudentInviteCodeRepository(db *gorm.DB) StudentInviteCodeRepository {
+	return &studentInviteCodeRepository{db: db}
+}
+
+func (r *studentInviteCodeRepository) Create(inviteCode *models.StudentInviteCode) error {
+	return r.db.Create(inviteCode).Error
+}
+
+func (r *studentInviteCodeRepository) FindByCode(code string) (*models.StudentInviteCode, error) {
+	var inviteCode models.StudentInviteCode
+	result := r.db.Where("code = ?", code).First(&inviteCode)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &inviteCode, nil
+}
+
+func (r *studentInviteCodeRepository) Update(inviteCode *models.StudentInviteCode) error {
+	return r.db.Save(inviteCode).Error
+}
+
+func (r *studentInviteCodeRepository) MarkRedeemed(id uuid.UUID, redeemedBy uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.Model(&models.StudentInviteCode{}).
+		Where("id = ? AND redeemed_at IS NULL AND expires_at > ?", id, at).
+		Updates(map[string]interface{}{
+			"redeemed_at": at,
+			"redeemed_by": redeemedBy,
+			"updated_by":  redeemedBy,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *studentInviteCodeRepository) DeleteExpiredUnredeemed() (int64, error) {
+	result := r.db.Where("expires_at < ? AND redeemed_at IS NULL", time.Now()).Delete(&models.StudentInviteCode{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"time"
+
+	"auth-barniee/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SubscriptionReminderRepository interface {
+	Exists(schoolID uuid.UUID, daysBefore int, subscriptionEndDate time.Time) (bool, error)
+	Create(reminder *models.SubscriptionReminder) error
+}
+
+type subscriptionReminderRepository struct {
+	db *gorm.DB
+}
+
+func NewSubscriptionReminderRepository(db *gorm.DB) SubscriptionReminderRepository {
+	return &subscriptionReminderRepository{db: db}
+}
+
+func (r *subscriptionReminderRepository) Exists(schoolID uuid.UUID, daysBefore int, subscriptionEndDate time.Time) (bool, error) {
+	var count int64
+	result := r.db.Model(&models.SubscriptionReminder{}).
+		Where("school_id = ? AND days_before = ? AND subscription_end_date = ?", schoolID, daysBefore, subscriptionEndDate).
+		Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+func (r *subscriptionReminderRepository) Create(reminder *models.SubscriptionReminder) error {
+	return r.db.Create(reminder).Error
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+)
+
+// Repositories are the service's repositories. Inside a unit of work all of them read and
+// write through the same transaction.
+type Repositories struct {
+	Users                 UserRepository
+	Roles                 RoleRepository
+	Schools               SchoolRepository
+	Packages              PackageRepository
+	EmailVerifications    EmailVerificationRepository
+	RegistrationSessions  RegistrationSessionRepository
+	Payments              PaymentRepository
+	PlanChanges           PlanChangeRepository
+	PurgedRegistrations   PurgedRegistrationRepository
+	PasswordSetupTokens   PasswordSetupTokenRepository
+	GuardianLinks         GuardianLinkRepository
+	StudentInviteCodes    StudentInviteCodeRepository
+	Permissions           PermissionRepository
+	SAMLConfigs           SAMLConfigRepository
+	LDAPConfigs           LDAPConfigRepository
+	Invoices              InvoiceRepository
+	Organizations         OrganizationRepository
+	SubscriptionReminders SubscriptionReminderRepository
+	UserImports           UserImportRepository
+	Jobs                  JobRepository
+}
+
+// NewRepositories builds every repository on db, which may be a transaction.
+func NewRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		Users:                 NewUserRepository(db),
+		Roles:                 NewRoleRepository(db),
+		Schools:               NewSchoolRepository(db),
+		Packages:              NewPackageRepository(db),
+		EmailVerifications:    NewEmailVerificationRepository(db),
+		RegistrationSessions:  NewRegistrationSessionRepository(db),
+		Payments:              NewPaymentRepository(db),
+		PlanChanges:           NewPlanChangeRepository(db),
+		PurgedRegistrations:   NewPurgedRegistrationRepository(db),
+		PasswordSetupTokens:   NewPasswordSetupTokenRepository(db),
+		GuardianLinks:         NewGuardianLinkRepository(db),
+		StudentInviteCodes:    NewStudentInviteCodeRepository(db),
+		Permissions:           NewPermissionRepository(db),
+		SAMLConfigs:           NewSAMLConfigRepository(db),
+		LDAPConfigs:           NewLDAPConfigRepository(db),
+		Invoices:              NewInvoiceRepository(db),
+		Organizations:         NewOrganizationRepository(db),
+		SubscriptionReminders: NewSubscriptionReminderRepository(db),
+		UserImports:           NewUserImportRepository(db),
+		Jobs:                  NewJobRepository(db),
+	}
+}
+
+// UnitOfWork runs writes that span several repositories atomically.
+type UnitOfWork interface {
+	// Do runs fn in one database transaction. The transaction is committed when fn returns nil
+	// and rolled back otherwise; the error from fn is returned unchanged.
+	Do(fn func(repos Repositories) error) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+func (u *unitOfWork) Do(fn func(repos Repositories) error) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepositories(tx))
+	})
+}
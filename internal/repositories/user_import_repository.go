@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"time"
+
+	"auth-barniee/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserImportRepository interface {
+	// Create saves the import together with its rows.
+	Create(userImport *models.UserImport, rows []models.UserImportRow) error
+	FindByID(id uuid.UUID) (*models.UserImport, error)
+	// ClaimNext moves the oldest pending import, or a running one not updated since staleBefore, to running.
+	// It returns nil when there is nothing to do. Rows are skipped while locked, so two workers never claim the same import.
+	ClaimNext(staleBefore, now time.Time) (*models.UserImport, error)
+	FindPendingRows(importID uuid.UUID) ([]models.UserImportRow, error)
+	FindRows(importID uuid.UUID) ([]models.UserImportRow, error)
+	// FinishRow stores the outcome of a row and counts it on the import.
+	FinishRow(row *models.UserImportRow) error
+	MarkCompleted(id uuid.UUID, at time.Time) error
+	// ClearPasswords removes the stored passwords of the import's rows. It returns false when they
+	// were already cleared, so only one caller ever receives them.
+	ClearPasswords(id uuid.UUID, at time.Time) (bool, error)
+	// ClearExpiredPasswords removes the stored passwords of imports finished before finishedBefore.
+	ClearExpiredPasswords(finishedBefore, at time.Time) (int64, error)
+}
+
+type userImportRepository struct {
+	db *gorm.DB
+}
+
+func NewUserImportRepository(db *gorm.DB) UserImportRepository {
+	return &userImportRepository{db: db}
+}
+
+func (r *userImportRepository) Create(userImport *models.UserImport, rows []models.UserImportRow) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(userImport).Error; err != nil {
+			return err
+		}
+		for i := range rows {
+			rows[i].ImportID = userImport.ID
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, 500).Error
+	})
+}
+
+func (r *userImportRepository) FindByID(id uuid.UUID) (*models.UserImport, error) {
+	var userImport models.UserImport
+	result := r.db.First(&userImport, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &userImport, nil
+}
+
+func (r *userImportRepository) ClaimNext(staleBefore, now time.Time) (*models.UserImport, error) {
+	var claimed *models.UserImport
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var userImport models.UserImport
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND updated_at < ?)", models.UserImportStatusPending, models.UserImportStatusRunning, staleBefore).
+			Order("created_at").
+			Limit(1).
+			Find(&userImport)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if userImport.StartedAt == nil {
+			userImport.StartedAt = &now
+		}
+		if err := tx.Model(&userImport).Updates(map[string]interface{}{
+			"status":     models.UserImportStatusRunning,
+			"started_at": userImport.StartedAt,
+			"updated_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		userImport.Status = models.UserImportStatusRunning
+		claimed = &userImport
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+func (r *userImportRepository) FindPendingRows(importID uuid.UUID) ([]models.UserImportRow, error) {
+	var rows []models.UserImportRow
+	result := r.db.Where("import_id = ? AND status = ?", importID, models.UserImportRowStatusPending).Order("row_number").Find(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rows, nil
+}
+
+func (r *userImportRepository) FindRows(importID uuid.UUID) ([]models.UserImportRow, error) {
+	var rows []models.UserImportRow
+	result := r.db.Where("import_id = ?", importID).Order("row_number").Find(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rows, nil
+}
+
+func (r *userImportRepository) FinishRow(row *models.UserImportRow) error {
+	counter := "created_count"
+	if row.Status == models.UserImportRowStatusFailed {
+		counter = "failed_count"
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(row).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.UserImport{}).Where("id = ?", row.ImportID).Updates(map[string]interface{}{
+			"processed_rows": gorm.Expr("processed_rows + 1"),
+			counter:          gorm.Expr(counter + " + 1"),
+			"updated_at":     time.Now(),
+		}).Error
+	})
+}
+
+func (r *userImportRepository) MarkCompleted(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.UserImport{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":      models.UserImportStatusCompleted,
+		"finished_at": at,
+		"updated_at":  at,
+	}).Error
+}
+
+func (r *userImportRepository) ClearPasswords(id uuid.UUID, at time.Time) (bool, error) {
+	cleared := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.UserImport{}).
+			Where("id = ? AND passwords_cleared_at IS NULL", id).
+			Update("passwords_cleared_at", at)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return nil
+		}
+		cleared = true
+		return tx.Model(&models.UserImportRow{}).Where("import_id = ? AND password <> ''", id).Update("password", "").Error
+	})
+	return cleared, err
+}
+
+func (r *userImportRepository) ClearExpiredPasswords(finishedBefore, at time.Time) (int64, error) {
+	var cleared int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&models.UserImport{}).Select("id").
+			Where("status = ? AND finished_at < ? AND passwords_cleared_at IS NULL", models.UserImportStatusCompleted, finishedBefore)
+		if err := tx.Model(&models.UserImportRow{}).Where("import_id IN (?) AND password <> ''", expired).Update("password", "").Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.UserImport{}).
+			Where("status = ? AND finished_at < ? AND passwords_cleared_at IS NULL", models.UserImportStatusCompleted, finishedBefore).
+			Update("passwords_cleared_at", at)
+		cleared = result.RowsAffected
+		return result.Error
+	})
+	return cleared, err
+}
@@ -1,18 +1,89 @@
 package repositories
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"auth-barniee/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StudentQuotaExceededError is returned when a school has no student seats left.
+type StudentQuotaExceededError struct {
+	Used  int64
+	Limit int
+}
+
+func (e *StudentQuotaExceededError) Error() string {
+	return fmt.Sprintf("student quota exceeded: %d of %d students used", e.Used, e.Limit)
+}
+
+// UserFilter narrows down the users returned by FindPage, Count and FindAllInBatches. Zero values are ignored.
+type UserFilter struct {
+	RoleID        *uuid.UUID // A system role; users on the schools' custom roles built on it match too
+	SchoolID      *uuid.UUID
+	Status        string
+	Search        string     // Matched against name and email
+	CreatedFrom   *time.Time // Inclusive
+	CreatedBefore *time.Time // Exclusive
+}
+
+// Columns a user page can be sorted by.
+const (
+	UserSortName      = "name"
+	UserSortEmail     = "email"
+	UserSortCreatedAt = "created_at"
 )
 
+// UserPage selects one page of FindPage. Users are ordered by SortBy and then by ID, so users
+// sharing a name or creation time keep a stable order across pages.
+type UserPage struct {
+	SortBy string
+	Desc   bool
+	Limit  int
+	After  *UserCursor // Continue after this user; nil for the first page
+}
+
+// UserCursor is the position of a user in a sorted list.
+type UserCursor struct {
+	Value interface{} // The user's SortBy column: a string, or a time.Time for created_at
+	ID    uuid.UUID
+}
+
 type UserRepository interface {
 	Create(user *models.User) error
 	FindByEmail(email string) (*models.User, error)
+	// FindByEmailIncludingDeleted also finds users in the recycle bin, whose email is still taken.
+	FindByEmailIncludingDeleted(email string) (*models.User, error)
+	// FindExistingEmails returns which of the given emails already belong to a user, deleted or not.
+	FindExistingEmails(emails []string) ([]string, error)
 	FindByID(id uuid.UUID) (*models.User, error)
-	FindAll(roleID *uuid.UUID, schoolID *uuid.UUID) ([]models.User, error) // Added schoolID
+	// FindPage returns up to page.Limit users matching the filter, starting after page.After.
+	FindPage(filter UserFilter, page UserPage) ([]models.User, error)
+	Count(filter UserFilter) (int64, error)
+	// FindAllInBatches hands the users matching the filter to fn a batch at a time,
+	// so callers can stream large schools without holding every user in memory.
+	FindAllInBatches(filter UserFilter, batchSize int, fn func(users []models.User) error) error
+	FindAllInOrganization(organizationID uuid.UUID, roleID *uuid.UUID, schoolID *uuid.UUID) ([]models.User, error)
 	Update(user *models.User) error
-	Delete(id uuid.UUID) error
+	UpdatePassword(id uuid.UUID, hashedPassword string) error
+	UpdateStatus(ids []uuid.UUID, status, reason string, changedBy uuid.UUID) error
+	// Delete moves the user to the recycle bin.
+	Delete(id uuid.UUID, deletedBy uuid.UUID) error
+	// FindDeleted returns the users in the recycle bin, most recently deleted first.
+	FindDeleted(schoolID *uuid.UUID) ([]models.User, error)
+	FindDeletedByID(id uuid.UUID) (*models.User, error)
+	Restore(id uuid.UUID) error
+	RestoreWithinStudentQuota(user *models.User) error // For deleted students
+	// PurgeDeleted permanently removes the users deleted before deletedBefore, with the rows that reference them.
+	PurgeDeleted(deletedBefore time.Time) (int64, error)
+	CountBySchoolIDGroupedByRole(schoolID uuid.UUID) (map[string]int64, error) // Keyed by system role name
+	CountStudentsBySchoolID(schoolID uuid.UUID) (int64, error)
+	CreateWithinStudentQuota(user *models.User) error
+	UpdateWithinStudentQuota(user *models.User) error // For users becoming students
 }
 
 type userRepository struct {
@@ -30,34 +101,137 @@ func (r *userRepository) Create(user *models.User) error {
 func (r *userRepository) FindByEmail(email string) (*models.User, error) {
 	var user models.User
 	// Removed Preload("School") to prevent circular dependency
-	result := r.db.Preload("Role").Where("email = ?", email).First(&user)
+	result := r.db.Preload("Role.BaseRole").Where("email = ?", email).First(&user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return &user, nil
 }
 
+func (r *userRepository) FindByEmailIncludingDeleted(email string) (*models.User, error) {
+	var user models.User
+	result := r.db.Unscoped().Preload("Role.BaseRole").Where("email = ?", email).First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindExistingEmails(emails []string) ([]string, error) {
+	var existing []string
+	if len(emails) == 0 {
+		return existing, nil
+	}
+	result := r.db.Unscoped().Model(&models.User{}).Where("email IN ?", emails).Pluck("email", &existing)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return existing, nil
+}
+
 func (r *userRepository) FindByID(id uuid.UUID) (*models.User, error) {
 	var user models.User
 	// Removed Preload("School") to prevent circular dependency
-	result := r.db.Preload("Role").First(&user, id)
+	result := r.db.Preload("Role.BaseRole").First(&user, id)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return &user, nil
 }
 
-func (r *userRepository) FindAll(roleID *uuid.UUID, schoolID *uuid.UUID) ([]models.User, error) {
+// whereRoleOrBasedOn keeps the users holding the role or a custom role built on it. The custom roles
+// are limited to those of schools, a list or subquery of school IDs, unless it is nil.
+func whereRoleOrBasedOn(query *gorm.DB, roleID uuid.UUID, schools interface{}) *gorm.DB {
+	customRoles := query.Session(&gorm.Session{NewDB: true}).Model(&models.Role{}).Select("id").Where("base_role_id = ?", roleID)
+	if schools != nil {
+		customRoles = customRoles.Where("school_id IN (?)", schools)
+	}
+	return query.Where("(role_id = ? OR role_id IN (?))", roleID, customRoles)
+}
+
+// applyUserFilter adds the conditions of the filter to the query.
+func applyUserFilter(query *gorm.DB, filter UserFilter) *gorm.DB {
+	if filter.RoleID != nil && *filter.RoleID != uuid.Nil {
+		var schools interface{}
+		if filter.SchoolID != nil && *filter.SchoolID != uuid.Nil {
+			schools = []uuid.UUID{*filter.SchoolID}
+		}
+		query = whereRoleOrBasedOn(query, *filter.RoleID, schools)
+	}
+	if filter.SchoolID != nil && *filter.SchoolID != uuid.Nil {
+		query = query.Where("school_id = ?", *filter.SchoolID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Search != "" {
+		search := containsPattern(filter.Search)
+		query = query.Where(`(name ILIKE ? ESCAPE '\' OR email ILIKE ? ESCAPE '\')`, search, search)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *filter.CreatedBefore)
+	}
+	return query
+}
+
+func (r *userRepository) FindPage(filter UserFilter, page UserPage) ([]models.User, error) {
+	switch page.SortBy {
+	case UserSortName, UserSortEmail, UserSortCreatedAt:
+	default:
+		return nil, fmt.Errorf("unsupported user sort column %q", page.SortBy)
+	}
+	direction, comparison := "ASC", ">"
+	if page.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	var users []models.User
+	query := applyUserFilter(r.db.Preload("Role.BaseRole"), filter)
+	if page.After != nil {
+		// Comparing (column, id) rows continues exactly after the cursor, also among users sharing a sort value
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", page.SortBy, comparison), page.After.Value, page.After.ID)
+	}
+	result := query.
+		Order(fmt.Sprintf("%s %s, id %s", page.SortBy, direction, direction)).
+		Limit(page.Limit).
+		Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return users, nil
+}
+
+func (r *userRepository) Count(filter UserFilter) (int64, error) {
+	var count int64
+	if err := applyUserFilter(r.db.Model(&models.User{}), filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *userRepository) FindAllInBatches(filter UserFilter, batchSize int, fn func(users []models.User) error) error {
+	var users []models.User
+	query := applyUserFilter(r.db.Preload("Role.BaseRole"), filter)
+	return query.FindInBatches(&users, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(users)
+	}).Error
+}
+
+// FindAllInOrganization returns the users of every campus of the organization, optionally narrowed to one campus.
+func (r *userRepository) FindAllInOrganization(organizationID uuid.UUID, roleID *uuid.UUID, schoolID *uuid.UUID) ([]models.User, error) {
 	var users []models.User
-	// Removed Preload("School")
-	query := r.db.Preload("Role")
+	campuses := r.db.Model(&models.School{}).Select("id").Where("organization_id = ?", organizationID)
+	query := r.db.Preload("Role.BaseRole").Where("school_id IN (?)", campuses)
 	if roleID != nil && *roleID != uuid.Nil {
-		query = query.Where("role_id = ?", *roleID)
+		query = whereRoleOrBasedOn(query, *roleID, campuses)
 	}
 	if schoolID != nil && *schoolID != uuid.Nil {
 		query = query.Where("school_id = ?", *schoolID)
 	}
-	result := query.Find(&users)
+	result := query.Order("name").Find(&users)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -68,6 +242,172 @@ func (r *userRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
 }
 
-func (r *userRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&models.User{}, id).Error
+func (r *userRepository) UpdatePassword(id uuid.UUID, hashedPassword string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("password", hashedPassword).Error
+}
+
+func (r *userRepository) UpdateStatus(ids []uuid.UUID, status, reason string, changedBy uuid.UUID) error {
+	now := time.Now()
+	return r.db.Model(&models.User{}).Where("id IN ?", ids).UpdateColumns(map[string]interface{}{
+		"status":            status,
+		"status_reason":     reason,
+		"status_changed_at": now,
+		"status_changed_by": changedBy,
+		"updated_at":        now,
+		"updated_by":        changedBy,
+	}).Error
+}
+
+func (r *userRepository) Delete(id uuid.UUID, deletedBy uuid.UUID) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"deleted_at": time.Now(),
+		"deleted_by": deletedBy,
+	}).Error
+}
+
+func (r *userRepository) FindDeleted(schoolID *uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	query := r.db.Unscoped().Preload("Role.BaseRole").Where("deleted_at IS NOT NULL")
+	if schoolID != nil && *schoolID != uuid.Nil {
+		query = query.Where("school_id = ?", *schoolID)
+	}
+	result := query.Order("deleted_at DESC").Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return users, nil
+}
+
+func (r *userRepository) FindDeletedByID(id uuid.UUID) (*models.User, error) {
+	var user models.User
+	result := r.db.Unscoped().Preload("Role.BaseRole").Where("id = ? AND deleted_at IS NOT NULL", id).First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+func (r *userRepository) Restore(id uuid.UUID) error {
+	return restoreUser(r.db, id)
+}
+
+func (r *userRepository) RestoreWithinStudentQuota(user *models.User) error {
+	return r.saveWithinStudentQuota(user, func(tx *gorm.DB) error {
+		return restoreUser(tx, user.ID)
+	})
+}
+
+func restoreUser(db *gorm.DB, id uuid.UUID) error {
+	return db.Unscoped().Model(&models.User{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"deleted_at": nil,
+		"deleted_by": nil,
+	}).Error
+}
+
+func (r *userRepository) PurgeDeleted(deletedBefore time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		expired := tx.Unscoped().Model(&models.User{}).Select("id").Where("deleted_at < ?", deletedBefore)
+
+		if err := tx.Where("parent_user_id IN (?) OR student_user_id IN (?)", expired, expired).Delete(&models.GuardianLink{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("student_user_id IN (?)", expired).Delete(&models.StudentInviteCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN (?)", expired).Delete(&models.EmailVerification{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN (?)", expired).Delete(&models.PasswordSetupToken{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("deleted_at < ?", deletedBefore).Delete(&models.User{})
+		if result.Error != nil {
+			return result.Error
+		}
+		purged = result.RowsAffected
+		return nil
+	})
+	return purged, err
+}
+
+func (r *userRepository) CountBySchoolIDGroupedByRole(schoolID uuid.UUID) (map[string]int64, error) {
+	var rows []struct {
+		RoleName string
+		Count    int64
+	}
+	result := r.db.Model(&models.User{}).
+		Select("COALESCE(base_roles.name, roles.name) AS role_name, COUNT(*) AS count").
+		Joins("JOIN roles ON roles.id = users.role_id").
+		Joins("LEFT JOIN roles base_roles ON base_roles.id = roles.base_role_id").
+		Where("users.school_id = ?", schoolID).
+		Group("COALESCE(base_roles.name, roles.name)").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.RoleName] = row.Count
+	}
+	return counts, nil
+}
+
+// studentsOfSchool selects the users of a school whose role is, or is based on, the student role.
+func studentsOfSchool(db *gorm.DB, schoolID uuid.UUID) *gorm.DB {
+	return db.Model(&models.User{}).
+		Joins("JOIN roles ON roles.id = users.role_id").
+		Joins("LEFT JOIN roles base_roles ON base_roles.id = roles.base_role_id").
+		Where("users.school_id = ? AND COALESCE(base_roles.name, roles.name) = ?", schoolID, "student")
+}
+
+func (r *userRepository) CountStudentsBySchoolID(schoolID uuid.UUID) (int64, error) {
+	var count int64
+	if err := studentsOfSchool(r.db, schoolID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *userRepository) CreateWithinStudentQuota(user *models.User) error {
+	return r.saveWithinStudentQuota(user, func(tx *gorm.DB) error {
+		return tx.Create(user).Error
+	})
+}
+
+func (r *userRepository) UpdateWithinStudentQuota(user *models.User) error {
+	return r.saveWithinStudentQuota(user, func(tx *gorm.DB) error {
+		return tx.Save(user).Error
+	})
+}
+
+// saveWithinStudentQuota locks the school row so concurrent requests for the same school
+// count and write students one at a time. A MaxStudentsAllowed of 0 means unlimited.
+func (r *userRepository) saveWithinStudentQuota(user *models.User, save func(tx *gorm.DB) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var school models.School
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&school, user.SchoolID).Error; err != nil {
+			return err
+		}
+
+		if school.MaxStudentsAllowed > 0 {
+			var used int64
+			if err := studentsOfSchool(tx, school.ID).Where("users.id <> ?", user.ID).Count(&used).Error; err != nil {
+				return err
+			}
+			if used >= int64(school.MaxStudentsAllowed) {
+				return &StudentQuotaExceededError{Used: used, Limit: school.MaxStudentsAllowed}
+			}
+		}
+		return save(tx)
+	})
+}
+
+// likeEscaper escapes the LIKE wildcards so a search term only matches itself.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern returns a LIKE pattern, used with ESCAPE '\', matching values that contain term.
+func containsPattern(term string) string {
+	return "%" + likeEscaper.Replace(term) + "%"
 }
@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newDryRunDB returns a Postgres connection that renders statements without running them, so the
+// queries built by the repositories can be checked without a database. The returned function gives
+// the first query or delete rendered so far on the table, with its arguments inlined.
+func newDryRunDB(t *testing.T) (*gorm.DB, func(table string) string) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: &dryRunConnPool{}}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to open dry-run database: %v", err)
+	}
+	type statement struct{ table, sql string }
+	var statements []statement
+	capture := func(tx *gorm.DB) {
+		statements = append(statements, statement{tx.Statement.Table, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...)})
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("test:capture", capture); err != nil {
+		t.Fatalf("failed to register query capture: %v", err)
+	}
+	if err := db.Callback().Delete().After("gorm:delete").Register("test:capture", capture); err != nil {
+		t.Fatalf("failed to register delete capture: %v", err)
+	}
+	return db, func(table string) string {
+		var rendered []string
+		for _, statement := range statements {
+			// Subqueries selecting ids are rendered on their own before the statement using them
+			if statement.table == table && !strings.HasPrefix(statement.sql, `SELECT "id"`) {
+				return statement.sql
+			}
+			rendered = append(rendered, statement.sql)
+		}
+		t.Fatalf("no statement on %s; rendered %v", table, rendered)
+		return ""
+	}
+}
+
+// dryRunConnPool lets dry-run statements open transactions; nothing reaches it otherwise.
+type dryRunConnPool struct{}
+
+func (*dryRunConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, errDryRun
+}
+
+func (*dryRunConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, errDryRun
+}
+
+func (*dryRunConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, errDryRun
+}
+
+func (*dryRunConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+func (pool *dryRunConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return pool, nil
+}
+
+func (*dryRunConnPool) Commit() error   { return nil }
+func (*dryRunConnPool) Rollback() error { return nil }
+
+var errDryRun = errors.New("dry-run connection cannot run statements")
+
+func TestUserRoleFilterIncludesCustomRolesOfTheSchool(t *testing.T) {
+	db, queries := newDryRunDB(t)
+	teacherRoleID, schoolID := uuid.New(), uuid.New()
+
+	if _, err := NewUserRepository(db).Count(UserFilter{RoleID: &teacherRoleID, SchoolID: &schoolID}); err != nil {
+		t.Fatalf("Count returned error: %v", err)
+	}
+	want := `(role_id = '` + teacherRoleID.String() + `' OR role_id IN (SELECT "id" FROM "roles" WHERE base_role_id = '` +
+		teacherRoleID.String() + `' AND school_id IN ('` + schoolID.String() + `')))`
+	if sql := queries("users"); !strings.Contains(sql, want) {
+		t.Errorf("count SQL = %s\nwant it to contain %s", sql, want)
+	}
+}
+
+func TestOrganizationRoleFilterIncludesCustomRolesOfItsCampuses(t *testing.T) {
+	db, queries := newDryRunDB(t)
+	teacherRoleID, organizationID := uuid.New(), uuid.New()
+
+	if _, err := NewUserRepository(db).FindAllInOrganization(organizationID, &teacherRoleID, nil); err != nil {
+		t.Fatalf("FindAllInOrganization returned error: %v", err)
+	}
+	want := `base_role_id = '` + teacherRoleID.String() + `' AND school_id IN (SELECT "id" FROM "schools" WHERE organization_id = '` +
+		organizationID.String() + `'`
+	if sql := queries("users"); !strings.Contains(sql, want) {
+		t.Errorf("organization users SQL = %s\nwant it to contain %s", sql, want)
+	}
+}
+
+func TestPurgeDeletedRemovesTheRowsLinkedToExpiredUsers(t *testing.T) {
+	db, statements := newDryRunDB(t)
+	deletedBefore := time.Date(2026, 9, 19, 0, 0, 0, 0, time.UTC)
+
+	if _, err := NewUserRepository(db).PurgeDeleted(deletedBefore); err != nil {
+		t.Fatalf("PurgeDeleted returned error: %v", err)
+	}
+	expired := `(SELECT "id" FROM "users" WHERE deleted_at < '2026-09-19 00:00:00')`
+	for table, want := range map[string]string{
+		"guardian_links":        `parent_user_id IN ` + expired + ` OR student_user_id IN ` + expired,
+		"student_invite_codes":  `student_user_id IN ` + expired,
+		"email_verifications":   `user_id IN ` + expired,
+		"password_setup_tokens": `user_id IN ` + expired,
+		"users":                 `DELETE FROM "users" WHERE deleted_at < '2026-09-19 00:00:00'`,
+	} {
+		if sql := statements(table); !strings.Contains(sql, want) {
+			t.Errorf("%s purge SQL = %s\nwant it to contain %s", table, sql, want)
+		}
+	}
+}
+
+func TestFindPageContinuesAfterTheCursorAmongTies(t *testing.T) {
+	afterID := uuid.New()
+
+	for _, tc := range []struct {
+		desc       bool
+		comparison string
+		order      string
+	}{
+		{false, `(name, id) > ('Budi', '` + afterID.String() + `')`, `ORDER BY name ASC, id ASC`},
+		{true, `(name, id) < ('Budi', '` + afterID.String() + `')`, `ORDER BY name DESC, id DESC`},
+	} {
+		db, statements := newDryRunDB(t)
+		page := UserPage{SortBy: UserSortName, Desc: tc.desc, Limit: 51, After: &UserCursor{Value: "Budi", ID: afterID}}
+		if _, err := NewUserRepository(db).FindPage(UserFilter{}, page); err != nil {
+			t.Fatalf("FindPage returned error: %v", err)
+		}
+		sql := statements("users")
+		if !strings.Contains(sql, tc.comparison) || !strings.Contains(sql, tc.order+` LIMIT 51`) {
+			t.Errorf("desc %v: page SQL = %s\nwant %s and %s", tc.desc, sql, tc.comparison, tc.order)
+		}
+	}
+}
+
+func TestFindPageRejectsUnknownSortColumns(t *testing.T) {
+	db, _ := newDryRunDB(t)
+
+	if _, err := NewUserRepository(db).FindPage(UserFilter{}, UserPage{SortBy: "password", Limit: 10}); err == nil {
+		t.Error("FindPage sorted by an unsupported column")
+	}
+}
+
+func TestUserSearchMatchesWildcardsLiterally(t *testing.T) {
+	for term, want := range map[string]string{
+		"budi":       `%budi%`,
+		"100%":       `%100\%%`,
+		"nama_siswa": `%nama\_siswa%`,
+		`c:\data`:    `%c:\\data%`,
+	} {
+		if got := containsPattern(term); got != want {
+			t.Errorf("containsPattern(%q) = %q, want %q", term, got, want)
+		}
+	}
+
+	db, statements := newDryRunDB(t)
+	if _, err := NewUserRepository(db).Count(UserFilter{Search: "50%_off"}); err != nil {
+		t.Fatalf("Count returned error: %v", err)
+	}
+	want := `(name ILIKE '%50\%\_off%' ESCAPE '\' OR email ILIKE '%50\%\_off%' ESCAPE '\')`
+	if sql := statements("users"); !strings.Contains(sql, want) {
+		t.Errorf("search SQL = %s\nwant it to contain %s", sql, want)
+	}
+}
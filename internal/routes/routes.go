@@ -1,10 +1,12 @@
 package routes
 
 import (
+	"time"
+
 	"auth-barniee/internal/config"
 	"auth-barniee/internal/handlers"
 	"auth-barniee/internal/middlewares"
-	"auth-barniee/internal/repositories"
+	"auth-barniee/internal/models"
 	"auth-barniee/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -13,30 +15,38 @@ import (
 	"github.com/gin-contrib/cors"
 )
 
-func SetupAuthRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
+// otpRequestsPerMinute is how often one client may request or submit registration OTPs.
+const otpRequestsPerMinute = 10
+
+func SetupAuthRoutes(r *gin.Engine, db *gorm.DB, svc *services.Services, cfg *config.Config) {
 	configCors := cors.DefaultConfig()
 	configCors.AllowAllOrigins = true
 	configCors.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
-	configCors.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+	configCors.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", middlewares.RegistrationTokenHeader}
 	configCors.ExposeHeaders = []string{"Content-Length"}
 	configCors.AllowCredentials = true
 	configCors.MaxAge = 30
 
 	r.Use(cors.New(configCors))
 
-	userRepo := repositories.NewUserRepository(db)
-	roleRepo := repositories.NewRoleRepository(db)
-	schoolRepo := repositories.NewSchoolRepository(db)
-	packageRepo := repositories.NewPackageRepository(db)
-	emailVerifyRepo := repositories.NewEmailVerificationRepository(db)
-
-	authService := services.NewAuthService(userRepo, roleRepo, schoolRepo, cfg) // Updated
-	userService := services.NewUserService(userRepo, roleRepo)
-	registrationService := services.NewRegistrationService(schoolRepo, userRepo, roleRepo, packageRepo, emailVerifyRepo, cfg)
-
-	authHandler := handlers.NewAuthHandler(authService)
-	userHandler := handlers.NewUserHandler(userService)
-	registrationHandler := handlers.NewRegistrationHandler(registrationService)
+	authHandler := handlers.NewAuthHandler(svc.Auth)
+	userHandler := handlers.NewUserHandler(svc.User)
+	userImportHandler := handlers.NewUserImportHandler(svc.UserImport)
+	userRecycleBinHandler := handlers.NewUserRecycleBinHandler(svc.UserRecycleBin)
+	userStatusHandler := handlers.NewUserStatusHandler(svc.UserStatus)
+	registrationHandler := handlers.NewRegistrationHandler(svc.Registration)
+	samlHandler := handlers.NewSAMLHandler(svc.SAML)
+	ldapHandler := handlers.NewLDAPHandler(svc.LDAPConfig)
+	guardianHandler := handlers.NewGuardianHandler(svc.Guardian)
+	roleHandler := handlers.NewRoleHandler(svc.Role)
+	platformSchoolHandler := handlers.NewPlatformSchoolHandler(svc.PlatformSchool)
+	platformPackageHandler := handlers.NewPlatformPackageHandler(svc.Package)
+	platformJobHandler := handlers.NewPlatformJobHandler(svc.Job)
+	platformRegistrationHandler := handlers.NewPlatformRegistrationHandler(svc.RegistrationCleanup)
+	invoiceHandler := handlers.NewInvoiceHandler(svc.Invoice)
+	paymentHandler := handlers.NewPaymentHandler(svc.Payment)
+	planHandler := handlers.NewPlanHandler(svc.PlanChange)
+	organizationHandler := handlers.NewOrganizationHandler(svc.Organization)
 
 	r.Use(func(c *gin.Context) {
 		c.Set("db", db)
@@ -46,34 +56,155 @@ func SetupAuthRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
 	public := r.Group("/api/v1")
 	{
 		public.POST("/auth/login", authHandler.Login)
+		public.POST("/auth/set-password", authHandler.SetPassword)
+
+		saml := public.Group("/auth/saml/:school_id")
+		{
+			saml.GET("/metadata", samlHandler.Metadata)
+			saml.GET("/login", samlHandler.Login)
+			saml.POST("/acs", samlHandler.AssertionConsumerService)
+		}
 
 		registration := public.Group("/register")
 		{
 			registration.POST("/school-info", registrationHandler.RegisterSchoolInfo)
-			registration.POST("/admin-info", registrationHandler.RegisterAdminInfo)
 			registration.GET("/packages", registrationHandler.GetAllPackages)
-			registration.POST("/select-package", registrationHandler.SelectPackage)
-			registration.POST("/email-verification/request-otp", registrationHandler.RequestEmailVerificationOTP)
-			registration.POST("/email-verification/verify-otp", registrationHandler.VerifyEmailOTP)
-			registration.POST("/complete", registrationHandler.CompleteRegistration)
+			// Codes are six digits, so guessing is also limited per client
+			otpLimit := middlewares.RateLimit(otpRequestsPerMinute, time.Minute)
+			registration.POST("/resume/request-otp", otpLimit, registrationHandler.RequestResumeOTP)
+			registration.POST("/resume/verify-otp", otpLimit, registrationHandler.ResumeRegistration)
+
+			session := registration.Group("")
+			session.Use(middlewares.RegistrationSessionMiddleware(svc.Registration))
+			{
+				session.POST("/admin-info", registrationHandler.RegisterAdminInfo)
+				session.POST("/select-package", registrationHandler.SelectPackage)
+				session.POST("/checkout", paymentHandler.CreateRegistrationCheckout)
+				session.POST("/email-verification/request-otp", otpLimit, registrationHandler.RequestEmailVerificationOTP)
+				session.POST("/email-verification/verify-otp", otpLimit, registrationHandler.VerifyEmailOTP)
+				session.POST("/complete", registrationHandler.CompleteRegistration)
+			}
 		}
+
+		public.POST("/payments/webhook", paymentHandler.Webhook)
 	}
 
 	authenticated := r.Group("/api/v1")
-	authenticated.Use(middlewares.AuthMiddleware(cfg))
+	authenticated.Use(middlewares.AuthMiddleware(cfg, svc.Subscription, svc.UserStatus))
 	{
 		authenticated.POST("/auth/logout", authHandler.Logout)
 
 		authenticated.GET("/profile", authHandler.GetUserProfile)
 
 		admin := authenticated.Group("/admin")
-		admin.Use(middlewares.AuthorizeRoles("admin"))
 		{
-			admin.POST("/users", userHandler.CreateTeacherOrStudent)
-			admin.GET("/users", userHandler.GetAllUsers)
-			admin.GET("/users/:id", userHandler.GetUserByID)
-			admin.PUT("/users/:id", userHandler.UpdateUser)
-			admin.DELETE("/users/:id", userHandler.DeleteUser)
+			admin.POST("/users", middlewares.RequirePermission(models.PermissionUsersCreate), userHandler.CreateTeacherOrStudent)
+			admin.GET("/users", middlewares.RequirePermission(models.PermissionUsersRead), userHandler.GetAllUsers)
+			admin.GET("/users/export", middlewares.RequirePermission(models.PermissionUsersRead), userHandler.ExportUsers)
+			admin.GET("/users/deleted", middlewares.RequirePermission(models.PermissionUsersDelete), userRecycleBinHandler.GetDeletedUsers)
+			admin.GET("/users/:id", middlewares.RequirePermission(models.PermissionUsersRead), userHandler.GetUserByID)
+			admin.PUT("/users/:id", middlewares.RequirePermission(models.PermissionUsersUpdate), userHandler.UpdateUser)
+			admin.DELETE("/users/:id", middlewares.RequirePermission(models.PermissionUsersDelete), userHandler.DeleteUser)
+			admin.POST("/users/:id/restore", middlewares.RequirePermission(models.PermissionUsersDelete), userRecycleBinHandler.RestoreUser)
+			admin.POST("/users/suspend", middlewares.RequirePermission(models.PermissionUsersUpdate), userStatusHandler.SuspendUsers)
+			admin.POST("/users/reactivate", middlewares.RequirePermission(models.PermissionUsersUpdate), userStatusHandler.ReactivateUsers)
+			admin.POST("/users/status", middlewares.RequirePermission(models.PermissionUsersUpdate), userStatusHandler.SetUserStatus)
+			admin.GET("/usage", middlewares.RequirePermission(models.PermissionUsersRead), userHandler.GetStudentUsage)
+
+			admin.POST("/user-imports/preview", middlewares.RequirePermission(models.PermissionUsersCreate), userImportHandler.PreviewImport)
+			admin.POST("/user-imports", middlewares.RequirePermission(models.PermissionUsersCreate), userImportHandler.StartImport)
+			admin.GET("/user-imports/:id", middlewares.RequirePermission(models.PermissionUsersCreate), userImportHandler.GetImport)
+			admin.GET("/user-imports/:id/results", middlewares.RequirePermission(models.PermissionUsersCreate), userImportHandler.DownloadResults)
+
+			admin.GET("/saml-config", middlewares.RequirePermission(models.PermissionSchoolRead), samlHandler.GetConfig)
+			admin.PUT("/saml-config", middlewares.RequirePermission(models.PermissionSchoolUpdate), samlHandler.SaveConfig)
+
+			admin.GET("/ldap-config", middlewares.RequirePermission(models.PermissionSchoolRead), ldapHandler.GetConfig)
+			admin.PUT("/ldap-config", middlewares.RequirePermission(models.PermissionSchoolUpdate), ldapHandler.SaveConfig)
+
+			admin.POST("/guardian-links", middlewares.RequirePermission(models.PermissionGuardiansManage), guardianHandler.CreateGuardianLink)
+			admin.DELETE("/guardian-links/:id", middlewares.RequirePermission(models.PermissionGuardiansManage), guardianHandler.DeleteGuardianLink)
+			admin.GET("/users/:id/guardians", middlewares.RequirePermission(models.PermissionGuardiansManage), guardianHandler.GetStudentGuardians)
+			admin.POST("/users/:id/invite-code", middlewares.RequirePermission(models.PermissionGuardiansManage), guardianHandler.CreateStudentInviteCode)
+
+			admin.GET("/roles", middlewares.RequirePermission(models.PermissionRolesManage), roleHandler.GetRoles)
+			admin.POST("/roles", middlewares.RequirePermission(models.PermissionRolesManage), roleHandler.CreateRole)
+			admin.PUT("/roles/:id", middlewares.RequirePermission(models.PermissionRolesManage), roleHandler.UpdateRole)
+			admin.DELETE("/roles/:id", middlewares.RequirePermission(models.PermissionRolesManage), roleHandler.DeleteRole)
+			admin.PUT("/users/:id/role", middlewares.RequirePermission(models.PermissionRolesManage), roleHandler.AssignRole)
+			admin.GET("/permissions", middlewares.RequirePermission(models.PermissionRolesManage), roleHandler.GetPermissions)
+
+			admin.GET("/invoices", middlewares.RequirePermission(models.PermissionBillingRead), invoiceHandler.GetSchoolInvoices)
+			admin.GET("/invoices/:id/download", middlewares.RequirePermission(models.PermissionBillingRead), invoiceHandler.DownloadSchoolInvoice)
+			admin.POST("/billing/checkout", middlewares.RequirePermission(models.PermissionBillingManage), paymentHandler.CreateSchoolCheckout)
+			admin.GET("/plan", middlewares.RequirePermission(models.PermissionBillingRead), planHandler.GetPlan)
+			admin.POST("/plan/preview", middlewares.RequirePermission(models.PermissionBillingManage), planHandler.PreviewChange)
+			admin.POST("/plan/change", middlewares.RequirePermission(models.PermissionBillingManage), planHandler.ChangePlan)
+			admin.DELETE("/plan/scheduled-change", middlewares.RequirePermission(models.PermissionBillingManage), planHandler.CancelScheduledChange)
+		}
+
+		platform := authenticated.Group("/platform")
+		platform.Use(middlewares.AuthorizeRoles("super_admin"))
+		{
+			platform.GET("/users", userHandler.GetAllUsers)
+			platform.GET("/users/export", userHandler.ExportUsers)
+			platform.GET("/users/deleted", userRecycleBinHandler.GetDeletedUsers)
+			platform.GET("/users/:id", userHandler.GetUserByID)
+			platform.PUT("/users/:id", userHandler.UpdateUser)
+			platform.DELETE("/users/:id", userHandler.DeleteUser)
+			platform.POST("/users/:id/restore", userRecycleBinHandler.RestoreUser)
+			platform.POST("/users/suspend", userStatusHandler.SuspendUsers)
+			platform.POST("/users/reactivate", userStatusHandler.ReactivateUsers)
+			platform.POST("/users/status", userStatusHandler.SetUserStatus)
+
+			platform.GET("/schools", platformSchoolHandler.ListSchools)
+			platform.GET("/schools/:id", platformSchoolHandler.GetSchoolDetail)
+			platform.POST("/schools/:id/suspend", platformSchoolHandler.SuspendSchool)
+			platform.POST("/schools/:id/reactivate", platformSchoolHandler.ReactivateSchool)
+			platform.DELETE("/schools/:id", platformSchoolHandler.DeleteSchool)
+			platform.GET("/registrations/purged", platformRegistrationHandler.GetPurgedRegistrations)
+
+			platform.GET("/packages", platformPackageHandler.GetAllPackages)
+			platform.POST("/packages", platformPackageHandler.CreatePackage)
+			platform.PUT("/packages/:id", platformPackageHandler.UpdatePackage)
+			platform.POST("/packages/:id/retire", platformPackageHandler.RetirePackage)
+
+			platform.GET("/invoices", invoiceHandler.ListInvoices)
+			platform.POST("/schools/:id/invoices", invoiceHandler.GenerateInvoice)
+			platform.GET("/invoices/:id/download", invoiceHandler.DownloadInvoice)
+			platform.POST("/invoices/:id/mark-paid", invoiceHandler.MarkInvoicePaid)
+			platform.POST("/invoices/:id/void", invoiceHandler.VoidInvoice)
+
+			platform.GET("/job-runs", platformJobHandler.GetJobRuns)
+
+			platform.GET("/organizations", organizationHandler.ListOrganizations)
+			platform.POST("/organizations", organizationHandler.CreateOrganization)
+			platform.GET("/organizations/:id", organizationHandler.GetOrganization)
+			platform.PUT("/organizations/:id", organizationHandler.UpdateOrganization)
+			platform.POST("/organizations/:id/schools", organizationHandler.AddSchool)
+			platform.DELETE("/organizations/:id/schools/:school_id", organizationHandler.RemoveSchool)
+			platform.POST("/organizations/:id/admins", organizationHandler.CreateOrganizationAdmin)
+			platform.POST("/organizations/:id/invoices", invoiceHandler.GenerateOrganizationInvoice)
+		}
+
+		org := authenticated.Group("/org")
+		{
+			org.GET("", middlewares.RequirePermission(models.PermissionOrganizationManage), organizationHandler.GetAdminOrganization)
+			org.GET("/users", middlewares.RequirePermission(models.PermissionUsersRead), userHandler.GetOrganizationUsers)
+			org.POST("/users", middlewares.RequirePermission(models.PermissionUsersCreate), userHandler.CreateCampusUser)
+			org.GET("/users/:id", middlewares.RequirePermission(models.PermissionUsersRead), userHandler.GetUserByID)
+			org.PUT("/users/:id", middlewares.RequirePermission(models.PermissionUsersUpdate), userHandler.UpdateUser)
+			org.DELETE("/users/:id", middlewares.RequirePermission(models.PermissionUsersDelete), userHandler.DeleteUser)
+			org.POST("/users/:id/transfer", middlewares.RequirePermission(models.PermissionOrganizationManage), userHandler.TransferTeacher)
+			org.GET("/invoices", middlewares.RequirePermission(models.PermissionBillingRead), invoiceHandler.GetOrganizationInvoices)
+			org.GET("/invoices/:id/download", middlewares.RequirePermission(models.PermissionBillingRead), invoiceHandler.DownloadOrganizationInvoice)
+		}
+
+		parent := authenticated.Group("/parent")
+		parent.Use(middlewares.RequirePermission(models.PermissionStudentsReadLinked))
+		{
+			parent.GET("/students", guardianHandler.GetLinkedStudents)
+			parent.POST("/students/redeem", guardianHandler.RedeemStudentInviteCode)
 		}
 	}
 }
@@ -0,0 +1,258 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+	"auth-barniee/internal/services"
+	"auth-barniee/internal/utils"
+)
+
+// trialReminderDays are the days before a trial ends on which the school admin is reminded.
+var trialReminderDays = []int{1, 3, 7}
+
+// NewDefault builds a scheduler with the service's maintenance and subscription jobs.
+// It runs the same services as the HTTP routes.
+func NewDefault(repos repositories.Repositories, svc *services.Services, cfg *config.Config) *Scheduler {
+	s := New(repos.Jobs)
+	s.Register(Job{
+		Name:     "purge-expired-codes",
+		Interval: time.Hour,
+		Run:      purgeExpiredCodes(repos.EmailVerifications, repos.StudentInviteCodes, repos.PasswordSetupTokens, repos.RegistrationSessions, cfg),
+	})
+	s.Register(Job{
+		Name:     "trial-expiry-reminders",
+		Interval: time.Hour,
+		Run:      sendTrialExpiryReminders(repos.Schools, repos.Users, repos.SubscriptionReminders, cfg),
+	})
+	s.Register(Job{
+		Name:     "subscription-transitions",
+		Interval: 15 * time.Minute,
+		Run:      advanceSubscriptions(repos.Schools, svc.Subscription),
+	})
+	s.Register(Job{
+		Name:     "overdue-invoices",
+		Interval: time.Hour,
+		Run:      markOverdueInvoices(repos.Invoices),
+	})
+	s.Register(Job{
+		Name:     "scheduled-plan-changes",
+		Interval: 15 * time.Minute,
+		Run:      applyScheduledPlanChanges(svc.PlanChange),
+	})
+	s.Register(Job{
+		Name:     "abandoned-registrations",
+		Interval: time.Hour,
+		Run:      cleanUpAbandonedRegistrations(svc.RegistrationCleanup),
+	})
+	s.Register(Job{
+		Name:     "user-imports",
+		Interval: 30 * time.Second,
+		Run:      processUserImports(svc.UserImport),
+	})
+	s.Register(Job{
+		Name:     "purge-deleted-users",
+		Interval: time.Hour,
+		Run:      purgeDeletedUsers(svc.UserRecycleBin),
+	})
+	return s
+}
+
+func purgeExpiredCodes(emailVerifyRepo repositories.EmailVerificationRepository, inviteCodeRepo repositories.StudentInviteCodeRepository,
+	setupTokenRepo repositories.PasswordSetupTokenRepository, sessionRepo repositories.RegistrationSessionRepository, cfg *config.Config) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		now := time.Now()
+		otps, err := emailVerifyRepo.DeleteExpired()
+		if err != nil {
+			return "", fmt.Errorf("failed to purge expired OTPs: %w", err)
+		}
+		inviteCodes, err := inviteCodeRepo.DeleteExpiredUnredeemed()
+		if err != nil {
+			return "", fmt.Errorf("failed to purge expired invite codes: %w", err)
+		}
+		setupTokens, err := setupTokenRepo.DeleteSpent(now)
+		if err != nil {
+			return "", fmt.Errorf("failed to purge password setup tokens: %w", err)
+		}
+		sessions, err := sessionRepo.DeleteInactive(now.Add(-time.Duration(cfg.RegistrationSessionIdleMinutes) * time.Minute))
+		if err != nil {
+			return "", fmt.Errorf("failed to purge registration sessions: %w", err)
+		}
+		return fmt.Sprintf("purged %d OTP(s), %d invite code(s), %d password setup token(s) and %d registration session(s)",
+			otps, inviteCodes, setupTokens, sessions), nil
+	}
+}
+
+func sendTrialExpiryReminders(schoolRepo repositories.SchoolRepository, userRepo repositories.UserRepository, reminderRepo repositories.SubscriptionReminderRepository, cfg *config.Config) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		now := time.Now()
+		horizon := now.Add(time.Duration(trialReminderDays[len(trialReminderDays)-1]) * 24 * time.Hour)
+		notSuspended := false
+		schools, err := schoolRepo.FindAll(repositories.SchoolFilter{
+			Suspended:     &notSuspended,
+			ExpiresAfter:  &now,
+			ExpiresBefore: &horizon,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to find expiring schools: %w", err)
+		}
+
+		sent, failed := 0, 0
+		for _, school := range schools {
+			if ctx.Err() != nil {
+				break
+			}
+			if !school.Package.IsTrial {
+				continue
+			}
+
+			// Only the closest threshold is sent, so a school first seen with 2 days left gets one email, not three.
+			daysLeft := int(math.Ceil(school.SubscriptionEndDate.Sub(now).Hours() / 24))
+			daysBefore := 0
+			for _, days := range trialReminderDays {
+				if daysLeft <= days {
+					daysBefore = days
+					break
+				}
+			}
+			if daysBefore == 0 {
+				continue
+			}
+
+			alreadySent, err := reminderRepo.Exists(school.ID, daysBefore, *school.SubscriptionEndDate)
+			if err != nil {
+				return "", fmt.Errorf("failed to check reminders: %w", err)
+			}
+			if alreadySent {
+				continue
+			}
+
+			adminUser, err := userRepo.FindByID(school.AdminUserID)
+			if err != nil {
+				failed++
+				continue
+			}
+			subject := "Barniee: Masa Trial Anda Segera Berakhir"
+			body := fmt.Sprintf("Halo %s,\n\nMasa trial %s akan berakhir dalam %d hari, pada %s.\nPilih paket berlangganan agar sekolah Anda tetap dapat menggunakan Barniee.\n\nTerima kasih,\nTim Barniee",
+				adminUser.Name, school.Name, daysLeft, school.SubscriptionEndDate.Format("02-01-2006"))
+			if err := utils.SendEmail(cfg, adminUser.Email, subject, body); err != nil {
+				failed++
+				continue
+			}
+
+			if err := reminderRepo.Create(&models.SubscriptionReminder{
+				SchoolID:            school.ID,
+				DaysBefore:          daysBefore,
+				SubscriptionEndDate: *school.SubscriptionEndDate,
+				SentTo:              adminUser.Email,
+			}); err != nil {
+				return "", fmt.Errorf("failed to record reminder: %w", err)
+			}
+			sent++
+		}
+
+		summary := fmt.Sprintf("sent %d reminder(s)", sent)
+		if failed > 0 {
+			return summary, fmt.Errorf("%d reminder(s) could not be sent", failed)
+		}
+		return summary, nil
+	}
+}
+
+func advanceSubscriptions(schoolRepo repositories.SchoolRepository, subscriptionService services.SubscriptionService) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		now := time.Now()
+		schools, err := schoolRepo.FindAll(repositories.SchoolFilter{ExpiresBefore: &now})
+		if err != nil {
+			return "", fmt.Errorf("failed to find ended subscriptions: %w", err)
+		}
+
+		changed := 0
+		for _, school := range schools {
+			if ctx.Err() != nil {
+				break
+			}
+			status := subscriptionService.EvaluateStatus(&school, now)
+			if status == school.SubscriptionStatus {
+				continue
+			}
+			if err := schoolRepo.UpdateSubscriptionStatus(school.ID, status); err != nil {
+				return "", fmt.Errorf("failed to update school %s: %w", school.ID, err)
+			}
+			changed++
+		}
+		return fmt.Sprintf("checked %d school(s), moved %d to a new state", len(schools), changed), nil
+	}
+}
+
+func markOverdueInvoices(invoiceRepo repositories.InvoiceRepository) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		overdue, err := invoiceRepo.MarkOverdue(time.Now())
+		if err != nil {
+			return "", fmt.Errorf("failed to mark overdue invoices: %w", err)
+		}
+		return fmt.Sprintf("marked %d invoice(s) overdue", overdue), nil
+	}
+}
+
+func applyScheduledPlanChanges(planChangeService services.PlanChangeService) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		applied, failed, err := planChangeService.ApplyDueChanges(time.Now())
+		if err != nil {
+			return "", err
+		}
+		summary := fmt.Sprintf("applied %d plan change(s)", applied)
+		if failed > 0 {
+			return summary, fmt.Errorf("%d plan change(s) could not be applied", failed)
+		}
+		return summary, nil
+	}
+}
+
+func cleanUpAbandonedRegistrations(cleanupService services.RegistrationCleanupService) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		now := time.Now()
+		sent, failed, err := cleanupService.SendReminders(now)
+		if err != nil {
+			return "", err
+		}
+		purged, err := cleanupService.PurgeAbandoned(now)
+		if err != nil {
+			return "", err
+		}
+		summary := fmt.Sprintf("sent %d reminder(s), purged %d registration(s)", sent, purged)
+		if failed > 0 {
+			return summary, fmt.Errorf("%d reminder(s) could not be sent", failed)
+		}
+		return summary, nil
+	}
+}
+
+func processUserImports(importService services.UserImportService) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		processed, err := importService.ProcessPendingImports(ctx)
+		if err != nil {
+			return "", err
+		}
+		cleared, err := importService.ClearExpiredPasswords(time.Now())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("processed %d import(s), discarded the passwords of %d import(s)", processed, cleared), nil
+	}
+}
+
+func purgeDeletedUsers(recycleBinService services.UserRecycleBinService) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		purged, err := recycleBinService.PurgeExpired(time.Now())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("purged %d user(s)", purged), nil
+	}
+}
@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Job is a periodic task. Run returns a short summary that is stored in the run history.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) (string, error)
+}
+
+// defaultLeaseTTL is how long a lease outlives a replica that stops renewing it, for example after a crash.
+// A running job renews its lease every third of it.
+const defaultLeaseTTL = time.Minute
+
+// Scheduler runs registered jobs in-process. Every replica runs a scheduler, but a job only
+// executes on the replica that wins its lease. The lease is held for as long as the job runs
+// and afterwards until the job's next slot, so each job runs at most once per interval.
+type Scheduler struct {
+	jobRepo  repositories.JobRepository
+	owner    string
+	jobs     []Job
+	leaseTTL time.Duration
+}
+
+func New(jobRepo repositories.JobRepository) *Scheduler {
+	hostname, _ := os.Hostname()
+	return &Scheduler{
+		jobRepo:  jobRepo,
+		owner:    fmt.Sprintf("%s/%d/%s", hostname, os.Getpid(), uuid.NewString()[:8]),
+		leaseTTL: defaultLeaseTTL,
+	}
+}
+
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches one goroutine per job; they stop when ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		go s.loop(ctx, job)
+	}
+	log.Printf("Scheduler started with %d job(s) as %s", len(s.jobs), s.owner)
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	s.runOnce(ctx, job)
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runOnce(ctx, job)
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	acquired, err := s.jobRepo.AcquireLease(job.Name, s.owner, s.leaseTTL)
+	if err != nil {
+		log.Printf("Scheduler: failed to acquire lease for job '%s': %v", job.Name, err)
+		return
+	}
+	if !acquired {
+		return
+	}
+	startedAt := time.Now()
+	defer func() {
+		// Slightly before the next slot so this replica's next tick can take it again.
+		if err := s.jobRepo.ReleaseLease(job.Name, s.owner, startedAt.Add(job.Interval-time.Second)); err != nil {
+			log.Printf("Scheduler: failed to release lease for job '%s': %v", job.Name, err)
+		}
+	}()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go s.holdLease(runCtx, job, cancel)
+
+	run := &models.JobRun{
+		JobName:   job.Name,
+		Owner:     s.owner,
+		Status:    models.JobRunStatusRunning,
+		StartedAt: startedAt,
+	}
+	if err := s.jobRepo.CreateRun(run); err != nil {
+		log.Printf("Scheduler: failed to record run of job '%s': %v", job.Name, err)
+	}
+
+	summary, runErr := s.safeRun(runCtx, job)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Summary = summary
+	run.Status = models.JobRunStatusSucceeded
+	if runErr != nil {
+		run.Status = models.JobRunStatusFailed
+		run.Error = runErr.Error()
+		log.Printf("Scheduler: job '%s' failed: %v", job.Name, runErr)
+	}
+	if err := s.jobRepo.UpdateRun(run); err != nil {
+		log.Printf("Scheduler: failed to record result of job '%s': %v", job.Name, err)
+	}
+}
+
+// holdLease renews the job's lease until ctx is done. If the lease is lost, for example because
+// renewals failed for longer than the lease TTL and another replica took over, the run is cancelled.
+func (s *Scheduler) holdLease(ctx context.Context, job Job, cancel context.CancelFunc) {
+	ticker := time.NewTicker(s.leaseTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			held, err := s.jobRepo.RenewLease(job.Name, s.owner, s.leaseTTL)
+			if err != nil {
+				log.Printf("Scheduler: failed to renew lease for job '%s': %v", job.Name, err)
+				continue
+			}
+			if !held {
+				log.Printf("Scheduler: lost lease for job '%s', cancelling the run", job.Name)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// safeRun keeps a panicking job from taking down the service.
+func (s *Scheduler) safeRun(ctx context.Context, job Job) (summary string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+)
+
+// fakeJobRepository keeps job leases in memory with the same rules as the database one.
+type fakeJobRepository struct {
+	repositories.JobRepository
+	mu     sync.Mutex
+	owners map[string]string
+	until  map[string]time.Time
+}
+
+func newFakeJobRepository() *fakeJobRepository {
+	return &fakeJobRepository{owners: make(map[string]string), until: make(map[string]time.Time)}
+}
+
+func (r *fakeJobRepository) AcquireLease(name, owner string, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	if current, ok := r.owners[name]; ok && current != owner && !r.until[name].Before(now) {
+		return false, nil
+	}
+	r.owners[name], r.until[name] = owner, now.Add(ttl)
+	return true, nil
+}
+
+func (r *fakeJobRepository) RenewLease(name, owner string, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.owners[name] != owner {
+		return false, nil
+	}
+	r.until[name] = time.Now().Add(ttl)
+	return true, nil
+}
+
+func (r *fakeJobRepository) ReleaseLease(name, owner string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.owners[name] == owner {
+		r.until[name] = until
+	}
+	return nil
+}
+
+func (r *fakeJobRepository) CreateRun(run *models.JobRun) error { return nil }
+func (r *fakeJobRepository) UpdateRun(run *models.JobRun) error { return nil }
+
+func TestLeaseIsHeldWhileAJobRunsPastItsTTL(t *testing.T) {
+	jobRepo := newFakeJobRepository()
+	first, second := New(jobRepo), New(jobRepo)
+	first.leaseTTL, second.leaseTTL = 30*time.Millisecond, 30*time.Millisecond
+
+	var running, runs atomic.Int32
+	var overlapped atomic.Bool
+	job := Job{
+		Name:     "slow-job",
+		Interval: 2 * time.Second,
+		Run: func(ctx context.Context) (string, error) {
+			runs.Add(1)
+			if running.Add(1) > 1 {
+				overlapped.Store(true)
+			}
+			defer running.Add(-1)
+			select {
+			case <-time.After(150 * time.Millisecond): // Five lease TTLs
+			case <-ctx.Done():
+				t.Error("the run was cancelled although its replica kept the lease")
+			}
+			return "done", nil
+		},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		first.runOnce(context.Background(), job)
+		close(done)
+	}()
+	for runs.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for {
+		select {
+		case <-done:
+			// The lease now lasts until the next slot, so the other replica still may not run the job
+			second.runOnce(context.Background(), job)
+			if overlapped.Load() {
+				t.Error("two replicas ran the job at the same time")
+			}
+			if got := runs.Load(); got != 1 {
+				t.Errorf("runs = %d, want 1 per interval", got)
+			}
+			return
+		default:
+			second.runOnce(context.Background(), job)
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestLostLeaseCancelsTheRun(t *testing.T) {
+	jobRepo := newFakeJobRepository()
+	s := New(jobRepo)
+	s.leaseTTL = 30 * time.Millisecond
+
+	job := Job{
+		Name:     "slow-job",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) (string, error) {
+			jobRepo.mu.Lock()
+			jobRepo.owners["slow-job"] = "another-replica"
+			jobRepo.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(time.Second):
+				t.Error("the run kept going after its lease was taken over")
+				return "", nil
+			}
+		},
+	}
+	s.runOnce(context.Background(), job)
+}
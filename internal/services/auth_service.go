@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"auth-barniee/internal/config"
 	"auth-barniee/internal/models"
@@ -17,21 +18,33 @@ type AuthService interface {
 	Login(email, password string) (string, error)
 	RegisterUser(name, email, password, roleName string, createdBy uuid.UUID) (*models.User, error)
 	GetUserProfile(userID uuid.UUID) (*models.User, *models.School, error) // Returns user and its school
+	// SetPassword sets the password of the user a set-password link was issued to. Each link works once.
+	SetPassword(token, newPassword string) error
 }
 
 type authService struct {
-	userRepo   repositories.UserRepository
-	roleRepo   repositories.RoleRepository
-	schoolRepo repositories.SchoolRepository // New: to fetch school details
-	config     *config.Config
+	userRepo          repositories.UserRepository
+	roleRepo          repositories.RoleRepository
+	schoolRepo        repositories.SchoolRepository // New: to fetch school details
+	ldapConfigRepo    repositories.LDAPConfigRepository
+	ldapAuthenticator LDAPAuthenticator
+	subscription      SubscriptionService
+	tokenIssuer       TokenIssuer
+	uow               repositories.UnitOfWork
+	config            *config.Config
 }
 
-func NewAuthService(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, schoolRepo repositories.SchoolRepository, cfg *config.Config) AuthService {
+func NewAuthService(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, schoolRepo repositories.SchoolRepository, ldapConfigRepo repositories.LDAPConfigRepository, ldapAuthenticator LDAPAuthenticator, subscription SubscriptionService, tokenIssuer TokenIssuer, uow repositories.UnitOfWork, cfg *config.Config) AuthService {
 	return &authService{
-		userRepo:   userRepo,
-		roleRepo:   roleRepo,
-		schoolRepo: schoolRepo,
-		config:     cfg,
+		userRepo:          userRepo,
+		roleRepo:          roleRepo,
+		schoolRepo:        schoolRepo,
+		ldapConfigRepo:    ldapConfigRepo,
+		ldapAuthenticator: ldapAuthenticator,
+		subscription:      subscription,
+		tokenIssuer:       tokenIssuer,
+		uow:               uow,
+		config:            cfg,
 	}
 }
 
@@ -44,19 +57,90 @@ func (s *authService) Login(email, password string) (string, error) {
 		return "", fmt.Errorf("failed to find user: %w", err)
 	}
 
-	if !utils.CheckPasswordHash(password, user.Password) {
+	ldapConfig, err := s.findLDAPConfigForUser(user)
+	if err != nil {
+		return "", err
+	}
+	if ldapConfig != nil {
+		if err := s.loginWithLDAP(user, ldapConfig, password); err != nil {
+			return "", err
+		}
+	} else if !utils.CheckPasswordHash(password, user.Password) {
 		return "", errors.New("invalid credentials: incorrect password")
 	}
+	// Checked after the credentials so the status is only revealed to the account holder
+	if err := ensureAccountActive(user); err != nil {
+		return "", err
+	}
+
+	return s.tokenIssuer.IssueToken(user)
+}
+
+// findLDAPConfigForUser returns the directory config a user must authenticate against, or nil for password login.
+// Students are not kept in school directories, and the school's primary admin keeps password login
+// so a broken directory config can always be fixed.
+func (s *authService) findLDAPConfigForUser(user *models.User) (*models.SchoolLDAPConfig, error) {
+	if user.SchoolID == uuid.Nil || user.Role.SystemName() == "student" {
+		return nil, nil
+	}
+
+	ldapConfig, err := s.ldapConfigRepo.FindBySchoolID(user.SchoolID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find LDAP config: %w", err)
+	}
+	if !ldapConfig.Enabled {
+		return nil, nil
+	}
 
-	token, err := utils.GenerateToken(user, s.config)
+	school, err := s.schoolRepo.FindByID(user.SchoolID)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate token: %w", err)
+		return nil, fmt.Errorf("failed to find school: %w", err)
+	}
+	if school.AdminUserID == user.ID {
+		return nil, nil
 	}
-	return token, nil
+	return ldapConfig, nil
+}
+
+func (s *authService) loginWithLDAP(user *models.User, ldapConfig *models.SchoolLDAPConfig, password string) error {
+	identity, err := s.ldapAuthenticator.Authenticate(ldapConfig, user.Email, password)
+	if err != nil {
+		return err
+	}
+
+	roleName, err := mapLDAPGroupsToRole(ldapConfig, identity.Groups)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	if roleName != user.Role.SystemName() {
+		role, err := s.roleRepo.FindByName(roleName)
+		if err != nil {
+			return fmt.Errorf("failed to find role '%s': %w", roleName, err)
+		}
+		user.RoleID = role.ID
+		user.Role = *role
+		changed = true
+	}
+	if identity.Name != "" && identity.Name != user.Name {
+		user.Name = identity.Name
+		changed = true
+	}
+	if changed {
+		user.UpdatedBy = uuid.Nil
+		if err := s.userRepo.Update(user); err != nil {
+			return fmt.Errorf("failed to sync directory user: %w", err)
+		}
+	}
+	return nil
 }
 
 func (s *authService) RegisterUser(name, email, password, roleName string, createdBy uuid.UUID) (*models.User, error) {
-	existingUser, err := s.userRepo.FindByEmail(email)
+	existingUser, err := s.userRepo.FindByEmailIncludingDeleted(email)
 	if err == nil && existingUser != nil {
 		return nil, errors.New("user with this email already exists")
 	}
@@ -103,13 +187,46 @@ func (s *authService) GetUserProfile(userID uuid.UUID) (*models.User, *models.Sc
 
 	var school *models.School
 	if user.SchoolID != uuid.Nil {
-		school, err = s.schoolRepo.FindByID(user.SchoolID)
-		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		school, err = s.subscription.RefreshSchoolStatus(user.SchoolID)
+		if err != nil && err.Error() != "school not found" {
 			// Log this error, but don't fail the user profile retrieval if school isn't found
-			// A user might exist without a linked school (e.g., master admin)
+			// A user might exist without a linked school (e.g., super admin)
 			fmt.Printf("Warning: Could not retrieve school for user %s: %v\n", user.ID.String(), err)
 		}
 	}
 
 	return user, school, nil
 }
+
+func (s *authService) SetPassword(token, newPassword string) error {
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return s.uow.Do(func(repos repositories.Repositories) error {
+		setupToken, err := repos.PasswordSetupTokens.FindByTokenHash(utils.HashSecureToken(token))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("invalid password setup link")
+			}
+			return fmt.Errorf("failed to find password setup link: %w", err)
+		}
+		now := time.Now()
+		if now.After(setupToken.ExpiresAt) {
+			return errors.New("password setup link has expired")
+		}
+		used, err := repos.PasswordSetupTokens.MarkUsed(setupToken.ID, now)
+		if err != nil {
+			return fmt.Errorf("failed to use password setup link: %w", err)
+		}
+		if !used {
+			return errors.New("password setup link has already been used")
+		}
+
+		if err := repos.Users.UpdatePassword(setupToken.UserID, hashedPassword); err != nil {
+			return fmt.Errorf("failed to set password: %w", err)
+		}
+		return nil
+	})
+}
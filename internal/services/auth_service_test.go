@@ -0,0 +1,204 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// fakeLDAPAuthenticator accepts the directory passwords it was given and counts the calls,
+// so tests can check whether login went to the directory at all.
+type fakeLDAPAuthenticator struct {
+	passwords  map[string]string
+	identities map[string]*LDAPIdentity
+	calls      int
+}
+
+func (a *fakeLDAPAuthenticator) Authenticate(ldapConfig *models.SchoolLDAPConfig, email, password string) (*LDAPIdentity, error) {
+	a.calls++
+	if password == "" || a.passwords[email] != password {
+		return nil, errors.New("invalid credentials: incorrect password")
+	}
+	return a.identities[email], nil
+}
+
+type ldapLoginFixture struct {
+	service       AuthService
+	users         *fakeUserRepository
+	authenticator *fakeLDAPAuthenticator
+	ldapConfig    *models.SchoolLDAPConfig
+	teacher       *models.User
+	primaryAdmin  *models.User
+	student       *models.User
+	adminRole     *models.Role
+}
+
+func newLDAPLoginFixture(t *testing.T) *ldapLoginFixture {
+	t.Helper()
+	hash := func(password string) string {
+		hashed, err := utils.HashPassword(password)
+		if err != nil {
+			t.Fatalf("failed to hash password: %v", err)
+		}
+		return hashed
+	}
+
+	adminRole := &models.Role{ID: uuid.New(), Name: "admin"}
+	teacherRole := &models.Role{ID: uuid.New(), Name: "teacher"}
+	studentRole := &models.Role{ID: uuid.New(), Name: "student"}
+	school := &models.School{ID: uuid.New(), Name: "SMA Barniee"}
+
+	primaryAdmin := &models.User{ID: uuid.New(), Name: "Kepala TU", Email: "tu@sekolah.sch.id", Password: hash("local-admin-pass"),
+		RoleID: adminRole.ID, Role: *adminRole, SchoolID: school.ID, Status: models.UserStatusActive}
+	teacher := &models.User{ID: uuid.New(), Name: "Budi", Email: "budi@sekolah.sch.id", Password: hash("old-local-pass"),
+		RoleID: teacherRole.ID, Role: *teacherRole, SchoolID: school.ID, Status: models.UserStatusActive}
+	student := &models.User{ID: uuid.New(), Name: "Siti", Email: "siti@sekolah.sch.id", Password: hash("student-pass"),
+		RoleID: studentRole.ID, Role: *studentRole, SchoolID: school.ID, Status: models.UserStatusActive}
+	school.AdminUserID = primaryAdmin.ID
+
+	ldapConfig := &models.SchoolLDAPConfig{ID: uuid.New(), SchoolID: school.ID, Enabled: true, URL: "ldap://directory.invalid",
+		AdminGroups: `["it"]`, TeacherGroups: `["guru"]`}
+	authenticator := &fakeLDAPAuthenticator{
+		passwords: map[string]string{
+			teacher.Email:      "directory-pass",
+			primaryAdmin.Email: "directory-admin-pass",
+			student.Email:      "directory-student-pass",
+		},
+		identities: map[string]*LDAPIdentity{
+			teacher.Email:      {DN: "uid=budi", Name: "Budi Santoso", Groups: []string{"guru"}},
+			primaryAdmin.Email: {DN: "uid=tu", Groups: []string{"it"}},
+			student.Email:      {DN: "uid=siti", Groups: []string{"guru"}},
+		},
+	}
+
+	users := newFakeUserRepository(primaryAdmin, teacher, student)
+	service := NewAuthService(users, &fakeRoleRepository{roles: []*models.Role{adminRole, teacherRole, studentRole}},
+		newFakeSchoolRepository(school), &fakeLDAPConfigRepository{configs: map[uuid.UUID]*models.SchoolLDAPConfig{school.ID: ldapConfig}},
+		authenticator, nil, fakeTokenIssuer{}, nil, &config.Config{})
+
+	return &ldapLoginFixture{
+		service:       service,
+		users:         users,
+		authenticator: authenticator,
+		ldapConfig:    ldapConfig,
+		teacher:       teacher,
+		primaryAdmin:  primaryAdmin,
+		student:       student,
+		adminRole:     adminRole,
+	}
+}
+
+func TestLoginAuthenticatesStaffAgainstDirectory(t *testing.T) {
+	f := newLDAPLoginFixture(t)
+
+	token, err := f.service.Login(f.teacher.Email, "directory-pass")
+	if err != nil {
+		t.Fatalf("Login with directory password returned error: %v", err)
+	}
+	if token != "token:"+f.teacher.Email {
+		t.Errorf("token = %q, want one for %s", token, f.teacher.Email)
+	}
+	if f.authenticator.calls != 1 {
+		t.Errorf("directory calls = %d, want 1", f.authenticator.calls)
+	}
+	// The directory name is synced onto the account
+	if synced := f.users.users[f.teacher.ID]; synced.Name != "Budi Santoso" {
+		t.Errorf("name after login = %q, want the directory name", synced.Name)
+	}
+}
+
+func TestLoginDoesNotFallBackToLocalPasswordForDirectoryUsers(t *testing.T) {
+	f := newLDAPLoginFixture(t)
+
+	if _, err := f.service.Login(f.teacher.Email, "old-local-pass"); err == nil {
+		t.Fatal("Login with the stored local password succeeded for a directory user")
+	}
+	if f.authenticator.calls != 1 {
+		t.Errorf("directory calls = %d, want 1", f.authenticator.calls)
+	}
+}
+
+func TestLoginMapsDirectoryGroupsToRole(t *testing.T) {
+	f := newLDAPLoginFixture(t)
+	f.authenticator.identities[f.teacher.Email].Groups = []string{"guru", "it"}
+
+	if _, err := f.service.Login(f.teacher.Email, "directory-pass"); err != nil {
+		t.Fatalf("Login returned error: %v", err)
+	}
+	if promoted := f.users.users[f.teacher.ID]; promoted.RoleID != f.adminRole.ID {
+		t.Errorf("role after login = %s, want admin", promoted.Role.Name)
+	}
+
+	f.authenticator.identities[f.teacher.Email].Groups = []string{"alumni"}
+	if _, err := f.service.Login(f.teacher.Email, "directory-pass"); err == nil {
+		t.Error("Login succeeded for a directory user outside the authorized groups")
+	}
+}
+
+func TestLoginFallsBackToLocalPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    func(f *ldapLoginFixture) string
+		password string
+		setup    func(f *ldapLoginFixture)
+	}{
+		{
+			name:     "primary admin keeps local login",
+			email:    func(f *ldapLoginFixture) string { return f.primaryAdmin.Email },
+			password: "local-admin-pass",
+		},
+		{
+			name:     "students are not in the directory",
+			email:    func(f *ldapLoginFixture) string { return f.student.Email },
+			password: "student-pass",
+		},
+		{
+			name:     "directory disabled",
+			email:    func(f *ldapLoginFixture) string { return f.teacher.Email },
+			password: "old-local-pass",
+			setup:    func(f *ldapLoginFixture) { f.ldapConfig.Enabled = false },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLDAPLoginFixture(t)
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			if _, err := f.service.Login(tt.email(f), tt.password); err != nil {
+				t.Fatalf("Login with local password returned error: %v", err)
+			}
+			if f.authenticator.calls != 0 {
+				t.Errorf("directory calls = %d, want 0", f.authenticator.calls)
+			}
+			if _, err := f.service.Login(tt.email(f), "wrong"); err == nil {
+				t.Error("Login with a wrong local password succeeded")
+			}
+		})
+	}
+}
+
+func TestLoginThroughStandInDirectory(t *testing.T) {
+	f := newLDAPLoginFixture(t)
+	directory := newSchoolDirectory(t)
+	standIn := standInLDAPConfig(directory)
+	standIn.ID, standIn.SchoolID = f.ldapConfig.ID, f.ldapConfig.SchoolID
+	*f.ldapConfig = *standIn
+
+	service := NewAuthService(f.users, &fakeRoleRepository{roles: []*models.Role{f.adminRole, &f.teacher.Role}},
+		newFakeSchoolRepository(&models.School{ID: f.teacher.SchoolID, AdminUserID: f.primaryAdmin.ID}),
+		&fakeLDAPConfigRepository{configs: map[uuid.UUID]*models.SchoolLDAPConfig{f.teacher.SchoolID: f.ldapConfig}},
+		NewLDAPAuthenticator(), nil, fakeTokenIssuer{}, nil, &config.Config{})
+
+	if _, err := service.Login(f.teacher.Email, "directory-pass"); err != nil {
+		t.Fatalf("Login against the stand-in returned error: %v", err)
+	}
+	if _, err := service.Login(f.teacher.Email, "old-local-pass"); err == nil {
+		t.Error("Login against the stand-in accepted the local password")
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"auth-barniee/internal/models"
+)
+
+// FakePaymentSignatureHeader carries the HMAC-SHA256 of the webhook body for the fake provider.
+const FakePaymentSignatureHeader = "X-Fake-Payment-Signature"
+
+// FakePaymentProvider never charges anyone. Its checkout URL is a placeholder, and payments are
+// completed by posting a notification signed with Sign to the webhook, e.g.
+//
+//	{"order_id": "BRN-...", "status": "paid", "amount": 1387500}
+type FakePaymentProvider struct {
+	secret  []byte
+	baseURL string
+}
+
+// NewFakePaymentProvider uses secret to sign webhooks. Without one, a random secret is generated,
+// so webhooks cannot be forged but can only be signed from within the process.
+func NewFakePaymentProvider(secret, baseURL string) *FakePaymentProvider {
+	key := []byte(secret)
+	if secret == "" {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			log.Fatalf("Failed to generate fake payment secret: %v", err)
+		}
+	}
+	return &FakePaymentProvider{secret: key, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+func (p *FakePaymentProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakePaymentProvider) CreateCheckout(ctx context.Context, req CheckoutRequest) (*CheckoutSession, error) {
+	return &CheckoutSession{
+		ProviderReference: "fake-" + req.OrderID,
+		RedirectURL:       fmt.Sprintf("%s/fake-checkout/%s", p.baseURL, req.OrderID),
+	}, nil
+}
+
+// Sign returns the signature header value for a webhook body.
+func (p *FakePaymentProvider) Sign(body []byte) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (p *FakePaymentProvider) ParseNotification(header http.Header, body []byte) (*PaymentNotification, error) {
+	signature, err := hex.DecodeString(header.Get(FakePaymentSignatureHeader))
+	if err != nil {
+		return nil, ErrInvalidPaymentSignature
+	}
+	expected, _ := hex.DecodeString(p.Sign(body))
+	if !hmac.Equal(signature, expected) {
+		return nil, ErrInvalidPaymentSignature
+	}
+
+	var notification struct {
+		OrderID string `json:"order_id"`
+		Status  string `json:"status"`
+		Amount  int64  `json:"amount"`
+	}
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, fmt.Errorf("invalid notification payload: %w", err)
+	}
+	switch notification.Status {
+	case models.PaymentStatusPending, models.PaymentStatusPaid, models.PaymentStatusFailed, models.PaymentStatusExpired:
+	default:
+		return nil, fmt.Errorf("invalid notification status '%s'", notification.Status)
+	}
+
+	return &PaymentNotification{
+		OrderID:           notification.OrderID,
+		Status:            notification.Status,
+		Amount:            notification.Amount,
+		ProviderReference: "fake-" + notification.OrderID,
+	}, nil
+}
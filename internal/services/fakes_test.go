@@ -0,0 +1,719 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The fakes below keep rows in memory and implement only the repository methods the tests reach;
+// the embedded interface makes any other call panic, which flags a test that strays off its path.
+
+type fakeUserRepository struct {
+	repositories.UserRepository
+	users map[uuid.UUID]*models.User
+	// Optional: roles fills in the role of created users like the real preload, and schools
+	// supplies the seat limits checked by the quota methods
+	roles   *fakeRoleRepository
+	schools *fakeSchoolRepository
+}
+
+func newFakeUserRepository(users ...*models.User) *fakeUserRepository {
+	r := &fakeUserRepository{users: make(map[uuid.UUID]*models.User)}
+	for _, user := range users {
+		r.users[user.ID] = user
+	}
+	return r
+}
+
+func (r *fakeUserRepository) FindByID(id uuid.UUID) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *user
+	return &found, nil
+}
+
+func (r *fakeUserRepository) FindByEmail(email string) (*models.User, error) {
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) && !user.DeletedAt.Valid {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepository) FindByEmailIncludingDeleted(email string) (*models.User, error) {
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepository) Create(user *models.User) error {
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	saved := *user
+	if r.roles != nil {
+		if role, err := r.roles.FindByID(user.RoleID); err == nil {
+			saved.Role = *role
+		}
+	}
+	r.users[user.ID] = &saved
+	return nil
+}
+
+// CreateWithinStudentQuota refuses the student when the school's seats are taken, like the repository.
+func (r *fakeUserRepository) CreateWithinStudentQuota(user *models.User) error {
+	if err := r.checkStudentQuota(user); err != nil {
+		return err
+	}
+	return r.Create(user)
+}
+
+func (r *fakeUserRepository) checkStudentQuota(user *models.User) error {
+	if r.schools == nil {
+		return nil
+	}
+	school, ok := r.schools.schools[user.SchoolID]
+	if !ok || school.MaxStudentsAllowed == 0 {
+		return nil
+	}
+	var used int64
+	for _, other := range r.users {
+		if other.ID != user.ID && other.SchoolID == school.ID && other.Role.Name == "student" && !other.DeletedAt.Valid {
+			used++
+		}
+	}
+	if used >= int64(school.MaxStudentsAllowed) {
+		return &repositories.StudentQuotaExceededError{Used: used, Limit: school.MaxStudentsAllowed}
+	}
+	return nil
+}
+
+func (r *fakeUserRepository) Update(user *models.User) error {
+	saved := *user
+	r.users[user.ID] = &saved
+	return nil
+}
+
+func (r *fakeUserRepository) UpdateStatus(ids []uuid.UUID, status, reason string, changedBy uuid.UUID) error {
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok {
+			user.Status = status
+			user.StatusReason = reason
+		}
+	}
+	return nil
+}
+
+func (r *fakeUserRepository) Delete(id uuid.UUID, deletedBy uuid.UUID) error {
+	if user, ok := r.users[id]; ok {
+		user.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		user.DeletedBy = &deletedBy
+	}
+	return nil
+}
+
+func (r *fakeUserRepository) FindDeletedByID(id uuid.UUID) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok || !user.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *user
+	return &found, nil
+}
+
+func (r *fakeUserRepository) Restore(id uuid.UUID) error {
+	if user, ok := r.users[id]; ok {
+		user.DeletedAt = gorm.DeletedAt{}
+		user.DeletedBy = nil
+	}
+	return nil
+}
+
+func (r *fakeUserRepository) RestoreWithinStudentQuota(user *models.User) error {
+	if err := r.checkStudentQuota(user); err != nil {
+		return err
+	}
+	return r.Restore(user.ID)
+}
+
+// PurgeDeleted drops the users deleted before deletedBefore; the linked rows are covered by the repository tests.
+func (r *fakeUserRepository) PurgeDeleted(deletedBefore time.Time) (int64, error) {
+	var purged int64
+	for id, user := range r.users {
+		if user.DeletedAt.Valid && user.DeletedAt.Time.Before(deletedBefore) {
+			delete(r.users, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// FindPage sorts by name or email and then ID like the repository, filtering on the school only.
+func (r *fakeUserRepository) FindPage(filter repositories.UserFilter, page repositories.UserPage) ([]models.User, error) {
+	sortValue := func(user *models.User) string {
+		if page.SortBy == repositories.UserSortEmail {
+			return user.Email
+		}
+		return user.Name
+	}
+	// compare orders a before b, or after it when the page is descending
+	compare := func(aValue string, aID uuid.UUID, bValue string, bID uuid.UUID) int {
+		order := strings.Compare(aValue, bValue)
+		if order == 0 {
+			order = strings.Compare(aID.String(), bID.String())
+		}
+		if page.Desc {
+			return -order
+		}
+		return order
+	}
+
+	var users []models.User
+	for _, user := range r.users {
+		if user.DeletedAt.Valid || (filter.SchoolID != nil && user.SchoolID != *filter.SchoolID) {
+			continue
+		}
+		if page.After != nil && compare(sortValue(user), user.ID, page.After.Value.(string), page.After.ID) <= 0 {
+			continue
+		}
+		users = append(users, *user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return compare(sortValue(&users[i]), users[i].ID, sortValue(&users[j]), users[j].ID) < 0
+	})
+	if len(users) > page.Limit {
+		users = users[:page.Limit]
+	}
+	return users, nil
+}
+
+func (r *fakeUserRepository) Count(filter repositories.UserFilter) (int64, error) {
+	var count int64
+	for _, user := range r.users {
+		if !user.DeletedAt.Valid && (filter.SchoolID == nil || user.SchoolID == *filter.SchoolID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeUserRepository) CountStudentsBySchoolID(schoolID uuid.UUID) (int64, error) {
+	var count int64
+	for _, user := range r.users {
+		if user.SchoolID == schoolID && user.Role.Name == "student" && !user.DeletedAt.Valid {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type fakeRoleRepository struct {
+	repositories.RoleRepository
+	roles       []*models.Role
+	permissions map[uuid.UUID][]string // Permission names by role ID
+}
+
+func (r *fakeRoleRepository) FindByID(id uuid.UUID) (*models.Role, error) {
+	for _, role := range r.roles {
+		if role.ID == id {
+			found := *role
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeRoleRepository) FindPermissionNames(roleID uuid.UUID) ([]string, error) {
+	return r.permissions[roleID], nil
+}
+
+func (r *fakeRoleRepository) FindByName(name string) (*models.Role, error) {
+	for _, role := range r.roles {
+		if role.Name == name && role.SchoolID == nil {
+			found := *role
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeSchoolRepository struct {
+	repositories.SchoolRepository
+	schools map[uuid.UUID]*models.School
+	paid    map[uuid.UUID]bool // Schools with a paid payment
+}
+
+func newFakeSchoolRepository(schools ...*models.School) *fakeSchoolRepository {
+	r := &fakeSchoolRepository{schools: make(map[uuid.UUID]*models.School)}
+	for _, school := range schools {
+		r.schools[school.ID] = school
+	}
+	return r
+}
+
+func (r *fakeSchoolRepository) FindByID(id uuid.UUID) (*models.School, error) {
+	school, ok := r.schools[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *school
+	return &found, nil
+}
+
+func (r *fakeSchoolRepository) FindByIDForUpdate(id uuid.UUID) (*models.School, error) {
+	return r.FindByID(id)
+}
+
+func (r *fakeSchoolRepository) Update(school *models.School) error {
+	saved := *school
+	r.schools[school.ID] = &saved
+	return nil
+}
+
+func (r *fakeSchoolRepository) UpdateSubscriptionStatus(id uuid.UUID, status string) error {
+	if school, ok := r.schools[id]; ok {
+		school.SubscriptionStatus = status
+	}
+	return nil
+}
+
+// FindIncompleteRegistrations leaves out the schools in paid, which the repository finds through their payments.
+func (r *fakeSchoolRepository) FindIncompleteRegistrations(idleBefore time.Time) ([]models.School, error) {
+	var schools []models.School
+	for _, school := range r.schools {
+		if school.RegistrationStatus != models.RegistrationStatusCompleted && school.UpdatedAt.Before(idleBefore) && !r.paid[school.ID] {
+			schools = append(schools, *school)
+		}
+	}
+	return schools, nil
+}
+
+func (r *fakeSchoolRepository) MarkRegistrationReminded(id uuid.UUID, at time.Time) error {
+	if school, ok := r.schools[id]; ok {
+		school.RegistrationReminderAt = &at
+	}
+	return nil
+}
+
+func (r *fakeSchoolRepository) Delete(id uuid.UUID) error {
+	delete(r.schools, id)
+	return nil
+}
+
+type fakeLDAPConfigRepository struct {
+	repositories.LDAPConfigRepository
+	configs map[uuid.UUID]*models.SchoolLDAPConfig
+}
+
+func (r *fakeLDAPConfigRepository) FindBySchoolID(schoolID uuid.UUID) (*models.SchoolLDAPConfig, error) {
+	ldapConfig, ok := r.configs[schoolID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *ldapConfig
+	return &found, nil
+}
+
+type fakePackageRepository struct {
+	repositories.PackageRepository
+	packages []*models.Package
+}
+
+func (r *fakePackageRepository) FindByID(id uuid.UUID) (*models.Package, error) {
+	for _, pkg := range r.packages {
+		if pkg.ID == id {
+			found := *pkg
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeInvoiceRepository struct {
+	repositories.InvoiceRepository
+	invoices map[uuid.UUID]*models.Invoice
+}
+
+func newFakeInvoiceRepository() *fakeInvoiceRepository {
+	return &fakeInvoiceRepository{invoices: make(map[uuid.UUID]*models.Invoice)}
+}
+
+func (r *fakeInvoiceRepository) CreateWithNextNumber(invoice *models.Invoice) error {
+	if invoice.ID == uuid.Nil {
+		invoice.ID = uuid.New()
+	}
+	invoice.Number = fmt.Sprintf("INV/%d/%06d", invoice.IssuedAt.Year(), len(r.invoices)+1)
+	saved := *invoice
+	r.invoices[invoice.ID] = &saved
+	return nil
+}
+
+func (r *fakeInvoiceRepository) FindByID(id uuid.UUID) (*models.Invoice, error) {
+	invoice, ok := r.invoices[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *invoice
+	return &found, nil
+}
+
+func (r *fakeInvoiceRepository) ExistsUnpaid(schoolID uuid.UUID) (bool, error) {
+	for _, invoice := range r.invoices {
+		if invoice.Status != models.InvoiceStatusIssued && invoice.Status != models.InvoiceStatusOverdue {
+			continue
+		}
+		if invoice.SchoolID != nil && *invoice.SchoolID == schoolID {
+			return true, nil
+		}
+		for _, line := range invoice.Lines {
+			if line.SchoolID != nil && *line.SchoolID == schoolID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeInvoiceRepository) FindPaidCovering(schoolID uuid.UUID, at time.Time) (*models.Invoice, error) {
+	for _, invoice := range r.invoices {
+		if invoice.SchoolID != nil && *invoice.SchoolID == schoolID && invoice.Status == models.InvoiceStatusPaid &&
+			!invoice.PeriodStart.After(at) && invoice.PeriodEnd.After(at) {
+			found := *invoice
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeInvoiceRepository) MarkPaid(invoice *models.Invoice) (bool, error) {
+	current, ok := r.invoices[invoice.ID]
+	if !ok || (current.Status != models.InvoiceStatusIssued && current.Status != models.InvoiceStatusOverdue) {
+		return false, nil
+	}
+	saved := *invoice
+	r.invoices[invoice.ID] = &saved
+	return true, nil
+}
+
+// fakeOrganizationRepository loads the campuses from the school fake, as the real one preloads them.
+type fakeOrganizationRepository struct {
+	repositories.OrganizationRepository
+	organizations map[uuid.UUID]*models.Organization
+	schools       *fakeSchoolRepository
+}
+
+func (r *fakeOrganizationRepository) FindByID(id uuid.UUID) (*models.Organization, error) {
+	organization, ok := r.organizations[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *organization
+	found.Schools = nil
+	for _, school := range r.schools.schools {
+		if school.OrganizationID != nil && *school.OrganizationID == id {
+			found.Schools = append(found.Schools, *school)
+		}
+	}
+	return &found, nil
+}
+
+// fakeUnitOfWork runs the function against the given repositories without a transaction.
+type fakeUnitOfWork struct {
+	repos repositories.Repositories
+}
+
+func (u *fakeUnitOfWork) Do(fn func(repos repositories.Repositories) error) error {
+	return fn(u.repos)
+}
+
+// fakeTokenIssuer issues a readable token naming the user, so tests can tell who logged in.
+type fakeTokenIssuer struct{}
+
+func (fakeTokenIssuer) IssueToken(user *models.User) (string, error) {
+	return "token:" + user.Email, nil
+}
+
+type fakePaymentRepository struct {
+	repositories.PaymentRepository
+	payments map[uuid.UUID]*models.Payment
+}
+
+func newFakePaymentRepository() *fakePaymentRepository {
+	return &fakePaymentRepository{payments: make(map[uuid.UUID]*models.Payment)}
+}
+
+func (r *fakePaymentRepository) Create(payment *models.Payment) error {
+	if payment.ID == uuid.Nil {
+		payment.ID = uuid.New()
+	}
+	saved := *payment
+	r.payments[payment.ID] = &saved
+	return nil
+}
+
+func (r *fakePaymentRepository) FindByOrderID(orderID string) (*models.Payment, error) {
+	for _, payment := range r.payments {
+		if payment.OrderID == orderID {
+			found := *payment
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakePaymentRepository) Update(payment *models.Payment) error {
+	saved := *payment
+	r.payments[payment.ID] = &saved
+	return nil
+}
+
+func (r *fakePaymentRepository) MarkPaid(id uuid.UUID, providerReference string, paidAt time.Time) (bool, error) {
+	payment, ok := r.payments[id]
+	if !ok || payment.Status == models.PaymentStatusPaid {
+		return false, nil
+	}
+	payment.Status = models.PaymentStatusPaid
+	payment.ProviderReference = providerReference
+	payment.PaidAt = &paidAt
+	return true, nil
+}
+
+func (r *fakePaymentRepository) UpdateStatus(id uuid.UUID, status string) error {
+	if payment, ok := r.payments[id]; ok {
+		payment.Status = status
+	}
+	return nil
+}
+
+func (r *fakePaymentRepository) DeleteUnpaidBySchoolID(schoolID uuid.UUID) error {
+	for id, payment := range r.payments {
+		if payment.SchoolID == schoolID && payment.Status != models.PaymentStatusPaid {
+			delete(r.payments, id)
+		}
+	}
+	return nil
+}
+
+type fakePurgedRegistrationRepository struct {
+	repositories.PurgedRegistrationRepository
+	purged []models.PurgedRegistration
+}
+
+func (r *fakePurgedRegistrationRepository) Create(purged *models.PurgedRegistration) error {
+	r.purged = append(r.purged, *purged)
+	return nil
+}
+
+type fakeRegistrationSessionRepository struct {
+	repositories.RegistrationSessionRepository
+	sessions []*models.RegistrationSession
+}
+
+func (r *fakeRegistrationSessionRepository) FindByID(id uuid.UUID) (*models.RegistrationSession, error) {
+	for _, session := range r.sessions {
+		if session.ID == id {
+			found := *session
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeRegistrationSessionRepository) Touch(id uuid.UUID, idleSince, now time.Time) (bool, error) {
+	for _, session := range r.sessions {
+		if session.ID == id && session.EndedAt == nil && !session.LastActivityAt.Before(idleSince) {
+			session.LastActivityAt = now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeRegistrationSessionRepository) EndForSchool(schoolID uuid.UUID, at time.Time) error {
+	for _, session := range r.sessions {
+		if session.SchoolID == schoolID && session.EndedAt == nil {
+			session.EndedAt = &at
+		}
+	}
+	return nil
+}
+
+type fakePasswordSetupTokenRepository struct {
+	repositories.PasswordSetupTokenRepository
+	tokens []*models.PasswordSetupToken
+}
+
+func (r *fakePasswordSetupTokenRepository) InvalidateForUser(userID uuid.UUID, at time.Time) error {
+	return nil
+}
+
+func (r *fakePasswordSetupTokenRepository) Create(token *models.PasswordSetupToken) error {
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+type fakeEmailVerificationRepository struct {
+	repositories.EmailVerificationRepository
+	verifications []*models.EmailVerification
+}
+
+func (r *fakeEmailVerificationRepository) FindByUserID(userID uuid.UUID, purpose string) (*models.EmailVerification, error) {
+	for i := len(r.verifications) - 1; i >= 0; i-- {
+		if verification := r.verifications[i]; verification.UserID == userID && verification.Purpose == purpose {
+			found := *verification
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeEmailVerificationRepository) Update(verification *models.EmailVerification) error {
+	for i, saved := range r.verifications {
+		if saved.ID == verification.ID {
+			updated := *verification
+			r.verifications[i] = &updated
+		}
+	}
+	return nil
+}
+
+type fakePlanChangeRepository struct {
+	repositories.PlanChangeRepository
+	changes map[uuid.UUID]*models.PlanChange
+}
+
+func newFakePlanChangeRepository(changes ...*models.PlanChange) *fakePlanChangeRepository {
+	r := &fakePlanChangeRepository{changes: make(map[uuid.UUID]*models.PlanChange)}
+	for _, change := range changes {
+		r.changes[change.ID] = change
+	}
+	return r
+}
+
+func (r *fakePlanChangeRepository) Create(change *models.PlanChange) error {
+	if change.ID == uuid.Nil {
+		change.ID = uuid.New()
+	}
+	saved := *change
+	r.changes[change.ID] = &saved
+	return nil
+}
+
+func (r *fakePlanChangeRepository) FindByID(id uuid.UUID) (*models.PlanChange, error) {
+	change, ok := r.changes[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *change
+	return &found, nil
+}
+
+func (r *fakePlanChangeRepository) FindScheduledBySchoolID(schoolID uuid.UUID) (*models.PlanChange, error) {
+	for _, change := range r.changes {
+		if change.SchoolID == schoolID && change.Status == models.PlanChangeStatusScheduled {
+			found := *change
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakePlanChangeRepository) FindOpenBySchoolID(schoolID uuid.UUID) ([]models.PlanChange, error) {
+	var changes []models.PlanChange
+	for _, change := range r.changes {
+		if change.SchoolID == schoolID && (change.Status == models.PlanChangeStatusScheduled || change.Status == models.PlanChangeStatusPendingPayment) {
+			changes = append(changes, *change)
+		}
+	}
+	return changes, nil
+}
+
+func (r *fakePlanChangeRepository) Update(change *models.PlanChange) error {
+	saved := *change
+	r.changes[change.ID] = &saved
+	return nil
+}
+
+type fakeUserImportRepository struct {
+	repositories.UserImportRepository
+	imports map[uuid.UUID]*models.UserImport
+	rows    map[uuid.UUID][]models.UserImportRow
+}
+
+func newFakeUserImportRepository() *fakeUserImportRepository {
+	return &fakeUserImportRepository{imports: make(map[uuid.UUID]*models.UserImport), rows: make(map[uuid.UUID][]models.UserImportRow)}
+}
+
+func (r *fakeUserImportRepository) Create(userImport *models.UserImport, rows []models.UserImportRow) error {
+	if userImport.ID == uuid.Nil {
+		userImport.ID = uuid.New()
+	}
+	saved := *userImport
+	r.imports[userImport.ID] = &saved
+	for i := range rows {
+		rows[i].ImportID = userImport.ID
+	}
+	r.rows[userImport.ID] = append([]models.UserImportRow(nil), rows...)
+	return nil
+}
+
+func (r *fakeUserImportRepository) FindByID(id uuid.UUID) (*models.UserImport, error) {
+	userImport, ok := r.imports[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *userImport
+	return &found, nil
+}
+
+func (r *fakeUserImportRepository) FindRows(importID uuid.UUID) ([]models.UserImportRow, error) {
+	return append([]models.UserImportRow(nil), r.rows[importID]...), nil
+}
+
+func (r *fakeUserImportRepository) ClearPasswords(id uuid.UUID, at time.Time) (bool, error) {
+	userImport, ok := r.imports[id]
+	if !ok || userImport.PasswordsClearedAt != nil {
+		return false, nil
+	}
+	userImport.PasswordsClearedAt = &at
+	for i := range r.rows[id] {
+		r.rows[id][i].Password = ""
+	}
+	return true, nil
+}
+
+type fakeSAMLConfigRepository struct {
+	repositories.SAMLConfigRepository
+	configs map[uuid.UUID]*models.SchoolSAMLConfig
+}
+
+func (r *fakeSAMLConfigRepository) FindBySchoolID(schoolID uuid.UUID) (*models.SchoolSAMLConfig, error) {
+	samlConfig, ok := r.configs[schoolID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *samlConfig
+	return &found, nil
+}
+
+func (r *fakeSAMLConfigRepository) Save(samlConfig *models.SchoolSAMLConfig) error {
+	saved := *samlConfig
+	r.configs[samlConfig.SchoolID] = &saved
+	return nil
+}
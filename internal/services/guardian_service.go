@@ -0,0 +1,220 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+	"auth-barniee/internal/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	studentInviteCodeLength = 8
+	studentInviteCodeTTL    = 7 * 24 * time.Hour
+)
+
+type GuardianService interface {
+	LinkParentToStudent(adminID, parentUserID, studentUserID uuid.UUID, relationship string) (*models.GuardianLink, error)
+	UnlinkGuardian(adminID, linkID uuid.UUID) error
+	GetStudentGuardians(adminID, studentUserID uuid.UUID) ([]models.GuardianLink, error)
+	CreateStudentInviteCode(adminID, studentUserID uuid.UUID) (*models.StudentInviteCode, error)
+	RedeemStudentInviteCode(parentUserID uuid.UUID, code, relationship string) (*models.GuardianLink, string, error) // Returns the link and a refreshed token
+	GetLinkedStudents(parentUserID uuid.UUID) ([]models.GuardianLink, error)
+}
+
+type guardianService struct {
+	guardianLinkRepo repositories.GuardianLinkRepository
+	inviteCodeRepo   repositories.StudentInviteCodeRepository
+	userRepo         repositories.UserRepository
+	tokenIssuer      TokenIssuer
+	uow              repositories.UnitOfWork
+}
+
+func NewGuardianService(
+	guardianLinkRepo repositories.GuardianLinkRepository,
+	inviteCodeRepo repositories.StudentInviteCodeRepository,
+	userRepo repositories.UserRepository,
+	tokenIssuer TokenIssuer,
+	uow repositories.UnitOfWork,
+) GuardianService {
+	return &guardianService{
+		guardianLinkRepo: guardianLinkRepo,
+		inviteCodeRepo:   inviteCodeRepo,
+		userRepo:         userRepo,
+		tokenIssuer:      tokenIssuer,
+		uow:              uow,
+	}
+}
+
+// findStudentInAdminSchool loads a student and checks that the admin manages the student's school.
+func (s *guardianService) findStudentInAdminSchool(adminID, studentUserID uuid.UUID) (*models.User, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+
+	student, err := s.userRepo.FindByID(studentUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student not found")
+		}
+		return nil, fmt.Errorf("failed to find student: %w", err)
+	}
+	if student.Role.SystemName() != "student" {
+		return nil, errors.New("user is not a student")
+	}
+	if !canAccessSchool(adminUser, student.SchoolID) {
+		return nil, errors.New("unauthorized: student does not belong to your school")
+	}
+	return student, nil
+}
+
+// createGuardianLink links a parent to a student unless they are linked already.
+func createGuardianLink(linkRepo repositories.GuardianLinkRepository, parentUserID, studentUserID uuid.UUID, relationship string, createdBy uuid.UUID) (*models.GuardianLink, error) {
+	_, err := linkRepo.FindByParentAndStudent(parentUserID, studentUserID)
+	if err == nil {
+		return nil, errors.New("parent is already linked to this student")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check existing guardian link: %w", err)
+	}
+
+	link := &models.GuardianLink{
+		ParentUserID:  parentUserID,
+		StudentUserID: studentUserID,
+		Relationship:  relationship,
+		CreatedBy:     createdBy,
+	}
+	if err := linkRepo.Create(link); err != nil {
+		return nil, fmt.Errorf("failed to create guardian link: %w", err)
+	}
+	return linkRepo.FindByID(link.ID)
+}
+
+func (s *guardianService) LinkParentToStudent(adminID, parentUserID, studentUserID uuid.UUID, relationship string) (*models.GuardianLink, error) {
+	if _, err := s.findStudentInAdminSchool(adminID, studentUserID); err != nil {
+		return nil, err
+	}
+
+	parent, err := s.userRepo.FindByID(parentUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("parent not found")
+		}
+		return nil, fmt.Errorf("failed to find parent: %w", err)
+	}
+	if parent.Role.SystemName() != "parent" {
+		return nil, errors.New("user is not a parent")
+	}
+
+	return createGuardianLink(s.guardianLinkRepo, parent.ID, studentUserID, relationship, adminID)
+}
+
+func (s *guardianService) UnlinkGuardian(adminID, linkID uuid.UUID) error {
+	link, err := s.guardianLinkRepo.FindByID(linkID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("guardian link not found")
+		}
+		return fmt.Errorf("failed to find guardian link: %w", err)
+	}
+	if _, err := s.findStudentInAdminSchool(adminID, link.StudentUserID); err != nil {
+		return err
+	}
+	return s.guardianLinkRepo.Delete(link.ID)
+}
+
+func (s *guardianService) GetStudentGuardians(adminID, studentUserID uuid.UUID) ([]models.GuardianLink, error) {
+	if _, err := s.findStudentInAdminSchool(adminID, studentUserID); err != nil {
+		return nil, err
+	}
+	links, err := s.guardianLinkRepo.FindByStudentUserID(studentUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve guardians: %w", err)
+	}
+	return links, nil
+}
+
+func (s *guardianService) CreateStudentInviteCode(adminID, studentUserID uuid.UUID) (*models.StudentInviteCode, error) {
+	if _, err := s.findStudentInAdminSchool(adminID, studentUserID); err != nil {
+		return nil, err
+	}
+
+	code, err := utils.GenerateInviteCode(studentInviteCodeLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate invite code: %w", err)
+	}
+
+	inviteCode := &models.StudentInviteCode{
+		StudentUserID: studentUserID,
+		Code:          code,
+		ExpiresAt:     time.Now().Add(studentInviteCodeTTL),
+		CreatedBy:     adminID,
+	}
+	if err := s.inviteCodeRepo.Create(inviteCode); err != nil {
+		return nil, fmt.Errorf("failed to save invite code: %w", err)
+	}
+	return inviteCode, nil
+}
+
+func (s *guardianService) RedeemStudentInviteCode(parentUserID uuid.UUID, code, relationship string) (*models.GuardianLink, string, error) {
+	parent, err := s.userRepo.FindByID(parentUserID)
+	if err != nil {
+		return nil, "", fmt.Errorf("parent user not found: %w", err)
+	}
+	if parent.Role.SystemName() != "parent" {
+		return nil, "", errors.New("only parents can redeem student invite codes")
+	}
+
+	inviteCode, err := s.inviteCodeRepo.FindByCode(code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", errors.New("invalid invite code")
+		}
+		return nil, "", fmt.Errorf("failed to find invite code: %w", err)
+	}
+	if inviteCode.RedeemedAt != nil {
+		return nil, "", errors.New("invite code has already been used")
+	}
+	if time.Now().After(inviteCode.ExpiresAt) {
+		return nil, "", errors.New("invite code has expired")
+	}
+
+	// Claiming the code and linking happen in one transaction, and the claim only succeeds for
+	// the first of two parents redeeming the same code at once.
+	var link *models.GuardianLink
+	err = s.uow.Do(func(repos repositories.Repositories) error {
+		redeemed, err := repos.StudentInviteCodes.MarkRedeemed(inviteCode.ID, parent.ID, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to mark invite code as used: %w", err)
+		}
+		if !redeemed {
+			return errors.New("invite code has already been used")
+		}
+		link, err = createGuardianLink(repos.GuardianLinks, parent.ID, inviteCode.StudentUserID, relationship, parent.ID)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The parent's current token does not list the new student yet.
+	token, err := s.tokenIssuer.IssueToken(parent)
+	if err != nil {
+		return nil, "", err
+	}
+	return link, token, nil
+}
+
+func (s *guardianService) GetLinkedStudents(parentUserID uuid.UUID) ([]models.GuardianLink, error) {
+	links, err := s.guardianLinkRepo.FindByParentUserID(parentUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve linked students: %w", err)
+	}
+	return links, nil
+}
@@ -0,0 +1,602 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InvoiceService interface {
+	GenerateInvoice(actorID, schoolID uuid.UUID) (*models.Invoice, error)
+	GenerateOrganizationInvoice(actorID, organizationID uuid.UUID) (*models.Invoice, error)
+	IssuePaidInvoice(payment *models.Payment, school *models.School, pkg *models.Package, lines []models.InvoiceLine) (*models.Invoice, error)
+	ListInvoices(filter repositories.InvoiceFilter) ([]models.Invoice, error)
+	GetInvoice(invoiceID uuid.UUID) (*models.Invoice, error)
+	GetSchoolInvoices(adminID uuid.UUID) ([]models.Invoice, error)
+	GetSchoolInvoice(adminID, invoiceID uuid.UUID) (*models.Invoice, error)
+	GetOrganizationInvoices(adminID uuid.UUID) ([]models.Invoice, error)
+	GetOrganizationInvoice(adminID, invoiceID uuid.UUID) (*models.Invoice, error)
+	MarkInvoicePaid(actorID, invoiceID uuid.UUID, paymentReference string, paidAt *time.Time) (*models.Invoice, error)
+	VoidInvoice(actorID, invoiceID uuid.UUID) (*models.Invoice, error)
+	RenderInvoiceHTML(invoice *models.Invoice) ([]byte, error)
+}
+
+type invoiceService struct {
+	invoiceRepo      repositories.InvoiceRepository
+	schoolRepo       repositories.SchoolRepository
+	organizationRepo repositories.OrganizationRepository
+	userRepo         repositories.UserRepository
+	subscription     SubscriptionService
+	uow              repositories.UnitOfWork
+	config           *config.Config
+}
+
+func NewInvoiceService(
+	invoiceRepo repositories.InvoiceRepository,
+	schoolRepo repositories.SchoolRepository,
+	organizationRepo repositories.OrganizationRepository,
+	userRepo repositories.UserRepository,
+	subscription SubscriptionService,
+	uow repositories.UnitOfWork,
+	cfg *config.Config,
+) InvoiceService {
+	return &invoiceService{
+		invoiceRepo:      invoiceRepo,
+		schoolRepo:       schoolRepo,
+		organizationRepo: organizationRepo,
+		userRepo:         userRepo,
+		subscription:     subscription,
+		uow:              uow,
+		config:           cfg,
+	}
+}
+
+// GenerateInvoice bills the school's next subscription period, the same period and student count
+// a checkout would charge. Paying the invoice adds the period to the school's subscription.
+func (s *invoiceService) GenerateInvoice(actorID, schoolID uuid.UUID) (*models.Invoice, error) {
+	school, err := s.schoolRepo.FindByID(schoolID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("school not found")
+		}
+		return nil, fmt.Errorf("failed to find school: %w", err)
+	}
+	if school.Package.IsTrial {
+		return nil, errors.New("trial packages are not invoiced")
+	}
+	if err := ensureBilledPerSchool(s.organizationRepo, school); err != nil {
+		return nil, err
+	}
+	exists, err := s.invoiceRepo.ExistsUnpaid(school.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing invoices: %w", err)
+	}
+	if exists {
+		return nil, errors.New("school already has an unpaid invoice")
+	}
+
+	studentCount, err := billableStudentCount(s.userRepo, school)
+	if err != nil {
+		return nil, err
+	}
+	charge := priceSubscription(school.Package, studentCount, s.config.PPNRatePercent)
+	if len(charge.Lines) == 0 {
+		return nil, errors.New("package has no price to invoice")
+	}
+
+	now := time.Now()
+	// Provisional: the period is fixed when the invoice is paid, see MarkInvoicePaid
+	periodStart, periodEnd := nextSubscriptionPeriod(school, &school.Package, now)
+	invoice := &models.Invoice{
+		SchoolID:       &school.ID,
+		SchoolName:     school.Name,
+		SchoolAddress:  school.Address,
+		PackageID:      &school.PackageID,
+		PackageName:    school.Package.Name,
+		PeriodStart:    periodStart,
+		PeriodEnd:      periodEnd,
+		StudentCount:   studentCount,
+		Subtotal:       charge.Subtotal,
+		TaxRatePercent: charge.TaxRatePercent,
+		TaxAmount:      charge.TaxAmount,
+		Total:          charge.Total,
+		Status:         models.InvoiceStatusIssued,
+		IssuedAt:       now,
+		DueDate:        now.AddDate(0, 0, s.config.InvoiceDueDays),
+		Lines:          charge.Lines,
+		CreatedBy:      actorID,
+		UpdatedBy:      actorID,
+	}
+	if err := s.invoiceRepo.CreateWithNextNumber(invoice); err != nil {
+		return nil, fmt.Errorf("failed to create invoice: %w", err)
+	}
+	return invoice, nil
+}
+
+// GenerateOrganizationInvoice bills every campus of an organization with consolidated billing on one invoice,
+// each for its next subscription period as GenerateInvoice would. Campuses on a trial or with an unpaid invoice
+// are skipped. Paying the invoice adds the period to every campus billed on it.
+func (s *invoiceService) GenerateOrganizationInvoice(actorID, organizationID uuid.UUID) (*models.Invoice, error) {
+	organization, err := s.organizationRepo.FindByID(organizationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("organization not found")
+		}
+		return nil, fmt.Errorf("failed to find organization: %w", err)
+	}
+	if !organization.ConsolidatedBilling {
+		return nil, errors.New("organization does not use consolidated billing")
+	}
+
+	now := time.Now()
+	invoice := &models.Invoice{
+		OrganizationID: &organization.ID,
+		SchoolName:     organization.Name,
+		SchoolAddress:  organization.Address,
+		TaxRatePercent: s.config.PPNRatePercent,
+		Status:         models.InvoiceStatusIssued,
+		CreatedBy:      actorID,
+		UpdatedBy:      actorID,
+	}
+	var packageNames []string
+	for _, campus := range organization.Schools {
+		if campus.Package.IsTrial {
+			continue
+		}
+		exists, err := s.invoiceRepo.ExistsUnpaid(campus.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing invoices: %w", err)
+		}
+		if exists {
+			continue
+		}
+
+		studentCount, err := billableStudentCount(s.userRepo, &campus)
+		if err != nil {
+			return nil, err
+		}
+		charge := priceSubscription(campus.Package, studentCount, s.config.PPNRatePercent)
+		if len(charge.Lines) == 0 {
+			continue
+		}
+		// Provisional like a school invoice's period; MarkInvoicePaid fixes it
+		periodStart, periodEnd := nextSubscriptionPeriod(&campus, &campus.Package, now)
+		for _, line := range charge.Lines {
+			line.Description = fmt.Sprintf("%s: %s", campus.Name, line.Description)
+			line.SchoolID = &campus.ID
+			line.PackageID = &campus.PackageID
+			line.PeriodStart = &periodStart
+			invoice.Lines = append(invoice.Lines, line)
+		}
+
+		if invoice.PeriodStart.IsZero() || periodStart.Before(invoice.PeriodStart) {
+			invoice.PeriodStart = periodStart
+		}
+		if periodEnd.After(invoice.PeriodEnd) {
+			invoice.PeriodEnd = periodEnd
+		}
+		invoice.StudentCount += studentCount
+		invoice.Subtotal += charge.Subtotal
+		if !slices.Contains(packageNames, campus.Package.Name) {
+			packageNames = append(packageNames, campus.Package.Name)
+		}
+	}
+	if len(invoice.Lines) == 0 {
+		return nil, errors.New("no campus has a paid package to invoice")
+	}
+
+	invoice.PackageName = strings.Join(packageNames, ", ")
+	invoice.TaxAmount = math.Round(invoice.Subtotal * float64(invoice.TaxRatePercent) / 100)
+	invoice.Total = invoice.Subtotal + invoice.TaxAmount
+	invoice.IssuedAt = now
+	invoice.DueDate = now.AddDate(0, 0, s.config.InvoiceDueDays)
+	if err := s.invoiceRepo.CreateWithNextNumber(invoice); err != nil {
+		return nil, fmt.Errorf("failed to create invoice: %w", err)
+	}
+	return invoice, nil
+}
+
+// IssuePaidInvoice records a gateway payment as an invoice that is already paid.
+func (s *invoiceService) IssuePaidInvoice(payment *models.Payment, school *models.School, pkg *models.Package, lines []models.InvoiceLine) (*models.Invoice, error) {
+	invoice := &models.Invoice{
+		SchoolID:         &school.ID,
+		SchoolName:       school.Name,
+		SchoolAddress:    school.Address,
+		PackageID:        &pkg.ID,
+		PackageName:      pkg.Name,
+		PeriodStart:      payment.PeriodStart,
+		PeriodEnd:        payment.PeriodEnd,
+		StudentCount:     payment.StudentCount,
+		Subtotal:         payment.Subtotal,
+		TaxRatePercent:   payment.TaxRatePercent,
+		TaxAmount:        payment.TaxAmount,
+		Total:            payment.Amount,
+		Status:           models.InvoiceStatusPaid,
+		IssuedAt:         *payment.PaidAt,
+		DueDate:          *payment.PaidAt,
+		PaidAt:           payment.PaidAt,
+		PaymentReference: payment.ProviderReference,
+		Lines:            lines,
+		CreatedBy:        payment.CreatedBy,
+		UpdatedBy:        payment.CreatedBy,
+	}
+	if err := s.invoiceRepo.CreateWithNextNumber(invoice); err != nil {
+		return nil, fmt.Errorf("failed to create invoice: %w", err)
+	}
+	return invoice, nil
+}
+
+// subscriptionCharge is the price of one subscription period, including PPN.
+type subscriptionCharge struct {
+	Lines          []models.InvoiceLine
+	Subtotal       float64
+	TaxRatePercent int
+	TaxAmount      float64
+	Total          float64
+}
+
+// priceSubscription charges the yearly package price plus the per-student price for studentCount students.
+func priceSubscription(pkg models.Package, studentCount int64, taxRatePercent int) subscriptionCharge {
+	charge := subscriptionCharge{
+		Lines:          invoiceLines(pkg, studentCount),
+		TaxRatePercent: taxRatePercent,
+	}
+	for _, line := range charge.Lines {
+		charge.Subtotal += line.Amount
+	}
+	// Rupiah has no minor unit in practice, so tax is rounded to whole rupiah
+	charge.TaxAmount = math.Round(charge.Subtotal * float64(taxRatePercent) / 100)
+	charge.Total = charge.Subtotal + charge.TaxAmount
+	return charge
+}
+
+// withCredit deducts credit from the subtotal and recomputes PPN on what is left.
+func (c subscriptionCharge) withCredit(credit float64) subscriptionCharge {
+	if credit <= 0 {
+		return c
+	}
+	c.Subtotal = max(c.Subtotal-credit, 0)
+	c.TaxAmount = math.Round(c.Subtotal * float64(c.TaxRatePercent) / 100)
+	c.Total = c.Subtotal + c.TaxAmount
+	return c
+}
+
+func creditLine(description string, amount float64) models.InvoiceLine {
+	return models.InvoiceLine{
+		Description: description,
+		Quantity:    1,
+		UnitPrice:   -amount,
+		Amount:      -amount,
+	}
+}
+
+func invoiceLines(pkg models.Package, studentCount int64) []models.InvoiceLine {
+	var lines []models.InvoiceLine
+	if pkg.PricePerYear != nil && *pkg.PricePerYear > 0 {
+		lines = append(lines, models.InvoiceLine{
+			Description: fmt.Sprintf("Langganan paket %s", pkg.Name),
+			Quantity:    1,
+			UnitPrice:   *pkg.PricePerYear,
+			Amount:      *pkg.PricePerYear,
+		})
+	}
+	if pkg.PricePerStudent != nil && *pkg.PricePerStudent > 0 && studentCount > 0 {
+		lines = append(lines, models.InvoiceLine{
+			Description: fmt.Sprintf("Paket %s per siswa", pkg.Name),
+			Quantity:    studentCount,
+			UnitPrice:   *pkg.PricePerStudent,
+			Amount:      *pkg.PricePerStudent * float64(studentCount),
+		})
+	}
+	return lines
+}
+
+func (s *invoiceService) ListInvoices(filter repositories.InvoiceFilter) ([]models.Invoice, error) {
+	invoices, err := s.invoiceRepo.FindAll(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+func (s *invoiceService) GetInvoice(invoiceID uuid.UUID) (*models.Invoice, error) {
+	invoice, err := s.invoiceRepo.FindByID(invoiceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invoice not found")
+		}
+		return nil, fmt.Errorf("failed to find invoice: %w", err)
+	}
+	return invoice, nil
+}
+
+func (s *invoiceService) adminSchoolID(adminID uuid.UUID) (uuid.UUID, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	if adminUser.SchoolID == uuid.Nil {
+		return uuid.Nil, errors.New("admin is not associated with a school")
+	}
+	return adminUser.SchoolID, nil
+}
+
+func (s *invoiceService) GetSchoolInvoices(adminID uuid.UUID) ([]models.Invoice, error) {
+	schoolID, err := s.adminSchoolID(adminID)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListInvoices(repositories.InvoiceFilter{SchoolID: &schoolID})
+}
+
+func (s *invoiceService) GetSchoolInvoice(adminID, invoiceID uuid.UUID) (*models.Invoice, error) {
+	schoolID, err := s.adminSchoolID(adminID)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := s.GetInvoice(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.SchoolID == nil || *invoice.SchoolID != schoolID {
+		return nil, errors.New("invoice not found")
+	}
+	return invoice, nil
+}
+
+func (s *invoiceService) adminOrganizationID(adminID uuid.UUID) (uuid.UUID, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	if adminUser.OrganizationID == nil {
+		return uuid.Nil, errors.New("admin is not associated with an organization")
+	}
+	return *adminUser.OrganizationID, nil
+}
+
+func (s *invoiceService) GetOrganizationInvoices(adminID uuid.UUID) ([]models.Invoice, error) {
+	organizationID, err := s.adminOrganizationID(adminID)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListInvoices(repositories.InvoiceFilter{OrganizationID: &organizationID})
+}
+
+func (s *invoiceService) GetOrganizationInvoice(adminID, invoiceID uuid.UUID) (*models.Invoice, error) {
+	organizationID, err := s.adminOrganizationID(adminID)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := s.GetInvoice(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.OrganizationID == nil || *invoice.OrganizationID != organizationID {
+		return nil, errors.New("invoice not found")
+	}
+	return invoice, nil
+}
+
+func (s *invoiceService) MarkInvoicePaid(actorID, invoiceID uuid.UUID, paymentReference string, paidAt *time.Time) (*models.Invoice, error) {
+	invoice, err := s.GetInvoice(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	switch invoice.Status {
+	case models.InvoiceStatusPaid:
+		return nil, errors.New("invoice is already paid")
+	case models.InvoiceStatusVoid:
+		return nil, errors.New("void invoices cannot be paid")
+	}
+
+	if paidAt == nil {
+		now := time.Now()
+		paidAt = &now
+	}
+	invoice.Status = models.InvoiceStatusPaid
+	invoice.PaidAt = paidAt
+	invoice.PaidBy = &actorID
+	invoice.PaymentReference = paymentReference
+	invoice.UpdatedBy = actorID
+
+	// Paying an invoice adds its period to the subscription of the school, or of each campus on an
+	// organization invoice. The conditional update makes sure two concurrent requests cannot both extend it.
+	err = s.uow.Do(func(repos repositories.Repositories) error {
+		if invoice.SchoolID != nil && invoice.PackageID != nil {
+			start, end, err := s.extendInvoicedSchool(repos, invoice, *invoice.SchoolID, *invoice.PackageID)
+			if err != nil {
+				return err
+			}
+			invoice.PeriodStart, invoice.PeriodEnd = start, end
+		}
+		if invoice.OrganizationID != nil {
+			if err := s.extendInvoicedCampuses(repos, invoice); err != nil {
+				return err
+			}
+		}
+		paid, err := repos.Invoices.MarkPaid(invoice)
+		if err != nil {
+			return fmt.Errorf("failed to mark invoice as paid: %w", err)
+		}
+		if !paid {
+			return errors.New("invoice is already paid")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// extendInvoicedSchool adds the paid invoice's period to the school, moves it to the invoiced
+// package and returns the period added. The period is recomputed from the payment date, as the
+// invoice may be paid late.
+func (s *invoiceService) extendInvoicedSchool(repos repositories.Repositories, invoice *models.Invoice, schoolID, packageID uuid.UUID) (time.Time, time.Time, error) {
+	school, err := repos.Schools.FindByIDForUpdate(schoolID)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to find school: %w", err)
+	}
+	pkg, err := repos.Packages.FindByID(packageID)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to find package: %w", err)
+	}
+
+	start, end := extendSubscription(school, pkg, *invoice.PaidAt)
+	switchSchoolPackage(school, pkg)
+	school.SubscriptionStatus = s.subscription.EvaluateStatus(school, time.Now())
+	school.UpdatedBy = invoice.UpdatedBy
+	if err := repos.Schools.Update(school); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to update subscription: %w", err)
+	}
+	return start, end, nil
+}
+
+// extendInvoicedCampuses extends every campus billed on a paid organization invoice and sets the
+// periods of its lines and of the invoice to the ones added.
+func (s *invoiceService) extendInvoicedCampuses(repos repositories.Repositories, invoice *models.Invoice) error {
+	periodStarts := make(map[uuid.UUID]time.Time)
+	invoice.PeriodStart, invoice.PeriodEnd = time.Time{}, time.Time{}
+	for i := range invoice.Lines {
+		line := &invoice.Lines[i]
+		if line.SchoolID == nil {
+			continue
+		}
+		// A campus billed per year and per student has several lines but one period
+		start, ok := periodStarts[*line.SchoolID]
+		if !ok {
+			packageID, err := invoicedCampusPackage(repos, line)
+			if err != nil {
+				return err
+			}
+			var end time.Time
+			start, end, err = s.extendInvoicedSchool(repos, invoice, *line.SchoolID, packageID)
+			if err != nil {
+				return err
+			}
+			periodStarts[*line.SchoolID] = start
+			if invoice.PeriodStart.IsZero() || start.Before(invoice.PeriodStart) {
+				invoice.PeriodStart = start
+			}
+			if end.After(invoice.PeriodEnd) {
+				invoice.PeriodEnd = end
+			}
+		}
+		line.PeriodStart = &start
+	}
+	if len(periodStarts) == 0 {
+		return errors.New("invoice has no campus to extend")
+	}
+	return nil
+}
+
+// invoicedCampusPackage is the package billed on an organization invoice line. Lines issued before
+// the package was recorded bill the campus's current package.
+func invoicedCampusPackage(repos repositories.Repositories, line *models.InvoiceLine) (uuid.UUID, error) {
+	if line.PackageID != nil {
+		return *line.PackageID, nil
+	}
+	campus, err := repos.Schools.FindByID(*line.SchoolID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to find school: %w", err)
+	}
+	return campus.PackageID, nil
+}
+
+func (s *invoiceService) VoidInvoice(actorID, invoiceID uuid.UUID) (*models.Invoice, error) {
+	invoice, err := s.GetInvoice(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	switch invoice.Status {
+	case models.InvoiceStatusPaid:
+		return nil, errors.New("paid invoices cannot be voided")
+	case models.InvoiceStatusVoid:
+		return nil, errors.New("invoice is already void")
+	}
+
+	invoice.Status = models.InvoiceStatusVoid
+	invoice.UpdatedBy = actorID
+	if err := s.invoiceRepo.Update(invoice); err != nil {
+		return nil, fmt.Errorf("failed to void invoice: %w", err)
+	}
+	return invoice, nil
+}
+
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"rupiah": formatRupiah,
+	"date":   func(t time.Time) string { return t.Format("02-01-2006") },
+}).Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.Number}}</title>
+<style>
+body { font-family: Arial, sans-serif; color: #222; margin: 40px; }
+table { width: 100%; border-collapse: collapse; margin-top: 24px; }
+th, td { padding: 8px; border-bottom: 1px solid #ddd; text-align: left; }
+td.amount, th.amount { text-align: right; }
+.status { text-transform: uppercase; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Invoice</h1>
+<p><strong>Nomor:</strong> {{.Number}}<br>
+<strong>Tanggal terbit:</strong> {{date .IssuedAt}}<br>
+<strong>Jatuh tempo:</strong> {{date .DueDate}}<br>
+<strong>Status:</strong> <span class="status">{{.Status}}</span></p>
+<p><strong>Kepada:</strong><br>{{.SchoolName}}<br>{{.SchoolAddress}}</p>
+<p><strong>Paket:</strong> {{.PackageName}}<br>
+<strong>Periode:</strong> {{date .PeriodStart}} s.d. {{date .PeriodEnd}}<br>
+<strong>Jumlah siswa:</strong> {{.StudentCount}}</p>
+<table>
+<tr><th>Deskripsi</th><th class="amount">Jumlah</th><th class="amount">Harga Satuan</th><th class="amount">Total</th></tr>
+{{range .Lines}}<tr><td>{{.Description}}</td><td class="amount">{{.Quantity}}</td><td class="amount">{{rupiah .UnitPrice}}</td><td class="amount">{{rupiah .Amount}}</td></tr>
+{{end}}<tr><td colspan="3" class="amount">Subtotal</td><td class="amount">{{rupiah .Subtotal}}</td></tr>
+<tr><td colspan="3" class="amount">PPN {{.TaxRatePercent}}%</td><td class="amount">{{rupiah .TaxAmount}}</td></tr>
+<tr><td colspan="3" class="amount"><strong>Total</strong></td><td class="amount"><strong>{{rupiah .Total}}</strong></td></tr>
+</table>
+{{if .PaidAt}}<p>Dibayar pada {{date .PaidAt}}{{if .PaymentReference}} (referensi {{.PaymentReference}}){{end}}.</p>{{end}}
+</body>
+</html>
+`))
+
+func (s *invoiceService) RenderInvoiceHTML(invoice *models.Invoice) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := invoiceTemplate.Execute(&buf, invoice); err != nil {
+		return nil, fmt.Errorf("failed to render invoice: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// formatRupiah formats an amount as Indonesian currency, e.g. Rp 1.250.000.
+func formatRupiah(amount float64) string {
+	digits := fmt.Sprintf("%.0f", math.Round(amount))
+	negative := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	if negative {
+		return "-Rp " + grouped.String()
+	}
+	return "Rp " + grouped.String()
+}
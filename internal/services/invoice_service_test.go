@@ -0,0 +1,210 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type billingFixture struct {
+	config        *config.Config
+	schools       *fakeSchoolRepository
+	organizations *fakeOrganizationRepository
+	users         *fakeUserRepository
+	packages      *fakePackageRepository
+	invoices      *fakeInvoiceRepository
+	uow           *fakeUnitOfWork
+	premium       *models.Package
+	school        *models.School
+	actorID       uuid.UUID
+}
+
+// newBillingFixture returns a Premium school that declared 100 students at registration but has
+// enrolled only three so far, and has not paid for a period yet.
+func newBillingFixture() *billingFixture {
+	pricePerStudent := 50000.0
+	premium := &models.Package{ID: uuid.New(), Name: "Premium", PricePerStudent: &pricePerStudent}
+	school := &models.School{ID: uuid.New(), Name: "SMA Barniee", PackageID: premium.ID, Package: *premium,
+		InitialStudentCount: 100, RegistrationStatus: models.RegistrationStatusCompleted}
+
+	studentRole := models.Role{ID: uuid.New(), Name: "student"}
+	users := newFakeUserRepository()
+	for range 3 {
+		student := &models.User{ID: uuid.New(), RoleID: studentRole.ID, Role: studentRole, SchoolID: school.ID}
+		users.users[student.ID] = student
+	}
+
+	f := &billingFixture{
+		config:   &config.Config{PPNRatePercent: 11, InvoiceDueDays: 14, SubscriptionPastDueDays: 7, SubscriptionGraceDays: 14},
+		schools:  newFakeSchoolRepository(school),
+		users:    users,
+		packages: &fakePackageRepository{packages: []*models.Package{premium}},
+		invoices: newFakeInvoiceRepository(),
+		premium:  premium,
+		school:   school,
+		actorID:  uuid.New(),
+	}
+	f.organizations = &fakeOrganizationRepository{organizations: make(map[uuid.UUID]*models.Organization), schools: f.schools}
+	f.uow = &fakeUnitOfWork{repos: repositories.Repositories{Schools: f.schools, Users: f.users, Packages: f.packages, Invoices: f.invoices}}
+	return f
+}
+
+func (f *billingFixture) invoiceService() InvoiceService {
+	return NewInvoiceService(f.invoices, f.schools, f.organizations, f.users, NewSubscriptionService(f.schools, f.config), f.uow, f.config)
+}
+
+func (f *billingFixture) savedSchool() *models.School {
+	return f.schools.schools[f.school.ID]
+}
+
+func assertAbout(t *testing.T, name string, got, want time.Time) {
+	t.Helper()
+	if d := got.Sub(want); d > time.Minute || d < -time.Minute {
+		t.Errorf("%s = %s, want about %s", name, got.Format(time.RFC3339), want.Format(time.RFC3339))
+	}
+}
+
+func TestGenerateInvoiceForSchoolWithoutPaidPeriod(t *testing.T) {
+	f := newBillingFixture()
+	service := f.invoiceService()
+
+	invoice, err := service.GenerateInvoice(f.actorID, f.school.ID)
+	if err != nil {
+		t.Fatalf("GenerateInvoice returned error: %v", err)
+	}
+	if invoice.StudentCount != 100 {
+		t.Errorf("student count = %d, want the 100 declared at registration", invoice.StudentCount)
+	}
+	if invoice.Subtotal != 5000000 || invoice.Total != 5550000 {
+		t.Errorf("subtotal, total = %.0f, %.0f; want 5000000, 5550000", invoice.Subtotal, invoice.Total)
+	}
+	assertAbout(t, "period start", invoice.PeriodStart, time.Now())
+	assertAbout(t, "period end", invoice.PeriodEnd, time.Now().AddDate(0, 0, defaultSubscriptionDays))
+
+	if _, err := service.GenerateInvoice(f.actorID, f.school.ID); err == nil || err.Error() != "school already has an unpaid invoice" {
+		t.Errorf("second GenerateInvoice error = %v, want the unpaid invoice to block it", err)
+	}
+}
+
+func TestMarkInvoicePaidStartsTheSubscription(t *testing.T) {
+	f := newBillingFixture()
+	service := f.invoiceService()
+	invoice, err := service.GenerateInvoice(f.actorID, f.school.ID)
+	if err != nil {
+		t.Fatalf("GenerateInvoice returned error: %v", err)
+	}
+
+	paidAt := time.Now().Add(-48 * time.Hour) // Transfer recorded two days late
+	paid, err := service.MarkInvoicePaid(f.actorID, invoice.ID, "TRF-001", &paidAt)
+	if err != nil {
+		t.Fatalf("MarkInvoicePaid returned error: %v", err)
+	}
+
+	school := f.savedSchool()
+	if school.SubscriptionStartDate == nil || school.SubscriptionEndDate == nil {
+		t.Fatal("paying the invoice left the school without a subscription period")
+	}
+	assertAbout(t, "subscription start", *school.SubscriptionStartDate, paidAt)
+	assertAbout(t, "subscription end", *school.SubscriptionEndDate, paidAt.AddDate(0, 0, defaultSubscriptionDays))
+	if school.SubscriptionStatus != models.SubscriptionStatusActive {
+		t.Errorf("subscription status = %q, want active", school.SubscriptionStatus)
+	}
+	if !paid.PeriodStart.Equal(*school.SubscriptionStartDate) || !paid.PeriodEnd.Equal(*school.SubscriptionEndDate) {
+		t.Errorf("invoice period %s - %s does not match the subscription", paid.PeriodStart, paid.PeriodEnd)
+	}
+
+	end := *school.SubscriptionEndDate
+	if _, err := service.MarkInvoicePaid(f.actorID, invoice.ID, "TRF-001", nil); err == nil {
+		t.Error("an invoice was paid twice")
+	}
+	if !f.savedSchool().SubscriptionEndDate.Equal(end) {
+		t.Error("paying the invoice again extended the subscription again")
+	}
+}
+
+func TestMarkInvoicePaidRenewsFromTheEndDate(t *testing.T) {
+	f := newBillingFixture()
+	start, end := time.Now().AddDate(0, 0, -355), time.Now().AddDate(0, 0, 10)
+	f.school.SubscriptionStartDate, f.school.SubscriptionEndDate = &start, &end
+
+	service := f.invoiceService()
+	invoice, err := service.GenerateInvoice(f.actorID, f.school.ID)
+	if err != nil {
+		t.Fatalf("GenerateInvoice returned error: %v", err)
+	}
+	if !invoice.PeriodStart.Equal(end) {
+		t.Errorf("renewal invoice starts %s, want the current end date %s", invoice.PeriodStart, end)
+	}
+
+	if _, err := service.MarkInvoicePaid(f.actorID, invoice.ID, "TRF-002", nil); err != nil {
+		t.Fatalf("MarkInvoicePaid returned error: %v", err)
+	}
+	school := f.savedSchool()
+	if !school.SubscriptionStartDate.Equal(start) {
+		t.Errorf("subscription start moved to %s on renewal", school.SubscriptionStartDate)
+	}
+	if want := end.AddDate(0, 0, defaultSubscriptionDays); !school.SubscriptionEndDate.Equal(want) {
+		t.Errorf("subscription end = %s, want %s", school.SubscriptionEndDate, want)
+	}
+}
+
+// addOrganization makes the schools Enterprise campuses of a foundation with consolidated billing.
+func (f *billingFixture) addOrganization(campuses ...*models.School) *models.Organization {
+	pricePerYear := 10000000.0
+	enterprise := &models.Package{ID: uuid.New(), Name: "Enterprise", PricePerYear: &pricePerYear, MultiCampus: true}
+	f.packages.packages = append(f.packages.packages, enterprise)
+
+	organization := &models.Organization{ID: uuid.New(), Name: "Yayasan Barniee", ConsolidatedBilling: true}
+	f.organizations.organizations[organization.ID] = organization
+	for _, campus := range campuses {
+		campus.OrganizationID = &organization.ID
+		campus.PackageID, campus.Package = enterprise.ID, *enterprise
+		f.schools.schools[campus.ID] = campus
+	}
+	return organization
+}
+
+func TestPayingAnOrganizationInvoiceExtendsEveryCampus(t *testing.T) {
+	f := newBillingFixture()
+	start, end := time.Now().AddDate(0, 0, -355), time.Now().AddDate(0, 0, 10)
+	renewing := &models.School{ID: uuid.New(), Name: "SMA Barniee Bandung", SubscriptionStartDate: &start, SubscriptionEndDate: &end}
+	joining := &models.School{ID: uuid.New(), Name: "SMA Barniee Bogor"} // Never paid for a period
+	organization := f.addOrganization(renewing, joining)
+	service := f.invoiceService()
+
+	invoice, err := service.GenerateOrganizationInvoice(f.actorID, organization.ID)
+	if err != nil {
+		t.Fatalf("GenerateOrganizationInvoice returned error: %v", err)
+	}
+	if len(invoice.Lines) != 2 || invoice.Total != 22200000 {
+		t.Fatalf("lines, total = %d, %.0f; want both campuses billed for 22200000", len(invoice.Lines), invoice.Total)
+	}
+	if _, err := service.GenerateOrganizationInvoice(f.actorID, organization.ID); err == nil {
+		t.Error("campuses with an unpaid invoice were invoiced again")
+	}
+	if _, err := service.GenerateInvoice(f.actorID, joining.ID); err == nil || err.Error() != "school is billed through its organization" {
+		t.Errorf("GenerateInvoice for a campus error = %v, want it billed through the organization", err)
+	}
+
+	paid, err := service.MarkInvoicePaid(f.actorID, invoice.ID, "TRF-003", nil)
+	if err != nil {
+		t.Fatalf("MarkInvoicePaid returned error: %v", err)
+	}
+	if want := end.AddDate(0, 0, defaultSubscriptionDays); !f.schools.schools[renewing.ID].SubscriptionEndDate.Equal(want) {
+		t.Errorf("renewing campus ends %s, want %s", f.schools.schools[renewing.ID].SubscriptionEndDate, want)
+	}
+	joined := f.schools.schools[joining.ID]
+	if joined.SubscriptionEndDate == nil || joined.SubscriptionStatus != models.SubscriptionStatusActive {
+		t.Fatalf("joining campus status = %q, want an active subscription", joined.SubscriptionStatus)
+	}
+	assertAbout(t, "joining campus end", *joined.SubscriptionEndDate, time.Now().AddDate(0, 0, defaultSubscriptionDays))
+	assertAbout(t, "invoice period start", paid.PeriodStart, time.Now())
+	if !paid.PeriodEnd.Equal(end.AddDate(0, 0, defaultSubscriptionDays)) {
+		t.Errorf("invoice period ends %s, want the latest campus end", paid.PeriodEnd)
+	}
+}
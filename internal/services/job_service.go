@@ -0,0 +1,39 @@
+package services
+
+import (
+	"fmt"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+)
+
+const (
+	defaultJobRunsLimit = 50
+	maxJobRunsLimit     = 500
+)
+
+type JobService interface {
+	GetJobRuns(jobName string, limit int) ([]models.JobRun, error)
+}
+
+type jobService struct {
+	jobRepo repositories.JobRepository
+}
+
+func NewJobService(jobRepo repositories.JobRepository) JobService {
+	return &jobService{jobRepo: jobRepo}
+}
+
+func (s *jobService) GetJobRuns(jobName string, limit int) ([]models.JobRun, error) {
+	if limit <= 0 {
+		limit = defaultJobRunsLimit
+	}
+	if limit > maxJobRunsLimit {
+		limit = maxJobRunsLimit
+	}
+	runs, err := s.jobRepo.FindRuns(jobName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve job runs: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LDAPIdentity is the directory entry a user authenticated as.
+type LDAPIdentity struct {
+	DN     string
+	Name   string
+	Groups []string
+}
+
+// LDAPAuthenticator verifies credentials against a school's directory.
+// It is an interface so login can be exercised against a local LDAP stand-in or a fake.
+type LDAPAuthenticator interface {
+	Authenticate(ldapConfig *models.SchoolLDAPConfig, email, password string) (*LDAPIdentity, error)
+}
+
+type ldapBindAuthenticator struct {
+	timeout time.Duration
+}
+
+func NewLDAPAuthenticator() LDAPAuthenticator {
+	return &ldapBindAuthenticator{timeout: 10 * time.Second}
+}
+
+func (a *ldapBindAuthenticator) Authenticate(ldapConfig *models.SchoolLDAPConfig, email, password string) (*LDAPIdentity, error) {
+	// An empty password would turn into an unauthenticated bind, which most servers accept.
+	if password == "" {
+		return nil, errors.New("invalid credentials: incorrect password")
+	}
+
+	conn, err := ldap.DialURL(ldapConfig.URL, ldap.DialWithDialer(&net.Dialer{Timeout: a.timeout}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to LDAP server: %w", err)
+	}
+	defer conn.Close()
+	conn.SetTimeout(a.timeout)
+
+	if ldapConfig.StartTLS {
+		serverURL, err := url.Parse(ldapConfig.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LDAP URL: %w", err)
+		}
+		if err := conn.StartTLS(&tls.Config{ServerName: serverURL.Hostname()}); err != nil {
+			return nil, fmt.Errorf("failed to start TLS with LDAP server: %w", err)
+		}
+	}
+
+	if ldapConfig.BindDN != "" {
+		if err := conn.Bind(ldapConfig.BindDN, ldapConfig.BindPassword); err != nil {
+			return nil, fmt.Errorf("failed to bind LDAP service account: %w", err)
+		}
+	}
+
+	attributes := []string{"dn"}
+	if ldapConfig.NameAttribute != "" {
+		attributes = append(attributes, ldapConfig.NameAttribute)
+	}
+	if ldapConfig.GroupAttribute != "" {
+		attributes = append(attributes, ldapConfig.GroupAttribute)
+	}
+	searchRequest := ldap.NewSearchRequest(
+		ldapConfig.BaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, int(a.timeout.Seconds()), false,
+		fmt.Sprintf(ldapConfig.UserFilter, ldap.EscapeFilter(email)),
+		attributes,
+		nil,
+	)
+	result, err := conn.Search(searchRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search LDAP directory: %w", err)
+	}
+	if len(result.Entries) != 1 {
+		return nil, errors.New("invalid credentials: user not found in directory")
+	}
+	entry := result.Entries[0]
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, errors.New("invalid credentials: incorrect password")
+		}
+		return nil, fmt.Errorf("failed to bind as LDAP user: %w", err)
+	}
+
+	identity := &LDAPIdentity{DN: entry.DN}
+	if ldapConfig.NameAttribute != "" {
+		identity.Name = entry.GetAttributeValue(ldapConfig.NameAttribute)
+	}
+	if ldapConfig.GroupAttribute != "" {
+		identity.Groups = entry.GetAttributeValues(ldapConfig.GroupAttribute)
+	}
+	return identity, nil
+}
+
+// mapLDAPGroupsToRole returns "admin" or "teacher" depending on group membership, admin taking precedence.
+func mapLDAPGroupsToRole(ldapConfig *models.SchoolLDAPConfig, groups []string) (string, error) {
+	var adminGroups, teacherGroups []string
+	if ldapConfig.AdminGroups != "" {
+		if err := json.Unmarshal([]byte(ldapConfig.AdminGroups), &adminGroups); err != nil {
+			return "", fmt.Errorf("invalid LDAP admin groups: %w", err)
+		}
+	}
+	if ldapConfig.TeacherGroups != "" {
+		if err := json.Unmarshal([]byte(ldapConfig.TeacherGroups), &teacherGroups); err != nil {
+			return "", fmt.Errorf("invalid LDAP teacher groups: %w", err)
+		}
+	}
+
+	if ldapGroupsIntersect(groups, adminGroups) {
+		return "admin", nil
+	}
+	if ldapGroupsIntersect(groups, teacherGroups) {
+		return "teacher", nil
+	}
+	return "", errors.New("invalid credentials: user is not a member of an authorized directory group")
+}
+
+func ldapGroupsIntersect(groups, allowed []string) bool {
+	for _, group := range groups {
+		for _, candidate := range allowed {
+			if strings.EqualFold(strings.TrimSpace(group), strings.TrimSpace(candidate)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type LDAPConfigInput struct {
+	Enabled        bool
+	URL            string
+	StartTLS       bool
+	BindDN         string
+	BindPassword   *string
+	BaseDN         string
+	UserFilter     string
+	NameAttribute  string
+	GroupAttribute string
+	AdminGroups    []string
+	TeacherGroups  []string
+}
+
+type LDAPConfigService interface {
+	GetConfig(adminID uuid.UUID) (*models.SchoolLDAPConfig, error)
+	SaveConfig(adminID uuid.UUID, input LDAPConfigInput) (*models.SchoolLDAPConfig, error)
+}
+
+type ldapConfigService struct {
+	ldapConfigRepo repositories.LDAPConfigRepository
+	userRepo       repositories.UserRepository
+}
+
+func NewLDAPConfigService(ldapConfigRepo repositories.LDAPConfigRepository, userRepo repositories.UserRepository) LDAPConfigService {
+	return &ldapConfigService{
+		ldapConfigRepo: ldapConfigRepo,
+		userRepo:       userRepo,
+	}
+}
+
+func (s *ldapConfigService) adminSchoolID(adminID uuid.UUID) (uuid.UUID, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	if adminUser.SchoolID == uuid.Nil {
+		return uuid.Nil, errors.New("admin is not associated with a school")
+	}
+	return adminUser.SchoolID, nil
+}
+
+func (s *ldapConfigService) GetConfig(adminID uuid.UUID) (*models.SchoolLDAPConfig, error) {
+	schoolID, err := s.adminSchoolID(adminID)
+	if err != nil {
+		return nil, err
+	}
+	ldapConfig, err := s.ldapConfigRepo.FindBySchoolID(schoolID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("LDAP config not found")
+		}
+		return nil, fmt.Errorf("failed to find LDAP config: %w", err)
+	}
+	return ldapConfig, nil
+}
+
+func (s *ldapConfigService) SaveConfig(adminID uuid.UUID, input LDAPConfigInput) (*models.SchoolLDAPConfig, error) {
+	schoolID, err := s.adminSchoolID(adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(input.URL, "ldap://") && !strings.HasPrefix(input.URL, "ldaps://") {
+		return nil, errors.New("LDAP URL must start with ldap:// or ldaps://")
+	}
+	if strings.Count(input.UserFilter, "%s") != 1 {
+		return nil, errors.New("user filter must contain exactly one %s placeholder for the email")
+	}
+	if input.Enabled && len(input.AdminGroups) == 0 && len(input.TeacherGroups) == 0 {
+		return nil, errors.New("at least one admin or teacher group is required to enable LDAP")
+	}
+	adminGroups, err := json.Marshal(input.AdminGroups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode admin groups: %w", err)
+	}
+	teacherGroups, err := json.Marshal(input.TeacherGroups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode teacher groups: %w", err)
+	}
+
+	ldapConfig, err := s.ldapConfigRepo.FindBySchoolID(schoolID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to find LDAP config: %w", err)
+		}
+		ldapConfig = &models.SchoolLDAPConfig{SchoolID: schoolID, CreatedBy: adminID}
+	}
+
+	ldapConfig.Enabled = input.Enabled
+	ldapConfig.URL = input.URL
+	ldapConfig.StartTLS = input.StartTLS
+	ldapConfig.BindDN = input.BindDN
+	if input.BindPassword != nil {
+		ldapConfig.BindPassword = *input.BindPassword
+	}
+	ldapConfig.BaseDN = input.BaseDN
+	ldapConfig.UserFilter = input.UserFilter
+	ldapConfig.NameAttribute = input.NameAttribute
+	ldapConfig.GroupAttribute = input.GroupAttribute
+	ldapConfig.AdminGroups = string(adminGroups)
+	ldapConfig.TeacherGroups = string(teacherGroups)
+	ldapConfig.UpdatedBy = adminID
+
+	if err := s.ldapConfigRepo.Save(ldapConfig); err != nil {
+		return nil, fmt.Errorf("failed to save LDAP config: %w", err)
+	}
+	return ldapConfig, nil
+}
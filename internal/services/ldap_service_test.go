@@ -0,0 +1,125 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"auth-barniee/internal/models"
+)
+
+const (
+	standInServiceDN = "cn=barniee,ou=services,dc=sekolah,dc=sch,dc=id"
+	standInTeacherDN = "uid=budi,ou=staff,dc=sekolah,dc=sch,dc=id"
+)
+
+func newSchoolDirectory(t *testing.T) *ldapStandIn {
+	return newLDAPStandIn(t,
+		ldapStandInEntry{DN: standInServiceDN, Password: "service-secret"},
+		ldapStandInEntry{
+			DN:       standInTeacherDN,
+			Password: "directory-pass",
+			Attributes: map[string][]string{
+				"mail":     {"budi@sekolah.sch.id"},
+				"cn":       {"Budi Santoso"},
+				"memberOf": {"cn=guru,ou=groups,dc=sekolah,dc=sch,dc=id"},
+			},
+		},
+	)
+}
+
+func standInLDAPConfig(directory *ldapStandIn) *models.SchoolLDAPConfig {
+	return &models.SchoolLDAPConfig{
+		Enabled:        true,
+		URL:            directory.URL(),
+		BindDN:         standInServiceDN,
+		BindPassword:   "service-secret",
+		BaseDN:         "dc=sekolah,dc=sch,dc=id",
+		UserFilter:     "(mail=%s)",
+		NameAttribute:  "cn",
+		GroupAttribute: "memberOf",
+		AdminGroups:    `["cn=it,ou=groups,dc=sekolah,dc=sch,dc=id"]`,
+		TeacherGroups:  `["cn=guru,ou=groups,dc=sekolah,dc=sch,dc=id"]`,
+	}
+}
+
+func TestLDAPBindAuthenticatorAgainstStandIn(t *testing.T) {
+	directory := newSchoolDirectory(t)
+	ldapConfig := standInLDAPConfig(directory)
+	authenticator := NewLDAPAuthenticator()
+
+	identity, err := authenticator.Authenticate(ldapConfig, "budi@sekolah.sch.id", "directory-pass")
+	if err != nil {
+		t.Fatalf("Authenticate returned error: %v", err)
+	}
+	if identity.DN != standInTeacherDN || identity.Name != "Budi Santoso" {
+		t.Errorf("identity = %+v, want DN %q and name Budi Santoso", identity, standInTeacherDN)
+	}
+	if want := []string{"cn=guru,ou=groups,dc=sekolah,dc=sch,dc=id"}; !reflect.DeepEqual(identity.Groups, want) {
+		t.Errorf("groups = %v, want %v", identity.Groups, want)
+	}
+	if want := []string{standInServiceDN, standInTeacherDN}; !reflect.DeepEqual(directory.Binds(), want) {
+		t.Errorf("binds = %v, want service account then user %v", directory.Binds(), want)
+	}
+}
+
+func TestLDAPBindAuthenticatorRejections(t *testing.T) {
+	directory := newSchoolDirectory(t)
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		mutate   func(*models.SchoolLDAPConfig)
+		wantErr  string
+	}{
+		{name: "wrong password", email: "budi@sekolah.sch.id", password: "wrong", wantErr: "invalid credentials: incorrect password"},
+		{name: "empty password", email: "budi@sekolah.sch.id", password: "", wantErr: "invalid credentials: incorrect password"},
+		{name: "not in directory", email: "siti@sekolah.sch.id", password: "directory-pass", wantErr: "invalid credentials: user not found in directory"},
+		{
+			name: "wrong service account password", email: "budi@sekolah.sch.id", password: "directory-pass",
+			mutate:  func(c *models.SchoolLDAPConfig) { c.BindPassword = "stale" },
+			wantErr: "failed to bind LDAP service account",
+		},
+		{
+			name: "unreachable server", email: "budi@sekolah.sch.id", password: "directory-pass",
+			mutate:  func(c *models.SchoolLDAPConfig) { c.URL = "ldap://127.0.0.1:1" },
+			wantErr: "failed to connect to LDAP server",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ldapConfig := standInLDAPConfig(directory)
+			if tt.mutate != nil {
+				tt.mutate(ldapConfig)
+			}
+			_, err := NewLDAPAuthenticator().Authenticate(ldapConfig, tt.email, tt.password)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Fatalf("Authenticate error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMapLDAPGroupsToRole(t *testing.T) {
+	ldapConfig := &models.SchoolLDAPConfig{
+		AdminGroups:   `["CN=IT,OU=Groups"]`,
+		TeacherGroups: `["cn=guru,ou=groups"]`,
+	}
+	tests := []struct {
+		groups   []string
+		wantRole string
+		wantErr  bool
+	}{
+		{groups: []string{"cn=guru,ou=groups"}, wantRole: "teacher"},
+		{groups: []string{"cn=guru,ou=groups", "cn=it,ou=groups"}, wantRole: "admin"},
+		{groups: []string{"cn=tu,ou=groups"}, wantErr: true},
+		{groups: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		role, err := mapLDAPGroupsToRole(ldapConfig, tt.groups)
+		if (err != nil) != tt.wantErr || role != tt.wantRole {
+			t.Errorf("mapLDAPGroupsToRole(%v) = %q, %v; want %q, error %v", tt.groups, role, err, tt.wantRole, tt.wantErr)
+		}
+	}
+}
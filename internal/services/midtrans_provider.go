@@ -0,0 +1,148 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"auth-barniee/internal/models"
+)
+
+const (
+	midtransSandboxSnapURL    = "https://app.sandbox.midtrans.com/snap/v1/transactions"
+	midtransProductionSnapURL = "https://app.midtrans.com/snap/v1/transactions"
+)
+
+// midtransProvider creates Snap checkouts and verifies HTTP notifications with the server key.
+type midtransProvider struct {
+	serverKey  string
+	snapURL    string
+	httpClient *http.Client
+}
+
+func NewMidtransProvider(serverKey string, production bool) PaymentProvider {
+	snapURL := midtransSandboxSnapURL
+	if production {
+		snapURL = midtransProductionSnapURL
+	}
+	return &midtransProvider{
+		serverKey:  serverKey,
+		snapURL:    snapURL,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *midtransProvider) Name() string {
+	return "midtrans"
+}
+
+func (p *midtransProvider) CreateCheckout(ctx context.Context, req CheckoutRequest) (*CheckoutSession, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"transaction_details": map[string]interface{}{
+			"order_id":     req.OrderID,
+			"gross_amount": req.Amount,
+		},
+		"item_details": []map[string]interface{}{{
+			"id":       req.OrderID,
+			"price":    req.Amount,
+			"quantity": 1,
+			"name":     req.Description,
+		}},
+		"customer_details": map[string]interface{}{
+			"first_name": req.CustomerName,
+			"email":      req.CustomerEmail,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode checkout request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.snapURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build checkout request: %w", err)
+	}
+	httpReq.SetBasicAuth(p.serverKey, "")
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach midtrans: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read midtrans response: %w", err)
+	}
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("midtrans rejected checkout with status %d: %s", resp.StatusCode, body)
+	}
+
+	var snap struct {
+		Token       string `json:"token"`
+		RedirectURL string `json:"redirect_url"`
+	}
+	if err := json.Unmarshal(body, &snap); err != nil {
+		return nil, fmt.Errorf("failed to decode midtrans response: %w", err)
+	}
+	return &CheckoutSession{ProviderReference: snap.Token, RedirectURL: snap.RedirectURL}, nil
+}
+
+// ParseNotification checks signature_key, which Midtrans computes as
+// SHA512(order_id + status_code + gross_amount + server_key).
+func (p *midtransProvider) ParseNotification(header http.Header, body []byte) (*PaymentNotification, error) {
+	var notification struct {
+		OrderID           string `json:"order_id"`
+		StatusCode        string `json:"status_code"`
+		GrossAmount       string `json:"gross_amount"`
+		SignatureKey      string `json:"signature_key"`
+		TransactionID     string `json:"transaction_id"`
+		TransactionStatus string `json:"transaction_status"`
+		FraudStatus       string `json:"fraud_status"`
+	}
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, fmt.Errorf("invalid notification payload: %w", err)
+	}
+
+	digest := sha512.Sum512([]byte(notification.OrderID + notification.StatusCode + notification.GrossAmount + p.serverKey))
+	expected := hex.EncodeToString(digest[:])
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(notification.SignatureKey)) != 1 {
+		return nil, ErrInvalidPaymentSignature
+	}
+
+	grossAmount, err := strconv.ParseFloat(notification.GrossAmount, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gross_amount: %w", err)
+	}
+
+	status := models.PaymentStatusPending
+	switch notification.TransactionStatus {
+	case "settlement":
+		status = models.PaymentStatusPaid
+	case "capture":
+		// Card payments are only final once the fraud check accepts them
+		if notification.FraudStatus == "accept" {
+			status = models.PaymentStatusPaid
+		}
+	case "deny", "cancel", "failure":
+		status = models.PaymentStatusFailed
+	case "expire":
+		status = models.PaymentStatusExpired
+	}
+
+	return &PaymentNotification{
+		OrderID:           notification.OrderID,
+		Status:            status,
+		Amount:            int64(math.Round(grossAmount)),
+		ProviderReference: notification.TransactionID,
+	}, nil
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+	"auth-barniee/internal/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const orgAdminRoleName = "org_admin"
+
+// OrganizationInput is the editable part of an organization.
+type OrganizationInput struct {
+	Name                string
+	Address             string
+	BillingEmail        string
+	ConsolidatedBilling bool
+}
+
+type OrganizationService interface {
+	CreateOrganization(actorID uuid.UUID, input OrganizationInput) (*models.Organization, error)
+	ListOrganizations() ([]models.Organization, error)
+	GetOrganization(organizationID uuid.UUID) (*models.Organization, error)
+	UpdateOrganization(actorID, organizationID uuid.UUID, input OrganizationInput) (*models.Organization, error)
+	AddSchool(actorID, organizationID, schoolID uuid.UUID) (*models.Organization, error)
+	RemoveSchool(actorID, organizationID, schoolID uuid.UUID) (*models.Organization, error)
+	CreateOrganizationAdmin(actorID, organizationID uuid.UUID, name, email, password string) (*models.User, error)
+	// GetAdminOrganization returns the organization of an organization admin, with its campuses.
+	GetAdminOrganization(adminID uuid.UUID) (*models.Organization, error)
+}
+
+type organizationService struct {
+	organizationRepo repositories.OrganizationRepository
+	schoolRepo       repositories.SchoolRepository
+	userRepo         repositories.UserRepository
+	roleRepo         repositories.RoleRepository
+}
+
+func NewOrganizationService(organizationRepo repositories.OrganizationRepository, schoolRepo repositories.SchoolRepository, userRepo repositories.UserRepository, roleRepo repositories.RoleRepository) OrganizationService {
+	return &organizationService{
+		organizationRepo: organizationRepo,
+		schoolRepo:       schoolRepo,
+		userRepo:         userRepo,
+		roleRepo:         roleRepo,
+	}
+}
+
+func (s *organizationService) CreateOrganization(actorID uuid.UUID, input OrganizationInput) (*models.Organization, error) {
+	organization := &models.Organization{
+		Name:                input.Name,
+		Address:             input.Address,
+		BillingEmail:        input.BillingEmail,
+		ConsolidatedBilling: input.ConsolidatedBilling,
+		CreatedBy:           actorID,
+		UpdatedBy:           actorID,
+	}
+	if err := s.organizationRepo.Create(organization); err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+	return organization, nil
+}
+
+func (s *organizationService) ListOrganizations() ([]models.Organization, error) {
+	organizations, err := s.organizationRepo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve organizations: %w", err)
+	}
+	return organizations, nil
+}
+
+func (s *organizationService) GetOrganization(organizationID uuid.UUID) (*models.Organization, error) {
+	organization, err := s.organizationRepo.FindByID(organizationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("organization not found")
+		}
+		return nil, fmt.Errorf("failed to find organization: %w", err)
+	}
+	return organization, nil
+}
+
+func (s *organizationService) UpdateOrganization(actorID, organizationID uuid.UUID, input OrganizationInput) (*models.Organization, error) {
+	organization, err := s.GetOrganization(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	organization.Name = input.Name
+	organization.Address = input.Address
+	organization.BillingEmail = input.BillingEmail
+	organization.ConsolidatedBilling = input.ConsolidatedBilling
+	organization.UpdatedBy = actorID
+	if err := s.organizationRepo.Update(organization); err != nil {
+		return nil, fmt.Errorf("failed to update organization: %w", err)
+	}
+	return organization, nil
+}
+
+func (s *organizationService) findSchool(schoolID uuid.UUID) (*models.School, error) {
+	school, err := s.schoolRepo.FindByID(schoolID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("school not found")
+		}
+		return nil, fmt.Errorf("failed to find school: %w", err)
+	}
+	return school, nil
+}
+
+func (s *organizationService) AddSchool(actorID, organizationID, schoolID uuid.UUID) (*models.Organization, error) {
+	organization, err := s.GetOrganization(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	school, err := s.findSchool(schoolID)
+	if err != nil {
+		return nil, err
+	}
+	if school.OrganizationID != nil {
+		if *school.OrganizationID == organization.ID {
+			return nil, errors.New("school already belongs to this organization")
+		}
+		return nil, errors.New("school already belongs to another organization")
+	}
+	if !school.Package.MultiCampus {
+		return nil, errors.New("school's package does not include multi-campus support")
+	}
+
+	school.OrganizationID = &organization.ID
+	school.UpdatedBy = actorID
+	if err := s.schoolRepo.Update(school); err != nil {
+		return nil, fmt.Errorf("failed to add school: %w", err)
+	}
+	return s.GetOrganization(organization.ID)
+}
+
+// ensureBilledPerSchool refuses to charge a campus on its own when its organization pays for it
+// on the consolidated organization invoice.
+func ensureBilledPerSchool(organizationRepo repositories.OrganizationRepository, school *models.School) error {
+	if school.OrganizationID == nil {
+		return nil
+	}
+	organization, err := organizationRepo.FindByID(*school.OrganizationID)
+	if err != nil {
+		return fmt.Errorf("failed to find organization: %w", err)
+	}
+	if organization.ConsolidatedBilling {
+		return errors.New("school is billed through its organization")
+	}
+	return nil
+}
+
+func (s *organizationService) RemoveSchool(actorID, organizationID, schoolID uuid.UUID) (*models.Organization, error) {
+	organization, err := s.GetOrganization(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	school, err := s.findSchool(schoolID)
+	if err != nil {
+		return nil, err
+	}
+	if school.OrganizationID == nil || *school.OrganizationID != organization.ID {
+		return nil, errors.New("school does not belong to this organization")
+	}
+
+	school.OrganizationID = nil
+	school.UpdatedBy = actorID
+	if err := s.schoolRepo.Update(school); err != nil {
+		return nil, fmt.Errorf("failed to remove school: %w", err)
+	}
+	return s.GetOrganization(organization.ID)
+}
+
+// CreateOrganizationAdmin creates a user with the org_admin role. Organization admins belong
+// to the organization rather than to one of its schools.
+func (s *organizationService) CreateOrganizationAdmin(actorID, organizationID uuid.UUID, name, email, password string) (*models.User, error) {
+	organization, err := s.GetOrganization(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	existingUser, err := s.userRepo.FindByEmailIncludingDeleted(email)
+	if err == nil && existingUser != nil {
+		return nil, errors.New("user with this email already exists")
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check existing user: %w", err)
+	}
+
+	role, err := s.roleRepo.FindByName(orgAdminRoleName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &models.User{
+		Name:           name,
+		Email:          email,
+		Password:       hashedPassword,
+		RoleID:         role.ID,
+		OrganizationID: &organization.ID,
+		CreatedBy:      actorID,
+		UpdatedBy:      actorID,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, fmt.Errorf("failed to create organization admin: %w", err)
+	}
+	user.Role = *role
+	return user, nil
+}
+
+func (s *organizationService) GetAdminOrganization(adminID uuid.UUID) (*models.Organization, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	if adminUser.OrganizationID == nil {
+		return nil, errors.New("admin is not associated with an organization")
+	}
+	return s.GetOrganization(*adminUser.OrganizationID)
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"testing"
+
+	"auth-barniee/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestAddSchoolRequiresAMultiCampusPackage(t *testing.T) {
+	f := newBillingFixture()
+	campus := &models.School{ID: uuid.New(), Name: "SMA Barniee Bandung"}
+	organization := f.addOrganization(campus)
+	service := NewOrganizationService(f.organizations, f.schools, f.users, nil)
+
+	if _, err := service.AddSchool(f.actorID, organization.ID, f.school.ID); err == nil {
+		t.Fatal("a Premium school joined an organization")
+	}
+	if f.savedSchool().OrganizationID != nil {
+		t.Fatal("the refused school was added")
+	}
+
+	f.school.PackageID, f.school.Package = campus.PackageID, campus.Package
+	updated, err := service.AddSchool(f.actorID, organization.ID, f.school.ID)
+	if err != nil {
+		t.Fatalf("AddSchool returned error: %v", err)
+	}
+	if len(updated.Schools) != 2 {
+		t.Errorf("campuses = %d, want 2", len(updated.Schools))
+	}
+}
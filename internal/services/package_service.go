@@ -0,0 +1,162 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PackageInput holds the editable fields of a package.
+type PackageInput struct {
+	Name            string
+	PricePerStudent *float64
+	PricePerYear    *float64
+	DurationDays    *int
+	MaxStudents     *int
+	Features        []string
+	IsTrial         bool
+	MultiCampus     bool
+}
+
+type PackageService interface {
+	GetAllPackages() ([]models.Package, error) // Includes retired packages
+	CreatePackage(actorID uuid.UUID, input PackageInput) (*models.Package, error)
+	UpdatePackage(actorID, packageID uuid.UUID, input PackageInput) (*models.Package, error)
+	RetirePackage(actorID, packageID uuid.UUID) (*models.Package, error)
+}
+
+type packageService struct {
+	packageRepo repositories.PackageRepository
+}
+
+func NewPackageService(packageRepo repositories.PackageRepository) PackageService {
+	return &packageService{packageRepo: packageRepo}
+}
+
+// applyPackageInput validates the input and copies it onto the package.
+func applyPackageInput(pkg *models.Package, input PackageInput) error {
+	if input.PricePerStudent != nil && *input.PricePerStudent < 0 {
+		return errors.New("price_per_student cannot be negative")
+	}
+	if input.PricePerYear != nil && *input.PricePerYear < 0 {
+		return errors.New("price_per_year cannot be negative")
+	}
+	if input.DurationDays != nil && *input.DurationDays <= 0 {
+		return errors.New("duration_days must be greater than zero")
+	}
+	if input.MaxStudents != nil && *input.MaxStudents < 0 {
+		return errors.New("max_students cannot be negative")
+	}
+	if input.IsTrial && input.DurationDays == nil {
+		return errors.New("trial packages require duration_days")
+	}
+
+	features := input.Features
+	if features == nil {
+		features = []string{}
+	}
+	featuresJSON, err := json.Marshal(features)
+	if err != nil {
+		return fmt.Errorf("failed to encode features: %w", err)
+	}
+
+	pkg.Name = input.Name
+	pkg.PricePerStudent = input.PricePerStudent
+	pkg.PricePerYear = input.PricePerYear
+	pkg.DurationDays = input.DurationDays
+	pkg.MaxStudents = input.MaxStudents
+	pkg.Features = string(featuresJSON)
+	pkg.IsTrial = input.IsTrial
+	pkg.MultiCampus = input.MultiCampus
+	return nil
+}
+
+func (s *packageService) findPackage(packageID uuid.UUID) (*models.Package, error) {
+	pkg, err := s.packageRepo.FindByID(packageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("package not found")
+		}
+		return nil, fmt.Errorf("failed to find package: %w", err)
+	}
+	return pkg, nil
+}
+
+func (s *packageService) ensureNameAvailable(name string, packageID uuid.UUID) error {
+	existing, err := s.packageRepo.FindByName(name)
+	if err == nil && existing.ID != packageID {
+		return fmt.Errorf("package with name '%s' already exists", name)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to check package name: %w", err)
+	}
+	return nil
+}
+
+func (s *packageService) GetAllPackages() ([]models.Package, error) {
+	packages, err := s.packageRepo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve packages: %w", err)
+	}
+	return packages, nil
+}
+
+func (s *packageService) CreatePackage(actorID uuid.UUID, input PackageInput) (*models.Package, error) {
+	if err := s.ensureNameAvailable(input.Name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	pkg := &models.Package{CreatedBy: actorID}
+	if err := applyPackageInput(pkg, input); err != nil {
+		return nil, err
+	}
+	if err := s.packageRepo.Create(pkg); err != nil {
+		return nil, fmt.Errorf("failed to create package: %w", err)
+	}
+	return pkg, nil
+}
+
+// UpdatePackage changes the catalog entry only; schools keep the limits and dates they were given when they selected it.
+func (s *packageService) UpdatePackage(actorID, packageID uuid.UUID, input PackageInput) (*models.Package, error) {
+	pkg, err := s.findPackage(packageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureNameAvailable(input.Name, pkg.ID); err != nil {
+		return nil, err
+	}
+
+	if err := applyPackageInput(pkg, input); err != nil {
+		return nil, err
+	}
+	pkg.UpdatedBy = actorID
+	if err := s.packageRepo.Update(pkg); err != nil {
+		return nil, fmt.Errorf("failed to update package: %w", err)
+	}
+	return pkg, nil
+}
+
+func (s *packageService) RetirePackage(actorID, packageID uuid.UUID) (*models.Package, error) {
+	pkg, err := s.findPackage(packageID)
+	if err != nil {
+		return nil, err
+	}
+	if pkg.RetiredAt != nil {
+		return nil, errors.New("package is already retired")
+	}
+
+	now := time.Now()
+	pkg.RetiredAt = &now
+	pkg.UpdatedBy = actorID
+	if err := s.packageRepo.Update(pkg); err != nil {
+		return nil, fmt.Errorf("failed to retire package: %w", err)
+	}
+	return pkg, nil
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+	"auth-barniee/internal/utils"
+)
+
+// issuePasswordSetupToken replaces the user's unused set-password links with a new one and returns its token.
+func issuePasswordSetupToken(tokens repositories.PasswordSetupTokenRepository, user *models.User, cfg *config.Config) (string, error) {
+	now := time.Now()
+	if err := tokens.InvalidateForUser(user.ID, now); err != nil {
+		return "", fmt.Errorf("failed to invalidate previous password setup links: %w", err)
+	}
+
+	token, err := utils.GenerateSecureToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate password setup token: %w", err)
+	}
+	if err := tokens.Create(&models.PasswordSetupToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashSecureToken(token),
+		ExpiresAt: now.Add(time.Duration(cfg.PasswordSetupExpiryHours) * time.Hour),
+	}); err != nil {
+		return "", fmt.Errorf("failed to save password setup token: %w", err)
+	}
+	return token, nil
+}
+
+func sendPasswordSetupEmail(cfg *config.Config, user *models.User, schoolName, token string) error {
+	subject := "Barniee: Atur Password Akun Anda"
+	body := fmt.Sprintf("Halo %s,\n\nRegistrasi %s di Barniee telah selesai. Atur password akun admin Anda melalui tautan berikut:\n%s?token=%s\n\nTautan ini hanya dapat digunakan sekali dan akan kedaluwarsa dalam %d jam.\n\nTerima kasih,\nTim Barniee",
+		user.Name, schoolName, cfg.PasswordSetupURL, token, cfg.PasswordSetupExpiryHours)
+	if err := utils.SendEmail(cfg, user.Email, subject, body); err != nil {
+		return fmt.Errorf("failed to send password setup email: %w", err)
+	}
+	return nil
+}
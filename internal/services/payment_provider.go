@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"auth-barniee/internal/config"
+)
+
+// ErrInvalidPaymentSignature is returned by PaymentProvider.ParseNotification for webhooks that
+// were not signed by the provider.
+var ErrInvalidPaymentSignature = errors.New("invalid payment notification signature")
+
+// CheckoutRequest describes what the customer is asked to pay. Amount is in whole rupiah.
+type CheckoutRequest struct {
+	OrderID       string
+	Amount        int64
+	Description   string
+	CustomerName  string
+	CustomerEmail string
+}
+
+// CheckoutSession is where the customer is sent to pay.
+type CheckoutSession struct {
+	ProviderReference string
+	RedirectURL       string
+}
+
+// PaymentNotification is a verified webhook from the provider, mapped to a models.PaymentStatus* value.
+type PaymentNotification struct {
+	OrderID           string
+	Status            string
+	Amount            int64
+	ProviderReference string
+}
+
+// PaymentProvider is a payment gateway (Midtrans, or the fake one for local development and tests).
+type PaymentProvider interface {
+	Name() string
+	CreateCheckout(ctx context.Context, req CheckoutRequest) (*CheckoutSession, error)
+	// ParseNotification verifies the webhook signature and decodes it.
+	ParseNotification(header http.Header, body []byte) (*PaymentNotification, error)
+}
+
+// NewPaymentProvider returns the provider selected by PAYMENT_PROVIDER. There is no default, so a
+// deployment that forgets the setting fails to start instead of accepting fake payments.
+func NewPaymentProvider(cfg *config.Config) (PaymentProvider, error) {
+	switch cfg.PaymentProvider {
+	case "":
+		return nil, errors.New("PAYMENT_PROVIDER is required, set it to midtrans or fake")
+	case "midtrans":
+		if cfg.MidtransServerKey == "" {
+			return nil, errors.New("MIDTRANS_SERVER_KEY is required for the midtrans payment provider")
+		}
+		return NewMidtransProvider(cfg.MidtransServerKey, cfg.MidtransProduction), nil
+	case "fake":
+		return NewFakePaymentProvider(cfg.FakePaymentSecret, cfg.PublicBaseURL), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider '%s'", cfg.PaymentProvider)
+	}
+}
@@ -0,0 +1,443 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultSubscriptionDays is the period bought by a paid package without DurationDays.
+	defaultSubscriptionDays = 365
+	// creditPaymentProvider marks payments settled entirely from billing credit.
+	creditPaymentProvider = "credit"
+)
+
+type PaymentService interface {
+	// CreateCheckout starts a payment for the next period of the school's selected package.
+	// actorID is uuid.Nil during registration.
+	CreateCheckout(schoolID, actorID uuid.UUID) (*models.Payment, error)
+	CreateSchoolCheckout(adminID uuid.UUID) (*models.Payment, error)
+	// StartCheckout creates the provider checkout for a priced payment and saves it.
+	StartCheckout(school *models.School, payment *models.Payment, description string) (*models.Payment, error)
+	// HandleNotification applies a provider webhook. Repeated notifications are harmless.
+	HandleNotification(header http.Header, body []byte) error
+}
+
+type paymentService struct {
+	paymentRepo      repositories.PaymentRepository
+	planChangeRepo   repositories.PlanChangeRepository
+	schoolRepo       repositories.SchoolRepository
+	organizationRepo repositories.OrganizationRepository
+	userRepo         repositories.UserRepository
+	provider         PaymentProvider
+	invoiceService   InvoiceService
+	subscription     SubscriptionService
+	uow              repositories.UnitOfWork
+	config           *config.Config
+}
+
+func NewPaymentService(
+	paymentRepo repositories.PaymentRepository,
+	planChangeRepo repositories.PlanChangeRepository,
+	schoolRepo repositories.SchoolRepository,
+	organizationRepo repositories.OrganizationRepository,
+	userRepo repositories.UserRepository,
+	provider PaymentProvider,
+	invoiceService InvoiceService,
+	subscription SubscriptionService,
+	uow repositories.UnitOfWork,
+	cfg *config.Config,
+) PaymentService {
+	return &paymentService{
+		paymentRepo:      paymentRepo,
+		planChangeRepo:   planChangeRepo,
+		schoolRepo:       schoolRepo,
+		organizationRepo: organizationRepo,
+		userRepo:         userRepo,
+		provider:         provider,
+		invoiceService:   invoiceService,
+		subscription:     subscription,
+		uow:              uow,
+		config:           cfg,
+	}
+}
+
+func (s *paymentService) CreateCheckout(schoolID, actorID uuid.UUID) (*models.Payment, error) {
+	school, err := s.schoolRepo.FindByID(schoolID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("school not found")
+		}
+		return nil, fmt.Errorf("failed to find school: %w", err)
+	}
+	pkg := school.Package
+	if pkg.IsTrial {
+		return nil, errors.New("trial packages do not require payment")
+	}
+	if err := ensureBilledPerSchool(s.organizationRepo, school); err != nil {
+		return nil, err
+	}
+
+	studentCount, err := billableStudentCount(s.userRepo, school)
+	if err != nil {
+		return nil, err
+	}
+
+	charge := priceSubscription(pkg, studentCount, s.config.PPNRatePercent)
+	if len(charge.Lines) == 0 {
+		return nil, errors.New("package has no price to pay")
+	}
+	creditApplied := min(school.BillingCredit, charge.Subtotal)
+	charge = charge.withCredit(creditApplied)
+
+	periodStart, periodEnd := nextSubscriptionPeriod(school, &pkg, time.Now())
+	payment := &models.Payment{
+		SchoolID:       school.ID,
+		PackageID:      pkg.ID,
+		PeriodStart:    periodStart,
+		PeriodEnd:      periodEnd,
+		StudentCount:   studentCount,
+		CreditApplied:  creditApplied,
+		Subtotal:       charge.Subtotal,
+		TaxRatePercent: charge.TaxRatePercent,
+		TaxAmount:      charge.TaxAmount,
+		Amount:         charge.Total,
+		CreatedBy:      actorID,
+	}
+	return s.StartCheckout(school, payment, fmt.Sprintf("Barniee %s", pkg.Name))
+}
+
+func (s *paymentService) StartCheckout(school *models.School, payment *models.Payment, description string) (*models.Payment, error) {
+	payment.OrderID = fmt.Sprintf("BRN-%s", uuid.NewString())
+	payment.Provider = s.provider.Name()
+	payment.Status = models.PaymentStatusPending
+
+	// Billing credit covers everything, so nothing is collected and the payment completes right away
+	if payment.Amount <= 0 {
+		payment.Provider = creditPaymentProvider
+		if err := s.paymentRepo.Create(payment); err != nil {
+			return nil, fmt.Errorf("failed to save payment: %w", err)
+		}
+		if err := s.completePayment(payment, payment.OrderID); err != nil {
+			return nil, err
+		}
+		return payment, nil
+	}
+
+	checkout := CheckoutRequest{
+		OrderID:      payment.OrderID,
+		Amount:       int64(math.Round(payment.Amount)),
+		Description:  description,
+		CustomerName: school.Name,
+	}
+	if school.AdminUserID != uuid.Nil {
+		if adminUser, err := s.userRepo.FindByID(school.AdminUserID); err == nil {
+			checkout.CustomerName = adminUser.Name
+			checkout.CustomerEmail = adminUser.Email
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	session, err := s.provider.CreateCheckout(ctx, checkout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkout: %w", err)
+	}
+	payment.ProviderReference = session.ProviderReference
+	payment.RedirectURL = session.RedirectURL
+
+	if err := s.paymentRepo.Create(payment); err != nil {
+		return nil, fmt.Errorf("failed to save payment: %w", err)
+	}
+	return payment, nil
+}
+
+func (s *paymentService) CreateSchoolCheckout(adminID uuid.UUID) (*models.Payment, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	if adminUser.SchoolID == uuid.Nil {
+		return nil, errors.New("admin is not associated with a school")
+	}
+	return s.CreateCheckout(adminUser.SchoolID, adminID)
+}
+
+func subscriptionDays(pkg *models.Package) int {
+	if pkg.DurationDays != nil && *pkg.DurationDays > 0 {
+		return *pkg.DurationDays
+	}
+	return defaultSubscriptionDays
+}
+
+// paidPeriodRunning reports whether the school is inside a paid (non-trial) subscription period.
+func paidPeriodRunning(school *models.School, now time.Time) bool {
+	return !school.Package.IsTrial && school.SubscriptionEndDate != nil && school.SubscriptionEndDate.After(now)
+}
+
+// nextSubscriptionPeriod continues a running paid subscription from its end date;
+// otherwise, including when a trial is upgraded, the new period starts now.
+func nextSubscriptionPeriod(school *models.School, pkg *models.Package, now time.Time) (time.Time, time.Time) {
+	start := now
+	if paidPeriodRunning(school, now) {
+		start = *school.SubscriptionEndDate
+	}
+	return start, start.AddDate(0, 0, subscriptionDays(pkg))
+}
+
+// billableStudentCount is the number of students a school pays for: the students it has now,
+// but at least the count declared at registration. Checkouts and invoices both bill this.
+func billableStudentCount(userRepo repositories.UserRepository, school *models.School) (int64, error) {
+	students, err := userRepo.CountStudentsBySchoolID(school.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count students: %w", err)
+	}
+	return billableStudents(school, students), nil
+}
+
+// billableStudents is billableStudentCount for a student count the caller already has.
+func billableStudents(school *models.School, students int64) int64 {
+	return max(students, int64(school.InitialStudentCount))
+}
+
+// extendSubscription adds one period of pkg to the school, continuing a running paid period from
+// its end date, and returns the period added. It does not switch the school to pkg.
+func extendSubscription(school *models.School, pkg *models.Package, now time.Time) (time.Time, time.Time) {
+	running := paidPeriodRunning(school, now)
+	start, end := nextSubscriptionPeriod(school, pkg, now)
+	if !running {
+		school.SubscriptionStartDate = &start
+	}
+	school.SubscriptionEndDate = &end
+	return start, end
+}
+
+// switchSchoolPackage moves the school to pkg and its student limit.
+func switchSchoolPackage(school *models.School, pkg *models.Package) {
+	school.PackageID = pkg.ID
+	school.Package = *pkg // Save writes the foreign key from the preloaded association
+	if pkg.MaxStudents != nil {
+		school.MaxStudentsAllowed = *pkg.MaxStudents
+	} else {
+		school.MaxStudentsAllowed = 0
+	}
+}
+
+func (s *paymentService) HandleNotification(header http.Header, body []byte) error {
+	notification, err := s.provider.ParseNotification(header, body)
+	if err != nil {
+		return err
+	}
+
+	payment, err := s.paymentRepo.FindByOrderID(notification.OrderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("payment not found")
+		}
+		return fmt.Errorf("failed to find payment: %w", err)
+	}
+
+	switch notification.Status {
+	case models.PaymentStatusPaid:
+		if notification.Amount != int64(math.Round(payment.Amount)) {
+			return errors.New("payment amount does not match")
+		}
+		return s.completePayment(payment, notification.ProviderReference)
+	case models.PaymentStatusFailed, models.PaymentStatusExpired:
+		return s.uow.Do(func(repos repositories.Repositories) error {
+			if err := repos.Payments.UpdateStatus(payment.ID, notification.Status); err != nil {
+				return fmt.Errorf("failed to update payment: %w", err)
+			}
+			if payment.PlanChangeID != nil {
+				return finishPlanChange(repos.PlanChanges, *payment.PlanChangeID, models.PlanChangeStatusFailed, "payment "+notification.Status)
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+// finishPlanChange records the outcome of a plan change that waited for its payment.
+func finishPlanChange(planChanges repositories.PlanChangeRepository, changeID uuid.UUID, status, failureReason string) error {
+	change, err := planChanges.FindByID(changeID)
+	if err != nil {
+		return fmt.Errorf("failed to find plan change %s: %w", changeID, err)
+	}
+	if change.Status != models.PlanChangeStatusPendingPayment {
+		return nil
+	}
+	change.Status = status
+	change.FailureReason = failureReason
+	if status == models.PlanChangeStatusApplied {
+		now := time.Now()
+		change.AppliedAt = &now
+	}
+	if err := planChanges.Update(change); err != nil {
+		return fmt.Errorf("failed to update plan change %s: %w", changeID, err)
+	}
+	return nil
+}
+
+// completePayment activates or extends the school's subscription and issues a paid invoice.
+// Marking the payment paid, extending the subscription and settling a plan change happen in one
+// transaction; if any of them fails nothing is kept and the provider's retry applies it again.
+func (s *paymentService) completePayment(payment *models.Payment, providerReference string) error {
+	now := time.Now()
+	var changed, credited bool
+	var school *models.School
+	var pkg *models.Package
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		var err error
+		changed, err = repos.Payments.MarkPaid(payment.ID, providerReference, now)
+		if err != nil {
+			return fmt.Errorf("failed to mark payment as paid: %w", err)
+		}
+		if !changed {
+			return nil
+		}
+		if payment.PlanChangeID != nil {
+			credited, err = creditStalePlanChange(repos, payment)
+			if err != nil || credited {
+				return err
+			}
+		}
+
+		school, pkg, err = s.applyPayment(repos, payment, now)
+		if err != nil {
+			return err
+		}
+		if payment.PlanChangeID != nil {
+			return finishPlanChange(repos.PlanChanges, *payment.PlanChangeID, models.PlanChangeStatusApplied, "")
+		}
+		return nil
+	})
+	if err != nil || !changed {
+		return err
+	}
+	if credited {
+		log.Printf("Payment %s was for a plan change that can no longer be applied; credited %.0f to school %s", payment.OrderID, payment.Amount, payment.SchoolID)
+		return nil
+	}
+	payment.Status = models.PaymentStatusPaid
+	payment.ProviderReference = providerReference
+	payment.PaidAt = &now
+
+	invoice, err := s.invoiceService.IssuePaidInvoice(payment, school, pkg, s.paymentInvoiceLines(payment, pkg))
+	if err != nil {
+		// The subscription is already extended, so the payment must not be retried; the invoice can be issued by hand.
+		log.Printf("Failed to issue invoice for payment %s: %v", payment.OrderID, err)
+		return nil
+	}
+	payment.InvoiceID = &invoice.ID
+	if err := s.paymentRepo.Update(payment); err != nil {
+		log.Printf("Failed to link invoice %s to payment %s: %v", invoice.Number, payment.OrderID, err)
+	}
+	return nil
+}
+
+// creditStalePlanChange handles a paid checkout whose plan change can no longer be applied, because
+// a newer change replaced it or the school moved to another package since it was quoted. The change
+// is not applied and the amount paid becomes billing credit. It reports whether that happened.
+func creditStalePlanChange(repos repositories.Repositories, payment *models.Payment) (bool, error) {
+	change, err := repos.PlanChanges.FindByID(*payment.PlanChangeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find plan change: %w", err)
+	}
+	school, err := repos.Schools.FindByIDForUpdate(payment.SchoolID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find school: %w", err)
+	}
+	if change.Status == models.PlanChangeStatusPendingPayment && change.FromPackageID == school.PackageID {
+		return false, nil
+	}
+
+	school.BillingCredit += payment.Amount
+	school.UpdatedBy = payment.CreatedBy
+	if err := repos.Schools.Update(school); err != nil {
+		return false, fmt.Errorf("failed to credit school: %w", err)
+	}
+	if change.Status == models.PlanChangeStatusPendingPayment {
+		change.Status = models.PlanChangeStatusFailed
+		change.FailureReason = "school changed package before this change was paid"
+		if err := repos.PlanChanges.Update(change); err != nil {
+			return false, fmt.Errorf("failed to update plan change %s: %w", change.ID, err)
+		}
+	}
+	return true, nil
+}
+
+func (s *paymentService) applyPayment(repos repositories.Repositories, payment *models.Payment, now time.Time) (*models.School, *models.Package, error) {
+	school, err := repos.Schools.FindByIDForUpdate(payment.SchoolID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find school: %w", err)
+	}
+	pkg, err := repos.Packages.FindByID(payment.PackageID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find package: %w", err)
+	}
+
+	// The checkout may have been paid days later, so the period is recomputed from now
+	running := paidPeriodRunning(school, now)
+	if payment.PlanChangeID != nil && running {
+		// A plan change paid mid-period keeps the current end date
+		payment.PeriodStart = now
+		payment.PeriodEnd = *school.SubscriptionEndDate
+	} else {
+		payment.PeriodStart, payment.PeriodEnd = extendSubscription(school, pkg, now)
+	}
+
+	creditAdded := 0.0
+	if payment.PlanChangeID != nil {
+		change, err := repos.PlanChanges.FindByID(*payment.PlanChangeID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to find plan change: %w", err)
+		}
+		creditAdded = change.CreditToBalance
+	}
+
+	switchSchoolPackage(school, pkg)
+	school.BillingCredit = max(school.BillingCredit-payment.CreditApplied, 0) + creditAdded
+	school.SubscriptionStatus = s.subscription.EvaluateStatus(school, now)
+	school.UpdatedBy = payment.CreatedBy
+	if err := repos.Schools.Update(school); err != nil {
+		return nil, nil, fmt.Errorf("failed to update subscription: %w", err)
+	}
+	return school, pkg, nil
+}
+
+// paymentInvoiceLines itemizes what a payment covered, including any proration and credit.
+func (s *paymentService) paymentInvoiceLines(payment *models.Payment, pkg *models.Package) []models.InvoiceLine {
+	var lines []models.InvoiceLine
+	if payment.PlanChangeID != nil {
+		if change, err := s.planChangeRepo.FindByID(*payment.PlanChangeID); err == nil {
+			lines = append(lines, models.InvoiceLine{
+				Description: fmt.Sprintf("Perubahan paket ke %s (prorata sisa periode)", change.ToPackage.Name),
+				Quantity:    1,
+				UnitPrice:   change.ProratedCharge,
+				Amount:      change.ProratedCharge,
+			})
+			if change.ProrationCredit > 0 {
+				lines = append(lines, creditLine(fmt.Sprintf("Kredit sisa periode paket %s", change.FromPackage.Name), change.ProrationCredit))
+			}
+		}
+	}
+	if len(lines) == 0 {
+		lines = invoiceLines(*pkg, payment.StudentCount)
+	}
+	if payment.CreditApplied > 0 {
+		lines = append(lines, creditLine("Saldo kredit", payment.CreditApplied))
+	}
+	return lines
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type paymentFixture struct {
+	*billingFixture
+	payments *fakePaymentRepository
+	provider *FakePaymentProvider
+	service  PaymentService
+}
+
+// newPaymentFixture is newBillingFixture with the school still registering: its admin has verified
+// the email after selecting Premium, and nothing has been paid.
+func newPaymentFixture() *paymentFixture {
+	f := &paymentFixture{billingFixture: newBillingFixture(), payments: newFakePaymentRepository()}
+	admin := &models.User{ID: uuid.New(), Name: "Kepala TU", Email: "tu@sekolah.sch.id", SchoolID: f.school.ID}
+	f.users.users[admin.ID] = admin
+	f.school.AdminUserID = admin.ID
+	f.school.RegistrationStatus = models.RegistrationStatusEmailVerified
+	f.school.SubscriptionStatus = models.SubscriptionStatusUnpaid
+
+	f.provider = NewFakePaymentProvider("test-secret", "http://localhost:8080")
+	f.uow.repos.Payments = f.payments
+	f.uow.repos.RegistrationSessions = &fakeRegistrationSessionRepository{}
+	f.uow.repos.PasswordSetupTokens = &fakePasswordSetupTokenRepository{}
+	f.service = NewPaymentService(f.payments, nil, f.schools, f.organizations, f.users, f.provider, f.invoiceService(),
+		NewSubscriptionService(f.schools, f.config), f.uow, f.config)
+	return f
+}
+
+// notify posts a fake provider webhook for the payment, signed unless signature is given.
+func (f *paymentFixture) notify(payment *models.Payment, status, signature string) error {
+	body := fmt.Appendf(nil, `{"order_id": %q, "status": %q, "amount": %d}`, payment.OrderID, status, int64(math.Round(payment.Amount)))
+	if signature == "" {
+		signature = f.provider.Sign(body)
+	}
+	header := http.Header{}
+	header.Set(FakePaymentSignatureHeader, signature)
+	return f.service.HandleNotification(header, body)
+}
+
+func TestRegistrationCheckoutIsActivatedByTheWebhook(t *testing.T) {
+	f := newPaymentFixture()
+	registration := NewRegistrationService(f.schools, f.packages, nil, f.uow, f.config)
+
+	if _, err := registration.CompleteRegistration(f.school.ID); err == nil || err.Error() != "payment is required to complete registration" {
+		t.Fatalf("CompleteRegistration before payment error = %v, want payment to be required", err)
+	}
+
+	payment, err := f.service.CreateCheckout(f.school.ID, uuid.Nil)
+	if err != nil {
+		t.Fatalf("CreateCheckout returned error: %v", err)
+	}
+	if payment.Amount != 5550000 || payment.Status != models.PaymentStatusPending {
+		t.Fatalf("checkout amount, status = %.0f, %q; want 5550000, pending", payment.Amount, payment.Status)
+	}
+	if status := f.savedSchool().SubscriptionStatus; status != models.SubscriptionStatusUnpaid {
+		t.Errorf("subscription status after checkout = %q, want unpaid until the webhook arrives", status)
+	}
+
+	if err := f.notify(payment, models.PaymentStatusPaid, "00"); !errors.Is(err, ErrInvalidPaymentSignature) {
+		t.Errorf("forged webhook error = %v, want an invalid signature", err)
+	}
+	if f.savedSchool().SubscriptionEndDate != nil {
+		t.Fatal("a forged webhook started the subscription")
+	}
+
+	if err := f.notify(payment, models.PaymentStatusPaid, ""); err != nil {
+		t.Fatalf("HandleNotification returned error: %v", err)
+	}
+	school := f.savedSchool()
+	if school.SubscriptionStartDate == nil || school.SubscriptionEndDate == nil {
+		t.Fatal("the paid webhook left the school without a subscription period")
+	}
+	assertAbout(t, "subscription end", *school.SubscriptionEndDate, time.Now().AddDate(0, 0, defaultSubscriptionDays))
+	if school.SubscriptionStatus != models.SubscriptionStatusActive {
+		t.Errorf("subscription status = %q, want active", school.SubscriptionStatus)
+	}
+	if len(f.invoices.invoices) != 1 {
+		t.Errorf("invoices = %d, want one paid invoice", len(f.invoices.invoices))
+	}
+
+	end := *school.SubscriptionEndDate
+	if err := f.notify(payment, models.PaymentStatusPaid, ""); err != nil {
+		t.Fatalf("repeated HandleNotification returned error: %v", err)
+	}
+	if !f.savedSchool().SubscriptionEndDate.Equal(end) || len(f.invoices.invoices) != 1 {
+		t.Error("a repeated webhook applied the payment twice")
+	}
+
+	// No SMTP server runs in tests, so the registration now gets as far as sending the email
+	_, err = registration.CompleteRegistration(f.school.ID)
+	if err == nil || err.Error() == "payment is required to complete registration" {
+		t.Errorf("CompleteRegistration after payment error = %v, want it past the payment check", err)
+	}
+}
+
+func TestSelectPackageKeepsThePaidPackage(t *testing.T) {
+	f := newPaymentFixture()
+	f.school.RegistrationStatus = models.RegistrationStatusPackageSelected
+	payment, err := f.service.CreateCheckout(f.school.ID, uuid.Nil)
+	if err != nil {
+		t.Fatalf("CreateCheckout returned error: %v", err)
+	}
+	if err := f.notify(payment, models.PaymentStatusPaid, ""); err != nil {
+		t.Fatalf("HandleNotification returned error: %v", err)
+	}
+
+	durationDays := 30
+	trial := &models.Package{ID: uuid.New(), Name: "Free Trial", IsTrial: true, DurationDays: &durationDays}
+	f.packages.packages = append(f.packages.packages, trial)
+	registration := NewRegistrationService(f.schools, f.packages, nil, f.uow, f.config)
+	if _, err := registration.SelectPackage(f.school.ID, trial.ID); err == nil {
+		t.Fatal("a paid registration switched to the free trial")
+	}
+	if school := f.savedSchool(); school.PackageID != f.premium.ID || school.Package.IsTrial {
+		t.Error("the refused package change was saved")
+	}
+}
+
+func TestEvaluateStatusKeepsUnpaidPackagesUnpaid(t *testing.T) {
+	f := newBillingFixture()
+	subscription := NewSubscriptionService(f.schools, f.config)
+
+	if status := subscription.EvaluateStatus(f.school, time.Now()); status != models.SubscriptionStatusUnpaid {
+		t.Errorf("status without a paid period = %q, want unpaid", status)
+	}
+	if err := subscription.CheckAccess(models.SubscriptionStatusUnpaid, "teacher", true); err == nil {
+		t.Error("a teacher signed in to an unpaid school")
+	}
+	if err := subscription.CheckAccess(models.SubscriptionStatusUnpaid, "admin", false); err != nil {
+		t.Errorf("the admin of an unpaid school could not sign in to pay: %v", err)
+	}
+}
+
+func TestNewPaymentProviderRequiresAnExplicitProvider(t *testing.T) {
+	if _, err := NewPaymentProvider(&config.Config{}); err == nil {
+		t.Error("an empty PAYMENT_PROVIDER selected a provider")
+	}
+	if _, err := NewPaymentProvider(&config.Config{PaymentProvider: "stripe"}); err == nil {
+		t.Error("an unknown PAYMENT_PROVIDER selected a provider")
+	}
+	provider, err := NewPaymentProvider(&config.Config{PaymentProvider: "fake", FakePaymentSecret: "test-secret"})
+	if err != nil || provider.Name() != "fake" {
+		t.Errorf("PAYMENT_PROVIDER=fake returned %v, %v; want the fake provider", provider, err)
+	}
+}
+
+func TestCheckoutRefusesCentrallyBilledCampuses(t *testing.T) {
+	f := newPaymentFixture()
+	f.addOrganization(f.school)
+
+	if _, err := f.service.CreateCheckout(f.school.ID, uuid.Nil); err == nil || err.Error() != "school is billed through its organization" {
+		t.Errorf("CreateCheckout error = %v, want the campus billed through its organization", err)
+	}
+	if len(f.payments.payments) != 0 {
+		t.Error("a refused checkout was saved")
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const superAdminRoleName = "super_admin"
+
+// isSuperAdmin reports whether the user is a platform administrator who may act across schools.
+func isSuperAdmin(user *models.User) bool {
+	return user.Role.SystemName() == superAdminRoleName
+}
+
+// canAccessSchool reports whether the actor may manage data of the given school.
+// Staff without a school are never treated as global unless they hold the super admin role.
+func canAccessSchool(actor *models.User, schoolID uuid.UUID) bool {
+	if isSuperAdmin(actor) {
+		return true
+	}
+	return actor.SchoolID != uuid.Nil && actor.SchoolID == schoolID
+}
+
+// canManageSchool is canAccessSchool extended to organization admins, who manage every campus of their organization.
+func canManageSchool(schoolRepo repositories.SchoolRepository, actor *models.User, schoolID uuid.UUID) bool {
+	if canAccessSchool(actor, schoolID) {
+		return true
+	}
+	if actor.OrganizationID == nil || schoolID == uuid.Nil {
+		return false
+	}
+	school, err := schoolRepo.FindByID(schoolID)
+	return err == nil && school.OrganizationID != nil && *school.OrganizationID == *actor.OrganizationID
+}
+
+func roleHasPermission(roleRepo repositories.RoleRepository, roleID uuid.UUID, permission string) (bool, error) {
+	permissions, err := roleRepo.FindPermissionNames(roleID)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+	return slices.Contains(permissions, permission), nil
+}
+
+// selfScopedPermissions only ever reach the holder's own data, such as a parent's linked children.
+// They grant nothing over other users, so handing them out is not an escalation.
+var selfScopedPermissions = []string{models.PermissionStudentsReadLinked}
+
+// roleCovers reports whether the actor's role holds every permission of the target role that reaches other users.
+func roleCovers(roleRepo repositories.RoleRepository, actorRoleID, targetRoleID uuid.UUID) (bool, error) {
+	actorPermissions, err := roleRepo.FindPermissionNames(actorRoleID)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+	targetPermissions, err := roleRepo.FindPermissionNames(targetRoleID)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+	for _, permission := range targetPermissions {
+		if !slices.Contains(actorPermissions, permission) && !slices.Contains(selfScopedPermissions, permission) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ensureCanAssignRole stops an actor from handing out a role that grants more than the actor's own role.
+func ensureCanAssignRole(roleRepo repositories.RoleRepository, actorRoleID, targetRoleID uuid.UUID) error {
+	covered, err := roleCovers(roleRepo, actorRoleID, targetRoleID)
+	if err != nil {
+		return err
+	}
+	if !covered {
+		return errors.New("unauthorized: cannot assign a role with permissions you do not have")
+	}
+	return nil
+}
+
+// ensureCanChangeUser is the mirror of ensureCanAssignRole: it stops an actor from changing a user
+// whose current role grants more than the actor's own, and protects each school's primary admin
+// from everyone but themselves and super admins.
+func ensureCanChangeUser(roleRepo repositories.RoleRepository, schoolRepo repositories.SchoolRepository, actor, target *models.User) error {
+	if actor.ID == target.ID || isSuperAdmin(actor) {
+		return nil
+	}
+	if target.SchoolID != uuid.Nil {
+		school, err := schoolRepo.FindByID(target.SchoolID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to find school: %w", err)
+		}
+		if err == nil && school.AdminUserID == target.ID {
+			return errors.New("unauthorized: cannot change the school's primary admin")
+		}
+	}
+	covered, err := roleCovers(roleRepo, actor.RoleID, target.RoleID)
+	if err != nil {
+		return err
+	}
+	if !covered {
+		return errors.New("unauthorized: cannot change a user with permissions you do not have")
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"testing"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// schoolStaffFixture is a school with its primary admin, a second admin, a deputy holding a
+// narrower custom role and a teacher.
+type schoolStaffFixture struct {
+	users        *fakeUserRepository
+	roles        *fakeRoleRepository
+	schools      *fakeSchoolRepository
+	primaryAdmin *models.User
+	coAdmin      *models.User
+	deputy       *models.User
+	teacher      *models.User
+	teacherRole  *models.Role
+}
+
+func newSchoolStaffFixture() *schoolStaffFixture {
+	school := &models.School{ID: uuid.New(), Name: "SMA Barniee"}
+	adminRole := &models.Role{ID: uuid.New(), Name: "admin"}
+	teacherRole := &models.Role{ID: uuid.New(), Name: "teacher"}
+	deputyRole := &models.Role{ID: uuid.New(), Name: "Wakasek", SchoolID: &school.ID}
+	roles := &fakeRoleRepository{
+		roles: []*models.Role{adminRole, teacherRole, deputyRole},
+		permissions: map[uuid.UUID][]string{
+			adminRole.ID: {models.PermissionUsersRead, models.PermissionUsersUpdate, models.PermissionUsersDelete,
+				models.PermissionRolesManage, models.PermissionBillingManage},
+			deputyRole.ID: {models.PermissionUsersRead, models.PermissionUsersUpdate, models.PermissionUsersDelete, models.PermissionRolesManage},
+		},
+	}
+
+	newUser := func(email string, role *models.Role) *models.User {
+		return &models.User{ID: uuid.New(), Email: email, RoleID: role.ID, Role: *role, SchoolID: school.ID}
+	}
+	primaryAdmin := newUser("tu@sekolah.sch.id", adminRole)
+	school.AdminUserID = primaryAdmin.ID
+
+	f := &schoolStaffFixture{
+		roles:        roles,
+		schools:      newFakeSchoolRepository(school),
+		primaryAdmin: primaryAdmin,
+		coAdmin:      newUser("admin2@sekolah.sch.id", adminRole),
+		deputy:       newUser("wakasek@sekolah.sch.id", deputyRole),
+		teacher:      newUser("budi@sekolah.sch.id", teacherRole),
+		teacherRole:  teacherRole,
+	}
+	f.users = newFakeUserRepository(f.primaryAdmin, f.coAdmin, f.deputy, f.teacher)
+	return f
+}
+
+func (f *schoolStaffFixture) userService() UserService {
+	return NewUserService(f.users, f.roles, f.schools, &fakeUnitOfWork{repos: repositories.Repositories{Users: f.users}})
+}
+
+func TestUpdateUserRequiresCoveringTargetPrivileges(t *testing.T) {
+	f := newSchoolStaffFixture()
+	service := f.userService()
+	hijack := "attacker@example.com"
+
+	if _, err := service.UpdateUser(f.primaryAdmin.ID, f.coAdmin.ID, nil, &hijack, nil); err == nil {
+		t.Error("a second admin changed the primary admin's email")
+	}
+	if _, err := service.UpdateUser(f.coAdmin.ID, f.deputy.ID, nil, &hijack, nil); err == nil {
+		t.Error("a deputy changed the email of an admin holding permissions the deputy lacks")
+	}
+	if err := service.DeleteUser(f.coAdmin.ID, f.deputy.ID); err == nil {
+		t.Error("a deputy deleted an admin holding permissions the deputy lacks")
+	}
+	if f.users.users[f.primaryAdmin.ID].Email == hijack || f.users.users[f.coAdmin.ID].Email == hijack {
+		t.Fatal("a refused update was saved")
+	}
+
+	name := "Budi Santoso"
+	if _, err := service.UpdateUser(f.teacher.ID, f.deputy.ID, &name, nil, nil); err != nil {
+		t.Errorf("deputy could not update a teacher: %v", err)
+	}
+	if _, err := service.UpdateUser(f.coAdmin.ID, f.primaryAdmin.ID, &name, nil, nil); err != nil {
+		t.Errorf("primary admin could not update a second admin: %v", err)
+	}
+}
+
+func TestAssignRoleRequiresCoveringTargetPrivileges(t *testing.T) {
+	f := newSchoolStaffFixture()
+	service := NewRoleService(f.roles, nil, f.users, f.schools)
+
+	if _, err := service.AssignRole(f.coAdmin.ID, f.primaryAdmin.ID, f.teacherRole.ID); err == nil {
+		t.Error("a second admin demoted the primary admin")
+	}
+	if _, err := service.AssignRole(f.deputy.ID, f.coAdmin.ID, f.teacherRole.ID); err == nil {
+		t.Error("a deputy demoted an admin holding permissions the deputy lacks")
+	}
+	if f.users.users[f.primaryAdmin.ID].RoleID == f.teacherRole.ID || f.users.users[f.coAdmin.ID].RoleID == f.teacherRole.ID {
+		t.Fatal("a refused role change was saved")
+	}
+
+	if _, err := service.AssignRole(f.deputy.ID, f.teacher.ID, f.teacherRole.ID); err != nil {
+		t.Errorf("deputy could not assign a role to a teacher: %v", err)
+	}
+}
+
+func TestSetStatusRequiresCoveringTargetPrivileges(t *testing.T) {
+	f := newSchoolStaffFixture()
+	service := NewUserStatusService(f.users, f.roles, f.schools)
+
+	result, err := service.SetStatus([]uuid.UUID{f.primaryAdmin.ID, f.deputy.ID}, models.UserStatusSuspended, "", f.coAdmin.ID)
+	if err != nil {
+		t.Fatalf("SetStatus returned error: %v", err)
+	}
+	if len(result.Updated) != 1 || result.Updated[0] != f.deputy.ID {
+		t.Errorf("updated = %v, want only the deputy", result.Updated)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].UserID != f.primaryAdmin.ID {
+		t.Errorf("failed = %v, want the primary admin refused", result.Failed)
+	}
+
+	result, err = service.SetStatus([]uuid.UUID{f.coAdmin.ID, f.teacher.ID}, models.UserStatusLocked, "", f.deputy.ID)
+	if err != nil {
+		t.Fatalf("SetStatus returned error: %v", err)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].UserID != f.coAdmin.ID {
+		t.Errorf("failed = %v, want the admin refused to the deputy", result.Failed)
+	}
+	if f.users.users[f.primaryAdmin.ID].Status == models.UserStatusSuspended || f.users.users[f.coAdmin.ID].Status == models.UserStatusLocked {
+		t.Error("a refused status change was saved")
+	}
+	if f.users.users[f.teacher.ID].Status != models.UserStatusLocked {
+		t.Error("the deputy could not lock a teacher")
+	}
+}
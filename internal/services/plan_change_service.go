@@ -0,0 +1,472 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PlanChangeQuote is what a plan change costs. For immediate changes during a paid period the
+// unused share of the current package is credited against the target package for the same days.
+type PlanChangeQuote struct {
+	CurrentPackage  models.Package `json:"current_package"`
+	TargetPackage   models.Package `json:"target_package"`
+	AtPeriodEnd     bool           `json:"at_period_end"`
+	EffectiveAt     time.Time      `json:"effective_at"`
+	StudentCount    int64          `json:"student_count" example:"340"`
+	RemainingDays   int            `json:"remaining_days" example:"200"`
+	ProrationCredit float64        `json:"proration_credit" example:"9315068"`
+	ProratedCharge  float64        `json:"prorated_charge" example:"18630137"`
+	CreditApplied   float64        `json:"credit_applied" example:"0"` // Taken from the school's billing credit
+	Subtotal        float64        `json:"subtotal" example:"9315069"`
+	TaxRatePercent  int            `json:"tax_rate_percent" example:"11"`
+	TaxAmount       float64        `json:"tax_amount" example:"1024658"`
+	AmountDue       float64        `json:"amount_due" example:"10339727"`
+	CreditToBalance float64        `json:"credit_to_balance" example:"0"` // Added to the billing credit when the target costs less
+	// Period the school will be in after the change; a change away from a trial starts a new period
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+}
+
+// PlanChangeResult is a requested plan change, with the checkout to complete when it costs money.
+type PlanChangeResult struct {
+	PlanChange models.PlanChange `json:"plan_change"`
+	Payment    *models.Payment   `json:"payment,omitempty"`
+}
+
+// PlanOverview is the school's current plan, billing credit and plan change history.
+type PlanOverview struct {
+	School          models.School       `json:"school"`
+	ScheduledChange *models.PlanChange  `json:"scheduled_change,omitempty"`
+	History         []models.PlanChange `json:"history"`
+}
+
+type PlanChangeService interface {
+	GetPlan(adminID uuid.UUID) (*PlanOverview, error)
+	PreviewChange(adminID, packageID uuid.UUID, atPeriodEnd bool) (*PlanChangeQuote, error)
+	ChangePlan(adminID, packageID uuid.UUID, atPeriodEnd bool) (*PlanChangeResult, error)
+	CancelScheduledChange(adminID uuid.UUID) (*models.PlanChange, error)
+	// ApplyDueChanges applies scheduled changes whose period has ended. Run by the scheduler.
+	ApplyDueChanges(now time.Time) (applied, failed int, err error)
+}
+
+type planChangeService struct {
+	planChangeRepo   repositories.PlanChangeRepository
+	schoolRepo       repositories.SchoolRepository
+	organizationRepo repositories.OrganizationRepository
+	packageRepo      repositories.PackageRepository
+	userRepo         repositories.UserRepository
+	invoiceRepo      repositories.InvoiceRepository
+	payments         PaymentService
+	subscription     SubscriptionService
+	uow              repositories.UnitOfWork
+	config           *config.Config
+}
+
+func NewPlanChangeService(
+	planChangeRepo repositories.PlanChangeRepository,
+	schoolRepo repositories.SchoolRepository,
+	organizationRepo repositories.OrganizationRepository,
+	packageRepo repositories.PackageRepository,
+	userRepo repositories.UserRepository,
+	invoiceRepo repositories.InvoiceRepository,
+	payments PaymentService,
+	subscription SubscriptionService,
+	uow repositories.UnitOfWork,
+	cfg *config.Config,
+) PlanChangeService {
+	return &planChangeService{
+		planChangeRepo:   planChangeRepo,
+		schoolRepo:       schoolRepo,
+		organizationRepo: organizationRepo,
+		packageRepo:      packageRepo,
+		userRepo:         userRepo,
+		invoiceRepo:      invoiceRepo,
+		payments:         payments,
+		subscription:     subscription,
+		uow:              uow,
+		config:           cfg,
+	}
+}
+
+func (s *planChangeService) adminSchool(adminID uuid.UUID) (*models.School, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	if adminUser.SchoolID == uuid.Nil {
+		return nil, errors.New("admin is not associated with a school")
+	}
+	school, err := s.schoolRepo.FindByID(adminUser.SchoolID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("school not found")
+		}
+		return nil, fmt.Errorf("failed to find school: %w", err)
+	}
+	return school, nil
+}
+
+func findScheduledChange(planChanges repositories.PlanChangeRepository, schoolID uuid.UUID) (*models.PlanChange, error) {
+	change, err := planChanges.FindScheduledBySchoolID(schoolID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find scheduled plan change: %w", err)
+	}
+	return change, nil
+}
+
+func (s *planChangeService) GetPlan(adminID uuid.UUID) (*PlanOverview, error) {
+	school, err := s.adminSchool(adminID)
+	if err != nil {
+		return nil, err
+	}
+	scheduled, err := findScheduledChange(s.planChangeRepo, school.ID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.planChangeRepo.FindBySchoolID(school.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve plan changes: %w", err)
+	}
+	return &PlanOverview{School: *school, ScheduledChange: scheduled, History: history}, nil
+}
+
+func (s *planChangeService) PreviewChange(adminID, packageID uuid.UUID, atPeriodEnd bool) (*PlanChangeQuote, error) {
+	school, err := s.adminSchool(adminID)
+	if err != nil {
+		return nil, err
+	}
+	return s.quote(school, packageID, atPeriodEnd, time.Now())
+}
+
+func (s *planChangeService) quote(school *models.School, packageID uuid.UUID, atPeriodEnd bool, now time.Time) (*PlanChangeQuote, error) {
+	target, err := s.packageRepo.FindByID(packageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("package not found")
+		}
+		return nil, fmt.Errorf("failed to find package: %w", err)
+	}
+	if target.RetiredAt != nil {
+		return nil, errors.New("package is no longer available")
+	}
+	if target.IsTrial {
+		return nil, errors.New("cannot change to a trial package")
+	}
+	if target.ID == school.PackageID {
+		return nil, errors.New("school is already on this package")
+	}
+	if school.OrganizationID != nil && !target.MultiCampus {
+		return nil, errors.New("campuses of an organization need a multi-campus package")
+	}
+	// A change at the period end charges nothing; the organization invoice bills the new package
+	if !atPeriodEnd {
+		if err := ensureBilledPerSchool(s.organizationRepo, school); err != nil {
+			return nil, err
+		}
+	}
+
+	students, err := s.userRepo.CountStudentsBySchoolID(school.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count students: %w", err)
+	}
+	if target.MaxStudents != nil && students > int64(*target.MaxStudents) {
+		return nil, fmt.Errorf("school has more students than the target package allows: %w",
+			&repositories.StudentQuotaExceededError{Used: students, Limit: *target.MaxStudents})
+	}
+
+	quote := &PlanChangeQuote{
+		CurrentPackage: school.Package,
+		TargetPackage:  *target,
+		AtPeriodEnd:    atPeriodEnd,
+		StudentCount:   billableStudents(school, students),
+		TaxRatePercent: s.config.PPNRatePercent,
+	}
+
+	running := paidPeriodRunning(school, now)
+	if atPeriodEnd {
+		if school.SubscriptionEndDate == nil || !school.SubscriptionEndDate.After(now) {
+			return nil, errors.New("school has no running subscription period; change the plan immediately instead")
+		}
+		// Nothing is charged now; the renewal after EffectiveAt bills the target package
+		quote.EffectiveAt = *school.SubscriptionEndDate
+		quote.PeriodStart, quote.PeriodEnd = quote.EffectiveAt, quote.EffectiveAt.AddDate(0, 0, subscriptionDays(target))
+		return quote, nil
+	}
+
+	quote.EffectiveAt = now
+	targetPrice := priceSubscription(*target, quote.StudentCount, quote.TaxRatePercent).Subtotal
+	if running {
+		periodStart := school.SubscriptionEndDate.AddDate(0, 0, -subscriptionDays(&school.Package))
+		if school.SubscriptionStartDate != nil {
+			periodStart = *school.SubscriptionStartDate
+		}
+		remaining := school.SubscriptionEndDate.Sub(now)
+		fraction := math.Min(remaining.Hours()/school.SubscriptionEndDate.Sub(periodStart).Hours(), 1)
+		quote.RemainingDays = int(math.Ceil(remaining.Hours() / 24))
+		quote.PeriodStart, quote.PeriodEnd = now, *school.SubscriptionEndDate
+
+		// Only a period that was actually paid earns credit
+		if paid, err := s.invoiceRepo.FindPaidCovering(school.ID, now); err == nil {
+			currentPrice := priceSubscription(school.Package, paid.StudentCount, quote.TaxRatePercent).Subtotal
+			quote.ProrationCredit = math.Round(currentPrice * fraction)
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to find paid invoice: %w", err)
+		}
+		quote.ProratedCharge = math.Round(targetPrice * fraction)
+	} else {
+		// Trials and lapsed subscriptions start a full new period on the target package
+		quote.PeriodStart, quote.PeriodEnd = now, now.AddDate(0, 0, subscriptionDays(target))
+		quote.RemainingDays = subscriptionDays(target)
+		quote.ProratedCharge = targetPrice
+	}
+
+	net := quote.ProratedCharge - quote.ProrationCredit
+	if net < 0 {
+		quote.CreditToBalance = -net
+		net = 0
+	}
+	quote.CreditApplied = min(school.BillingCredit, net)
+	quote.Subtotal = net - quote.CreditApplied
+	quote.TaxAmount = math.Round(quote.Subtotal * float64(quote.TaxRatePercent) / 100)
+	quote.AmountDue = quote.Subtotal + quote.TaxAmount
+	return quote, nil
+}
+
+func (s *planChangeService) ChangePlan(adminID, packageID uuid.UUID, atPeriodEnd bool) (*PlanChangeResult, error) {
+	school, err := s.adminSchool(adminID)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := s.quote(school, packageID, atPeriodEnd, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	change := &models.PlanChange{
+		SchoolID:        school.ID,
+		FromPackageID:   school.PackageID,
+		ToPackageID:     quote.TargetPackage.ID,
+		AtPeriodEnd:     atPeriodEnd,
+		EffectiveAt:     quote.EffectiveAt,
+		Status:          models.PlanChangeStatusScheduled,
+		ProrationCredit: quote.ProrationCredit,
+		ProratedCharge:  quote.ProratedCharge,
+		AmountDue:       quote.AmountDue,
+		CreditToBalance: quote.CreditToBalance,
+		FromPackage:     quote.CurrentPackage,
+		ToPackage:       quote.TargetPackage,
+		CreatedBy:       adminID,
+		UpdatedBy:       adminID,
+	}
+	if !atPeriodEnd {
+		change.Status = models.PlanChangeStatusPendingPayment
+	}
+	var replaced *models.PlanChange
+	err = s.uow.Do(func(repos repositories.Repositories) error {
+		// A new request replaces every open change, so two checkouts quoted from the same package
+		// and the same credit are never both live
+		var err error
+		replaced, err = cancelOpenChanges(repos, school.ID, adminID)
+		if err != nil {
+			return err
+		}
+		if err := repos.PlanChanges.Create(change); err != nil {
+			return fmt.Errorf("failed to save plan change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if atPeriodEnd {
+		return &PlanChangeResult{PlanChange: *change}, nil
+	}
+
+	// Changes that cost nothing settle from credit inside StartCheckout and are applied right away
+	payment, err := s.payments.StartCheckout(school, &models.Payment{
+		SchoolID:       school.ID,
+		PackageID:      quote.TargetPackage.ID,
+		PlanChangeID:   &change.ID,
+		PeriodStart:    quote.PeriodStart,
+		PeriodEnd:      quote.PeriodEnd,
+		StudentCount:   quote.StudentCount,
+		CreditApplied:  quote.CreditApplied,
+		Subtotal:       quote.Subtotal,
+		TaxRatePercent: quote.TaxRatePercent,
+		TaxAmount:      quote.TaxAmount,
+		Amount:         quote.AmountDue,
+		CreatedBy:      adminID,
+	}, fmt.Sprintf("Barniee: perubahan paket ke %s", quote.TargetPackage.Name))
+	if err != nil {
+		if undoErr := s.abandonChange(change, replaced, err); undoErr != nil {
+			log.Printf("Failed to undo plan change %s after its checkout failed: %v", change.ID, undoErr)
+		}
+		return nil, err
+	}
+
+	// The checkout is live and the payment already names the change, so a failed link is only logged
+	updated, err := s.planChangeRepo.FindByID(change.ID)
+	if err != nil {
+		log.Printf("Failed to reload plan change %s: %v", change.ID, err)
+		return &PlanChangeResult{PlanChange: *change, Payment: payment}, nil
+	}
+	updated.PaymentID = &payment.ID
+	if err := s.planChangeRepo.Update(updated); err != nil {
+		log.Printf("Failed to link payment %s to plan change %s: %v", payment.OrderID, change.ID, err)
+	}
+	return &PlanChangeResult{PlanChange: *updated, Payment: payment}, nil
+}
+
+// abandonChange undoes a plan change whose checkout could not be started. The checkout calls the
+// payment provider, so it runs after the change is saved rather than inside that transaction; this
+// marks the change failed and brings back the scheduled change it replaced.
+func (s *planChangeService) abandonChange(change, replaced *models.PlanChange, cause error) error {
+	return s.uow.Do(func(repos repositories.Repositories) error {
+		change.Status = models.PlanChangeStatusFailed
+		change.FailureReason = cause.Error()
+		if err := repos.PlanChanges.Update(change); err != nil {
+			return fmt.Errorf("failed to mark plan change as failed: %w", err)
+		}
+		if replaced != nil {
+			replaced.Status = models.PlanChangeStatusScheduled
+			if err := repos.PlanChanges.Update(replaced); err != nil {
+				return fmt.Errorf("failed to restore scheduled plan change: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *planChangeService) CancelScheduledChange(adminID uuid.UUID) (*models.PlanChange, error) {
+	school, err := s.adminSchool(adminID)
+	if err != nil {
+		return nil, err
+	}
+	change, err := cancelScheduled(s.planChangeRepo, school.ID, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if change == nil {
+		return nil, errors.New("no scheduled plan change")
+	}
+	return change, nil
+}
+
+func cancelScheduled(planChanges repositories.PlanChangeRepository, schoolID, actorID uuid.UUID) (*models.PlanChange, error) {
+	change, err := findScheduledChange(planChanges, schoolID)
+	if err != nil || change == nil {
+		return nil, err
+	}
+	change.Status = models.PlanChangeStatusCancelled
+	change.UpdatedBy = actorID
+	if err := planChanges.Update(change); err != nil {
+		return nil, fmt.Errorf("failed to cancel plan change: %w", err)
+	}
+	return change, nil
+}
+
+// cancelOpenChanges cancels the school's scheduled change and any immediate change still waiting for
+// its payment, expiring that payment. A checkout paid anyway is credited to the school by the payment
+// webhook instead of applied. It returns the cancelled scheduled change, if there was one.
+func cancelOpenChanges(repos repositories.Repositories, schoolID, actorID uuid.UUID) (*models.PlanChange, error) {
+	changes, err := repos.PlanChanges.FindOpenBySchoolID(schoolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find open plan changes: %w", err)
+	}
+	var scheduled *models.PlanChange
+	for i := range changes {
+		change := &changes[i]
+		if change.Status == models.PlanChangeStatusScheduled {
+			scheduled = change
+		} else {
+			change.FailureReason = "replaced by a newer plan change"
+			if change.PaymentID != nil {
+				if err := repos.Payments.UpdateStatus(*change.PaymentID, models.PaymentStatusExpired); err != nil {
+					return nil, fmt.Errorf("failed to expire payment of plan change %s: %w", change.ID, err)
+				}
+			}
+		}
+		change.Status = models.PlanChangeStatusCancelled
+		change.UpdatedBy = actorID
+		if err := repos.PlanChanges.Update(change); err != nil {
+			return nil, fmt.Errorf("failed to cancel plan change: %w", err)
+		}
+	}
+	return scheduled, nil
+}
+
+func (s *planChangeService) ApplyDueChanges(now time.Time) (int, int, error) {
+	changes, err := s.planChangeRepo.FindDue(now)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to find due plan changes: %w", err)
+	}
+
+	applied, failed := 0, 0
+	for i := range changes {
+		change := &changes[i]
+		// The school and the change are updated together, so a change is never marked
+		// applied without the package switch or the other way round.
+		err := s.uow.Do(func(repos repositories.Repositories) error {
+			if err := s.applyScheduled(repos, change, now); err != nil {
+				return err
+			}
+			change.Status = models.PlanChangeStatusApplied
+			change.AppliedAt = &now
+			return repos.PlanChanges.Update(change)
+		})
+		if err == nil {
+			applied++
+			continue
+		}
+
+		change.Status = models.PlanChangeStatusFailed
+		change.FailureReason = err.Error()
+		change.AppliedAt = nil
+		failed++
+		if err := s.planChangeRepo.Update(change); err != nil {
+			return applied, failed, fmt.Errorf("failed to update plan change %s: %w", change.ID, err)
+		}
+	}
+	return applied, failed, nil
+}
+
+// applyScheduled switches the school's package at the end of its period. The school then
+// renews on the new package through the usual checkout.
+func (s *planChangeService) applyScheduled(repos repositories.Repositories, change *models.PlanChange, now time.Time) error {
+	school, err := repos.Schools.FindByIDForUpdate(change.SchoolID)
+	if err != nil {
+		return fmt.Errorf("failed to find school: %w", err)
+	}
+	if school.PackageID != change.FromPackageID {
+		return errors.New("school changed package since this change was scheduled")
+	}
+	students, err := repos.Users.CountStudentsBySchoolID(school.ID)
+	if err != nil {
+		return fmt.Errorf("failed to count students: %w", err)
+	}
+	if change.ToPackage.MaxStudents != nil && students > int64(*change.ToPackage.MaxStudents) {
+		return fmt.Errorf("school has more students than the target package allows: %w",
+			&repositories.StudentQuotaExceededError{Used: students, Limit: *change.ToPackage.MaxStudents})
+	}
+
+	switchSchoolPackage(school, &change.ToPackage)
+	school.SubscriptionStatus = s.subscription.EvaluateStatus(school, now)
+	school.UpdatedBy = change.CreatedBy
+	if err := repos.Schools.Update(school); err != nil {
+		return fmt.Errorf("failed to update school: %w", err)
+	}
+	return nil
+}
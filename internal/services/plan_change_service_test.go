@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"auth-barniee/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// unavailablePaymentProvider fails every checkout, like a gateway that is down.
+type unavailablePaymentProvider struct{}
+
+func (unavailablePaymentProvider) Name() string { return "unavailable" }
+
+func (unavailablePaymentProvider) CreateCheckout(ctx context.Context, req CheckoutRequest) (*CheckoutSession, error) {
+	return nil, errors.New("gateway unavailable")
+}
+
+func (unavailablePaymentProvider) ParseNotification(header http.Header, body []byte) (*PaymentNotification, error) {
+	return nil, ErrInvalidPaymentSignature
+}
+
+func TestChangePlanUndoesTheChangeWhenTheCheckoutFails(t *testing.T) {
+	f := newPaymentFixture()
+	start, end := time.Now().AddDate(0, 0, -100), time.Now().AddDate(0, 0, 265)
+	f.school.SubscriptionStartDate, f.school.SubscriptionEndDate = &start, &end
+
+	pricePerYear := 10000000.0
+	enterprise := &models.Package{ID: uuid.New(), Name: "Enterprise", PricePerYear: &pricePerYear}
+	basic := &models.Package{ID: uuid.New(), Name: "Basic", PricePerYear: &pricePerYear}
+	f.packages.packages = append(f.packages.packages, enterprise, basic)
+	scheduled := &models.PlanChange{ID: uuid.New(), SchoolID: f.school.ID, FromPackageID: f.premium.ID, ToPackageID: basic.ID,
+		AtPeriodEnd: true, EffectiveAt: end, Status: models.PlanChangeStatusScheduled}
+	planChanges := newFakePlanChangeRepository(scheduled)
+	f.uow.repos.PlanChanges = planChanges
+
+	payments := NewPaymentService(f.payments, planChanges, f.schools, f.organizations, f.users, unavailablePaymentProvider{},
+		f.invoiceService(), NewSubscriptionService(f.schools, f.config), f.uow, f.config)
+	service := NewPlanChangeService(planChanges, f.schools, f.organizations, f.packages, f.users, f.invoices, payments,
+		NewSubscriptionService(f.schools, f.config), f.uow, f.config)
+
+	if _, err := service.ChangePlan(f.school.AdminUserID, enterprise.ID, false); err == nil {
+		t.Fatal("ChangePlan succeeded without a checkout")
+	}
+
+	var failed int
+	for _, change := range planChanges.changes {
+		switch {
+		case change.ID == scheduled.ID:
+			if change.Status != models.PlanChangeStatusScheduled {
+				t.Errorf("replaced change status = %q, want it scheduled again", change.Status)
+			}
+		case change.Status == models.PlanChangeStatusFailed:
+			failed++
+			if change.FailureReason == "" {
+				t.Error("the failed change has no reason")
+			}
+		default:
+			t.Errorf("plan change left %q after the checkout failed", change.Status)
+		}
+	}
+	if failed != 1 {
+		t.Errorf("failed changes = %d, want 1", failed)
+	}
+}
+
+func TestOnlyTheLatestPaidPlanChangeIsApplied(t *testing.T) {
+	f := newPaymentFixture()
+	start, end := time.Now().AddDate(0, 0, -100), time.Now().AddDate(0, 0, 265)
+	f.school.SubscriptionStartDate, f.school.SubscriptionEndDate = &start, &end
+	f.school.SubscriptionStatus = models.SubscriptionStatusActive
+
+	pricePerYear := 20000000.0
+	enterprise := &models.Package{ID: uuid.New(), Name: "Enterprise", PricePerYear: &pricePerYear}
+	campus := &models.Package{ID: uuid.New(), Name: "Campus", PricePerYear: &pricePerYear}
+	f.packages.packages = append(f.packages.packages, enterprise, campus)
+	planChanges := newFakePlanChangeRepository()
+	f.uow.repos.PlanChanges = planChanges
+
+	payments := NewPaymentService(f.payments, planChanges, f.schools, f.organizations, f.users, f.provider,
+		f.invoiceService(), NewSubscriptionService(f.schools, f.config), f.uow, f.config)
+	f.service = payments
+	service := NewPlanChangeService(planChanges, f.schools, f.organizations, f.packages, f.users, f.invoices, payments,
+		NewSubscriptionService(f.schools, f.config), f.uow, f.config)
+
+	first, err := service.ChangePlan(f.school.AdminUserID, enterprise.ID, false)
+	if err != nil {
+		t.Fatalf("first ChangePlan returned error: %v", err)
+	}
+	second, err := service.ChangePlan(f.school.AdminUserID, campus.ID, false)
+	if err != nil {
+		t.Fatalf("second ChangePlan returned error: %v", err)
+	}
+	if first.Payment == nil || second.Payment == nil {
+		t.Fatal("the plan changes have no checkout")
+	}
+	if status := planChanges.changes[first.PlanChange.ID].Status; status != models.PlanChangeStatusCancelled {
+		t.Errorf("replaced change status = %q, want cancelled", status)
+	}
+	if status := f.payments.payments[first.Payment.ID].Status; status != models.PaymentStatusExpired {
+		t.Errorf("replaced checkout status = %q, want expired", status)
+	}
+
+	// The admin pays the newer checkout, then the replaced one that was still open at the gateway
+	if err := f.notify(second.Payment, models.PaymentStatusPaid, ""); err != nil {
+		t.Fatalf("HandleNotification for the second change returned error: %v", err)
+	}
+	if err := f.notify(first.Payment, models.PaymentStatusPaid, ""); err != nil {
+		t.Fatalf("HandleNotification for the first change returned error: %v", err)
+	}
+
+	school := f.savedSchool()
+	if school.PackageID != campus.ID {
+		t.Errorf("school package = %s, want the package of the latest change", school.Package.Name)
+	}
+	if school.BillingCredit != first.Payment.Amount {
+		t.Errorf("billing credit = %.0f, want the replaced checkout's %.0f", school.BillingCredit, first.Payment.Amount)
+	}
+	applied := 0
+	for _, change := range planChanges.changes {
+		if change.Status == models.PlanChangeStatusApplied {
+			applied++
+		}
+	}
+	if applied != 1 {
+		t.Errorf("applied changes = %d, want 1", applied)
+	}
+}
+
+func TestPaidPlanChangeIsCreditedWhenTheSchoolChangedPackage(t *testing.T) {
+	f := newPaymentFixture()
+	start, end := time.Now().AddDate(0, 0, -100), time.Now().AddDate(0, 0, 265)
+	f.school.SubscriptionStartDate, f.school.SubscriptionEndDate = &start, &end
+
+	pricePerYear := 20000000.0
+	enterprise := &models.Package{ID: uuid.New(), Name: "Enterprise", PricePerYear: &pricePerYear}
+	f.packages.packages = append(f.packages.packages, enterprise)
+	planChanges := newFakePlanChangeRepository()
+	f.uow.repos.PlanChanges = planChanges
+	f.service = NewPaymentService(f.payments, planChanges, f.schools, f.organizations, f.users, f.provider,
+		f.invoiceService(), NewSubscriptionService(f.schools, f.config), f.uow, f.config)
+	service := NewPlanChangeService(planChanges, f.schools, f.organizations, f.packages, f.users, f.invoices, f.service,
+		NewSubscriptionService(f.schools, f.config), f.uow, f.config)
+
+	result, err := service.ChangePlan(f.school.AdminUserID, enterprise.ID, false)
+	if err != nil {
+		t.Fatalf("ChangePlan returned error: %v", err)
+	}
+	// A super admin moves the school to another package before the checkout is paid
+	other := &models.Package{ID: uuid.New(), Name: "Custom"}
+	f.school.PackageID, f.school.Package = other.ID, *other
+
+	if err := f.notify(result.Payment, models.PaymentStatusPaid, ""); err != nil {
+		t.Fatalf("HandleNotification returned error: %v", err)
+	}
+	if school := f.savedSchool(); school.PackageID != other.ID || school.BillingCredit != result.Payment.Amount {
+		t.Errorf("school package, credit = %s, %.0f; want the change refused and %.0f credited",
+			school.Package.Name, school.BillingCredit, result.Payment.Amount)
+	}
+	if change := planChanges.changes[result.PlanChange.ID]; change.Status != models.PlanChangeStatusFailed {
+		t.Errorf("plan change status = %q, want failed", change.Status)
+	}
+}
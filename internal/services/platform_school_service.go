@@ -0,0 +1,151 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SchoolDetail is a school as seen by platform admins, with its primary admin and user counts.
+type SchoolDetail struct {
+	School     models.School    `json:"school"`
+	AdminUser  *models.User     `json:"admin_user"`
+	UserCounts map[string]int64 `json:"user_counts" example:"admin:1,teacher:12,student:340"`
+	TotalUsers int64            `json:"total_users" example:"353"`
+}
+
+type PlatformSchoolService interface {
+	ListSchools(filter repositories.SchoolFilter) ([]models.School, error)
+	GetSchoolDetail(schoolID uuid.UUID) (*SchoolDetail, error)
+	SuspendSchool(actorID, schoolID uuid.UUID, reason string) (*models.School, error)
+	ReactivateSchool(actorID, schoolID uuid.UUID) (*models.School, error)
+	DeleteSchool(schoolID uuid.UUID) error
+}
+
+type platformSchoolService struct {
+	schoolRepo   repositories.SchoolRepository
+	userRepo     repositories.UserRepository
+	subscription SubscriptionService
+}
+
+func NewPlatformSchoolService(schoolRepo repositories.SchoolRepository, userRepo repositories.UserRepository, subscription SubscriptionService) PlatformSchoolService {
+	return &platformSchoolService{
+		schoolRepo:   schoolRepo,
+		userRepo:     userRepo,
+		subscription: subscription,
+	}
+}
+
+func (s *platformSchoolService) findSchool(schoolID uuid.UUID) (*models.School, error) {
+	school, err := s.schoolRepo.FindByID(schoolID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("school not found")
+		}
+		return nil, fmt.Errorf("failed to find school: %w", err)
+	}
+	return school, nil
+}
+
+func (s *platformSchoolService) ListSchools(filter repositories.SchoolFilter) ([]models.School, error) {
+	schools, err := s.schoolRepo.FindAll(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve schools: %w", err)
+	}
+	return schools, nil
+}
+
+func (s *platformSchoolService) GetSchoolDetail(schoolID uuid.UUID) (*SchoolDetail, error) {
+	school, err := s.findSchool(schoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &SchoolDetail{School: *school}
+	if school.AdminUserID != uuid.Nil {
+		adminUser, err := s.userRepo.FindByID(school.AdminUserID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to find school admin: %w", err)
+		}
+		detail.AdminUser = adminUser
+	}
+
+	counts, err := s.userRepo.CountBySchoolIDGroupedByRole(school.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count school users: %w", err)
+	}
+	detail.UserCounts = counts
+	for _, count := range counts {
+		detail.TotalUsers += count
+	}
+	return detail, nil
+}
+
+func (s *platformSchoolService) SuspendSchool(actorID, schoolID uuid.UUID, reason string) (*models.School, error) {
+	school, err := s.findSchool(schoolID)
+	if err != nil {
+		return nil, err
+	}
+	if school.SuspendedAt != nil {
+		return nil, errors.New("school is already suspended")
+	}
+
+	now := time.Now()
+	school.SuspendedAt = &now
+	school.SuspendedReason = reason
+	school.SuspendedBy = &actorID
+	school.SubscriptionStatus = models.SubscriptionStatusSuspended
+	school.UpdatedBy = actorID
+	if err := s.schoolRepo.Update(school); err != nil {
+		return nil, fmt.Errorf("failed to suspend school: %w", err)
+	}
+	return school, nil
+}
+
+func (s *platformSchoolService) ReactivateSchool(actorID, schoolID uuid.UUID) (*models.School, error) {
+	school, err := s.findSchool(schoolID)
+	if err != nil {
+		return nil, err
+	}
+	if school.SuspendedAt == nil {
+		return nil, errors.New("school is not suspended")
+	}
+
+	school.SuspendedAt = nil
+	school.SuspendedReason = ""
+	school.SuspendedBy = nil
+	school.SubscriptionStatus = s.subscription.EvaluateStatus(school, time.Now())
+	school.UpdatedBy = actorID
+	if err := s.schoolRepo.Update(school); err != nil {
+		return nil, fmt.Errorf("failed to reactivate school: %w", err)
+	}
+	return school, nil
+}
+
+func (s *platformSchoolService) DeleteSchool(schoolID uuid.UUID) error {
+	school, err := s.findSchool(schoolID)
+	if err != nil {
+		return err
+	}
+	if school.OrganizationID != nil {
+		return errors.New("school belongs to an organization, remove it from the organization first")
+	}
+	hasBillingHistory, err := s.schoolRepo.HasBillingHistory(school.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check billing history: %w", err)
+	}
+	if hasBillingHistory {
+		// Paid payments and invoices are tax records and outlive the school; suspend it instead
+		return errors.New("school has billing history and cannot be deleted")
+	}
+	if err := s.schoolRepo.Delete(school.ID); err != nil {
+		return fmt.Errorf("failed to delete school: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+	"auth-barniee/internal/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPurgedRegistrationsLimit = 50
+	maxPurgedRegistrationsLimit     = 500
+)
+
+// RegistrationCleanupService follows up on registrations that were started but never completed.
+type RegistrationCleanupService interface {
+	// SendReminders emails applicants whose registration has been idle for the reminder period.
+	// Each registration is reminded once.
+	SendReminders(now time.Time) (sent, failed int, err error)
+	// PurgeAbandoned deletes registrations idle for the purge period, with their users, OTPs and unpaid checkouts.
+	PurgeAbandoned(now time.Time) (int, error)
+	GetPurgedRegistrations(limit int) ([]models.PurgedRegistration, error)
+}
+
+type registrationCleanupService struct {
+	schoolRepo             repositories.SchoolRepository
+	userRepo               repositories.UserRepository
+	purgedRegistrationRepo repositories.PurgedRegistrationRepository
+	uow                    repositories.UnitOfWork
+	config                 *config.Config
+}
+
+func NewRegistrationCleanupService(schoolRepo repositories.SchoolRepository, userRepo repositories.UserRepository, purgedRegistrationRepo repositories.PurgedRegistrationRepository, uow repositories.UnitOfWork, cfg *config.Config) RegistrationCleanupService {
+	return &registrationCleanupService{
+		schoolRepo:             schoolRepo,
+		userRepo:               userRepo,
+		purgedRegistrationRepo: purgedRegistrationRepo,
+		uow:                    uow,
+		config:                 cfg,
+	}
+}
+
+func (s *registrationCleanupService) SendReminders(now time.Time) (int, int, error) {
+	idleBefore := now.Add(-time.Duration(s.config.RegistrationReminderHours) * time.Hour)
+	schools, err := s.schoolRepo.FindIncompleteRegistrations(idleBefore)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to find unfinished registrations: %w", err)
+	}
+
+	sent, failed := 0, 0
+	for _, school := range schools {
+		// Drafts have no applicant to write to yet
+		if school.RegistrationReminderAt != nil || school.AdminUserID == uuid.Nil {
+			continue
+		}
+		adminUser, err := s.userRepo.FindByID(school.AdminUserID)
+		if err != nil {
+			failed++
+			continue
+		}
+
+		subject := "Barniee: Selesaikan Registrasi Sekolah Anda"
+		body := fmt.Sprintf("Halo %s,\n\nRegistrasi %s di Barniee belum selesai.\nLanjutkan registrasi dengan meminta kode verifikasi di halaman registrasi menggunakan email ini. Registrasi yang tidak diselesaikan akan dihapus %d hari setelah langkah terakhir.\n\nTerima kasih,\nTim Barniee",
+			adminUser.Name, school.Name, s.config.RegistrationPurgeDays)
+		if err := utils.SendEmail(s.config, adminUser.Email, subject, body); err != nil {
+			failed++
+			continue
+		}
+		if err := s.schoolRepo.MarkRegistrationReminded(school.ID, now); err != nil {
+			return sent, failed, fmt.Errorf("failed to record reminder: %w", err)
+		}
+		sent++
+	}
+	return sent, failed, nil
+}
+
+func (s *registrationCleanupService) PurgeAbandoned(now time.Time) (int, error) {
+	idleBefore := now.AddDate(0, 0, -s.config.RegistrationPurgeDays)
+	schools, err := s.schoolRepo.FindIncompleteRegistrations(idleBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find abandoned registrations: %w", err)
+	}
+
+	purged := 0
+	for _, school := range schools {
+		deleted := false
+		err := s.uow.Do(func(repos repositories.Repositories) error {
+			// The applicant may have come back since the list was read
+			current, err := repos.Schools.FindByIDForUpdate(school.ID)
+			if err != nil {
+				return err
+			}
+			if current.RegistrationStatus == models.RegistrationStatusCompleted || !current.UpdatedAt.Before(idleBefore) {
+				return nil
+			}
+
+			record := &models.PurgedRegistration{
+				SchoolID:           current.ID,
+				SchoolName:         current.Name,
+				RegistrationStatus: current.RegistrationStatus,
+				StartedAt:          current.CreatedAt,
+				LastActivityAt:     current.UpdatedAt,
+				ReminderSentAt:     current.RegistrationReminderAt,
+				PurgedAt:           now,
+			}
+			if admin, err := repos.Users.FindByID(current.AdminUserID); err == nil {
+				record.AdminEmail = admin.Email
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			if err := repos.Payments.DeleteUnpaidBySchoolID(current.ID); err != nil {
+				return err
+			}
+			if err := repos.Schools.Delete(current.ID); err != nil {
+				return err
+			}
+			if err := repos.PurgedRegistrations.Create(record); err != nil {
+				return err
+			}
+			deleted = true
+			return nil
+		})
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return purged, fmt.Errorf("failed to purge registration of school %s: %w", school.ID, err)
+		}
+		if deleted {
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (s *registrationCleanupService) GetPurgedRegistrations(limit int) ([]models.PurgedRegistration, error) {
+	if limit <= 0 {
+		limit = defaultPurgedRegistrationsLimit
+	}
+	if limit > maxPurgedRegistrationsLimit {
+		limit = maxPurgedRegistrationsLimit
+	}
+	purged, err := s.purgedRegistrationRepo.FindRecent(limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve purged registrations: %w", err)
+	}
+	return purged, nil
+}
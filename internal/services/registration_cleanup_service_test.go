@@ -0,0 +1,266 @@
+package services
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// smtpSink accepts mail, with any credentials, on a local port and records the recipients.
+type smtpSink struct {
+	listener   net.Listener
+	mu         sync.Mutex
+	recipients []string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen for SMTP: %v", err)
+	}
+	sink := &smtpSink{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go sink.serve(conn)
+		}
+	}()
+	return sink
+}
+
+func (s *smtpSink) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			// utils.SendEmail always authenticates
+			text.PrintfLine("250-localhost")
+			text.PrintfLine("250 AUTH PLAIN")
+		case strings.HasPrefix(command, "AUTH"):
+			text.PrintfLine("235 Authenticated")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.mu.Lock()
+			s.recipients = append(s.recipients, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			s.mu.Unlock()
+			text.PrintfLine("250 OK")
+		case command == "DATA":
+			text.PrintfLine("354 Go ahead")
+			if _, err := text.ReadDotBytes(); err != nil {
+				return
+			}
+			text.PrintfLine("250 OK")
+		case command == "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("250 OK")
+		}
+	}
+}
+
+func (s *smtpSink) configure(cfg *config.Config) {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	cfg.SMTPHost, cfg.SMTPPort, cfg.SenderEmail = "localhost", addr.Port, "noreply@barniee.id"
+}
+
+func (s *smtpSink) sentTo() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.recipients...)
+}
+
+type registrationCleanupFixture struct {
+	service  RegistrationCleanupService
+	schools  *fakeSchoolRepository
+	users    *fakeUserRepository
+	payments *fakePaymentRepository
+	purged   *fakePurgedRegistrationRepository
+	config   *config.Config
+	now      time.Time
+}
+
+func newRegistrationCleanupFixture() *registrationCleanupFixture {
+	f := &registrationCleanupFixture{
+		schools:  newFakeSchoolRepository(),
+		users:    newFakeUserRepository(),
+		payments: newFakePaymentRepository(),
+		purged:   &fakePurgedRegistrationRepository{},
+		config:   &config.Config{RegistrationReminderHours: 24, RegistrationPurgeDays: 14},
+		now:      time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+	}
+	f.schools.paid = make(map[uuid.UUID]bool)
+	uow := &fakeUnitOfWork{repos: repositories.Repositories{
+		Schools: f.schools, Users: f.users, Payments: f.payments, PurgedRegistrations: f.purged,
+	}}
+	f.service = NewRegistrationCleanupService(f.schools, f.users, f.purged, uow, f.config)
+	return f
+}
+
+// registration adds an unfinished registration whose last step was idle ago, with an applicant unless it is a draft.
+func (f *registrationCleanupFixture) registration(name, status string, idle time.Duration) *models.School {
+	school := &models.School{ID: uuid.New(), Name: name, RegistrationStatus: status, CreatedAt: f.now.Add(-idle), UpdatedAt: f.now.Add(-idle)}
+	if status != models.RegistrationStatusDraft {
+		admin := &models.User{ID: uuid.New(), Name: "Pak Budi", Email: strings.ReplaceAll(strings.ToLower(name), " ", ".") + "@sekolah.sch.id", SchoolID: school.ID}
+		f.users.users[admin.ID] = admin
+		school.AdminUserID = admin.ID
+	}
+	f.schools.schools[school.ID] = school
+	return school
+}
+
+func TestSendRemindersAfterTheReminderPeriod(t *testing.T) {
+	f := newRegistrationCleanupFixture()
+	sink := newSMTPSink(t)
+	sink.configure(f.config)
+	idle := f.registration("SMA Lama", models.RegistrationStatusPackageSelected, 25*time.Hour)
+	recent := f.registration("SMA Baru", models.RegistrationStatusPackageSelected, 23*time.Hour)
+	done := f.registration("SMA Selesai", models.RegistrationStatusCompleted, 25*time.Hour)
+	draft := f.registration("SMA Draf", models.RegistrationStatusDraft, 25*time.Hour)
+
+	sent, failed, err := f.service.SendReminders(f.now)
+	if err != nil {
+		t.Fatalf("SendReminders returned error: %v", err)
+	}
+	if sent != 1 || failed != 0 {
+		t.Errorf("sent %d, failed %d; want one reminder sent", sent, failed)
+	}
+	if to := sink.sentTo(); len(to) != 1 || to[0] != "sma.lama@sekolah.sch.id" {
+		t.Errorf("reminders sent to %v, want only the idle applicant", to)
+	}
+	if idle.RegistrationReminderAt == nil || !idle.RegistrationReminderAt.Equal(f.now) {
+		t.Errorf("reminder time = %v, want %v", idle.RegistrationReminderAt, f.now)
+	}
+	for _, school := range []*models.School{recent, done, draft} {
+		if school.RegistrationReminderAt != nil {
+			t.Errorf("%s was reminded", school.Name)
+		}
+	}
+
+	if sent, _, _ := f.service.SendReminders(f.now.Add(time.Hour)); sent != 0 || len(sink.sentTo()) != 1 {
+		t.Error("a registration was reminded twice")
+	}
+}
+
+func TestSendRemindersKeepsFailedRemindersForTheNextRun(t *testing.T) {
+	f := newRegistrationCleanupFixture()
+	closed := newSMTPSink(t)
+	closed.configure(f.config)
+	closed.listener.Close()
+	school := f.registration("SMA Lama", models.RegistrationStatusAdminAdded, 25*time.Hour)
+
+	sent, failed, err := f.service.SendReminders(f.now)
+	if err != nil {
+		t.Fatalf("SendReminders returned error: %v", err)
+	}
+	if sent != 0 || failed != 1 {
+		t.Errorf("sent %d, failed %d; want the reminder counted as failed", sent, failed)
+	}
+	if school.RegistrationReminderAt != nil {
+		t.Error("a reminder that was not sent was recorded")
+	}
+}
+
+func TestPurgeAbandonedAfterThePurgePeriod(t *testing.T) {
+	f := newRegistrationCleanupFixture()
+	abandoned := f.registration("SMA Lama", models.RegistrationStatusPackageSelected, 15*24*time.Hour)
+	abandonedDraft := f.registration("SMA Draf", models.RegistrationStatusDraft, 15*24*time.Hour)
+	recent := f.registration("SMA Baru", models.RegistrationStatusPackageSelected, 13*24*time.Hour)
+	unpaid := &models.Payment{ID: uuid.New(), SchoolID: abandoned.ID, Status: models.PaymentStatusPending}
+	f.payments.payments[unpaid.ID] = unpaid
+
+	purged, err := f.service.PurgeAbandoned(f.now)
+	if err != nil {
+		t.Fatalf("PurgeAbandoned returned error: %v", err)
+	}
+	if purged != 2 {
+		t.Errorf("purged = %d, want 2", purged)
+	}
+	for _, school := range []*models.School{abandoned, abandonedDraft} {
+		if _, ok := f.schools.schools[school.ID]; ok {
+			t.Errorf("%s was kept", school.Name)
+		}
+	}
+	if _, ok := f.schools.schools[recent.ID]; !ok {
+		t.Error("the registration within the purge period was deleted")
+	}
+	if _, ok := f.payments.payments[unpaid.ID]; ok {
+		t.Error("the unpaid checkout of the purged registration was kept")
+	}
+
+	records := map[uuid.UUID]models.PurgedRegistration{}
+	for _, record := range f.purged.purged {
+		records[record.SchoolID] = record
+	}
+	if record := records[abandoned.ID]; record.AdminEmail != "sma.lama@sekolah.sch.id" || !record.PurgedAt.Equal(f.now) {
+		t.Errorf("purge record = %+v, want the applicant's email and the purge time", record)
+	}
+	if record, ok := records[abandonedDraft.ID]; !ok || record.AdminEmail != "" {
+		t.Errorf("draft purge record = %+v, want one without an applicant", record)
+	}
+}
+
+func TestPurgeAbandonedSkipsPaidRegistrations(t *testing.T) {
+	f := newRegistrationCleanupFixture()
+	paidDraft := f.registration("SMA Lunas", models.RegistrationStatusDraft, 30*24*time.Hour)
+	f.schools.paid[paidDraft.ID] = true
+
+	purged, err := f.service.PurgeAbandoned(f.now)
+	if err != nil {
+		t.Fatalf("PurgeAbandoned returned error: %v", err)
+	}
+	if purged != 0 || len(f.purged.purged) != 0 {
+		t.Errorf("purged = %d, want the paid registration kept", purged)
+	}
+	if _, ok := f.schools.schools[paidDraft.ID]; !ok {
+		t.Error("a registration with a paid payment was deleted")
+	}
+}
+
+func TestPurgeAbandonedKeepsRegistrationsResumedSinceTheyWereListed(t *testing.T) {
+	f := newRegistrationCleanupFixture()
+	school := f.registration("SMA Lama", models.RegistrationStatusPackageSelected, 15*24*time.Hour)
+	resumed := &resumingSchoolRepository{fakeSchoolRepository: f.schools, now: f.now}
+	uow := &fakeUnitOfWork{repos: repositories.Repositories{Schools: resumed, Users: f.users, Payments: f.payments, PurgedRegistrations: f.purged}}
+	service := NewRegistrationCleanupService(f.schools, f.users, f.purged, uow, f.config)
+
+	if purged, err := service.PurgeAbandoned(f.now); err != nil || purged != 0 {
+		t.Fatalf("PurgeAbandoned = %d, %v; want the resumed registration kept", purged, err)
+	}
+	if _, ok := f.schools.schools[school.ID]; !ok {
+		t.Error("the resumed registration was deleted")
+	}
+}
+
+// resumingSchoolRepository has the applicant take a step between the listing and the locked read.
+type resumingSchoolRepository struct {
+	*fakeSchoolRepository
+	now time.Time
+}
+
+func (r *resumingSchoolRepository) FindByIDForUpdate(id uuid.UUID) (*models.School, error) {
+	if school, ok := r.schools[id]; ok {
+		school.UpdatedAt = r.now
+	}
+	return r.fakeSchoolRepository.FindByIDForUpdate(id)
+}
@@ -1,8 +1,10 @@
 package services
 
 import (
+	"crypto/subtle"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"auth-barniee/internal/config"
@@ -15,47 +17,75 @@ import (
 )
 
 type RegistrationService interface {
-	RegisterSchoolInfo(schoolName, educationLevel, status, address string, initialStudentCount int) (*models.School, error)
-	RegisterAdminInfo(schoolID uuid.UUID, adminName, adminEmail, whatsappNumber, position string) (*models.User, string, error)
+	// RegisterSchoolInfo creates the school and returns the registration-session token required by the later steps.
+	RegisterSchoolInfo(schoolName, educationLevel, status, address string, initialStudentCount int) (*models.School, string, error)
+	// ResolveSession validates a registration-session token, records the activity and returns the school it is bound to.
+	ResolveSession(token string) (uuid.UUID, error)
+	// RegisterAdminInfo creates the school admin without a usable password; the admin sets one
+	// through the link emailed by CompleteRegistration.
+	RegisterAdminInfo(schoolID uuid.UUID, adminName, adminEmail, whatsappNumber, position string) (*models.User, error)
 	SelectPackage(schoolID, packageID uuid.UUID) (*models.School, error)
-	RequestEmailVerificationOTP(userID uuid.UUID) error
-	VerifyEmailOTP(userID uuid.UUID, otp string) error
+	RequestEmailVerificationOTP(schoolID uuid.UUID) error
+	VerifyEmailOTP(schoolID uuid.UUID, otp string) error
+	// CompleteRegistration finishes the registration and emails the admin a single-use set-password link.
 	CompleteRegistration(schoolID uuid.UUID) (*models.School, error)
+	// RequestResumeOTP emails a code that lets the admin of an unfinished registration resume it.
+	RequestResumeOTP(adminEmail string) error
+	// ResumeRegistration checks that code and starts a new registration session for the school,
+	// ending any earlier one.
+	ResumeRegistration(adminEmail, otp string) (*models.School, string, error)
 	GetSchoolByID(schoolID uuid.UUID) (*models.School, error)
 	GetPackageByID(packageID uuid.UUID) (*models.Package, error)
 }
 
 type registrationService struct {
-	schoolRepo      repositories.SchoolRepository
-	userRepo        repositories.UserRepository
-	roleRepo        repositories.RoleRepository
-	packageRepo     repositories.PackageRepository
-	emailVerifyRepo repositories.EmailVerificationRepository
-	config          *config.Config
+	schoolRepo  repositories.SchoolRepository
+	packageRepo repositories.PackageRepository
+	sessionRepo repositories.RegistrationSessionRepository
+	uow         repositories.UnitOfWork
+	config      *config.Config
 }
 
 func NewRegistrationService(
 	schoolRepo repositories.SchoolRepository,
-	userRepo repositories.UserRepository,
-	roleRepo repositories.RoleRepository,
 	packageRepo repositories.PackageRepository,
-	emailVerifyRepo repositories.EmailVerificationRepository,
+	sessionRepo repositories.RegistrationSessionRepository,
+	uow repositories.UnitOfWork,
 	cfg *config.Config,
 ) RegistrationService {
 	return &registrationService{
-		schoolRepo:      schoolRepo,
-		userRepo:        userRepo,
-		roleRepo:        roleRepo,
-		packageRepo:     packageRepo,
-		emailVerifyRepo: emailVerifyRepo,
-		config:          cfg,
+		schoolRepo:  schoolRepo,
+		packageRepo: packageRepo,
+		sessionRepo: sessionRepo,
+		uow:         uow,
+		config:      cfg,
 	}
 }
 
-func (s *registrationService) RegisterSchoolInfo(schoolName, educationLevel, status, address string, initialStudentCount int) (*models.School, error) {
+// registrationTransitions lists the states each registration state may move to. Selecting a
+// package again before verifying the email replaces the earlier choice.
+var registrationTransitions = map[string][]string{
+	models.RegistrationStatusDraft:           {models.RegistrationStatusAdminAdded},
+	models.RegistrationStatusAdminAdded:      {models.RegistrationStatusPackageSelected},
+	models.RegistrationStatusPackageSelected: {models.RegistrationStatusPackageSelected, models.RegistrationStatusEmailVerified},
+	models.RegistrationStatusEmailVerified:   {models.RegistrationStatusCompleted},
+}
+
+// checkRegistrationTransition reports whether the school's registration may move to the given state.
+func checkRegistrationTransition(school *models.School, to string) error {
+	if school.RegistrationStatus == models.RegistrationStatusCompleted {
+		return errors.New("registration is already completed")
+	}
+	if !slices.Contains(registrationTransitions[school.RegistrationStatus], to) {
+		return errors.New("registration step is out of order")
+	}
+	return nil
+}
+
+func (s *registrationService) RegisterSchoolInfo(schoolName, educationLevel, status, address string, initialStudentCount int) (*models.School, string, error) {
 	freeTrialPkg, err := s.packageRepo.FindByName("Free Trial")
 	if err != nil {
-		return nil, fmt.Errorf("Free Trial package not found in system: %w", err)
+		return nil, "", fmt.Errorf("Free Trial package not found in system: %w", err)
 	}
 
 	school := &models.School{
@@ -66,217 +96,474 @@ func (s *registrationService) RegisterSchoolInfo(schoolName, educationLevel, sta
 		InitialStudentCount: initialStudentCount,
 		PackageID:           freeTrialPkg.ID,
 		MaxStudentsAllowed:  *freeTrialPkg.MaxStudents,
+		RegistrationStatus:  models.RegistrationStatusDraft,
 		CreatedBy:           uuid.Nil,
 	}
 
-	err = s.schoolRepo.Create(school)
+	var token string
+	err = s.uow.Do(func(repos repositories.Repositories) error {
+		if err := repos.Schools.Create(school); err != nil {
+			return fmt.Errorf("failed to register school info: %w", err)
+		}
+		token, err = s.startSession(repos.RegistrationSessions, school.ID)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to register school info: %w", err)
+		return nil, "", err
 	}
-	return school, nil
+	return school, token, nil
 }
 
-func (s *registrationService) RegisterAdminInfo(schoolID uuid.UUID, adminName, adminEmail, whatsappNumber, position string) (*models.User, string, error) {
-	school, err := s.schoolRepo.FindByID(schoolID)
+func (s *registrationService) startSession(sessionRepo repositories.RegistrationSessionRepository, schoolID uuid.UUID) (string, error) {
+	session := &models.RegistrationSession{
+		SchoolID:       schoolID,
+		LastActivityAt: time.Now(),
+	}
+	if err := sessionRepo.Create(session); err != nil {
+		return "", fmt.Errorf("failed to start registration session: %w", err)
+	}
+	token, err := utils.GenerateRegistrationToken(session.ID, schoolID, s.config)
+	if err != nil {
+		return "", fmt.Errorf("failed to issue registration token: %w", err)
+	}
+	return token, nil
+}
+
+// lockSchool loads the school being registered and holds its row until the unit of work ends,
+// so concurrent steps of the same registration cannot both pass the status check.
+func lockSchool(repos repositories.Repositories, schoolID uuid.UUID) (*models.School, error) {
+	school, err := repos.Schools.FindByIDForUpdate(schoolID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, "", errors.New("school not found")
+			return nil, errors.New("school not found")
 		}
-		return nil, "", fmt.Errorf("failed to find school: %w", err)
+		return nil, fmt.Errorf("failed to find school: %w", err)
 	}
+	return school, nil
+}
 
-	existingUser, err := s.userRepo.FindByEmail(adminEmail)
-	if err == nil && existingUser != nil {
-		return nil, "", errors.New("admin user with this email already exists")
-	}
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, "", fmt.Errorf("failed to check existing user: %w", err)
+func (s *registrationService) ResolveSession(token string) (uuid.UUID, error) {
+	claims, err := utils.ParseRegistrationToken(token, s.config)
+	if err != nil {
+		return uuid.Nil, errors.New("invalid registration session")
 	}
 
-	adminRole, err := s.roleRepo.FindByName("admin")
+	session, err := s.sessionRepo.FindByID(claims.SessionID)
 	if err != nil {
-		return nil, "", fmt.Errorf("admin role not found: %w", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return uuid.Nil, errors.New("invalid registration session")
+		}
+		return uuid.Nil, fmt.Errorf("failed to find registration session: %w", err)
+	}
+	if session.SchoolID != claims.SchoolID {
+		return uuid.Nil, errors.New("invalid registration session")
+	}
+	if session.EndedAt != nil {
+		return uuid.Nil, errors.New("registration session has ended")
 	}
 
-	generatedPassword := utils.GenerateRandomPassword(12)
-	hashedPassword, err := utils.HashPassword(generatedPassword)
+	// The idle check and the activity update are one statement, so two concurrent steps
+	// cannot revive a session that has already expired.
+	now := time.Now()
+	idleSince := now.Add(-time.Duration(s.config.RegistrationSessionIdleMinutes) * time.Minute)
+	active, err := s.sessionRepo.Touch(session.ID, idleSince, now)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to hash generated password: %w", err)
+		return uuid.Nil, fmt.Errorf("failed to update registration session: %w", err)
 	}
-
-	adminUser := &models.User{
-		Name:           adminName,
-		Email:          adminEmail,
-		Password:       hashedPassword,
-		WhatsappNumber: whatsappNumber,
-		Position:       position,
-		RoleID:         adminRole.ID,
-		SchoolID:       schoolID,
-		CreatedBy:      uuid.Nil,
+	if !active {
+		return uuid.Nil, errors.New("registration session expired")
 	}
+	return session.SchoolID, nil
+}
 
-	err = s.userRepo.Create(adminUser)
+func (s *registrationService) RegisterAdminInfo(schoolID uuid.UUID, adminName, adminEmail, whatsappNumber, position string) (*models.User, error) {
+	// The admin chooses a password through the link sent on completion; until then nobody knows this one.
+	hashedPassword, err := utils.HashPassword(utils.GenerateRandomPassword(32))
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to create admin user: %w", err)
+		return nil, fmt.Errorf("failed to hash placeholder password: %w", err)
 	}
 
-	school.AdminUserID = adminUser.ID
-	school.UpdatedBy = uuid.Nil
-	err = s.schoolRepo.Update(school)
+	var adminUser *models.User
+	err = s.uow.Do(func(repos repositories.Repositories) error {
+		school, err := lockSchool(repos, schoolID)
+		if err != nil {
+			return err
+		}
+		if err := checkRegistrationTransition(school, models.RegistrationStatusAdminAdded); err != nil {
+			return err
+		}
+
+		existingUser, err := repos.Users.FindByEmailIncludingDeleted(adminEmail)
+		if err == nil && existingUser != nil {
+			return errors.New("admin user with this email already exists")
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to check existing user: %w", err)
+		}
+
+		adminRole, err := repos.Roles.FindByName("admin")
+		if err != nil {
+			return fmt.Errorf("admin role not found: %w", err)
+		}
+
+		adminUser = &models.User{
+			Name:           adminName,
+			Email:          adminEmail,
+			Password:       hashedPassword,
+			WhatsappNumber: whatsappNumber,
+			Position:       position,
+			RoleID:         adminRole.ID,
+			SchoolID:       schoolID,
+			CreatedBy:      uuid.Nil,
+		}
+		if err := repos.Users.Create(adminUser); err != nil {
+			return fmt.Errorf("failed to create admin user: %w", err)
+		}
+
+		school.AdminUserID = adminUser.ID
+		school.RegistrationStatus = models.RegistrationStatusAdminAdded
+		school.UpdatedBy = uuid.Nil
+		if err := repos.Schools.Update(school); err != nil {
+			return fmt.Errorf("failed to update school with admin user ID: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to update school with admin user ID: %w", err)
+		return nil, err
 	}
 
-	return adminUser, generatedPassword, nil
+	return adminUser, nil
 }
 
 func (s *registrationService) SelectPackage(schoolID, packageID uuid.UUID) (*models.School, error) {
-	school, err := s.schoolRepo.FindByID(schoolID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("school not found")
+	var school *models.School
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		var err error
+		school, err = lockSchool(repos, schoolID)
+		if err != nil {
+			return err
 		}
-		return nil, fmt.Errorf("failed to find school: %w", err)
-	}
-
-	pkg, err := s.packageRepo.FindByID(packageID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("package not found")
+		if err := checkRegistrationTransition(school, models.RegistrationStatusPackageSelected); err != nil {
+			return err
 		}
-		return nil, fmt.Errorf("failed to find package: %w", err)
-	}
 
-	school.PackageID = pkg.ID
-	if pkg.MaxStudents != nil {
-		school.MaxStudentsAllowed = *pkg.MaxStudents
-	} else {
-		school.MaxStudentsAllowed = 0
-	}
+		pkg, err := repos.Packages.FindByID(packageID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("package not found")
+			}
+			return fmt.Errorf("failed to find package: %w", err)
+		}
+		if pkg.RetiredAt != nil {
+			return errors.New("package is no longer available")
+		}
+		// A paid period belongs to the package it was bought for
+		if paidPeriodRunning(school, time.Now()) {
+			return errors.New("package cannot be changed after payment")
+		}
 
-	if pkg.Name == "Free Trial" && pkg.DurationDays != nil {
-		now := time.Now()
-		school.SubscriptionStartDate = &now
-		expiry := now.Add(time.Duration(*pkg.DurationDays) * 24 * time.Hour)
-		school.SubscriptionEndDate = &expiry
-	} else {
-		school.SubscriptionStartDate = nil
-		school.SubscriptionEndDate = nil
-	}
+		switchSchoolPackage(school, pkg)
+		if pkg.IsTrial && pkg.DurationDays != nil {
+			now := time.Now()
+			school.SubscriptionStartDate = &now
+			expiry := now.Add(time.Duration(*pkg.DurationDays) * 24 * time.Hour)
+			school.SubscriptionEndDate = &expiry
+			school.SubscriptionStatus = models.SubscriptionStatusTrialing
+		} else {
+			school.SubscriptionStartDate = nil
+			school.SubscriptionEndDate = nil
+			school.SubscriptionStatus = models.SubscriptionStatusUnpaid
+		}
 
-	school.UpdatedBy = uuid.Nil
-	err = s.schoolRepo.Update(school)
+		school.RegistrationStatus = models.RegistrationStatusPackageSelected
+		school.UpdatedBy = uuid.Nil
+		if err := repos.Schools.Update(school); err != nil {
+			return fmt.Errorf("failed to select package for school: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to select package for school: %w", err)
+		return nil, err
 	}
 	return school, nil
 }
 
-func (s *registrationService) RequestEmailVerificationOTP(userID uuid.UUID) error {
-	user, err := s.userRepo.FindByID(userID)
+// findRegisteringAdmin locks the school and returns it with the admin registered for it in step 2.
+func findRegisteringAdmin(repos repositories.Repositories, schoolID uuid.UUID) (*models.School, *models.User, error) {
+	school, err := lockSchool(repos, schoolID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if school.AdminUserID == uuid.Nil {
+		return nil, nil, errors.New("admin info has not been registered yet")
+	}
+
+	user, err := repos.Users.FindByID(school.AdminUserID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("user not found for verification")
+			return nil, nil, errors.New("user not found for verification")
 		}
-		return fmt.Errorf("failed to find user for OTP request: %w", err)
+		return nil, nil, fmt.Errorf("failed to find user for OTP request: %w", err)
 	}
+	return school, user, nil
+}
 
-	existingVerification, err := s.emailVerifyRepo.FindByUserID(userID)
+// issueOTP saves a new code for the purpose, invalidating the user's earlier unused one.
+func (s *registrationService) issueOTP(emailVerifications repositories.EmailVerificationRepository, user *models.User, purpose string) (string, error) {
+	existingVerification, err := emailVerifications.FindByUserID(user.ID, purpose)
 	if err == nil && existingVerification != nil && !existingVerification.IsVerified {
-		existingVerification.IsVerified = false // Mark as invalidated if not yet verified
-		s.emailVerifyRepo.Update(existingVerification)
+		existingVerification.ExpiresAt = time.Now() // Only the newest code stays usable
+		if err := emailVerifications.Update(existingVerification); err != nil {
+			return "", fmt.Errorf("failed to invalidate previous OTP: %w", err)
+		}
 	}
 
 	otp := utils.GenerateOTP()
-	expiresAt := time.Now().Add(time.Duration(s.config.OTPExpiryMinutes) * time.Minute)
-
 	verification := &models.EmailVerification{
-		UserID:    userID,
+		UserID:    user.ID,
 		Email:     user.Email,
 		OTP:       otp,
-		ExpiresAt: expiresAt,
+		Purpose:   purpose,
+		ExpiresAt: time.Now().Add(time.Duration(s.config.OTPExpiryMinutes) * time.Minute),
 	}
-
-	err = s.emailVerifyRepo.Create(verification)
-	if err != nil {
-		return fmt.Errorf("failed to save OTP: %w", err)
+	if err := emailVerifications.Create(verification); err != nil {
+		return "", fmt.Errorf("failed to save OTP: %w", err)
 	}
+	return otp, nil
+}
 
-	subject := "Barniee: Kode Verifikasi Email Anda"
+func (s *registrationService) sendOTP(user *models.User, otp, subject string) error {
 	body := fmt.Sprintf("Halo %s,\n\nKode verifikasi Anda adalah: %s\nKode ini akan kedaluwarsa dalam %d menit.\n\nTerima kasih,\nTim Barniee", user.Name, otp, s.config.OTPExpiryMinutes)
 
-	err = utils.SendEmail(s.config, user.Email, subject, body)
-	if err != nil {
+	if err := utils.SendEmail(s.config, user.Email, subject, body); err != nil {
 		return fmt.Errorf("failed to send OTP email: %w", err)
 	}
 	return nil
 }
 
-func (s *registrationService) VerifyEmailOTP(userID uuid.UUID, otp string) error {
-	verification, err := s.emailVerifyRepo.FindByUserIDAndOTP(userID, otp)
+// checkOTP marks the user's latest code for the purpose as used. Every wrong guess is counted on the
+// code, and after maxAttempts of them the code is invalidated and a new one must be requested. Callers
+// commit their transaction when the check fails, so the count is kept.
+func checkOTP(emailVerifications repositories.EmailVerificationRepository, userID uuid.UUID, otp, purpose string, maxAttempts int) error {
+	// Only the latest code is usable, see issueOTP
+	verification, err := emailVerifications.FindByUserID(userID, purpose)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("invalid OTP or user ID")
+			return errors.New("invalid OTP")
 		}
 		return fmt.Errorf("failed to find OTP: %w", err)
 	}
 
 	if verification.IsVerified {
-		return errors.New("email already verified")
+		return errors.New("OTP has already been used")
+	}
+	if verification.Attempts >= maxAttempts {
+		return errors.New("too many invalid OTP attempts, request a new OTP")
 	}
-
 	if time.Now().After(verification.ExpiresAt) {
 		return errors.New("OTP has expired")
 	}
 
+	if subtle.ConstantTimeCompare([]byte(otp), []byte(verification.OTP)) != 1 {
+		verification.Attempts++
+		if err := emailVerifications.Update(verification); err != nil {
+			return fmt.Errorf("failed to record OTP attempt: %w", err)
+		}
+		if verification.Attempts >= maxAttempts {
+			return errors.New("too many invalid OTP attempts, request a new OTP")
+		}
+		return errors.New("invalid OTP")
+	}
+
 	verification.IsVerified = true
-	err = s.emailVerifyRepo.Update(verification)
-	if err != nil {
+	if err := emailVerifications.Update(verification); err != nil {
 		return fmt.Errorf("failed to mark OTP as verified: %w", err)
 	}
-
 	return nil
 }
 
-func (s *registrationService) CompleteRegistration(schoolID uuid.UUID) (*models.School, error) {
-	school, err := s.schoolRepo.FindByID(schoolID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("school not found for completion")
+func (s *registrationService) RequestEmailVerificationOTP(schoolID uuid.UUID) error {
+	var user *models.User
+	var otp string
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		school, admin, err := findRegisteringAdmin(repos, schoolID)
+		if err != nil {
+			return err
 		}
-		return nil, fmt.Errorf("failed to find school: %w", err)
+		if err := checkRegistrationTransition(school, models.RegistrationStatusEmailVerified); err != nil {
+			return err
+		}
+		user = admin
+		otp, err = s.issueOTP(repos.EmailVerifications, user, models.EmailVerificationPurposeVerify)
+		return err
+	})
+	if err != nil {
+		return err
 	}
 
-	if school.AdminUserID != uuid.Nil {
-		adminUser, err := s.userRepo.FindByID(school.AdminUserID)
+	return s.sendOTP(user, otp, "Barniee: Kode Verifikasi Email Anda")
+}
+
+func (s *registrationService) VerifyEmailOTP(schoolID uuid.UUID, otp string) error {
+	var otpErr error
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		school, user, err := findRegisteringAdmin(repos, schoolID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to find admin user for school: %w", err)
+			return err
+		}
+		if err := checkRegistrationTransition(school, models.RegistrationStatusEmailVerified); err != nil {
+			return err
 		}
 
-		latestVerification, err := s.emailVerifyRepo.FindByUserID(adminUser.ID)
-		if err != nil || latestVerification == nil || !latestVerification.IsVerified {
-			return nil, errors.New("admin email not verified yet")
+		if otpErr = checkOTP(repos.EmailVerifications, user.ID, otp, models.EmailVerificationPurposeVerify, s.config.OTPMaxAttempts); otpErr != nil {
+			return nil // Commit the attempt count
 		}
-	} else {
-		return nil, errors.New("admin user not associated with school")
+
+		school.RegistrationStatus = models.RegistrationStatusEmailVerified
+		school.UpdatedBy = uuid.Nil
+		if err := repos.Schools.Update(school); err != nil {
+			return fmt.Errorf("failed to update registration status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
+	return otpErr
+}
+
+func (s *registrationService) CompleteRegistration(schoolID uuid.UUID) (*models.School, error) {
+	var school *models.School
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		var err error
+		school, err = repos.Schools.FindByIDForUpdate(schoolID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("school not found for completion")
+			}
+			return fmt.Errorf("failed to find school: %w", err)
+		}
+		if err := checkRegistrationTransition(school, models.RegistrationStatusCompleted); err != nil {
+			return err
+		}
 
-	// If Free Trial, set subscription start/end dates if not already set by SelectPackage
-	if school.Package.Name == "Free Trial" && school.SubscriptionStartDate == nil {
-		now := time.Now()
-		school.SubscriptionStartDate = &now
-		expiry := now.Add(time.Duration(*school.Package.DurationDays) * 24 * time.Hour)
-		school.SubscriptionEndDate = &expiry
+		// Paid packages start when the checkout is paid; the payment webhook sets the period
+		if !school.Package.IsTrial && !paidPeriodRunning(school, time.Now()) {
+			return errors.New("payment is required to complete registration")
+		}
+
+		// If Free Trial, set subscription start/end dates if not already set by SelectPackage
+		if school.Package.IsTrial && school.SubscriptionStartDate == nil && school.Package.DurationDays != nil {
+			now := time.Now()
+			school.SubscriptionStartDate = &now
+			expiry := now.Add(time.Duration(*school.Package.DurationDays) * 24 * time.Hour)
+			school.SubscriptionEndDate = &expiry
+		}
+
+		school.RegistrationStatus = models.RegistrationStatusCompleted
 		school.UpdatedBy = uuid.Nil
-		err = s.schoolRepo.Update(school)
+		if err := repos.Schools.Update(school); err != nil {
+			return fmt.Errorf("failed to complete registration: %w", err)
+		}
+
+		if err := repos.RegistrationSessions.EndForSchool(school.ID, time.Now()); err != nil {
+			return fmt.Errorf("failed to end registration session: %w", err)
+		}
+
+		admin, err := repos.Users.FindByID(school.AdminUserID)
+		if err != nil {
+			return fmt.Errorf("failed to find school admin: %w", err)
+		}
+		token, err := issuePasswordSetupToken(repos.PasswordSetupTokens, admin, s.config)
 		if err != nil {
-			return nil, fmt.Errorf("failed to finalize free trial subscription dates: %w", err)
+			return err
 		}
+		// Sent before commit: without the email the admin could never log in, so a failed send
+		// leaves the registration open for another attempt.
+		return sendPasswordSetupEmail(s.config, admin, school.Name, token)
+	})
+	if err != nil {
+		return nil, err
 	}
-
 	return school, nil
 }
 
+// findResumableRegistration returns the unfinished registration whose admin has the given email.
+func findResumableRegistration(repos repositories.Repositories, adminEmail string) (*models.School, *models.User, error) {
+	user, err := repos.Users.FindByEmail(adminEmail)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("no unfinished registration for this email")
+		}
+		return nil, nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user.SchoolID == uuid.Nil {
+		return nil, nil, errors.New("no unfinished registration for this email")
+	}
+
+	school, err := lockSchool(repos, user.SchoolID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if school.AdminUserID != user.ID {
+		return nil, nil, errors.New("no unfinished registration for this email")
+	}
+	if school.RegistrationStatus == models.RegistrationStatusCompleted {
+		return nil, nil, errors.New("registration is already completed")
+	}
+	return school, user, nil
+}
+
+func (s *registrationService) RequestResumeOTP(adminEmail string) error {
+	var user *models.User
+	var otp string
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		_, admin, err := findResumableRegistration(repos, adminEmail)
+		if err != nil {
+			return err
+		}
+		user = admin
+		otp, err = s.issueOTP(repos.EmailVerifications, user, models.EmailVerificationPurposeResume)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.sendOTP(user, otp, "Barniee: Kode untuk Melanjutkan Registrasi")
+}
+
+func (s *registrationService) ResumeRegistration(adminEmail, otp string) (*models.School, string, error) {
+	var school *models.School
+	var token string
+	var otpErr error
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		var user *models.User
+		var err error
+		school, user, err = findResumableRegistration(repos, adminEmail)
+		if err != nil {
+			return err
+		}
+
+		if otpErr = checkOTP(repos.EmailVerifications, user.ID, otp, models.EmailVerificationPurposeResume, s.config.OTPMaxAttempts); otpErr != nil {
+			return nil // Commit the attempt count
+		}
+
+		if err := repos.RegistrationSessions.EndForSchool(school.ID, time.Now()); err != nil {
+			return fmt.Errorf("failed to end registration session: %w", err)
+		}
+		token, err = s.startSession(repos.RegistrationSessions, school.ID)
+		return err
+	})
+	if err == nil {
+		err = otpErr
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return school, token, nil
+}
+
 func (s *registrationService) GetSchoolByID(schoolID uuid.UUID) (*models.School, error) {
 	school, err := s.schoolRepo.FindByID(schoolID)
 	if err != nil {
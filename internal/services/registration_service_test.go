@@ -0,0 +1,97 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+	"auth-barniee/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+func TestResumeOTPIsInvalidatedAfterTooManyWrongGuesses(t *testing.T) {
+	school := &models.School{ID: uuid.New(), Name: "SMA Barniee", RegistrationStatus: models.RegistrationStatusPackageSelected}
+	admin := &models.User{ID: uuid.New(), Email: "tu@sekolah.sch.id", SchoolID: school.ID}
+	school.AdminUserID = admin.ID
+	verifications := &fakeEmailVerificationRepository{verifications: []*models.EmailVerification{{
+		ID: uuid.New(), UserID: admin.ID, Email: admin.Email, OTP: "483920",
+		Purpose: models.EmailVerificationPurposeResume, ExpiresAt: time.Now().Add(10 * time.Minute),
+	}}}
+	uow := &fakeUnitOfWork{repos: repositories.Repositories{
+		Users: newFakeUserRepository(admin), Schools: newFakeSchoolRepository(school), EmailVerifications: verifications,
+	}}
+	service := NewRegistrationService(nil, nil, nil, uow, &config.Config{OTPMaxAttempts: 3})
+
+	for i := range 3 {
+		_, _, err := service.ResumeRegistration(admin.Email, fmt.Sprintf("00000%d", i))
+		if err == nil {
+			t.Fatalf("guess %d was accepted", i)
+		}
+	}
+	if attempts := verifications.verifications[0].Attempts; attempts != 3 {
+		t.Errorf("attempts = %d, want every wrong guess counted", attempts)
+	}
+
+	_, _, err := service.ResumeRegistration(admin.Email, "483920")
+	if err == nil || err.Error() != "too many invalid OTP attempts, request a new OTP" {
+		t.Errorf("correct code after the lockout error = %v, want the code invalidated", err)
+	}
+	if verifications.verifications[0].IsVerified {
+		t.Error("the locked code was marked as used")
+	}
+}
+
+func TestResolveSessionChecksTheRegistrationSession(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "rahasia", RegistrationSessionIdleMinutes: 30}
+	schoolID := uuid.New()
+	now := time.Now()
+	ended := now.Add(-time.Minute)
+	active := &models.RegistrationSession{ID: uuid.New(), SchoolID: schoolID, LastActivityAt: now.Add(-29 * time.Minute)}
+	idle := &models.RegistrationSession{ID: uuid.New(), SchoolID: schoolID, LastActivityAt: now.Add(-31 * time.Minute)}
+	completed := &models.RegistrationSession{ID: uuid.New(), SchoolID: schoolID, LastActivityAt: now, EndedAt: &ended}
+	sessions := &fakeRegistrationSessionRepository{sessions: []*models.RegistrationSession{active, idle, completed}}
+	service := NewRegistrationService(nil, nil, sessions, nil, cfg)
+
+	token := func(session *models.RegistrationSession, schoolID uuid.UUID) string {
+		t.Helper()
+		token, err := utils.GenerateRegistrationToken(session.ID, schoolID, cfg)
+		if err != nil {
+			t.Fatalf("GenerateRegistrationToken returned error: %v", err)
+		}
+		return token
+	}
+	loginToken, err := utils.GenerateToken(&models.User{ID: uuid.New(), Email: "tu@sekolah.sch.id", SchoolID: schoolID}, utils.ExtraClaims{}, cfg)
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		token string
+		want  string
+	}{
+		"login token":       {loginToken, "invalid registration session"},
+		"other school":      {token(active, uuid.New()), "invalid registration session"},
+		"unknown session":   {token(&models.RegistrationSession{ID: uuid.New()}, schoolID), "invalid registration session"},
+		"idle session":      {token(idle, schoolID), "registration session expired"},
+		"completed session": {token(completed, schoolID), "registration session has ended"},
+	} {
+		if _, err := service.ResolveSession(tc.token); err == nil || err.Error() != tc.want {
+			t.Errorf("%s: error = %v, want %q", name, err, tc.want)
+		}
+	}
+
+	resolved, err := service.ResolveSession(token(active, schoolID))
+	if err != nil {
+		t.Fatalf("active session: ResolveSession returned error: %v", err)
+	}
+	if resolved != schoolID {
+		t.Errorf("resolved school = %s, want %s", resolved, schoolID)
+	}
+	if active.LastActivityAt.Before(now) {
+		t.Error("resolving the session did not record the activity")
+	}
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RoleService interface {
+	GetRoles(adminID uuid.UUID) ([]models.Role, error) // System roles plus the admin's school roles
+	GetPermissions() ([]models.Permission, error)
+	CreateCustomRole(adminID uuid.UUID, name, description, baseRoleName string, permissionNames []string) (*models.Role, error)
+	UpdateCustomRole(adminID, roleID uuid.UUID, name, description *string, permissionNames []string) (*models.Role, error)
+	DeleteCustomRole(adminID, roleID uuid.UUID) error
+	AssignRole(adminID, userID, roleID uuid.UUID) (*models.User, error)
+}
+
+type roleService struct {
+	roleRepo       repositories.RoleRepository
+	permissionRepo repositories.PermissionRepository
+	userRepo       repositories.UserRepository
+	schoolRepo     repositories.SchoolRepository
+}
+
+func NewRoleService(roleRepo repositories.RoleRepository, permissionRepo repositories.PermissionRepository, userRepo repositories.UserRepository, schoolRepo repositories.SchoolRepository) RoleService {
+	return &roleService{
+		roleRepo:       roleRepo,
+		permissionRepo: permissionRepo,
+		schoolRepo:     schoolRepo,
+		userRepo:       userRepo,
+	}
+}
+
+func (s *roleService) findSchoolAdmin(adminID uuid.UUID) (*models.User, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	if adminUser.SchoolID == uuid.Nil {
+		return nil, errors.New("custom roles can only be managed by school admins")
+	}
+	return adminUser, nil
+}
+
+// findSchoolRole only returns roles owned by the school; other schools' roles are reported as missing.
+func (s *roleService) findSchoolRole(schoolID, roleID uuid.UUID) (*models.Role, error) {
+	role, err := s.roleRepo.FindByID(roleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("role not found")
+		}
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+	if role.SchoolID == nil || *role.SchoolID != schoolID {
+		return nil, errors.New("role not found")
+	}
+	return role, nil
+}
+
+// resolveCustomRolePermissions keeps a custom role within both its base role and the acting admin.
+func (s *roleService) resolveCustomRolePermissions(adminRoleID, baseRoleID uuid.UUID, permissionNames []string) ([]models.Permission, error) {
+	basePermissions, err := s.roleRepo.FindPermissionNames(baseRoleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve base role permissions: %w", err)
+	}
+	adminPermissions, err := s.roleRepo.FindPermissionNames(adminRoleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+	for _, name := range permissionNames {
+		if !slices.Contains(basePermissions, name) {
+			return nil, fmt.Errorf("permission '%s' is not granted by the base role", name)
+		}
+		if !slices.Contains(adminPermissions, name) {
+			return nil, fmt.Errorf("unauthorized: cannot grant permission '%s' you do not have", name)
+		}
+	}
+
+	if len(permissionNames) == 0 {
+		return []models.Permission{}, nil
+	}
+	permissions, err := s.permissionRepo.FindByNames(permissionNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find permissions: %w", err)
+	}
+	return permissions, nil
+}
+
+func (s *roleService) GetRoles(adminID uuid.UUID) ([]models.Role, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+
+	roles, err := s.roleRepo.FindGlobal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve roles: %w", err)
+	}
+	if adminUser.SchoolID != uuid.Nil {
+		schoolRoles, err := s.roleRepo.FindBySchoolID(adminUser.SchoolID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve school roles: %w", err)
+		}
+		roles = append(roles, schoolRoles...)
+	}
+	return roles, nil
+}
+
+func (s *roleService) GetPermissions() ([]models.Permission, error) {
+	permissions, err := s.permissionRepo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve permissions: %w", err)
+	}
+	return permissions, nil
+}
+
+func (s *roleService) CreateCustomRole(adminID uuid.UUID, name, description, baseRoleName string, permissionNames []string) (*models.Role, error) {
+	adminUser, err := s.findSchoolAdmin(adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.roleRepo.FindByName(name); err == nil {
+		return nil, fmt.Errorf("role name '%s' is reserved for a system role", name)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check role name: %w", err)
+	}
+
+	baseRole, err := s.roleRepo.FindByName(baseRoleName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("base role '%s' not found", baseRoleName)
+		}
+		return nil, fmt.Errorf("failed to find base role: %w", err)
+	}
+	if baseRole.Name == superAdminRoleName {
+		return nil, errors.New("custom roles cannot be based on the super admin role")
+	}
+
+	permissions, err := s.resolveCustomRolePermissions(adminUser.RoleID, baseRole.ID, permissionNames)
+	if err != nil {
+		return nil, err
+	}
+
+	schoolID := adminUser.SchoolID
+	role := &models.Role{
+		Name:        name,
+		Description: description,
+		SchoolID:    &schoolID,
+		BaseRoleID:  &baseRole.ID,
+		CreatedBy:   adminID,
+	}
+	if err := s.roleRepo.Create(role); err != nil {
+		return nil, fmt.Errorf("failed to create role: %w", err)
+	}
+	if err := s.roleRepo.ReplacePermissions(role, permissions); err != nil {
+		return nil, fmt.Errorf("failed to set role permissions: %w", err)
+	}
+	return s.roleRepo.FindByID(role.ID)
+}
+
+func (s *roleService) UpdateCustomRole(adminID, roleID uuid.UUID, name, description *string, permissionNames []string) (*models.Role, error) {
+	adminUser, err := s.findSchoolAdmin(adminID)
+	if err != nil {
+		return nil, err
+	}
+	role, err := s.findSchoolRole(adminUser.SchoolID, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != nil && *name != role.Name {
+		if _, err := s.roleRepo.FindByName(*name); err == nil {
+			return nil, fmt.Errorf("role name '%s' is reserved for a system role", *name)
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to check role name: %w", err)
+		}
+		role.Name = *name
+	}
+	if description != nil {
+		role.Description = *description
+	}
+	role.UpdatedBy = adminID
+	if err := s.roleRepo.Update(role); err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+
+	if permissionNames != nil {
+		permissions, err := s.resolveCustomRolePermissions(adminUser.RoleID, *role.BaseRoleID, permissionNames)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.roleRepo.ReplacePermissions(role, permissions); err != nil {
+			return nil, fmt.Errorf("failed to set role permissions: %w", err)
+		}
+	}
+	return s.roleRepo.FindByID(role.ID)
+}
+
+func (s *roleService) DeleteCustomRole(adminID, roleID uuid.UUID) error {
+	adminUser, err := s.findSchoolAdmin(adminID)
+	if err != nil {
+		return err
+	}
+	role, err := s.findSchoolRole(adminUser.SchoolID, roleID)
+	if err != nil {
+		return err
+	}
+
+	userCount, err := s.roleRepo.CountUsers(role.ID)
+	if err != nil {
+		return fmt.Errorf("failed to count role users: %w", err)
+	}
+	if userCount > 0 {
+		return fmt.Errorf("role is still assigned to %d user(s)", userCount)
+	}
+	return s.roleRepo.Delete(role.ID)
+}
+
+func (s *roleService) AssignRole(adminID, userID, roleID uuid.UUID) (*models.User, error) {
+	adminUser, err := s.findSchoolAdmin(adminID)
+	if err != nil {
+		return nil, err
+	}
+	if userID == adminID {
+		return nil, errors.New("cannot change your own role")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user.SchoolID != adminUser.SchoolID {
+		return nil, errors.New("user not found")
+	}
+	if err := ensureCanChangeUser(s.roleRepo, s.schoolRepo, adminUser, user); err != nil {
+		return nil, err
+	}
+
+	role, err := s.roleRepo.FindByID(roleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("role not found")
+		}
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+	if role.SchoolID != nil && *role.SchoolID != adminUser.SchoolID {
+		return nil, errors.New("role not found")
+	}
+	if err := ensureCanAssignRole(s.roleRepo, adminUser.RoleID, role.ID); err != nil {
+		return nil, err
+	}
+
+	wasStudent := user.Role.SystemName() == "student"
+	user.RoleID = role.ID
+	user.Role = *role // Save writes the preloaded association back, so keep it in sync with RoleID
+	user.UpdatedBy = adminID
+	if err := saveUserRole(s.userRepo, user, wasStudent, role); err != nil {
+		var quotaErr *repositories.StudentQuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return nil, quotaErr
+		}
+		return nil, fmt.Errorf("failed to assign role: %w", err)
+	}
+	return s.userRepo.FindByID(user.ID)
+}
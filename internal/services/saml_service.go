@@ -0,0 +1,579 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+	"auth-barniee/internal/utils"
+
+	"github.com/crewjam/saml"
+	"github.com/crewjam/saml/samlsp"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const idpMetadataCacheTTL = time.Hour
+
+// idpMetadataClient fetches IdP metadata. The URL is set by school admins and fetched from the
+// unauthenticated login route, and the metadata carries the certificate that signs assertions, so it
+// is only fetched over https and never from loopback, private or link-local addresses.
+var idpMetadataClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext:         (&net.Dialer{Timeout: 5 * time.Second, Control: refuseNonPublicAddress}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if req.URL.Scheme != "https" {
+			return errors.New("IdP metadata redirect must use https")
+		}
+		if len(via) >= 5 {
+			return errors.New("too many IdP metadata redirects")
+		}
+		return nil
+	},
+}
+
+// refuseNonPublicAddress is a net.Dialer Control that runs after name resolution, so a host that
+// resolves to an internal address is refused too.
+func refuseNonPublicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() || ip.IsInterfaceLocalMulticast() {
+		return fmt.Errorf("IdP metadata host %s is not a public address", host)
+	}
+	return nil
+}
+
+// parseIDPMetadataURL accepts only absolute https URLs.
+func parseIDPMetadataURL(rawURL string) (*url.URL, error) {
+	metadataURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IdP metadata URL: %w", err)
+	}
+	if metadataURL.Scheme != "https" || metadataURL.Host == "" {
+		return nil, errors.New("IdP metadata URL must be an https URL")
+	}
+	return metadataURL, nil
+}
+
+// samlRolePriority decides which role wins when an assertion maps to several roles.
+var samlRolePriority = []string{"admin", "teacher", "student"}
+
+type SAMLConfigInput struct {
+	Enabled         bool
+	IDPMetadataURL  string
+	IDPMetadataXML  string
+	EmailAttribute  string
+	NameAttribute   string
+	RoleAttribute   string
+	RoleMapping     map[string]string
+	DefaultRoleName string
+	AutoProvision   bool
+}
+
+type SAMLService interface {
+	GetMetadata(schoolID uuid.UUID) ([]byte, error)
+	BuildLoginRedirect(schoolID uuid.UUID, relayState string) (string, string, error) // Returns redirect URL and AuthnRequest ID
+	ConsumeAssertion(schoolID uuid.UUID, r *http.Request, possibleRequestIDs []string) (string, error)
+	GetConfig(adminID uuid.UUID) (*models.SchoolSAMLConfig, error)
+	SaveConfig(adminID uuid.UUID, input SAMLConfigInput) (*models.SchoolSAMLConfig, error)
+}
+
+type cachedIDPMetadata struct {
+	descriptor *saml.EntityDescriptor
+	updatedAt  time.Time
+	fetchedAt  time.Time
+}
+
+type samlService struct {
+	samlConfigRepo repositories.SAMLConfigRepository
+	schoolRepo     repositories.SchoolRepository
+	userRepo       repositories.UserRepository
+	roleRepo       repositories.RoleRepository
+	tokenIssuer    TokenIssuer
+	config         *config.Config
+	key            crypto.Signer
+	certificate    *x509.Certificate
+	keyErr         error
+
+	metadataMu    sync.Mutex
+	metadataCache map[uuid.UUID]cachedIDPMetadata
+}
+
+func NewSAMLService(
+	samlConfigRepo repositories.SAMLConfigRepository,
+	schoolRepo repositories.SchoolRepository,
+	userRepo repositories.UserRepository,
+	roleRepo repositories.RoleRepository,
+	tokenIssuer TokenIssuer,
+	cfg *config.Config,
+) SAMLService {
+	s := &samlService{
+		samlConfigRepo: samlConfigRepo,
+		schoolRepo:     schoolRepo,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		tokenIssuer:    tokenIssuer,
+		config:         cfg,
+		metadataCache:  make(map[uuid.UUID]cachedIDPMetadata),
+	}
+	s.key, s.certificate, s.keyErr = loadSAMLKeyPair(cfg)
+	return s
+}
+
+func loadSAMLKeyPair(cfg *config.Config) (crypto.Signer, *x509.Certificate, error) {
+	if cfg.SAMLCertFile == "" || cfg.SAMLKeyFile == "" || cfg.PublicBaseURL == "" {
+		return nil, nil, errors.New("SAML is not configured on this server")
+	}
+
+	keyPair, err := tls.LoadX509KeyPair(cfg.SAMLCertFile, cfg.SAMLKeyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load SAML key pair: %w", err)
+	}
+	certificate, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse SAML certificate: %w", err)
+	}
+	key, ok := keyPair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, errors.New("SAML private key cannot be used for signing")
+	}
+	return key, certificate, nil
+}
+
+func (s *samlService) serviceProvider(schoolID uuid.UUID, idpMetadata *saml.EntityDescriptor) (*saml.ServiceProvider, error) {
+	if s.keyErr != nil {
+		return nil, s.keyErr
+	}
+
+	baseURL, err := url.Parse(s.config.PublicBaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PUBLIC_BASE_URL: %w", err)
+	}
+	samlPath := fmt.Sprintf("/api/v1/auth/saml/%s", schoolID.String())
+	metadataURL := baseURL.ResolveReference(&url.URL{Path: samlPath + "/metadata"})
+	acsURL := baseURL.ResolveReference(&url.URL{Path: samlPath + "/acs"})
+
+	return &saml.ServiceProvider{
+		EntityID:          metadataURL.String(),
+		Key:               s.key,
+		Certificate:       s.certificate,
+		MetadataURL:       *metadataURL,
+		AcsURL:            *acsURL,
+		IDPMetadata:       idpMetadata,
+		AllowIDPInitiated: true,
+	}, nil
+}
+
+func (s *samlService) enabledConfig(schoolID uuid.UUID) (*models.SchoolSAMLConfig, error) {
+	samlConfig, err := s.samlConfigRepo.FindBySchoolID(schoolID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("SAML is not enabled for this school")
+		}
+		return nil, fmt.Errorf("failed to find SAML config: %w", err)
+	}
+	if !samlConfig.Enabled {
+		return nil, errors.New("SAML is not enabled for this school")
+	}
+	return samlConfig, nil
+}
+
+func (s *samlService) idpMetadata(samlConfig *models.SchoolSAMLConfig) (*saml.EntityDescriptor, error) {
+	if samlConfig.IDPMetadataXML != "" {
+		descriptor, err := samlsp.ParseMetadata([]byte(samlConfig.IDPMetadataXML))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse IdP metadata: %w", err)
+		}
+		return descriptor, nil
+	}
+
+	s.metadataMu.Lock()
+	cached, ok := s.metadataCache[samlConfig.SchoolID]
+	s.metadataMu.Unlock()
+	if ok && cached.updatedAt.Equal(samlConfig.UpdatedAt) && time.Since(cached.fetchedAt) < idpMetadataCacheTTL {
+		return cached.descriptor, nil
+	}
+
+	metadataURL, err := parseIDPMetadataURL(samlConfig.IDPMetadataURL)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	descriptor, err := samlsp.FetchMetadata(ctx, idpMetadataClient, *metadataURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch IdP metadata: %w", err)
+	}
+
+	s.metadataMu.Lock()
+	s.metadataCache[samlConfig.SchoolID] = cachedIDPMetadata{
+		descriptor: descriptor,
+		updatedAt:  samlConfig.UpdatedAt,
+		fetchedAt:  time.Now(),
+	}
+	s.metadataMu.Unlock()
+	return descriptor, nil
+}
+
+func (s *samlService) GetMetadata(schoolID uuid.UUID) ([]byte, error) {
+	if _, err := s.schoolRepo.FindByID(schoolID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("school not found")
+		}
+		return nil, fmt.Errorf("failed to find school: %w", err)
+	}
+
+	sp, err := s.serviceProvider(schoolID, nil)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := xml.MarshalIndent(sp.Metadata(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render SP metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+func (s *samlService) BuildLoginRedirect(schoolID uuid.UUID, relayState string) (string, string, error) {
+	samlConfig, err := s.enabledConfig(schoolID)
+	if err != nil {
+		return "", "", err
+	}
+	idpMetadata, err := s.idpMetadata(samlConfig)
+	if err != nil {
+		return "", "", err
+	}
+	sp, err := s.serviceProvider(schoolID, idpMetadata)
+	if err != nil {
+		return "", "", err
+	}
+
+	authnRequest, err := sp.MakeAuthenticationRequest(sp.GetSSOBindingLocation(saml.HTTPRedirectBinding), saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create SAML authentication request: %w", err)
+	}
+	redirectURL, err := authnRequest.Redirect(relayState, sp)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to build SAML redirect: %w", err)
+	}
+	return redirectURL.String(), authnRequest.ID, nil
+}
+
+func (s *samlService) ConsumeAssertion(schoolID uuid.UUID, r *http.Request, possibleRequestIDs []string) (string, error) {
+	samlConfig, err := s.enabledConfig(schoolID)
+	if err != nil {
+		return "", err
+	}
+	idpMetadata, err := s.idpMetadata(samlConfig)
+	if err != nil {
+		return "", err
+	}
+	sp, err := s.serviceProvider(schoolID, idpMetadata)
+	if err != nil {
+		return "", err
+	}
+
+	// ParseResponse reads the posted form but does not parse it
+	if err := r.ParseForm(); err != nil {
+		return "", fmt.Errorf("invalid SAML response: %w", err)
+	}
+	assertion, err := sp.ParseResponse(r, possibleRequestIDs)
+	if err != nil {
+		var invalidResponse *saml.InvalidResponseError
+		if errors.As(err, &invalidResponse) {
+			return "", fmt.Errorf("invalid SAML response: %w", invalidResponse.PrivateErr)
+		}
+		return "", fmt.Errorf("invalid SAML response: %w", err)
+	}
+
+	email := samlAttributeValue(assertion, samlConfig.EmailAttribute)
+	if email == "" && samlConfig.EmailAttribute == "" && assertion.Subject != nil && assertion.Subject.NameID != nil {
+		email = assertion.Subject.NameID.Value
+	}
+	if email == "" {
+		return "", errors.New("SAML assertion does not contain an email address")
+	}
+	name := samlAttributeValue(assertion, samlConfig.NameAttribute)
+
+	mappedRoleName, err := s.mapSAMLRole(assertion, samlConfig)
+	if err != nil {
+		return "", err
+	}
+
+	user, err := s.findOrProvisionSAMLUser(schoolID, samlConfig, email, name, mappedRoleName)
+	if err != nil {
+		return "", err
+	}
+	if err := ensureAccountActive(user); err != nil {
+		return "", err
+	}
+
+	return s.tokenIssuer.IssueToken(user)
+}
+
+func (s *samlService) mapSAMLRole(assertion *saml.Assertion, samlConfig *models.SchoolSAMLConfig) (string, error) {
+	if samlConfig.RoleAttribute == "" || samlConfig.RoleMapping == "" {
+		return "", nil
+	}
+
+	var roleMapping map[string]string
+	if err := json.Unmarshal([]byte(samlConfig.RoleMapping), &roleMapping); err != nil {
+		return "", fmt.Errorf("invalid SAML role mapping: %w", err)
+	}
+
+	mapped := make(map[string]bool)
+	for _, value := range samlAttributeValues(assertion, samlConfig.RoleAttribute) {
+		if roleName, ok := roleMapping[value]; ok {
+			mapped[roleName] = true
+		}
+	}
+	for _, roleName := range samlRolePriority {
+		if mapped[roleName] {
+			return roleName, nil
+		}
+	}
+	return "", nil
+}
+
+func (s *samlService) findOrProvisionSAMLUser(schoolID uuid.UUID, samlConfig *models.SchoolSAMLConfig, email, name, mappedRoleName string) (*models.User, error) {
+	school, err := s.schoolRepo.FindByID(schoolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find school: %w", err)
+	}
+
+	user, err := s.userRepo.FindByEmailIncludingDeleted(email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	// The email stays taken while the account is in the recycle bin, so it can't be provisioned again
+	if user != nil && user.DeletedAt.Valid {
+		return nil, errors.New("this account has been deleted")
+	}
+
+	if user == nil {
+		if !samlConfig.AutoProvision {
+			return nil, errors.New("no account exists for this SAML identity")
+		}
+		roleName := mappedRoleName
+		if roleName == "" {
+			roleName = samlConfig.DefaultRoleName
+		}
+		if roleName == "" {
+			return nil, errors.New("SAML assertion could not be mapped to a role")
+		}
+		role, err := s.roleRepo.FindByName(roleName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find role '%s': %w", roleName, err)
+		}
+		// SSO users never sign in with a password, so store an unguessable one.
+		hashedPassword, err := utils.HashPassword(utils.GenerateRandomPassword(32))
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+		if name == "" {
+			name = email
+		}
+		user = &models.User{
+			Name:      name,
+			Email:     email,
+			Password:  hashedPassword,
+			RoleID:    role.ID,
+			SchoolID:  schoolID,
+			CreatedBy: uuid.Nil,
+		}
+		if role.SystemName() == "student" {
+			err = s.userRepo.CreateWithinStudentQuota(user)
+		} else {
+			err = s.userRepo.Create(user)
+		}
+		if err != nil {
+			var quotaErr *repositories.StudentQuotaExceededError
+			if errors.As(err, &quotaErr) {
+				return nil, quotaErr
+			}
+			return nil, fmt.Errorf("failed to provision SAML user: %w", err)
+		}
+		return s.userRepo.FindByID(user.ID)
+	}
+
+	if user.SchoolID != schoolID {
+		return nil, errors.New("this account does not belong to the school")
+	}
+
+	changed := false
+	var newRole *models.Role
+	wasStudent := user.Role.SystemName() == "student"
+	if name != "" && name != user.Name {
+		user.Name = name
+		changed = true
+	}
+	// The school's primary admin keeps its role regardless of IdP group membership.
+	if mappedRoleName != "" && mappedRoleName != user.Role.SystemName() && user.ID != school.AdminUserID {
+		role, err := s.roleRepo.FindByName(mappedRoleName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find role '%s': %w", mappedRoleName, err)
+		}
+		user.RoleID = role.ID
+		user.Role = *role
+		newRole = role
+		changed = true
+	}
+	if changed {
+		user.UpdatedBy = uuid.Nil
+		if newRole != nil {
+			err = saveUserRole(s.userRepo, user, wasStudent, newRole)
+		} else {
+			err = s.userRepo.Update(user)
+		}
+		if err != nil {
+			var quotaErr *repositories.StudentQuotaExceededError
+			if errors.As(err, &quotaErr) {
+				return nil, quotaErr
+			}
+			return nil, fmt.Errorf("failed to sync SAML user: %w", err)
+		}
+	}
+	return user, nil
+}
+
+func samlAttributeValues(assertion *saml.Assertion, attributeName string) []string {
+	if attributeName == "" {
+		return nil
+	}
+	var values []string
+	for _, statement := range assertion.AttributeStatements {
+		for _, attribute := range statement.Attributes {
+			if attribute.Name != attributeName && attribute.FriendlyName != attributeName {
+				continue
+			}
+			for _, value := range attribute.Values {
+				values = append(values, strings.TrimSpace(value.Value))
+			}
+		}
+	}
+	return values
+}
+
+func samlAttributeValue(assertion *saml.Assertion, attributeName string) string {
+	values := samlAttributeValues(assertion, attributeName)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (s *samlService) adminSchoolID(adminID uuid.UUID) (uuid.UUID, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	if adminUser.SchoolID == uuid.Nil {
+		return uuid.Nil, errors.New("admin is not associated with a school")
+	}
+	return adminUser.SchoolID, nil
+}
+
+func (s *samlService) GetConfig(adminID uuid.UUID) (*models.SchoolSAMLConfig, error) {
+	schoolID, err := s.adminSchoolID(adminID)
+	if err != nil {
+		return nil, err
+	}
+	samlConfig, err := s.samlConfigRepo.FindBySchoolID(schoolID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("SAML config not found")
+		}
+		return nil, fmt.Errorf("failed to find SAML config: %w", err)
+	}
+	return samlConfig, nil
+}
+
+func (s *samlService) SaveConfig(adminID uuid.UUID, input SAMLConfigInput) (*models.SchoolSAMLConfig, error) {
+	schoolID, err := s.adminSchoolID(adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Enabled && input.IDPMetadataURL == "" && input.IDPMetadataXML == "" {
+		return nil, errors.New("IdP metadata URL or XML is required to enable SAML")
+	}
+	if input.IDPMetadataURL != "" {
+		if _, err := parseIDPMetadataURL(input.IDPMetadataURL); err != nil {
+			return nil, err
+		}
+	}
+	if input.IDPMetadataXML != "" {
+		if _, err := samlsp.ParseMetadata([]byte(input.IDPMetadataXML)); err != nil {
+			return nil, fmt.Errorf("invalid IdP metadata XML: %w", err)
+		}
+	}
+	for _, roleName := range input.RoleMapping {
+		if err := s.validateSAMLRole(roleName); err != nil {
+			return nil, err
+		}
+	}
+	if input.DefaultRoleName != "" {
+		if err := s.validateSAMLRole(input.DefaultRoleName); err != nil {
+			return nil, err
+		}
+	}
+	roleMapping, err := json.Marshal(input.RoleMapping)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode role mapping: %w", err)
+	}
+
+	samlConfig, err := s.samlConfigRepo.FindBySchoolID(schoolID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to find SAML config: %w", err)
+		}
+		samlConfig = &models.SchoolSAMLConfig{SchoolID: schoolID, CreatedBy: adminID}
+	}
+
+	samlConfig.Enabled = input.Enabled
+	samlConfig.IDPMetadataURL = input.IDPMetadataURL
+	samlConfig.IDPMetadataXML = input.IDPMetadataXML
+	samlConfig.EmailAttribute = input.EmailAttribute
+	samlConfig.NameAttribute = input.NameAttribute
+	samlConfig.RoleAttribute = input.RoleAttribute
+	samlConfig.RoleMapping = string(roleMapping)
+	samlConfig.DefaultRoleName = input.DefaultRoleName
+	samlConfig.AutoProvision = input.AutoProvision
+	samlConfig.UpdatedBy = adminID
+
+	if err := s.samlConfigRepo.Save(samlConfig); err != nil {
+		return nil, fmt.Errorf("failed to save SAML config: %w", err)
+	}
+	return samlConfig, nil
+}
+
+func (s *samlService) validateSAMLRole(roleName string) error {
+	for _, allowed := range samlRolePriority {
+		if roleName == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("role '%s' cannot be assigned through SAML", roleName)
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+
+	"github.com/crewjam/saml"
+	"github.com/google/uuid"
+)
+
+// newTestKeyPair returns a key and a self-signed certificate for it.
+func newTestKeyPair(t *testing.T, commonName string) (*rsa.PrivateKey, *x509.Certificate) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return key, certificate
+}
+
+func newTestIDP(t *testing.T, entityID string) *saml.IdentityProvider {
+	key, certificate := newTestKeyPair(t, "idp.sekolah.sch.id")
+	metadataURL, _ := url.Parse(entityID)
+	ssoURL, _ := url.Parse(entityID + "/sso")
+	return &saml.IdentityProvider{Key: key, Signer: key, Certificate: certificate, MetadataURL: *metadataURL, SSOURL: *ssoURL}
+}
+
+type samlFixture struct {
+	school  *models.School
+	users   *fakeUserRepository
+	configs *fakeSAMLConfigRepository
+	idp     *saml.IdentityProvider
+	service SAMLService
+}
+
+// newSAMLFixture sets up a school whose SAML login trusts idp, with the SP key pair in temporary files.
+func newSAMLFixture(t *testing.T) *samlFixture {
+	dir := t.TempDir()
+	key, certificate := newTestKeyPair(t, "auth.barniee.id")
+	certFile, keyFile := filepath.Join(dir, "sp.crt"), filepath.Join(dir, "sp.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &samlFixture{
+		school:  &models.School{ID: uuid.New(), Name: "SMA Barniee"},
+		users:   newFakeUserRepository(),
+		configs: &fakeSAMLConfigRepository{configs: make(map[uuid.UUID]*models.SchoolSAMLConfig)},
+		idp:     newTestIDP(t, "https://idp.sekolah.sch.id/metadata"),
+	}
+	idpMetadata, err := xml.Marshal(f.idp.Metadata())
+	if err != nil {
+		t.Fatalf("failed to render IdP metadata: %v", err)
+	}
+	f.configs.configs[f.school.ID] = &models.SchoolSAMLConfig{
+		SchoolID:        f.school.ID,
+		Enabled:         true,
+		IDPMetadataXML:  string(idpMetadata),
+		EmailAttribute:  "mail",
+		NameAttribute:   "cn",
+		RoleAttribute:   "eduPersonAffiliation",
+		RoleMapping:     `{"staff": "teacher", "it": "admin"}`,
+		DefaultRoleName: "student",
+		AutoProvision:   true,
+	}
+	roles := &fakeRoleRepository{roles: []*models.Role{
+		{ID: uuid.New(), Name: "admin"}, {ID: uuid.New(), Name: "teacher"}, {ID: uuid.New(), Name: "student"},
+	}}
+	cfg := &config.Config{PublicBaseURL: "https://auth.barniee.id", SAMLCertFile: certFile, SAMLKeyFile: keyFile}
+	f.service = NewSAMLService(f.configs, newFakeSchoolRepository(f.school), f.users, roles, fakeTokenIssuer{}, cfg)
+	return f
+}
+
+// acsRequest is the browser POST to the ACS carrying a response that idp signed for the session.
+func (f *samlFixture) acsRequest(t *testing.T, idp *saml.IdentityProvider, session *saml.Session) *http.Request {
+	t.Helper()
+	spMetadataXML, err := f.service.GetMetadata(f.school.ID)
+	if err != nil {
+		t.Fatalf("GetMetadata returned error: %v", err)
+	}
+	var spMetadata saml.EntityDescriptor
+	if err := xml.Unmarshal(spMetadataXML, &spMetadata); err != nil {
+		t.Fatalf("failed to parse SP metadata: %v", err)
+	}
+	descriptor := spMetadata.SPSSODescriptors[0]
+	req := &saml.IdpAuthnRequest{
+		IDP:                     idp,
+		HTTPRequest:             httptest.NewRequest(http.MethodGet, idp.SSOURL.String(), nil),
+		Now:                     saml.TimeNow(),
+		ServiceProviderMetadata: &spMetadata,
+		SPSSODescriptor:         &descriptor,
+		ACSEndpoint:             &descriptor.AssertionConsumerServices[0],
+	}
+	if err := (saml.DefaultAssertionMaker{}).MakeAssertion(req, session); err != nil {
+		t.Fatalf("failed to make assertion: %v", err)
+	}
+	form, err := req.PostBinding()
+	if err != nil {
+		t.Fatalf("failed to build SAML response: %v", err)
+	}
+	body := url.Values{"SAMLResponse": {form.SAMLResponse}}.Encode()
+	r := httptest.NewRequest(http.MethodPost, form.URL, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func TestSAMLAssertionProvisionsTheMappedRole(t *testing.T) {
+	f := newSAMLFixture(t)
+	session := &saml.Session{NameID: "budi", UserEmail: "budi@sekolah.sch.id", UserCommonName: "Budi Santoso", Groups: []string{"alumni", "staff"}}
+
+	token, err := f.service.ConsumeAssertion(f.school.ID, f.acsRequest(t, f.idp, session), nil)
+	if err != nil {
+		t.Fatalf("ConsumeAssertion returned error: %v", err)
+	}
+	if token != "token:budi@sekolah.sch.id" {
+		t.Errorf("token = %q, want one for budi", token)
+	}
+	user, err := f.users.FindByEmail("budi@sekolah.sch.id")
+	if err != nil {
+		t.Fatalf("the SAML user was not provisioned: %v", err)
+	}
+	if user.Name != "Budi Santoso" || user.SchoolID != f.school.ID {
+		t.Errorf("provisioned user = %q at %s, want Budi Santoso at the school", user.Name, user.SchoolID)
+	}
+	if role := f.users.users[user.ID].RoleID; role != f.roleID(t, "teacher") {
+		t.Error("the staff group was not mapped to the teacher role")
+	}
+}
+
+func (f *samlFixture) roleID(t *testing.T, name string) uuid.UUID {
+	role, err := f.service.(*samlService).roleRepo.FindByName(name)
+	if err != nil {
+		t.Fatalf("role %s not found: %v", name, err)
+	}
+	return role.ID
+}
+
+func TestSAMLRoleMappingPrefersTheHighestRole(t *testing.T) {
+	f := newSAMLFixture(t)
+	samlConfig := f.configs.configs[f.school.ID]
+	assertion := &saml.Assertion{AttributeStatements: []saml.AttributeStatement{{Attributes: []saml.Attribute{{
+		Name: "urn:oid:1.3.6.1.4.1.5923.1.1.1.1", FriendlyName: "eduPersonAffiliation",
+		Values: []saml.AttributeValue{{Value: "staff"}, {Value: " it "}, {Value: "unknown"}},
+	}}}}}
+
+	role, err := f.service.(*samlService).mapSAMLRole(assertion, samlConfig)
+	if err != nil || role != "admin" {
+		t.Errorf("mapped role = %q, %v; want admin over teacher", role, err)
+	}
+	assertion.AttributeStatements[0].Attributes[0].Values = []saml.AttributeValue{{Value: "unknown"}}
+	if role, _ := f.service.(*samlService).mapSAMLRole(assertion, samlConfig); role != "" {
+		t.Errorf("mapped role = %q for an unmapped group, want none", role)
+	}
+}
+
+func TestSAMLAssertionSignedByAnotherIdPIsRejected(t *testing.T) {
+	f := newSAMLFixture(t)
+	// Same entity ID as the school's IdP, but a key the school never trusted
+	forger := newTestIDP(t, "https://idp.sekolah.sch.id/metadata")
+	session := &saml.Session{NameID: "admin", UserEmail: "tu@sekolah.sch.id", Groups: []string{"it"}}
+
+	_, err := f.service.ConsumeAssertion(f.school.ID, f.acsRequest(t, forger, session), nil)
+	if err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("ConsumeAssertion error = %v, want the forged response rejected", err)
+	}
+	if _, err := f.users.FindByEmail("tu@sekolah.sch.id"); err == nil {
+		t.Error("a forged assertion provisioned a user")
+	}
+}
+
+func TestSAMLLoginRefusesInactiveAccounts(t *testing.T) {
+	f := newSAMLFixture(t)
+	teacher := &models.User{ID: uuid.New(), Name: "Budi Santoso", Email: "budi@sekolah.sch.id", SchoolID: f.school.ID,
+		Role: models.Role{Name: "teacher"}, Status: models.UserStatusSuspended, StatusReason: "cuti"}
+	f.users.users[teacher.ID] = teacher
+	session := &saml.Session{NameID: "budi", UserEmail: teacher.Email, Groups: []string{"staff"}}
+
+	_, err := f.service.ConsumeAssertion(f.school.ID, f.acsRequest(t, f.idp, session), nil)
+	var inactive *AccountInactiveError
+	if !errors.As(err, &inactive) || inactive.Status != models.UserStatusSuspended {
+		t.Errorf("ConsumeAssertion error = %v, want the suspended account refused", err)
+	}
+}
+
+func TestSAMLConfigRequiresAnHTTPSMetadataURL(t *testing.T) {
+	f := newSAMLFixture(t)
+	admin := &models.User{ID: uuid.New(), Email: "tu@sekolah.sch.id", SchoolID: f.school.ID}
+	f.users.users[admin.ID] = admin
+
+	for _, metadataURL := range []string{"http://idp.sekolah.sch.id/metadata", "file:///etc/passwd", "https:///metadata"} {
+		if _, err := f.service.SaveConfig(admin.ID, SAMLConfigInput{Enabled: true, IDPMetadataURL: metadataURL}); err == nil {
+			t.Errorf("SaveConfig accepted the metadata URL %q", metadataURL)
+		}
+	}
+	if _, err := f.service.SaveConfig(admin.ID, SAMLConfigInput{Enabled: true, IDPMetadataURL: "https://idp.sekolah.sch.id/metadata"}); err != nil {
+		t.Errorf("SaveConfig rejected an https metadata URL: %v", err)
+	}
+}
+
+func TestIDPMetadataIsNotFetchedFromInternalAddresses(t *testing.T) {
+	f := newSAMLFixture(t)
+	internal := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the IdP metadata was fetched from a loopback address")
+	}))
+	defer internal.Close()
+
+	samlConfig := f.configs.configs[f.school.ID]
+	samlConfig.IDPMetadataXML = ""
+	samlConfig.IDPMetadataURL = internal.URL + "/metadata"
+	if _, _, err := f.service.BuildLoginRedirect(f.school.ID, ""); err == nil || !strings.Contains(err.Error(), "not a public address") {
+		t.Errorf("BuildLoginRedirect error = %v, want the loopback host refused", err)
+	}
+}
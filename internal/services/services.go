@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/repositories"
+)
+
+// Services are the application services. They are built once at startup and shared by the
+// HTTP routes and the scheduler, so both run the same wiring.
+type Services struct {
+	Subscription        SubscriptionService
+	TokenIssuer         TokenIssuer
+	Auth                AuthService
+	User                UserService
+	UserImport          UserImportService
+	UserRecycleBin      UserRecycleBinService
+	UserStatus          UserStatusService
+	Registration        RegistrationService
+	RegistrationCleanup RegistrationCleanupService
+	SAML                SAMLService
+	LDAPConfig          LDAPConfigService
+	Guardian            GuardianService
+	Role                RoleService
+	PlatformSchool      PlatformSchoolService
+	Package             PackageService
+	Job                 JobService
+	Invoice             InvoiceService
+	Payment             PaymentService
+	Organization        OrganizationService
+	PlanChange          PlanChangeService
+}
+
+func NewServices(repos repositories.Repositories, uow repositories.UnitOfWork, cfg *config.Config) (*Services, error) {
+	paymentProvider, err := NewPaymentProvider(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up payment provider: %w", err)
+	}
+
+	s := &Services{}
+	s.Subscription = NewSubscriptionService(repos.Schools, cfg)
+	s.TokenIssuer = NewTokenIssuer(repos.Roles, repos.GuardianLinks, s.Subscription, cfg)
+
+	s.Auth = NewAuthService(repos.Users, repos.Roles, repos.Schools, repos.LDAPConfigs, NewLDAPAuthenticator(), s.Subscription, s.TokenIssuer, uow, cfg)
+	s.User = NewUserService(repos.Users, repos.Roles, repos.Schools, uow)
+	s.UserImport = NewUserImportService(repos.UserImports, repos.Users, repos.Schools, s.User)
+	s.UserRecycleBin = NewUserRecycleBinService(repos.Users, repos.Roles, repos.Schools, cfg)
+	s.UserStatus = NewUserStatusService(repos.Users, repos.Roles, repos.Schools)
+	s.Registration = NewRegistrationService(repos.Schools, repos.Packages, repos.RegistrationSessions, uow, cfg)
+	s.RegistrationCleanup = NewRegistrationCleanupService(repos.Schools, repos.Users, repos.PurgedRegistrations, uow, cfg)
+	s.SAML = NewSAMLService(repos.SAMLConfigs, repos.Schools, repos.Users, repos.Roles, s.TokenIssuer, cfg)
+	s.LDAPConfig = NewLDAPConfigService(repos.LDAPConfigs, repos.Users)
+	s.Guardian = NewGuardianService(repos.GuardianLinks, repos.StudentInviteCodes, repos.Users, s.TokenIssuer, uow)
+	s.Role = NewRoleService(repos.Roles, repos.Permissions, repos.Users, repos.Schools)
+	s.PlatformSchool = NewPlatformSchoolService(repos.Schools, repos.Users, s.Subscription)
+	s.Package = NewPackageService(repos.Packages)
+	s.Job = NewJobService(repos.Jobs)
+	s.Invoice = NewInvoiceService(repos.Invoices, repos.Schools, repos.Organizations, repos.Users, s.Subscription, uow, cfg)
+	s.Payment = NewPaymentService(repos.Payments, repos.PlanChanges, repos.Schools, repos.Organizations, repos.Users, paymentProvider, s.Invoice, s.Subscription, uow, cfg)
+	s.Organization = NewOrganizationService(repos.Organizations, repos.Schools, repos.Users, repos.Roles)
+	s.PlanChange = NewPlanChangeService(repos.PlanChanges, repos.Schools, repos.Organizations, repos.Packages, repos.Users, repos.Invoices, s.Payment, s.Subscription, uow, cfg)
+	return s, nil
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SubscriptionService derives a school's subscription state from its package and dates
+// and decides what its users may do in each state.
+//
+//	unpaid --payment--> active
+//	trialing/active --end date--> past_due (paid only) --> grace --> expired
+//	any state --platform suspension--> suspended
+type SubscriptionService interface {
+	EvaluateStatus(school *models.School, now time.Time) string
+	RefreshSchoolStatus(schoolID uuid.UUID) (*models.School, error) // Persists the status when it changed
+	CheckAccess(status, roleName string, readOnly bool) error
+}
+
+type subscriptionService struct {
+	schoolRepo repositories.SchoolRepository
+	config     *config.Config
+}
+
+func NewSubscriptionService(schoolRepo repositories.SchoolRepository, cfg *config.Config) SubscriptionService {
+	return &subscriptionService{
+		schoolRepo: schoolRepo,
+		config:     cfg,
+	}
+}
+
+func (s *subscriptionService) EvaluateStatus(school *models.School, now time.Time) string {
+	if school.SuspendedAt != nil {
+		return models.SubscriptionStatusSuspended
+	}
+	if school.SubscriptionEndDate == nil {
+		// Paid packages only get a period from a payment
+		if school.Package.IsTrial {
+			return models.SubscriptionStatusTrialing
+		}
+		return models.SubscriptionStatusUnpaid
+	}
+	if now.Before(*school.SubscriptionEndDate) {
+		if school.Package.IsTrial {
+			return models.SubscriptionStatusTrialing
+		}
+		return models.SubscriptionStatusActive
+	}
+
+	overdue := now.Sub(*school.SubscriptionEndDate)
+	// Trials owe nothing, so they skip straight to the grace period
+	if !school.Package.IsTrial {
+		pastDue := time.Duration(s.config.SubscriptionPastDueDays) * 24 * time.Hour
+		if overdue < pastDue {
+			return models.SubscriptionStatusPastDue
+		}
+		overdue -= pastDue
+	}
+	if overdue < time.Duration(s.config.SubscriptionGraceDays)*24*time.Hour {
+		return models.SubscriptionStatusGrace
+	}
+	return models.SubscriptionStatusExpired
+}
+
+func (s *subscriptionService) RefreshSchoolStatus(schoolID uuid.UUID) (*models.School, error) {
+	school, err := s.schoolRepo.FindByID(schoolID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("school not found")
+		}
+		return nil, fmt.Errorf("failed to find school: %w", err)
+	}
+
+	status := s.EvaluateStatus(school, time.Now())
+	if status != school.SubscriptionStatus {
+		if err := s.schoolRepo.UpdateSubscriptionStatus(school.ID, status); err != nil {
+			return nil, fmt.Errorf("failed to update subscription status: %w", err)
+		}
+		school.SubscriptionStatus = status
+	}
+	return school, nil
+}
+
+// CheckAccess applies the per-state limits: grace schools are read-only except for admins,
+// unpaid and expired schools are admin-only, and suspended schools are closed to everyone.
+func (s *subscriptionService) CheckAccess(status, roleName string, readOnly bool) error {
+	switch status {
+	case models.SubscriptionStatusSuspended:
+		return errors.New("school is suspended")
+	case models.SubscriptionStatusGrace:
+		if roleName != "admin" && !readOnly {
+			return errors.New("school subscription is in its grace period: access is read-only")
+		}
+	case models.SubscriptionStatusUnpaid:
+		if roleName != "admin" {
+			return errors.New("school subscription has not been paid: only school admins can sign in")
+		}
+	case models.SubscriptionStatusExpired:
+		if roleName != "admin" {
+			return errors.New("school subscription has expired: only school admins can sign in")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestEvaluateStatusBoundaries(t *testing.T) {
+	subscription := NewSubscriptionService(nil, &config.Config{SubscriptionPastDueDays: 7, SubscriptionGraceDays: 14})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	suspendedAt := now.Add(-day)
+
+	tests := []struct {
+		name      string
+		trial     bool
+		endsIn    *time.Duration // Subscription end relative to now; nil when there is no period
+		suspended bool
+		want      string
+	}{
+		{name: "trial without a period", trial: true, want: models.SubscriptionStatusTrialing},
+		{name: "paid package without a period", want: models.SubscriptionStatusUnpaid},
+		{name: "trial running", trial: true, endsIn: durationPtr(day), want: models.SubscriptionStatusTrialing},
+		{name: "paid period running", endsIn: durationPtr(time.Nanosecond), want: models.SubscriptionStatusActive},
+		{name: "paid period ends now", endsIn: durationPtr(0), want: models.SubscriptionStatusPastDue},
+		{name: "last moment past due", endsIn: durationPtr(-7*day + time.Nanosecond), want: models.SubscriptionStatusPastDue},
+		{name: "grace after past due", endsIn: durationPtr(-7 * day), want: models.SubscriptionStatusGrace},
+		{name: "last moment of grace", endsIn: durationPtr(-21*day + time.Nanosecond), want: models.SubscriptionStatusGrace},
+		{name: "paid expired", endsIn: durationPtr(-21 * day), want: models.SubscriptionStatusExpired},
+		{name: "trial ends straight into grace", trial: true, endsIn: durationPtr(0), want: models.SubscriptionStatusGrace},
+		{name: "last moment of trial grace", trial: true, endsIn: durationPtr(-14*day + time.Nanosecond), want: models.SubscriptionStatusGrace},
+		{name: "trial expired", trial: true, endsIn: durationPtr(-14 * day), want: models.SubscriptionStatusExpired},
+		{name: "suspension wins over a running period", endsIn: durationPtr(30 * day), suspended: true, want: models.SubscriptionStatusSuspended},
+		{name: "suspension wins over unpaid", suspended: true, want: models.SubscriptionStatusSuspended},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			school := &models.School{Package: models.Package{IsTrial: tt.trial}}
+			if tt.endsIn != nil {
+				end := now.Add(*tt.endsIn)
+				school.SubscriptionEndDate = &end
+			}
+			if tt.suspended {
+				school.SuspendedAt = &suspendedAt
+			}
+			if got := subscription.EvaluateStatus(school, now); got != tt.want {
+				t.Errorf("EvaluateStatus = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
+func TestCheckAccessRules(t *testing.T) {
+	subscription := NewSubscriptionService(nil, &config.Config{})
+	// allowed lists who gets in: admin, non-admin reading, non-admin writing
+	tests := []struct {
+		status  string
+		allowed [3]bool
+	}{
+		{models.SubscriptionStatusTrialing, [3]bool{true, true, true}},
+		{models.SubscriptionStatusActive, [3]bool{true, true, true}},
+		{models.SubscriptionStatusPastDue, [3]bool{true, true, true}},
+		{models.SubscriptionStatusGrace, [3]bool{true, true, false}},
+		{models.SubscriptionStatusUnpaid, [3]bool{true, false, false}},
+		{models.SubscriptionStatusExpired, [3]bool{true, false, false}},
+		{models.SubscriptionStatusSuspended, [3]bool{false, false, false}},
+	}
+	for _, tt := range tests {
+		for _, roleName := range []string{"teacher", "student", "parent"} {
+			cases := []struct {
+				roleName string
+				readOnly bool
+				allowed  bool
+			}{
+				{"admin", false, tt.allowed[0]},
+				{"admin", true, tt.allowed[0]},
+				{roleName, true, tt.allowed[1]},
+				{roleName, false, tt.allowed[2]},
+			}
+			for _, c := range cases {
+				err := subscription.CheckAccess(tt.status, c.roleName, c.readOnly)
+				if (err == nil) != c.allowed {
+					t.Errorf("CheckAccess(%q, %q, readOnly=%v) = %v, want allowed=%v", tt.status, c.roleName, c.readOnly, err, c.allowed)
+				}
+			}
+		}
+	}
+}
+
+func TestRefreshSchoolStatusPersistsAChange(t *testing.T) {
+	end := time.Now().Add(-time.Hour)
+	school := &models.School{ID: uuid.New(), SubscriptionEndDate: &end, SubscriptionStatus: models.SubscriptionStatusActive}
+	schools := newFakeSchoolRepository(school)
+	subscription := NewSubscriptionService(schools, &config.Config{SubscriptionPastDueDays: 7, SubscriptionGraceDays: 14})
+
+	refreshed, err := subscription.RefreshSchoolStatus(school.ID)
+	if err != nil {
+		t.Fatalf("RefreshSchoolStatus returned error: %v", err)
+	}
+	if refreshed.SubscriptionStatus != models.SubscriptionStatusPastDue {
+		t.Errorf("refreshed status = %q, want past_due", refreshed.SubscriptionStatus)
+	}
+	if saved := schools.schools[school.ID].SubscriptionStatus; saved != models.SubscriptionStatusPastDue {
+		t.Errorf("saved status = %q, want past_due", saved)
+	}
+}
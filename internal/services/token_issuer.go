@@ -0,0 +1,71 @@
+package services
+
+import (
+	"fmt"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+	"auth-barniee/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// TokenIssuer is the single path every login method uses to turn a user into a JWT.
+type TokenIssuer interface {
+	IssueToken(user *models.User) (string, error)
+}
+
+type tokenIssuer struct {
+	roleRepo         repositories.RoleRepository
+	guardianLinkRepo repositories.GuardianLinkRepository
+	subscription     SubscriptionService
+	config           *config.Config
+}
+
+func NewTokenIssuer(roleRepo repositories.RoleRepository, guardianLinkRepo repositories.GuardianLinkRepository, subscription SubscriptionService, cfg *config.Config) TokenIssuer {
+	return &tokenIssuer{
+		roleRepo:         roleRepo,
+		guardianLinkRepo: guardianLinkRepo,
+		subscription:     subscription,
+		config:           cfg,
+	}
+}
+
+func (t *tokenIssuer) IssueToken(user *models.User) (string, error) {
+	var extra utils.ExtraClaims
+
+	if user.SchoolID != uuid.Nil {
+		school, err := t.subscription.RefreshSchoolStatus(user.SchoolID)
+		if err != nil {
+			return "", err
+		}
+		// Signing in only reads, so grace-period schools can still log in
+		if err := t.subscription.CheckAccess(school.SubscriptionStatus, user.Role.SystemName(), true); err != nil {
+			return "", err
+		}
+	}
+
+	permissions, err := t.roleRepo.FindPermissionNames(user.RoleID)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+	extra.Permissions = permissions
+
+	if user.Role.SystemName() == "parent" {
+		links, err := t.guardianLinkRepo.FindByParentUserID(user.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed to load linked students: %w", err)
+		}
+		extra.StudentIDs = make([]uuid.UUID, 0, len(links))
+		for _, link := range links {
+			extra.StudentIDs = append(extra.StudentIDs, link.StudentUserID)
+		}
+	}
+
+	token, err := utils.GenerateToken(user, extra, t.config)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return token, nil
+}
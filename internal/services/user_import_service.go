@@ -0,0 +1,436 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+	"auth-barniee/internal/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	maxImportRows = 5000
+	// A running import whose progress has not moved for this long is assumed to have lost its worker.
+	importStaleAfter        = 10 * time.Minute
+	generatedPasswordLength = 12
+	// Generated passwords not downloaded within this long after the import finished are discarded.
+	importPasswordRetention = 7 * 24 * time.Hour
+	minImportPasswordLength = 6
+)
+
+// importableRoles are the roles a roster may assign.
+var importableRoles = []string{"student", "teacher"}
+
+// ImportRowPreview is the validation result of one line of an import file.
+type ImportRowPreview struct {
+	Row    int      `json:"row" example:"2"` // Line in the file, counting the header
+	Name   string   `json:"name" example:"Budi Santoso"`
+	Email  string   `json:"email" example:"budi@example.com"`
+	Role   string   `json:"role" example:"student"`
+	Errors []string `json:"errors,omitempty" example:"email already exists in the file on row 5"`
+}
+
+// ImportPreview is the dry-run result of an import file. Nothing is created while a row has errors.
+type ImportPreview struct {
+	TotalRows   int                `json:"total_rows" example:"120"`
+	ValidRows   int                `json:"valid_rows" example:"118"`
+	InvalidRows int                `json:"invalid_rows" example:"2"`
+	Students    int                `json:"students" example:"110"`
+	Teachers    int                `json:"teachers" example:"10"`
+	Rows        []ImportRowPreview `json:"rows"`
+}
+
+// ErrImportHasInvalidRows is returned by StartImport together with the preview listing the row errors.
+var ErrImportHasInvalidRows = errors.New("import file has invalid rows")
+
+type UserImportService interface {
+	// PreviewImport validates a CSV or XLSX roster against the admin's school without creating anything.
+	PreviewImport(adminID uuid.UUID, fileName string, data []byte) (*ImportPreview, error)
+	// StartImport validates the roster and queues it for the user-imports job. When a row is invalid
+	// nothing is queued and ErrImportHasInvalidRows is returned with the preview.
+	StartImport(adminID uuid.UUID, fileName string, data []byte) (*models.UserImport, *ImportPreview, error)
+	GetImport(adminID, importID uuid.UUID) (*models.UserImport, error)
+	// GetImportResults returns a CSV with the outcome of every row. The generated passwords are only in
+	// the first download within importPasswordRetention and are removed from the database afterwards.
+	GetImportResults(adminID, importID uuid.UUID) (*models.UserImport, []byte, error)
+	// ProcessPendingImports creates the users of queued imports until none are left or ctx is cancelled.
+	ProcessPendingImports(ctx context.Context) (int, error)
+	// ClearExpiredPasswords discards the generated passwords of imports finished more than
+	// importPasswordRetention ago and returns how many imports were cleared.
+	ClearExpiredPasswords(now time.Time) (int64, error)
+}
+
+type userImportService struct {
+	importRepo  repositories.UserImportRepository
+	userRepo    repositories.UserRepository
+	schoolRepo  repositories.SchoolRepository
+	userService UserService
+}
+
+func NewUserImportService(importRepo repositories.UserImportRepository, userRepo repositories.UserRepository, schoolRepo repositories.SchoolRepository, userService UserService) UserImportService {
+	return &userImportService{
+		importRepo:  importRepo,
+		userRepo:    userRepo,
+		schoolRepo:  schoolRepo,
+		userService: userService,
+	}
+}
+
+// importRow is a parsed line of an import file.
+type importRow struct {
+	number   int
+	name     string
+	email    string
+	role     string
+	password string
+}
+
+// parseImportFile reads the roster. The header row names the columns: name, email and role are
+// required, password is optional.
+func parseImportFile(fileName string, data []byte) ([]importRow, error) {
+	cells, err := utils.ReadSpreadsheetRows(fileName, data)
+	if err != nil {
+		if errors.Is(err, utils.ErrUnsupportedSpreadsheet) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("invalid import file: %w", err)
+	}
+	if len(cells) == 0 {
+		return nil, errors.New("invalid import file: the file is empty")
+	}
+
+	columns := map[string]int{}
+	for i, header := range cells[0] {
+		columns[strings.ToLower(strings.TrimSpace(header))] = i
+	}
+	for _, required := range []string{"name", "email", "role"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("invalid import file: missing column '%s'", required)
+		}
+	}
+	cell := func(line []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(line) {
+			return ""
+		}
+		return strings.TrimSpace(line[i])
+	}
+
+	var rows []importRow
+	for i, line := range cells[1:] {
+		if !slices.ContainsFunc(line, func(value string) bool { return strings.TrimSpace(value) != "" }) {
+			continue
+		}
+		rows = append(rows, importRow{
+			number:   i + 2,
+			name:     cell(line, "name"),
+			email:    cell(line, "email"),
+			role:     strings.ToLower(cell(line, "role")),
+			password: cell(line, "password"),
+		})
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("invalid import file: the file has no rows")
+	}
+	if len(rows) > maxImportRows {
+		return nil, fmt.Errorf("invalid import file: at most %d rows can be imported at once", maxImportRows)
+	}
+	return rows, nil
+}
+
+func (s *userImportService) findAdminSchool(adminID uuid.UUID) (*models.School, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	if adminUser.SchoolID == uuid.Nil {
+		return nil, errors.New("admin is not associated with a school")
+	}
+	school, err := s.schoolRepo.FindByID(adminUser.SchoolID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("school not found")
+		}
+		return nil, fmt.Errorf("failed to find school: %w", err)
+	}
+	return school, nil
+}
+
+// validateImport checks every row against the others, the existing users and the school's student quota.
+func (s *userImportService) validateImport(school *models.School, rows []importRow) (*ImportPreview, error) {
+	emails := make([]string, 0, len(rows))
+	for _, row := range rows {
+		emails = append(emails, row.email)
+	}
+	existing, err := s.userRepo.FindExistingEmails(emails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing users: %w", err)
+	}
+
+	remainingStudents := -1 // Unlimited
+	if school.MaxStudentsAllowed > 0 {
+		used, err := s.userRepo.CountStudentsBySchoolID(school.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count students: %w", err)
+		}
+		remainingStudents = max(school.MaxStudentsAllowed-int(used), 0)
+	}
+
+	preview := &ImportPreview{TotalRows: len(rows), Rows: make([]ImportRowPreview, 0, len(rows))}
+	firstRowByEmail := map[string]int{}
+	for _, row := range rows {
+		result := ImportRowPreview{Row: row.number, Name: row.name, Email: row.email, Role: row.role}
+
+		if row.name == "" {
+			result.Errors = append(result.Errors, "name is required")
+		}
+		if row.email == "" {
+			result.Errors = append(result.Errors, "email is required")
+		} else if address, err := mail.ParseAddress(row.email); err != nil || address.Address != row.email {
+			result.Errors = append(result.Errors, "email is not a valid address")
+		} else if firstRow, ok := firstRowByEmail[strings.ToLower(row.email)]; ok {
+			result.Errors = append(result.Errors, fmt.Sprintf("email already exists in the file on row %d", firstRow))
+		} else {
+			firstRowByEmail[strings.ToLower(row.email)] = row.number
+			if slices.Contains(existing, row.email) {
+				result.Errors = append(result.Errors, "user with this email already exists")
+			}
+		}
+		if !slices.Contains(importableRoles, row.role) {
+			result.Errors = append(result.Errors, fmt.Sprintf("role must be one of: %s", strings.Join(importableRoles, ", ")))
+		}
+		if row.password != "" && len(row.password) < minImportPasswordLength {
+			result.Errors = append(result.Errors, fmt.Sprintf("password must be at least %d characters", minImportPasswordLength))
+		}
+
+		// Seats go to valid students in file order
+		if len(result.Errors) == 0 && row.role == "student" {
+			if remainingStudents == 0 {
+				result.Errors = append(result.Errors, "student quota exceeded")
+			} else if remainingStudents > 0 {
+				remainingStudents--
+			}
+		}
+
+		if len(result.Errors) == 0 {
+			preview.ValidRows++
+			if row.role == "student" {
+				preview.Students++
+			} else {
+				preview.Teachers++
+			}
+		} else {
+			preview.InvalidRows++
+		}
+		preview.Rows = append(preview.Rows, result)
+	}
+	return preview, nil
+}
+
+func (s *userImportService) PreviewImport(adminID uuid.UUID, fileName string, data []byte) (*ImportPreview, error) {
+	school, err := s.findAdminSchool(adminID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := parseImportFile(fileName, data)
+	if err != nil {
+		return nil, err
+	}
+	return s.validateImport(school, rows)
+}
+
+func (s *userImportService) StartImport(adminID uuid.UUID, fileName string, data []byte) (*models.UserImport, *ImportPreview, error) {
+	school, err := s.findAdminSchool(adminID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, err := parseImportFile(fileName, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	preview, err := s.validateImport(school, rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	if preview.InvalidRows > 0 {
+		return nil, preview, ErrImportHasInvalidRows
+	}
+
+	userImport := &models.UserImport{
+		SchoolID:  school.ID,
+		FileName:  fileName,
+		Status:    models.UserImportStatusPending,
+		TotalRows: len(rows),
+		CreatedBy: adminID,
+	}
+	importRows := make([]models.UserImportRow, 0, len(rows))
+	for _, row := range rows {
+		importRow := models.UserImportRow{
+			RowNumber: row.number,
+			Name:      row.name,
+			Email:     row.email,
+			RoleName:  row.role,
+			Password:  row.password,
+			Status:    models.UserImportRowStatusPending,
+		}
+		if importRow.Password == "" {
+			importRow.Password = generateImportPassword()
+			importRow.PasswordGenerated = true
+		}
+		importRows = append(importRows, importRow)
+	}
+	if err := s.importRepo.Create(userImport, importRows); err != nil {
+		return nil, nil, fmt.Errorf("failed to queue import: %w", err)
+	}
+	return userImport, preview, nil
+}
+
+// generateImportPassword returns a random password that the results file can show unchanged, that is
+// one not starting with a character a spreadsheet would read as a formula.
+func generateImportPassword() string {
+	for {
+		password := utils.GenerateRandomPassword(generatedPasswordLength)
+		if utils.EscapeSpreadsheetFormula(password) == password {
+			return password
+		}
+	}
+}
+
+func (s *userImportService) GetImport(adminID, importID uuid.UUID) (*models.UserImport, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	userImport, err := s.importRepo.FindByID(importID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("import not found")
+		}
+		return nil, fmt.Errorf("failed to find import: %w", err)
+	}
+	if !canAccessSchool(adminUser, userImport.SchoolID) {
+		return nil, errors.New("import not found")
+	}
+	return userImport, nil
+}
+
+func (s *userImportService) GetImportResults(adminID, importID uuid.UUID) (*models.UserImport, []byte, error) {
+	userImport, err := s.GetImport(adminID, importID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if userImport.Status != models.UserImportStatusCompleted {
+		return nil, nil, errors.New("import has not finished yet")
+	}
+	rows, err := s.importRepo.FindRows(userImport.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve import rows: %w", err)
+	}
+	now := time.Now()
+	withPasswords := false
+	if userImport.PasswordsClearedAt == nil {
+		// Clearing first means a concurrent download cannot receive the passwords as well
+		withPasswords, err = s.importRepo.ClearPasswords(userImport.ID, now)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to clear import passwords: %w", err)
+		}
+		if withPasswords {
+			userImport.PasswordsClearedAt = &now
+		}
+		if userImport.FinishedAt != nil && userImport.FinishedAt.Before(now.Add(-importPasswordRetention)) {
+			withPasswords = false
+		}
+	}
+
+	var buf bytes.Buffer
+	writer, err := utils.NewSpreadsheetWriter("csv", &buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	writer.WriteRow([]string{"row", "name", "email", "role", "status", "password", "error"})
+	for _, row := range rows {
+		password := ""
+		if withPasswords && row.Status == models.UserImportRowStatusCreated && row.PasswordGenerated {
+			password = row.Password
+		}
+		writer.WriteRow([]string{strconv.Itoa(row.RowNumber), row.Name, row.Email, row.RoleName, row.Status, password, row.Error})
+	}
+	if err := writer.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to write import results: %w", err)
+	}
+	return userImport, buf.Bytes(), nil
+}
+
+func (s *userImportService) ProcessPendingImports(ctx context.Context) (int, error) {
+	processed := 0
+	for ctx.Err() == nil {
+		now := time.Now()
+		userImport, err := s.importRepo.ClaimNext(now.Add(-importStaleAfter), now)
+		if err != nil {
+			return processed, fmt.Errorf("failed to claim import: %w", err)
+		}
+		if userImport == nil {
+			break
+		}
+		if err := s.processImport(ctx, userImport); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+func (s *userImportService) ClearExpiredPasswords(now time.Time) (int64, error) {
+	cleared, err := s.importRepo.ClearExpiredPasswords(now.Add(-importPasswordRetention), now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear expired import passwords: %w", err)
+	}
+	return cleared, nil
+}
+
+// processImport creates the users of the import's remaining rows. Each user goes through the same
+// checks as POST /admin/users on behalf of the admin who uploaded the file, so changes made since
+// the preview (new users, a full quota, lost permissions) fail the affected rows only.
+func (s *userImportService) processImport(ctx context.Context, userImport *models.UserImport) error {
+	rows, err := s.importRepo.FindPendingRows(userImport.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rows of import %s: %w", userImport.ID, err)
+	}
+
+	for i := range rows {
+		if ctx.Err() != nil {
+			return nil // Resumed by the next run
+		}
+		row := &rows[i]
+		user, err := s.userService.CreateTeacherOrStudent(row.Name, row.Email, row.Password, row.RoleName, userImport.CreatedBy)
+		if err != nil {
+			row.Status = models.UserImportRowStatusFailed
+			row.Error = err.Error()
+		} else {
+			row.Status = models.UserImportRowStatusCreated
+			row.UserID = &user.ID
+		}
+		if !row.PasswordGenerated || row.Status == models.UserImportRowStatusFailed {
+			row.Password = ""
+		}
+		if err := s.importRepo.FinishRow(row); err != nil {
+			return fmt.Errorf("failed to record row %d of import %s: %w", row.RowNumber, userImport.ID, err)
+		}
+	}
+
+	if err := s.importRepo.MarkCompleted(userImport.ID, time.Now()); err != nil {
+		return fmt.Errorf("failed to complete import %s: %w", userImport.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"auth-barniee/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// newCompletedImport saves a finished import of the admin's school with one created student whose
+// password was generated.
+func newCompletedImport(imports *fakeUserImportRepository, admin *models.User, finishedAt time.Time) *models.UserImport {
+	userImport := &models.UserImport{
+		SchoolID:   admin.SchoolID,
+		FileName:   "siswa.csv",
+		Status:     models.UserImportStatusCompleted,
+		TotalRows:  1,
+		FinishedAt: &finishedAt,
+		CreatedBy:  admin.ID,
+	}
+	imports.Create(userImport, []models.UserImportRow{{
+		RowNumber:         2,
+		Name:              "Budi Santoso",
+		Email:             "budi@sekolah.sch.id",
+		RoleName:          "student",
+		Password:          "Xy7pQ2mL9aBc",
+		PasswordGenerated: true,
+		Status:            models.UserImportRowStatusCreated,
+	}})
+	return userImport
+}
+
+func TestImportResultsDeliverGeneratedPasswordsOnce(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Name: "Kepala TU", Email: "tu@sekolah.sch.id", SchoolID: uuid.New()}
+	imports := newFakeUserImportRepository()
+	service := NewUserImportService(imports, newFakeUserRepository(admin), nil, nil)
+	userImport := newCompletedImport(imports, admin, time.Now())
+
+	_, first, err := service.GetImportResults(admin.ID, userImport.ID)
+	if err != nil {
+		t.Fatalf("GetImportResults returned error: %v", err)
+	}
+	if !strings.Contains(string(first), "Xy7pQ2mL9aBc") {
+		t.Fatalf("first results download is missing the generated password:\n%s", first)
+	}
+	if rows := imports.rows[userImport.ID]; rows[0].Password != "" {
+		t.Error("the generated password is still stored after it was delivered")
+	}
+
+	_, second, err := service.GetImportResults(admin.ID, userImport.ID)
+	if err != nil {
+		t.Fatalf("repeated GetImportResults returned error: %v", err)
+	}
+	if strings.Contains(string(second), "Xy7pQ2mL9aBc") {
+		t.Error("a repeated results download contained the generated password")
+	}
+	if !strings.Contains(string(second), "budi@sekolah.sch.id") {
+		t.Error("a repeated results download lost the row outcomes")
+	}
+}
+
+func TestImportResultsOmitExpiredPasswords(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Name: "Kepala TU", Email: "tu@sekolah.sch.id", SchoolID: uuid.New()}
+	imports := newFakeUserImportRepository()
+	service := NewUserImportService(imports, newFakeUserRepository(admin), nil, nil)
+	userImport := newCompletedImport(imports, admin, time.Now().Add(-importPasswordRetention-time.Hour))
+
+	_, body, err := service.GetImportResults(admin.ID, userImport.ID)
+	if err != nil {
+		t.Fatalf("GetImportResults returned error: %v", err)
+	}
+	if strings.Contains(string(body), "Xy7pQ2mL9aBc") {
+		t.Error("the results download contained a password past its retention")
+	}
+	if rows := imports.rows[userImport.ID]; rows[0].Password != "" {
+		t.Error("the expired password is still stored")
+	}
+}
+
+func TestImportResultsKeepFormulasAsText(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Name: "Kepala TU", Email: "tu@sekolah.sch.id", SchoolID: uuid.New()}
+	imports := newFakeUserImportRepository()
+	service := NewUserImportService(imports, newFakeUserRepository(admin), nil, nil)
+	userImport := newCompletedImport(imports, admin, time.Now())
+	imports.rows[userImport.ID][0].Name = `=HYPERLINK("http://evil.example","Budi")`
+
+	_, body, err := service.GetImportResults(admin.ID, userImport.ID)
+	if err != nil {
+		t.Fatalf("GetImportResults returned error: %v", err)
+	}
+	if !strings.Contains(string(body), `"'=HYPERLINK(`) {
+		t.Errorf("the results file does not escape a formula in the name:\n%s", body)
+	}
+}
+
+func TestGeneratedImportPasswordsAreNotFormulas(t *testing.T) {
+	for range 1000 {
+		if password := generateImportPassword(); strings.ContainsAny(password[:1], "=+-@") {
+			t.Fatalf("generated password %q starts with a formula character", password)
+		}
+	}
+}
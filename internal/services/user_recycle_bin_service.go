@@ -0,0 +1,126 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DeletedUser is a user in the recycle bin.
+type DeletedUser struct {
+	User            models.User `json:"user"`
+	RestorableUntil time.Time   `json:"restorable_until"` // The user is purged afterwards
+}
+
+// UserRecycleBinService manages deleted users until they are restored or purged for good.
+type UserRecycleBinService interface {
+	// GetDeletedUsers lists the recycle bin of the admin's school; super admins see every school, or the requested one.
+	GetDeletedUsers(schoolID *uuid.UUID, adminID uuid.UUID) ([]DeletedUser, error)
+	RestoreUser(userID, adminID uuid.UUID) (*models.User, error)
+	// PurgeExpired permanently deletes the users whose retention period has passed.
+	PurgeExpired(now time.Time) (int64, error)
+}
+
+type userRecycleBinService struct {
+	userRepo   repositories.UserRepository
+	roleRepo   repositories.RoleRepository
+	schoolRepo repositories.SchoolRepository
+	config     *config.Config
+}
+
+func NewUserRecycleBinService(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, schoolRepo repositories.SchoolRepository, cfg *config.Config) UserRecycleBinService {
+	return &userRecycleBinService{
+		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+		schoolRepo: schoolRepo,
+		config:     cfg,
+	}
+}
+
+func (s *userRecycleBinService) restorableUntil(user *models.User) time.Time {
+	return user.DeletedAt.Time.AddDate(0, 0, s.config.DeletedUserRetentionDays)
+}
+
+func (s *userRecycleBinService) GetDeletedUsers(schoolID *uuid.UUID, adminID uuid.UUID) ([]DeletedUser, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	targetSchoolID, err := userListSchool(adminUser, schoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.FindDeleted(targetSchoolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve deleted users: %w", err)
+	}
+	deleted := make([]DeletedUser, len(users))
+	for i := range users {
+		deleted[i] = DeletedUser{User: users[i], RestorableUntil: s.restorableUntil(&users[i])}
+	}
+	return deleted, nil
+}
+
+func (s *userRecycleBinService) RestoreUser(userID, adminID uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.FindDeletedByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("deleted user not found")
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	canDelete, err := roleHasPermission(s.roleRepo, adminUser.RoleID, models.PermissionUsersDelete)
+	if err != nil {
+		return nil, err
+	}
+	if !canDelete {
+		return nil, errors.New("unauthorized: you do not have permission to restore users")
+	}
+	if !canManageSchool(s.schoolRepo, adminUser, user.SchoolID) {
+		return nil, errors.New("unauthorized: school admin cannot restore users outside their school")
+	}
+	if time.Now().After(s.restorableUntil(user)) {
+		return nil, errors.New("user can no longer be restored")
+	}
+
+	// Seats freed by the deletion may have been taken since
+	if user.Role.SystemName() == "student" && user.SchoolID != uuid.Nil {
+		err = s.userRepo.RestoreWithinStudentQuota(user)
+	} else {
+		err = s.userRepo.Restore(user.ID)
+	}
+	if err != nil {
+		var quotaErr *repositories.StudentQuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return nil, quotaErr
+		}
+		return nil, fmt.Errorf("failed to restore user: %w", err)
+	}
+
+	restored, err := s.userRepo.FindByID(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find restored user: %w", err)
+	}
+	return restored, nil
+}
+
+func (s *userRecycleBinService) PurgeExpired(now time.Time) (int64, error) {
+	purged, err := s.userRepo.PurgeDeleted(now.AddDate(0, 0, -s.config.DeletedUserRetentionDays))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted users: %w", err)
+	}
+	return purged, nil
+}
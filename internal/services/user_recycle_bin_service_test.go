@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"auth-barniee/internal/config"
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+const testDeletedUserRetentionDays = 30
+
+type recycleBinFixture struct {
+	*userCreationFixture
+	recycleBin UserRecycleBinService
+}
+
+func newRecycleBinFixture() *recycleBinFixture {
+	f := newUserCreationFixture()
+	return &recycleBinFixture{
+		userCreationFixture: f,
+		recycleBin: NewUserRecycleBinService(f.users, f.users.roles, f.users.schools,
+			&config.Config{DeletedUserRetentionDays: testDeletedUserRetentionDays}),
+	}
+}
+
+// deleteUser creates a user and moves it to the recycle bin as if it had been deleted at deletedAt.
+func (f *recycleBinFixture) deleteUser(t *testing.T, email, roleName string, deletedAt time.Time) *models.User {
+	t.Helper()
+	user, err := f.service.CreateTeacherOrStudent("Budi", email, "secret123", roleName, f.admin.ID)
+	if err != nil {
+		t.Fatalf("creating %s returned error: %v", email, err)
+	}
+	if err := f.service.DeleteUser(user.ID, f.admin.ID); err != nil {
+		t.Fatalf("deleting %s returned error: %v", email, err)
+	}
+	f.users.users[user.ID].DeletedAt.Time = deletedAt
+	return user
+}
+
+func TestDeleteUserMovesItToTheRecycleBin(t *testing.T) {
+	f := newRecycleBinFixture()
+	user := f.deleteUser(t, "budi@sekolah.sch.id", "teacher", time.Now())
+
+	if _, err := f.users.FindByID(user.ID); err == nil {
+		t.Error("the deleted user is still found")
+	}
+	deleted, err := f.users.FindDeletedByID(user.ID)
+	if err != nil {
+		t.Fatalf("the deleted user is not in the recycle bin: %v", err)
+	}
+	if deleted.DeletedBy == nil || *deleted.DeletedBy != f.admin.ID {
+		t.Errorf("deleted by = %v, want the admin", deleted.DeletedBy)
+	}
+	if _, err := f.service.CreateTeacherOrStudent("Budi", "budi@sekolah.sch.id", "secret123", "teacher", f.admin.ID); err == nil {
+		t.Error("the email of a user in the recycle bin was reused")
+	}
+}
+
+func TestRestoreUserWithinRetention(t *testing.T) {
+	f := newRecycleBinFixture()
+	user := f.deleteUser(t, "budi@sekolah.sch.id", "teacher", time.Now().AddDate(0, 0, -testDeletedUserRetentionDays+1))
+
+	restored, err := f.recycleBin.RestoreUser(user.ID, f.admin.ID)
+	if err != nil {
+		t.Fatalf("RestoreUser returned error: %v", err)
+	}
+	if restored.DeletedAt.Valid || restored.DeletedBy != nil {
+		t.Errorf("restored user still marked deleted: %+v", restored.DeletedAt)
+	}
+}
+
+func TestRestoreUserAfterRetentionIsRefused(t *testing.T) {
+	f := newRecycleBinFixture()
+	user := f.deleteUser(t, "budi@sekolah.sch.id", "teacher", time.Now().AddDate(0, 0, -testDeletedUserRetentionDays-1))
+
+	if _, err := f.recycleBin.RestoreUser(user.ID, f.admin.ID); err == nil || err.Error() != "user can no longer be restored" {
+		t.Fatalf("error = %v, want the retention period reported as over", err)
+	}
+	if _, err := f.users.FindByID(user.ID); err == nil {
+		t.Error("the user was restored after the retention period")
+	}
+}
+
+func TestRestoreStudentOverTheQuotaIsRefused(t *testing.T) {
+	f := newRecycleBinFixture()
+	f.school.MaxStudentsAllowed = 1
+	student := f.deleteUser(t, "siswa@sekolah.sch.id", "student", time.Now())
+	// The freed seat is taken while the student is in the recycle bin
+	f.fillStudentSeats(t, 1)
+
+	_, err := f.recycleBin.RestoreUser(student.ID, f.admin.ID)
+	var quotaErr *repositories.StudentQuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("error = %v, want the student quota reported as exceeded", err)
+	}
+	if _, err := f.users.FindDeletedByID(student.ID); err != nil {
+		t.Error("the student left the recycle bin although the quota was full")
+	}
+}
+
+func TestRestoreUserRequiresPermissionOverTheSchool(t *testing.T) {
+	f := newRecycleBinFixture()
+	user := f.deleteUser(t, "budi@sekolah.sch.id", "teacher", time.Now())
+	otherSchool := &models.School{ID: uuid.New(), Name: "SMP Lain"}
+	f.users.schools.schools[otherSchool.ID] = otherSchool
+	otherAdmin := &models.User{ID: uuid.New(), Email: "tu@smp.sch.id", RoleID: f.admin.RoleID, Role: f.admin.Role, SchoolID: otherSchool.ID}
+	f.users.users[otherAdmin.ID] = otherAdmin
+
+	if _, err := f.recycleBin.RestoreUser(user.ID, otherAdmin.ID); err == nil {
+		t.Error("an admin of another school restored the user")
+	}
+}
+
+func TestPurgeExpiredRemovesOnlyUsersPastRetention(t *testing.T) {
+	f := newRecycleBinFixture()
+	now := time.Now()
+	expired := f.deleteUser(t, "lama@sekolah.sch.id", "teacher", now.AddDate(0, 0, -testDeletedUserRetentionDays-1))
+	recent := f.deleteUser(t, "baru@sekolah.sch.id", "teacher", now.AddDate(0, 0, -1))
+
+	purged, err := f.recycleBin.PurgeExpired(now)
+	if err != nil {
+		t.Fatalf("PurgeExpired returned error: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("purged = %d, want 1", purged)
+	}
+	if _, ok := f.users.users[expired.ID]; ok {
+		t.Error("the user past retention was kept")
+	}
+	if _, err := f.users.FindDeletedByID(recent.ID); err != nil {
+		t.Error("the user still within retention was purged")
+	}
+}
@@ -1,8 +1,13 @@
 package services
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"auth-barniee/internal/models"
 	"auth-barniee/internal/repositories"
@@ -14,33 +19,143 @@ import (
 
 type UserService interface {
 	CreateTeacherOrStudent(name, email, password, roleName string, adminID uuid.UUID) (*models.User, error)
-	GetAllUsers(roleName string, adminUserID uuid.UUID) ([]models.User, error) // Added adminUserID
-	GetUserByID(userID uuid.UUID) (*models.User, error)
+	GetAllUsers(query UserListQuery, adminUserID uuid.UUID) (*UserListPage, error) // Added adminUserID
+	GetUserByID(userID, adminID uuid.UUID) (*models.User, error)
 	UpdateUser(userID, adminID uuid.UUID, name, email *string, roleName *string) (*models.User, error)
 	DeleteUser(userID, adminID uuid.UUID) error
+	GetStudentUsage(adminID uuid.UUID) (*StudentUsage, error)
+	// Organization admins manage the users of all campuses of their organization.
+	GetOrganizationUsers(roleName string, schoolID *uuid.UUID, adminID uuid.UUID) ([]models.User, error)
+	CreateCampusUser(name, email, password, roleName string, schoolID, adminID uuid.UUID) (*models.User, error)
+	TransferTeacher(userID, targetSchoolID, adminID uuid.UUID) (*models.User, error)
+	// ExportUsers writes the users GetAllUsers would return, optionally limited to one school for
+	// super admins, with the chosen columns. open is called only once the request is valid, so
+	// errors returned before that can still be sent to the client as JSON.
+	ExportUsers(roleName string, schoolID *uuid.UUID, columns []string, adminID uuid.UUID, open func() (utils.SpreadsheetWriter, error)) error
 }
 
+// UserExportColumns are the columns an export may contain, in the default order.
+var UserExportColumns = []string{"id", "name", "email", "role", "status", "position", "whatsapp_number", "school_id", "created_at"}
+
+var userExportValues = map[string]func(user *models.User) string{
+	"id":              func(user *models.User) string { return user.ID.String() },
+	"name":            func(user *models.User) string { return user.Name },
+	"email":           func(user *models.User) string { return user.Email },
+	"role":            func(user *models.User) string { return user.Role.Name },
+	"status":          func(user *models.User) string { return user.Status },
+	"position":        func(user *models.User) string { return user.Position },
+	"whatsapp_number": func(user *models.User) string { return user.WhatsappNumber },
+	"school_id": func(user *models.User) string {
+		if user.SchoolID == uuid.Nil {
+			return ""
+		}
+		return user.SchoolID.String()
+	},
+	"created_at": func(user *models.User) string { return user.CreatedAt.Format(time.RFC3339) },
+}
+
+const userExportBatchSize = 500
+
+const (
+	defaultUserListLimit = 50
+	maxUserListLimit     = 200
+)
+
+// UserListQuery selects the users returned by GetAllUsers.
+type UserListQuery struct {
+	RoleName      string
+	Status        string
+	SchoolID      *uuid.UUID // Only honoured for super admins
+	Search        string     // Matched against name and email
+	CreatedFrom   *time.Time
+	CreatedBefore *time.Time
+	SortBy        string // name (default), email or created_at
+	Desc          bool
+	Limit         int
+	Cursor        string // NextCursor of the previous page
+}
+
+// UserListPage is one page of a user list.
+type UserListPage struct {
+	Users      []models.User
+	Total      int64  // Users matching the filters, over all pages
+	NextCursor string // Empty on the last page
+}
+
+// userListCursor is the opaque cursor handed to clients. It records the sort it was made for,
+// so it can't be replayed against a different order.
+type userListCursor struct {
+	SortBy string    `json:"s"`
+	Desc   bool      `json:"d,omitempty"`
+	Value  string    `json:"v"`
+	ID     uuid.UUID `json:"id"`
+}
+
+// StudentUsage reports how many of a school's student seats are taken.
+type StudentUsage struct {
+	StudentsUsed       int64  `json:"students_used" example:"42"`
+	MaxStudentsAllowed int    `json:"max_students_allowed" example:"50"` // 0 means unlimited
+	Unlimited          bool   `json:"unlimited" example:"false"`
+	Remaining          *int64 `json:"remaining,omitempty" example:"8"`
+}
+
+// creatableRoles are the roles a school admin may create accounts with. Admin accounts are
+// created by registering the school or by an organization admin, see campusCreatableRoles.
+var creatableRoles = []string{"teacher", "student", "parent"}
+
+// campusCreatableRoles are the roles an organization admin may create at a campus.
+var campusCreatableRoles = []string{"teacher", "student", "parent", "admin"}
+
 type userService struct {
-	userRepo repositories.UserRepository
-	roleRepo repositories.RoleRepository
+	userRepo   repositories.UserRepository
+	roleRepo   repositories.RoleRepository
+	schoolRepo repositories.SchoolRepository
+	uow        repositories.UnitOfWork
 }
 
-func NewUserService(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository) UserService {
+func NewUserService(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, schoolRepo repositories.SchoolRepository, uow repositories.UnitOfWork) UserService {
 	return &userService{
-		userRepo: userRepo,
-		roleRepo: roleRepo,
+		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+		schoolRepo: schoolRepo,
+		uow:        uow,
 	}
 }
 
+// saveUserRole persists a role change, going through the student quota when the user becomes a student.
+func saveUserRole(userRepo repositories.UserRepository, user *models.User, wasStudent bool, newRole *models.Role) error {
+	if newRole.SystemName() == "student" && !wasStudent {
+		return userRepo.UpdateWithinStudentQuota(user)
+	}
+	return userRepo.Update(user)
+}
+
 func (s *userService) CreateTeacherOrStudent(name, email, password, roleName string, adminID uuid.UUID) (*models.User, error) {
-	existingUser, err := s.userRepo.FindByEmail(email)
-	if err == nil && existingUser != nil {
-		return nil, errors.New("user with this email already exists")
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
 	}
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, fmt.Errorf("failed to check existing user: %w", err)
+	if adminUser.SchoolID == uuid.Nil {
+		return nil, errors.New("admin is not associated with a school")
 	}
+	return s.createUser(name, email, password, roleName, creatableRoles, adminUser, adminUser.SchoolID) // Same school as the admin who created it
+}
 
+func (s *userService) CreateCampusUser(name, email, password, roleName string, schoolID, adminID uuid.UUID) (*models.User, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	if !canManageSchool(s.schoolRepo, adminUser, schoolID) {
+		return nil, errors.New("school not found")
+	}
+	return s.createUser(name, email, password, roleName, campusCreatableRoles, adminUser, schoolID)
+}
+
+func (s *userService) createUser(name, email, password, roleName string, allowedRoles []string, adminUser *models.User, schoolID uuid.UUID) (*models.User, error) {
+	if !slices.Contains(allowedRoles, roleName) {
+		return nil, fmt.Errorf("can only create users with roles: %s", strings.Join(allowedRoles, ", "))
+	}
 	role, err := s.roleRepo.FindByName(roleName)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -49,8 +164,15 @@ func (s *userService) CreateTeacherOrStudent(name, email, password, roleName str
 		return nil, fmt.Errorf("failed to find role: %w", err)
 	}
 
-	if role.Name != "teacher" && role.Name != "student" {
-		return nil, errors.New("can only create users with 'teacher' or 'student' roles")
+	canCreate, err := roleHasPermission(s.roleRepo, adminUser.RoleID, models.PermissionUsersCreate)
+	if err != nil {
+		return nil, err
+	}
+	if !canCreate {
+		return nil, errors.New("unauthorized: you do not have permission to create users")
+	}
+	if err := ensureCanAssignRole(s.roleRepo, adminUser.RoleID, role.ID); err != nil {
+		return nil, err
 	}
 
 	hashedPassword, err := utils.HashPassword(password)
@@ -58,65 +180,179 @@ func (s *userService) CreateTeacherOrStudent(name, email, password, roleName str
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	adminUser, err := s.userRepo.FindByID(adminID)
-	if err != nil {
-		return nil, fmt.Errorf("admin user not found: %w", err)
-	}
-	if adminUser.Role.Name != "admin" {
-		return nil, errors.New("only administrators can create new teachers or students")
-	}
-
 	user := &models.User{
 		Name:      name,
 		Email:     email,
 		Password:  hashedPassword,
 		RoleID:    role.ID,
-		SchoolID:  adminUser.SchoolID, // Assign to the same school as the admin who created it
-		CreatedBy: adminID,
+		SchoolID:  schoolID,
+		CreatedBy: adminUser.ID,
 	}
 
-	err = s.userRepo.Create(user)
+	err = s.uow.Do(func(repos repositories.Repositories) error {
+		existingUser, err := repos.Users.FindByEmailIncludingDeleted(email)
+		if err == nil && existingUser != nil {
+			return errors.New("user with this email already exists")
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to check existing user: %w", err)
+		}
+
+		if role.SystemName() == "student" {
+			err = repos.Users.CreateWithinStudentQuota(user)
+		} else {
+			err = repos.Users.Create(user)
+		}
+		if err != nil {
+			var quotaErr *repositories.StudentQuotaExceededError
+			if errors.As(err, &quotaErr) {
+				return quotaErr
+			}
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+		return nil, err
 	}
 	return user, nil
 }
 
-// GetAllUsers now filters by the admin's school ID, unless it's a master admin.
-func (s *userService) GetAllUsers(roleName string, adminUserID uuid.UUID) ([]models.User, error) {
+// GetAllUsers filters by the admin's school ID, unless it's a super admin.
+func (s *userService) GetAllUsers(query UserListQuery, adminUserID uuid.UUID) (*UserListPage, error) {
 	adminUser, err := s.userRepo.FindByID(adminUserID)
 	if err != nil {
 		return nil, fmt.Errorf("admin user not found: %w", err)
 	}
 
-	var targetRoleID *uuid.UUID
-	if roleName != "" {
-		role, err := s.roleRepo.FindByName(roleName)
+	if query.SortBy == "" {
+		query.SortBy = repositories.UserSortName
+	}
+	switch query.SortBy {
+	case repositories.UserSortName, repositories.UserSortEmail, repositories.UserSortCreatedAt:
+	default:
+		return nil, errors.New("invalid sort, expected name, email or created_at")
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultUserListLimit
+	}
+	if query.Limit > maxUserListLimit {
+		query.Limit = maxUserListLimit
+	}
+	page := repositories.UserPage{SortBy: query.SortBy, Desc: query.Desc, Limit: query.Limit + 1} // One extra row tells whether there is a next page
+	if query.Cursor != "" {
+		after, err := decodeUserListCursor(query.Cursor, query.SortBy, query.Desc)
 		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, fmt.Errorf("role '%s' not found", roleName)
-			}
-			return nil, fmt.Errorf("failed to find role: %w", err)
+			return nil, err
 		}
-		targetRoleID = &role.ID
+		page.After = after
 	}
 
-	var targetSchoolID *uuid.UUID
-	// If it's a school admin (not master admin), filter by their school_id
-	if adminUser.SchoolID != uuid.Nil {
-		targetSchoolID = &adminUser.SchoolID
+	targetRoleID, err := s.findRoleFilter(query.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	targetSchoolID, err := userListSchool(adminUser, query.SchoolID)
+	if err != nil {
+		return nil, err
+	}
+	filter := repositories.UserFilter{
+		RoleID:        targetRoleID,
+		SchoolID:      targetSchoolID,
+		Status:        query.Status,
+		Search:        query.Search,
+		CreatedFrom:   query.CreatedFrom,
+		CreatedBefore: query.CreatedBefore,
 	}
-	// If it's a master admin (SchoolID is nil), they can see all users across all schools
-	// This logic is implicitly handled if targetSchoolID remains nil.
 
-	users, err := s.userRepo.FindAll(targetRoleID, targetSchoolID)
+	users, err := s.userRepo.FindPage(filter, page)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve users: %w", err)
 	}
-	return users, nil
+	total, err := s.userRepo.Count(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	result := &UserListPage{Users: users, Total: total}
+	if len(users) > query.Limit {
+		result.Users = users[:query.Limit]
+		result.NextCursor = encodeUserListCursor(&result.Users[query.Limit-1], query.SortBy, query.Desc)
+	}
+	return result, nil
+}
+
+func encodeUserListCursor(last *models.User, sortBy string, desc bool) string {
+	cursor := userListCursor{SortBy: sortBy, Desc: desc, ID: last.ID}
+	switch sortBy {
+	case repositories.UserSortName:
+		cursor.Value = last.Name
+	case repositories.UserSortEmail:
+		cursor.Value = last.Email
+	case repositories.UserSortCreatedAt:
+		cursor.Value = last.CreatedAt.Format(time.RFC3339Nano)
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
 }
 
-func (s *userService) GetUserByID(userID uuid.UUID) (*models.User, error) {
+func decodeUserListCursor(encoded, sortBy string, desc bool) (*repositories.UserCursor, error) {
+	var cursor userListCursor
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || json.Unmarshal(data, &cursor) != nil || cursor.ID == uuid.Nil {
+		return nil, errors.New("invalid cursor")
+	}
+	if cursor.SortBy != sortBy || cursor.Desc != desc {
+		return nil, errors.New("cursor does not match the requested sort")
+	}
+	after := &repositories.UserCursor{Value: cursor.Value, ID: cursor.ID}
+	if sortBy == repositories.UserSortCreatedAt {
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+		after.Value = createdAt
+	}
+	return after, nil
+}
+
+// findRoleFilter resolves the system role a user list is filtered by; the repository also matches the
+// custom roles built on it. An empty name means every role.
+func (s *userService) findRoleFilter(roleName string) (*uuid.UUID, error) {
+	if roleName == "" {
+		return nil, nil
+	}
+	role, err := s.roleRepo.FindByName(roleName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("role '%s' not found", roleName)
+		}
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+	return &role.ID, nil
+}
+
+// userListSchool returns the school a user list of the admin is limited to. A super admin sees users
+// across all schools, or of the requested one; everyone else only sees their own school.
+func userListSchool(adminUser *models.User, requestedSchoolID *uuid.UUID) (*uuid.UUID, error) {
+	if isSuperAdmin(adminUser) {
+		return requestedSchoolID, nil
+	}
+	if adminUser.SchoolID == uuid.Nil {
+		return nil, errors.New("admin is not associated with a school")
+	}
+	if requestedSchoolID != nil && *requestedSchoolID != adminUser.SchoolID {
+		return nil, errors.New("school not found")
+	}
+	return &adminUser.SchoolID, nil
+}
+
+func (s *userService) GetUserByID(userID, adminID uuid.UUID) (*models.User, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -124,6 +360,10 @@ func (s *userService) GetUserByID(userID uuid.UUID) (*models.User, error) {
 		}
 		return nil, fmt.Errorf("failed to retrieve user: %w", err)
 	}
+	// Users of other schools are reported as missing to school admins
+	if !canManageSchool(s.schoolRepo, adminUser, user.SchoolID) {
+		return nil, errors.New("user not found")
+	}
 	return user, nil
 }
 
@@ -141,26 +381,30 @@ func (s *userService) UpdateUser(userID, adminID uuid.UUID, name, email *string,
 		return nil, fmt.Errorf("admin user not found: %w", err)
 	}
 
-	// Authorization check: master admin can update any user; school admin can only update users within their school.
-	if adminUser.Role.Name == "admin" && adminUser.SchoolID != uuid.Nil && user.SchoolID != adminUser.SchoolID {
+	canUpdate, err := roleHasPermission(s.roleRepo, adminUser.RoleID, models.PermissionUsersUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if !canUpdate {
+		return nil, errors.New("unauthorized: you do not have permission to update users")
+	}
+	// Authorization check: super admin can update any user; school admin can only update users within their school.
+	if !canManageSchool(s.schoolRepo, adminUser, user.SchoolID) {
 		return nil, errors.New("unauthorized: school admin cannot update users outside their school")
 	}
+	if err := ensureCanChangeUser(s.roleRepo, s.schoolRepo, adminUser, user); err != nil {
+		return nil, err
+	}
 
+	emailChanged := email != nil && *email != user.Email
 	if name != nil {
 		user.Name = *name
 	}
 	if email != nil {
-		if *email != user.Email {
-			existingUser, err := s.userRepo.FindByEmail(*email)
-			if err == nil && existingUser != nil && existingUser.ID != user.ID {
-				return nil, errors.New("email already taken by another user")
-			}
-			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, fmt.Errorf("failed to check existing email: %w", err)
-			}
-		}
 		user.Email = *email
 	}
+	wasStudent := user.Role.SystemName() == "student"
+	var newRole *models.Role
 	if roleName != nil {
 		role, err := s.roleRepo.FindByName(*roleName)
 		if err != nil {
@@ -169,13 +413,43 @@ func (s *userService) UpdateUser(userID, adminID uuid.UUID, name, email *string,
 			}
 			return nil, fmt.Errorf("failed to find role: %w", err)
 		}
+		if err := ensureCanAssignRole(s.roleRepo, adminUser.RoleID, role.ID); err != nil {
+			return nil, err
+		}
 		user.RoleID = role.ID
+		user.Role = *role // Save writes the preloaded association back, so keep it in sync with RoleID
+		newRole = role
 	}
 	user.UpdatedBy = adminID
 
-	err = s.userRepo.Update(user)
+	err = s.uow.Do(func(repos repositories.Repositories) error {
+		if emailChanged {
+			existingUser, err := repos.Users.FindByEmailIncludingDeleted(user.Email)
+			if err == nil && existingUser != nil && existingUser.ID != user.ID {
+				return errors.New("email already taken by another user")
+			}
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("failed to check existing email: %w", err)
+			}
+		}
+
+		var err error
+		if newRole != nil {
+			err = saveUserRole(repos.Users, user, wasStudent, newRole)
+		} else {
+			err = repos.Users.Update(user)
+		}
+		if err != nil {
+			var quotaErr *repositories.StudentQuotaExceededError
+			if errors.As(err, &quotaErr) {
+				return quotaErr
+			}
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update user: %w", err)
+		return nil, err
 	}
 	return user, nil
 }
@@ -194,13 +468,192 @@ func (s *userService) DeleteUser(userID, adminID uuid.UUID) error {
 		return fmt.Errorf("admin user not found: %w", err)
 	}
 
-	if adminUser.Role.Name == "admin" && adminUser.SchoolID != uuid.Nil && user.SchoolID != adminUser.SchoolID {
+	canDelete, err := roleHasPermission(s.roleRepo, adminUser.RoleID, models.PermissionUsersDelete)
+	if err != nil {
+		return err
+	}
+	if !canDelete {
+		return errors.New("unauthorized: you do not have permission to delete users")
+	}
+	if !canManageSchool(s.schoolRepo, adminUser, user.SchoolID) {
 		return errors.New("unauthorized: school admin cannot delete users outside their school")
 	}
 
 	if user.ID == adminID {
 		return errors.New("cannot delete your own admin account")
 	}
+	if err := ensureCanChangeUser(s.roleRepo, s.schoolRepo, adminUser, user); err != nil {
+		return err
+	}
 
-	return s.userRepo.Delete(user.ID)
+	return s.userRepo.Delete(user.ID, adminID)
+}
+
+func (s *userService) GetStudentUsage(adminID uuid.UUID) (*StudentUsage, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	if adminUser.SchoolID == uuid.Nil {
+		return nil, errors.New("admin is not associated with a school")
+	}
+
+	school, err := s.schoolRepo.FindByID(adminUser.SchoolID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("school not found")
+		}
+		return nil, fmt.Errorf("failed to find school: %w", err)
+	}
+	used, err := s.userRepo.CountStudentsBySchoolID(school.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count students: %w", err)
+	}
+
+	usage := &StudentUsage{
+		StudentsUsed:       used,
+		MaxStudentsAllowed: school.MaxStudentsAllowed,
+		Unlimited:          school.MaxStudentsAllowed == 0,
+	}
+	if !usage.Unlimited {
+		remaining := max(int64(school.MaxStudentsAllowed)-used, 0)
+		usage.Remaining = &remaining
+	}
+	return usage, nil
+}
+
+func (s *userService) GetOrganizationUsers(roleName string, schoolID *uuid.UUID, adminID uuid.UUID) ([]models.User, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	if adminUser.OrganizationID == nil {
+		return nil, errors.New("admin is not associated with an organization")
+	}
+
+	targetRoleID, err := s.findRoleFilter(roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.FindAllInOrganization(*adminUser.OrganizationID, targetRoleID, schoolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve users: %w", err)
+	}
+	return users, nil
+}
+
+// TransferTeacher moves a teacher to another campus of the same organization. A custom role of the
+// old campus does not exist at the new one, so the teacher falls back to the system teacher role.
+func (s *userService) TransferTeacher(userID, targetSchoolID, adminID uuid.UUID) (*models.User, error) {
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	canTransfer, err := roleHasPermission(s.roleRepo, adminUser.RoleID, models.PermissionOrganizationManage)
+	if err != nil {
+		return nil, err
+	}
+	if !canTransfer {
+		return nil, errors.New("unauthorized: you do not have permission to transfer teachers")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if !canManageSchool(s.schoolRepo, adminUser, user.SchoolID) {
+		return nil, errors.New("user not found")
+	}
+	if user.Role.SystemName() != "teacher" {
+		return nil, errors.New("only teachers can be transferred between campuses")
+	}
+	if user.SchoolID == targetSchoolID {
+		return nil, errors.New("teacher is already at this campus")
+	}
+
+	source, err := s.schoolRepo.FindByID(user.SchoolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find school: %w", err)
+	}
+	target, err := s.schoolRepo.FindByID(targetSchoolID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("school not found")
+		}
+		return nil, fmt.Errorf("failed to find school: %w", err)
+	}
+	if source.OrganizationID == nil || target.OrganizationID == nil || *source.OrganizationID != *target.OrganizationID {
+		return nil, errors.New("teachers can only be transferred between campuses of the same organization")
+	}
+	if !canManageSchool(s.schoolRepo, adminUser, target.ID) {
+		return nil, errors.New("school not found")
+	}
+
+	if user.Role.SchoolID != nil {
+		teacherRole, err := s.roleRepo.FindByName("teacher")
+		if err != nil {
+			return nil, fmt.Errorf("failed to find role: %w", err)
+		}
+		user.RoleID = teacherRole.ID
+		user.Role = *teacherRole // Save writes the preloaded association back, so keep it in sync with RoleID
+	}
+	user.SchoolID = target.ID
+	user.UpdatedBy = adminID
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to transfer teacher: %w", err)
+	}
+	return user, nil
+}
+
+func (s *userService) ExportUsers(roleName string, schoolID *uuid.UUID, columns []string, adminID uuid.UUID, open func() (utils.SpreadsheetWriter, error)) error {
+	if len(columns) == 0 {
+		columns = UserExportColumns
+	}
+	for _, column := range columns {
+		if _, ok := userExportValues[column]; !ok {
+			return fmt.Errorf("unknown export column '%s'", column)
+		}
+	}
+
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return fmt.Errorf("admin user not found: %w", err)
+	}
+	targetRoleID, err := s.findRoleFilter(roleName)
+	if err != nil {
+		return err
+	}
+	targetSchoolID, err := userListSchool(adminUser, schoolID)
+	if err != nil {
+		return err
+	}
+
+	writer, err := open()
+	if err != nil {
+		return err
+	}
+	if err := writer.WriteRow(columns); err != nil {
+		return err
+	}
+	filter := repositories.UserFilter{RoleID: targetRoleID, SchoolID: targetSchoolID}
+	err = s.userRepo.FindAllInBatches(filter, userExportBatchSize, func(users []models.User) error {
+		for i := range users {
+			row := make([]string, len(columns))
+			for j, column := range columns {
+				row[j] = userExportValues[column](&users[i])
+			}
+			if err := writer.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		return writer.Flush()
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export users: %w", err)
+	}
+	return writer.Close()
 }
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type userCreationFixture struct {
+	service  UserService
+	users    *fakeUserRepository
+	school   *models.School
+	admin    *models.User
+	orgAdmin *models.User
+	campusID uuid.UUID
+}
+
+func newUserCreationFixture() *userCreationFixture {
+	adminPermissions := []string{
+		models.PermissionUsersRead, models.PermissionUsersCreate, models.PermissionUsersUpdate, models.PermissionUsersDelete,
+		models.PermissionSchoolRead, models.PermissionSchoolUpdate, models.PermissionGuardiansManage, models.PermissionRolesManage,
+		models.PermissionBillingRead, models.PermissionBillingManage,
+	}
+	orgAdminRole := &models.Role{ID: uuid.New(), Name: "org_admin"}
+	adminRole := &models.Role{ID: uuid.New(), Name: "admin"}
+	teacherRole := &models.Role{ID: uuid.New(), Name: "teacher"}
+	parentRole := &models.Role{ID: uuid.New(), Name: "parent"}
+	studentRole := &models.Role{ID: uuid.New(), Name: "student"}
+	roles := &fakeRoleRepository{
+		roles: []*models.Role{orgAdminRole, adminRole, teacherRole, parentRole, studentRole},
+		permissions: map[uuid.UUID][]string{
+			orgAdminRole.ID: append([]string{models.PermissionOrganizationManage}, adminPermissions...),
+			adminRole.ID:    adminPermissions,
+			parentRole.ID:   {models.PermissionStudentsReadLinked},
+		},
+	}
+
+	organizationID := uuid.New()
+	school := &models.School{ID: uuid.New(), Name: "SMA Barniee"}
+	campus := &models.School{ID: uuid.New(), Name: "SMA Barniee Bandung", OrganizationID: &organizationID}
+	admin := &models.User{ID: uuid.New(), Email: "tu@sekolah.sch.id", RoleID: adminRole.ID, Role: *adminRole, SchoolID: school.ID}
+	orgAdmin := &models.User{ID: uuid.New(), Email: "yayasan@sekolah.sch.id", RoleID: orgAdminRole.ID, Role: *orgAdminRole,
+		OrganizationID: &organizationID}
+
+	users := newFakeUserRepository(admin, orgAdmin)
+	schools := newFakeSchoolRepository(school, campus)
+	users.roles, users.schools = roles, schools
+	return &userCreationFixture{
+		service:  NewUserService(users, roles, schools, &fakeUnitOfWork{repos: repositories.Repositories{Users: users}}),
+		users:    users,
+		school:   schools.schools[school.ID],
+		admin:    admin,
+		orgAdmin: orgAdmin,
+		campusID: campus.ID,
+	}
+}
+
+func TestCreateTeacherOrStudentCreatesParents(t *testing.T) {
+	f := newUserCreationFixture()
+
+	parent, err := f.service.CreateTeacherOrStudent("Ibu Siti", "ibu.siti@example.com", "secret123", "parent", f.admin.ID)
+	if err != nil {
+		t.Fatalf("creating a parent returned error: %v", err)
+	}
+	if parent.SchoolID != f.admin.SchoolID {
+		t.Errorf("parent school = %s, want the admin's school", parent.SchoolID)
+	}
+}
+
+func TestCreateTeacherOrStudentRejectsOtherRoles(t *testing.T) {
+	f := newUserCreationFixture()
+
+	for _, roleName := range []string{"admin", "org_admin", "super_admin"} {
+		if _, err := f.service.CreateTeacherOrStudent("Budi", roleName+"@example.com", "secret123", roleName, f.admin.ID); err == nil {
+			t.Errorf("school admin created a %s account", roleName)
+		}
+	}
+	if len(f.users.users) != 2 {
+		t.Errorf("users = %d, want no new users", len(f.users.users))
+	}
+}
+
+func TestCreateCampusUserAppointsCampusAdmin(t *testing.T) {
+	f := newUserCreationFixture()
+
+	if _, err := f.service.CreateCampusUser("Kepala TU", "tu.bandung@example.com", "secret123", "admin", f.campusID, f.orgAdmin.ID); err != nil {
+		t.Fatalf("organization admin could not appoint a campus admin: %v", err)
+	}
+	if _, err := f.service.CreateCampusUser("Yayasan", "yayasan2@example.com", "secret123", "org_admin", f.campusID, f.orgAdmin.ID); err == nil {
+		t.Error("organization admin created another organization admin at a campus")
+	}
+}
+
+// fillStudentSeats creates students until the school has count of them.
+func (f *userCreationFixture) fillStudentSeats(t *testing.T, count int) {
+	t.Helper()
+	for i := range count {
+		email := fmt.Sprintf("siswa%d@sekolah.sch.id", i)
+		if _, err := f.service.CreateTeacherOrStudent("Siswa", email, "secret123", "student", f.admin.ID); err != nil {
+			t.Fatalf("creating student %d of %d returned error: %v", i+1, count, err)
+		}
+	}
+}
+
+func TestCreateStudentUpToTheQuota(t *testing.T) {
+	f := newUserCreationFixture()
+	f.school.MaxStudentsAllowed = 3
+
+	f.fillStudentSeats(t, 3)
+	usage, err := f.service.GetStudentUsage(f.admin.ID)
+	if err != nil {
+		t.Fatalf("GetStudentUsage returned error: %v", err)
+	}
+	if usage.StudentsUsed != 3 || usage.Remaining == nil || *usage.Remaining != 0 {
+		t.Errorf("usage = %d used, %v remaining; want 3 used and none remaining", usage.StudentsUsed, usage.Remaining)
+	}
+}
+
+func TestCreateStudentOverTheQuotaIsRefused(t *testing.T) {
+	f := newUserCreationFixture()
+	f.school.MaxStudentsAllowed = 2
+	f.fillStudentSeats(t, 2)
+
+	_, err := f.service.CreateTeacherOrStudent("Siswa Baru", "baru@sekolah.sch.id", "secret123", "student", f.admin.ID)
+	var quotaErr *repositories.StudentQuotaExceededError
+	if !errors.As(err, &quotaErr) || quotaErr.Used != 2 || quotaErr.Limit != 2 {
+		t.Fatalf("error = %v, want the quota of 2 reported as exceeded", err)
+	}
+	if _, err := f.users.FindByEmail("baru@sekolah.sch.id"); err == nil {
+		t.Error("the student over the quota was saved")
+	}
+}
+
+func TestCreateStudentWithoutAMaximumIsUnlimited(t *testing.T) {
+	f := newUserCreationFixture()
+	f.school.MaxStudentsAllowed = 0
+
+	f.fillStudentSeats(t, 25)
+	usage, err := f.service.GetStudentUsage(f.admin.ID)
+	if err != nil {
+		t.Fatalf("GetStudentUsage returned error: %v", err)
+	}
+	if !usage.Unlimited || usage.Remaining != nil {
+		t.Errorf("usage = %+v, want unlimited", usage)
+	}
+}
+
+func TestTeachersDoNotTakeStudentSeats(t *testing.T) {
+	f := newUserCreationFixture()
+	f.school.MaxStudentsAllowed = 1
+
+	for i := range 3 {
+		email := fmt.Sprintf("guru%d@sekolah.sch.id", i)
+		if _, err := f.service.CreateTeacherOrStudent("Guru", email, "secret123", "teacher", f.admin.ID); err != nil {
+			t.Fatalf("creating teacher %d returned error: %v", i+1, err)
+		}
+	}
+	if _, err := f.service.CreateTeacherOrStudent("Siswa", "siswa@sekolah.sch.id", "secret123", "student", f.admin.ID); err != nil {
+		t.Errorf("the teachers used up the only student seat: %v", err)
+	}
+	if _, err := f.service.CreateTeacherOrStudent("Guru", "guru.penuh@sekolah.sch.id", "secret123", "teacher", f.admin.ID); err != nil {
+		t.Errorf("a full student quota blocked a teacher: %v", err)
+	}
+}
+
+func TestUserListCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 10, 1, 7, 30, 0, 123456789, time.UTC)
+	user := &models.User{ID: uuid.New(), Name: "Budi", Email: "budi@sekolah.sch.id", CreatedAt: createdAt}
+
+	for _, tc := range []struct {
+		sortBy string
+		desc   bool
+		want   interface{}
+	}{
+		{repositories.UserSortName, false, "Budi"},
+		{repositories.UserSortEmail, true, "budi@sekolah.sch.id"},
+		{repositories.UserSortCreatedAt, false, createdAt},
+	} {
+		after, err := decodeUserListCursor(encodeUserListCursor(user, tc.sortBy, tc.desc), tc.sortBy, tc.desc)
+		if err != nil {
+			t.Errorf("%s cursor: decoding returned error: %v", tc.sortBy, err)
+			continue
+		}
+		if after.ID != user.ID {
+			t.Errorf("%s cursor: ID = %s, want %s", tc.sortBy, after.ID, user.ID)
+		}
+		if wantTime, ok := tc.want.(time.Time); ok {
+			if got, ok := after.Value.(time.Time); !ok || !got.Equal(wantTime) {
+				t.Errorf("%s cursor: value = %v, want %v", tc.sortBy, after.Value, wantTime)
+			}
+		} else if after.Value != tc.want {
+			t.Errorf("%s cursor: value = %v, want %v", tc.sortBy, after.Value, tc.want)
+		}
+	}
+}
+
+func TestDecodeUserListCursorRejectsInvalidCursors(t *testing.T) {
+	encode := func(json string) string { return base64.RawURLEncoding.EncodeToString([]byte(json)) }
+	id := uuid.New().String()
+
+	for name, tc := range map[string]struct {
+		cursor string
+		sortBy string
+		desc   bool
+		want   string
+	}{
+		"not base64":           {"%%%", "name", false, "invalid cursor"},
+		"not json":             {encode("budi"), "name", false, "invalid cursor"},
+		"without id":           {encode(`{"s":"name","v":"Budi"}`), "name", false, "invalid cursor"},
+		"bad creation time":    {encode(`{"s":"created_at","v":"kemarin","id":"` + id + `"}`), "created_at", false, "invalid cursor"},
+		"other sort column":    {encode(`{"s":"email","v":"budi@sekolah.sch.id","id":"` + id + `"}`), "name", false, "cursor does not match the requested sort"},
+		"other sort direction": {encode(`{"s":"name","v":"Budi","id":"` + id + `"}`), "name", true, "cursor does not match the requested sort"},
+	} {
+		if _, err := decodeUserListCursor(tc.cursor, tc.sortBy, tc.desc); err == nil || err.Error() != tc.want {
+			t.Errorf("%s: error = %v, want %q", name, err, tc.want)
+		}
+	}
+}
+
+func TestGetAllUsersPagesThroughUsersSharingAName(t *testing.T) {
+	f := newUserCreationFixture()
+	// Five teachers share a name, so only the ID orders them
+	for range 5 {
+		teacher := &models.User{ID: uuid.New(), Name: "Budi", Email: uuid.NewString() + "@sekolah.sch.id", SchoolID: f.admin.SchoolID}
+		f.users.users[teacher.ID] = teacher
+	}
+
+	for _, desc := range []bool{false, true} {
+		seen := make(map[uuid.UUID]bool)
+		query := UserListQuery{SortBy: repositories.UserSortName, Desc: desc, Limit: 2}
+		for pages := 0; ; pages++ {
+			if pages > 10 {
+				t.Fatalf("desc %v: paging did not end", desc)
+			}
+			page, err := f.service.GetAllUsers(query, f.admin.ID)
+			if err != nil {
+				t.Fatalf("desc %v: GetAllUsers returned error: %v", desc, err)
+			}
+			if page.Total != 6 {
+				t.Errorf("desc %v: total = %d, want 6", desc, page.Total)
+			}
+			for _, user := range page.Users {
+				if seen[user.ID] {
+					t.Errorf("desc %v: user %s returned on two pages", desc, user.ID)
+				}
+				seen[user.ID] = true
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			query.Cursor = page.NextCursor
+		}
+		if len(seen) != 6 {
+			t.Errorf("desc %v: paging returned %d users, want all 6", desc, len(seen))
+		}
+	}
+}
+
+func TestGetAllUsersRejectsACursorOfAnotherSort(t *testing.T) {
+	f := newUserCreationFixture()
+
+	cursor := encodeUserListCursor(f.admin, repositories.UserSortEmail, false)
+	if _, err := f.service.GetAllUsers(UserListQuery{SortBy: repositories.UserSortName, Cursor: cursor}, f.admin.ID); err == nil {
+		t.Error("a cursor made for the email order was accepted for the name order")
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"auth-barniee/internal/models"
+	"auth-barniee/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxUserStatusBatch is the most users whose status can be changed in one request.
+const maxUserStatusBatch = 500
+
+// AccountInactiveError is returned when a user whose account is not active logs in or uses a token.
+type AccountInactiveError struct {
+	Status string
+	Reason string
+}
+
+func (e *AccountInactiveError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("account is %s", e.Status)
+	}
+	return fmt.Sprintf("account is %s: %s", e.Status, e.Reason)
+}
+
+// ensureAccountActive refuses users who have been suspended, locked or graduated.
+func ensureAccountActive(user *models.User) error {
+	if user.Status == models.UserStatusActive || user.Status == "" {
+		return nil
+	}
+	return &AccountInactiveError{Status: user.Status, Reason: user.StatusReason}
+}
+
+// UserStatusChangeFailure is a user whose status could not be changed.
+type UserStatusChangeFailure struct {
+	UserID uuid.UUID `json:"user_id"`
+	Error  string    `json:"error"`
+}
+
+// UserStatusChangeResult reports a bulk status change user by user.
+type UserStatusChangeResult struct {
+	Updated []uuid.UUID               `json:"updated"`
+	Failed  []UserStatusChangeFailure `json:"failed"`
+}
+
+type UserStatusService interface {
+	// CheckActive returns an *AccountInactiveError unless the user is active. The auth middleware
+	// calls it on every request, so a suspension takes effect before the user's token expires.
+	CheckActive(userID uuid.UUID) error
+	// SetStatus changes the status of each user the admin manages and skips the others, reporting why.
+	SetStatus(userIDs []uuid.UUID, status, reason string, adminID uuid.UUID) (*UserStatusChangeResult, error)
+}
+
+type userStatusService struct {
+	userRepo   repositories.UserRepository
+	roleRepo   repositories.RoleRepository
+	schoolRepo repositories.SchoolRepository
+}
+
+func NewUserStatusService(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, schoolRepo repositories.SchoolRepository) UserStatusService {
+	return &userStatusService{
+		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+		schoolRepo: schoolRepo,
+	}
+}
+
+func (s *userStatusService) CheckActive(userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("account no longer exists")
+		}
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+	return ensureAccountActive(user)
+}
+
+func (s *userStatusService) SetStatus(userIDs []uuid.UUID, status, reason string, adminID uuid.UUID) (*UserStatusChangeResult, error) {
+	switch status {
+	case models.UserStatusActive, models.UserStatusSuspended, models.UserStatusLocked, models.UserStatusGraduated:
+	default:
+		return nil, errors.New("invalid status, expected active, suspended, locked or graduated")
+	}
+	if len(userIDs) == 0 {
+		return nil, errors.New("no users given")
+	}
+	if len(userIDs) > maxUserStatusBatch {
+		return nil, fmt.Errorf("at most %d users can be changed at once", maxUserStatusBatch)
+	}
+
+	adminUser, err := s.userRepo.FindByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin user not found: %w", err)
+	}
+	canUpdate, err := roleHasPermission(s.roleRepo, adminUser.RoleID, models.PermissionUsersUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if !canUpdate {
+		return nil, errors.New("unauthorized: you do not have permission to update users")
+	}
+
+	result := &UserStatusChangeResult{Updated: []uuid.UUID{}, Failed: []UserStatusChangeFailure{}}
+	seen := make(map[uuid.UUID]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		if err := s.checkStatusChange(adminUser, userID, status); err != nil {
+			result.Failed = append(result.Failed, UserStatusChangeFailure{UserID: userID, Error: err.Error()})
+			continue
+		}
+		result.Updated = append(result.Updated, userID)
+	}
+
+	if len(result.Updated) > 0 {
+		if err := s.userRepo.UpdateStatus(result.Updated, status, reason, adminID); err != nil {
+			return nil, fmt.Errorf("failed to update user status: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// checkStatusChange tells why the admin may not give the user the status, if they may not.
+func (s *userStatusService) checkStatusChange(adminUser *models.User, userID uuid.UUID, status string) error {
+	if userID == adminUser.ID {
+		return errors.New("cannot change the status of your own account")
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+	if !canManageSchool(s.schoolRepo, adminUser, user.SchoolID) {
+		return errors.New("user is outside your school")
+	}
+	if err := ensureCanChangeUser(s.roleRepo, s.schoolRepo, adminUser, user); err != nil {
+		return err
+	}
+	if status == models.UserStatusGraduated && user.Role.SystemName() != "student" {
+		return errors.New("only students can graduate")
+	}
+	return nil
+}
@@ -10,19 +10,29 @@ import (
 )
 
 type Claims struct {
-	UserID   uuid.UUID  `json:"user_id"`
-	Email    string     `json:"email"`
-	Role     string     `json:"role"`
-	SchoolID *uuid.UUID `json:"school_id,omitempty"` // Add SchoolID to claims
+	UserID      uuid.UUID   `json:"user_id"`
+	Email       string      `json:"email"`
+	Role        string      `json:"role"`
+	SchoolID    *uuid.UUID  `json:"school_id,omitempty"` // Add SchoolID to claims
+	StudentIDs  []uuid.UUID `json:"student_ids,omitempty"`
+	Permissions []string    `json:"permissions,omitempty"`
 	jwt.StandardClaims
 }
 
-func GenerateToken(user *models.User, cfg *config.Config) (string, error) {
+// ExtraClaims carries claims that are resolved outside the user row.
+type ExtraClaims struct {
+	StudentIDs  []uuid.UUID // Students linked to a parent account
+	Permissions []string    // Permission names granted by the user's role
+}
+
+func GenerateToken(user *models.User, extra ExtraClaims, cfg *config.Config) (string, error) {
 	expirationTime := time.Now().Add(24 * time.Hour)
 	claims := &Claims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Role:   user.Role.Name,
+		UserID:      user.ID,
+		Email:       user.Email,
+		Role:        user.Role.SystemName(),
+		StudentIDs:  extra.StudentIDs,
+		Permissions: extra.Permissions,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: expirationTime.Unix(),
 		},
@@ -56,3 +66,48 @@ func ParseToken(tokenString string, cfg *config.Config) (*Claims, error) {
 
 	return claims, nil
 }
+
+// RegistrationTokenAudience marks registration-session tokens so they are never accepted as login tokens.
+const RegistrationTokenAudience = "registration"
+
+// RegistrationClaims identify a registration session. Inactivity is tracked on the session itself;
+// the token expiry only caps how long a registration can take in total.
+type RegistrationClaims struct {
+	SessionID uuid.UUID `json:"session_id"`
+	SchoolID  uuid.UUID `json:"school_id"`
+	jwt.StandardClaims
+}
+
+func GenerateRegistrationToken(sessionID, schoolID uuid.UUID, cfg *config.Config) (string, error) {
+	claims := &RegistrationClaims{
+		SessionID: sessionID,
+		SchoolID:  schoolID,
+		StandardClaims: jwt.StandardClaims{
+			Audience:  RegistrationTokenAudience,
+			ExpiresAt: time.Now().Add(7 * 24 * time.Hour).Unix(),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(cfg.JWTSecret))
+}
+
+func ParseRegistrationToken(tokenString string, cfg *config.Config) (*RegistrationClaims, error) {
+	claims := &RegistrationClaims{}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(cfg.JWTSecret), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid || !claims.VerifyAudience(RegistrationTokenAudience, true) {
+		return nil, jwt.ErrSignatureInvalid
+	}
+
+	return claims, nil
+}